@@ -11,9 +11,11 @@ import (
 	"github.com/NetSys/quilt/api"
 	"github.com/NetSys/quilt/api/pb"
 	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/stitch"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
@@ -48,9 +50,50 @@ type Client interface {
 	// QueryClusters retrieves cluster information tracked by the Quilt daemon.
 	QueryClusters() ([]db.Cluster, error)
 
+	// QueryEvents retrieves the events recorded by the Quilt daemon, from oldest
+	// to newest.
+	QueryEvents() ([]db.Event, error)
+
+	// QueryStatuses retrieves the status of each namespace's deployment.
+	QueryStatuses() ([]db.Status, error)
+
+	// QueryAsOf retrieves the rows of `table` as they were at time `at`.  The
+	// result is a slice of the table's type, e.g. []db.Machine for the
+	// MachineTable.
+	QueryAsOf(table db.TableType, at time.Time) (interface{}, error)
+
+	// Watch streams the contents of `tables` from the Quilt daemon.  The contents
+	// of each table are delivered when the watch starts, and again every time
+	// the table changes.
+	Watch(tables ...db.TableType) (*Watcher, error)
+
 	// Deploy makes a request to the Quilt daemon to deploy the given deployment.
 	Deploy(deployment string) error
 
+	// ListDeployments retrieves the recent deployments, from oldest to newest.
+	ListDeployments() ([]db.Deployment, error)
+
+	// Rollback redeploys the stitch of the deployment with the given ID.
+	Rollback(id int) error
+
+	// Plan asks the Quilt daemon for the changes deploying the given deployment
+	// would make, without deploying it.
+	Plan(deployment string) (db.Plan, error)
+
+	// Version retrieves the version of Quilt the daemon is running, and the schema
+	// version it speaks.
+	Version() (string, int, error)
+
+	// CreateToken creates an API token with the given name and role, and returns
+	// its ID along with the token itself.
+	CreateToken(name string, role db.TokenRole) (int, string, error)
+
+	// ListTokens retrieves the API tokens, from oldest to newest.
+	ListTokens() ([]db.Token, error)
+
+	// RevokeToken revokes the API token with the given ID.
+	RevokeToken(id int) error
+
 	// Host returns the server address the Client is connected to.
 	Host() string
 }
@@ -68,14 +111,46 @@ type Getter interface {
 	LeaderClient(Client) (Client, error)
 }
 
+// A TableUpdate holds the contents of a table delivered by a Watcher.
+type TableUpdate struct {
+	Table db.TableType
+	Rows  interface{} // A slice of the table's type, e.g. []db.Machine.
+}
+
+// A Watcher delivers the updates of a Client.Watch.
+type Watcher struct {
+	// The channel on which updates are delivered.  It is closed once the watch
+	// ends, after which Err() reports why.
+	C <-chan TableUpdate
+
+	cancel context.CancelFunc
+	err    error
+}
+
+// Stop ends the watch, and closes the underlying stream.
+func (w *Watcher) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+// Err returns the error that ended the watch, or nil if it was stopped.  It may
+// only be called after C is closed.
+func (w *Watcher) Err() error {
+	return w.err
+}
+
 type clientImpl struct {
 	pbClient   pb.APIClient
 	cc         *grpc.ClientConn
 	serverHost string
 }
 
-// New creates a new Quilt client connected to `lAddr`.
-func New(lAddr string) (Client, error) {
+// New creates a new Quilt client connected to `lAddr`, which authenticates itself
+// and the server using `creds`.
+func New(lAddr string, creds credentials.TransportCredentials, token string) (
+	Client, error) {
+
 	proto, addr, err := api.ParseListenAddress(lAddr)
 	if err != nil {
 		return nil, err
@@ -84,8 +159,14 @@ func New(lAddr string) (Client, error) {
 	dialer := func(dialAddr string, t time.Duration) (net.Conn, error) {
 		return net.DialTimeout(proto, dialAddr, t)
 	}
-	cc, err := grpc.Dial(addr, grpc.WithDialer(dialer), grpc.WithInsecure(),
-		grpc.WithBlock(), grpc.WithTimeout(connectTimeout))
+	opts := []grpc.DialOption{grpc.WithDialer(dialer),
+		grpc.WithTransportCredentials(creds), grpc.WithBlock(),
+		grpc.WithTimeout(connectTimeout)}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials(token)))
+	}
+
+	cc, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -100,13 +181,43 @@ func New(lAddr string) (Client, error) {
 }
 
 func query(pbClient pb.APIClient, table db.TableType) (interface{}, error) {
+	return queryAsOf(pbClient, table, time.Time{})
+}
+
+// queryAsOf queries the contents of `table` at time `at`, or its current contents
+// if `at` is the zero time.
+func queryAsOf(pbClient pb.APIClient, table db.TableType, at time.Time) (
+	interface{}, error) {
+
+	dbQuery := &pb.DBQuery{Table: string(table)}
+	if !at.IsZero() {
+		dbQuery.AsOf = at.UnixNano()
+	}
+
 	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
-	reply, err := pbClient.Query(ctx, &pb.DBQuery{Table: string(table)})
+	reply, err := pbClient.Query(ctx, dbQuery)
 	if err != nil {
 		return nil, err
 	}
 
-	replyBytes := []byte(reply.TableContents)
+	return parseRows(table, reply.Rows, reply.TableContents)
+}
+
+// parseRows converts the contents of `table` into a slice of the table's type.
+// Daemons from before the typed rows only send their JSON encoding, so `contents`
+// is decoded if `rows` is unset.
+func parseRows(table db.TableType, rows *pb.TableRows, contents string) (
+	interface{}, error) {
+
+	if rows != nil {
+		return db.PBToRows(table, rows), nil
+	}
+	return parseJSONRows(table, []byte(contents))
+}
+
+// parseJSONRows decodes the JSON contents of `table` into a slice of the table's
+// type.
+func parseJSONRows(table db.TableType, replyBytes []byte) (interface{}, error) {
 	switch table {
 	case db.MachineTable:
 		var machines []db.Machine
@@ -214,13 +325,192 @@ func (c clientImpl) QueryClusters() ([]db.Cluster, error) {
 	return rows.([]db.Cluster), nil
 }
 
+// QueryEvents retrieves the events recorded by the Quilt daemon, from oldest to
+// newest.
+func (c clientImpl) QueryEvents() ([]db.Event, error) {
+	rows, err := query(c.pbClient, db.EventTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows.([]db.Event), nil
+}
+
+// QueryStatuses retrieves the status of each namespace's deployment.
+func (c clientImpl) QueryStatuses() ([]db.Status, error) {
+	rows, err := query(c.pbClient, db.StatusTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows.([]db.Status), nil
+}
+
+// QueryAsOf retrieves the rows of `table` as they were at time `at`.
+func (c clientImpl) QueryAsOf(table db.TableType, at time.Time) (interface{}, error) {
+	return queryAsOf(c.pbClient, table, at)
+}
+
+// Watch streams the contents of `tables` from the Quilt daemon.
+func (c clientImpl) Watch(tables ...db.TableType) (*Watcher, error) {
+	req := &pb.WatchRequest{}
+	for _, table := range tables {
+		req.Tables = append(req.Tables, string(table))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.pbClient.Watch(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	updates := make(chan TableUpdate)
+	w := &Watcher{C: updates, cancel: cancel}
+	go func() {
+		defer close(updates)
+		for {
+			reply, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() == nil {
+					w.err = err
+				}
+				return
+			}
+
+			table := db.TableType(reply.Table)
+			rows, err := parseRows(table, reply.Rows, reply.TableContents)
+			if err != nil {
+				w.err = err
+				cancel()
+				return
+			}
+
+			select {
+			case updates <- TableUpdate{table, rows}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return w, nil
+}
+
 // Deploy makes a request to the Quilt daemon to deploy the given deployment.
 func (c clientImpl) Deploy(deployment string) error {
+	stc, err := stitch.FromJSON(deployment)
+	if err != nil {
+		return err
+	}
+
+	// The JSON is sent as well, so that daemons from before the typed Stitch
+	// can still deploy it.
+	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
+	_, err = c.pbClient.Deploy(ctx, &pb.DeployRequest{
+		Deployment: deployment,
+		Stitch:     stc.ToPB(),
+	})
+	return err
+}
+
+// ListDeployments retrieves the recent deployments, from oldest to newest.
+func (c clientImpl) ListDeployments() ([]db.Deployment, error) {
+	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
+	reply, err := c.pbClient.ListDeployments(ctx, &pb.ListDeploymentsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var deployments []db.Deployment
+	for _, d := range reply.Deployments {
+		deployments = append(deployments, db.PBToDeployment(d))
+	}
+	return deployments, nil
+}
+
+// Rollback redeploys the stitch of the deployment with the given ID.
+func (c clientImpl) Rollback(id int) error {
+	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
+	_, err := c.pbClient.Rollback(ctx, &pb.RollbackRequest{ID: int32(id)})
+	return err
+}
+
+// Plan asks the Quilt daemon for the changes deploying the given deployment would
+// make, without deploying it.
+func (c clientImpl) Plan(deployment string) (db.Plan, error) {
+	stc, err := stitch.FromJSON(deployment)
+	if err != nil {
+		return db.Plan{}, err
+	}
+
+	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
+	reply, err := c.pbClient.Plan(ctx, &pb.PlanRequest{Stitch: stc.ToPB()})
+	if err != nil {
+		return db.Plan{}, err
+	}
+	return db.PBToPlan(reply), nil
+}
+
+// Version retrieves the version of Quilt the daemon is running, and the schema
+// version it speaks.
+func (c clientImpl) Version() (string, int, error) {
+	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
+	reply, err := c.pbClient.Version(ctx, &pb.VersionRequest{})
+	if err != nil {
+		return "", 0, err
+	}
+	return reply.Version, int(reply.SchemaVersion), nil
+}
+
+// CreateToken creates an API token with the given name and role, and returns its ID
+// along with the token itself.
+func (c clientImpl) CreateToken(name string, role db.TokenRole) (int, string, error) {
 	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
-	_, err := c.pbClient.Deploy(ctx, &pb.DeployRequest{Deployment: deployment})
+	reply, err := c.pbClient.CreateToken(ctx, &pb.CreateTokenRequest{
+		Name: name,
+		Role: string(role),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	return int(reply.ID), reply.Token, nil
+}
+
+// ListTokens retrieves the API tokens, from oldest to newest.
+func (c clientImpl) ListTokens() ([]db.Token, error) {
+	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
+	reply, err := c.pbClient.ListTokens(ctx, &pb.ListTokensRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []db.Token
+	for _, t := range reply.Tokens {
+		tokens = append(tokens, db.PBToToken(t))
+	}
+	return tokens, nil
+}
+
+// RevokeToken revokes the API token with the given ID.
+func (c clientImpl) RevokeToken(id int) error {
+	ctx, _ := context.WithTimeout(context.Background(), requestTimeout)
+	_, err := c.pbClient.RevokeToken(ctx, &pb.RevokeTokenRequest{ID: int32(id)})
 	return err
 }
 
 func (c clientImpl) Host() string {
 	return c.serverHost
 }
+
+// tokenCredentials attaches an API token to every RPC.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (
+	map[string]string, error) {
+
+	return map[string]string{api.TokenMetadataKey: string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
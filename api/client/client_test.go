@@ -2,33 +2,118 @@ package client
 
 import (
 	"errors"
+	"io"
 	"reflect"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
 	"github.com/NetSys/quilt/api/pb"
 	"github.com/NetSys/quilt/db"
+	"github.com/stretchr/testify/assert"
 )
 
 type mockAPIClient struct {
 	mockResponse string
+	mockRows     *pb.TableRows
 	mockError    error
+
+	// If non-nil, Deploy stores its request here.
+	deployed *pb.DeployRequest
 }
 
 func (c mockAPIClient) Query(ctx context.Context, in *pb.DBQuery,
 	opts ...grpc.CallOption) (*pb.QueryReply, error) {
 
-	return &pb.QueryReply{TableContents: c.mockResponse}, c.mockError
+	return &pb.QueryReply{TableContents: c.mockResponse, Rows: c.mockRows},
+		c.mockError
 }
 
 func (c mockAPIClient) Deploy(ctx context.Context, in *pb.DeployRequest,
 	opts ...grpc.CallOption) (*pb.DeployReply, error) {
 
+	if c.deployed != nil {
+		*c.deployed = *in
+	}
 	return &pb.DeployReply{}, nil
 }
 
+func (c mockAPIClient) Plan(ctx context.Context, in *pb.PlanRequest,
+	opts ...grpc.CallOption) (*pb.PlanReply, error) {
+
+	return &pb.PlanReply{BootMachines: c.mockRows.GetMachines()}, c.mockError
+}
+
+func (c mockAPIClient) ListDeployments(ctx context.Context,
+	in *pb.ListDeploymentsRequest, opts ...grpc.CallOption) (
+	*pb.ListDeploymentsReply, error) {
+
+	return &pb.ListDeploymentsReply{}, c.mockError
+}
+
+func (c mockAPIClient) Rollback(ctx context.Context, in *pb.RollbackRequest,
+	opts ...grpc.CallOption) (*pb.DeployReply, error) {
+
+	return &pb.DeployReply{}, c.mockError
+}
+
+func (c mockAPIClient) Version(ctx context.Context, in *pb.VersionRequest,
+	opts ...grpc.CallOption) (*pb.VersionReply, error) {
+
+	return &pb.VersionReply{Version: "1.0", SchemaVersion: 1}, c.mockError
+}
+
+func (c mockAPIClient) CreateToken(ctx context.Context, in *pb.CreateTokenRequest,
+	opts ...grpc.CallOption) (*pb.CreateTokenReply, error) {
+
+	return &pb.CreateTokenReply{ID: 1, Token: in.Name + "-" + in.Role}, c.mockError
+}
+
+func (c mockAPIClient) ListTokens(ctx context.Context, in *pb.ListTokensRequest,
+	opts ...grpc.CallOption) (*pb.ListTokensReply, error) {
+
+	return &pb.ListTokensReply{Tokens: []*pb.Token{
+		{ID: 1, Name: "ci", Role: "deployer", Created: 100},
+	}}, c.mockError
+}
+
+func (c mockAPIClient) RevokeToken(ctx context.Context, in *pb.RevokeTokenRequest,
+	opts ...grpc.CallOption) (*pb.RevokeTokenReply, error) {
+
+	return &pb.RevokeTokenReply{}, c.mockError
+}
+
+func (c mockAPIClient) Watch(ctx context.Context, in *pb.WatchRequest,
+	opts ...grpc.CallOption) (pb.API_WatchClient, error) {
+
+	var replies []*pb.WatchReply
+	for _, table := range in.Tables {
+		replies = append(replies, &pb.WatchReply{
+			Table:         table,
+			TableContents: c.mockResponse,
+		})
+	}
+	return &mockWatchClient{replies: replies}, c.mockError
+}
+
+type mockWatchClient struct {
+	grpc.ClientStream
+
+	replies []*pb.WatchReply
+}
+
+func (c *mockWatchClient) Recv() (*pb.WatchReply, error) {
+	if len(c.replies) == 0 {
+		return nil, io.EOF
+	}
+
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply, nil
+}
+
 func TestUnmarshalMachine(t *testing.T) {
 	t.Parallel()
 
@@ -92,6 +177,118 @@ func TestUnmarshalContainer(t *testing.T) {
 	}
 }
 
+func TestQueryRows(t *testing.T) {
+	t.Parallel()
+
+	// The typed rows take precedence over their JSON encoding.
+	apiClient := mockAPIClient{
+		mockResponse: `[{"ID":2}]`,
+		mockRows: &pb.TableRows{Machines: []*pb.Machine{
+			{ID: 1, Role: "Master", Provider: "Amazon", PublicIP: "8.8.8.8"},
+		}},
+	}
+	c := clientImpl{pbClient: apiClient}
+	res, err := c.QueryMachines()
+	assert.NoError(t, err)
+	assert.Equal(t, []db.Machine{{ID: 1, Role: db.Master, Provider: db.Amazon,
+		PublicIP: "8.8.8.8"}}, res)
+
+	apiClient = mockAPIClient{mockRows: &pb.TableRows{Events: []*pb.Event{
+		{ID: 1, Time: 100, Type: "MachineBooted", Message: "booted"},
+	}}}
+	c = clientImpl{pbClient: apiClient}
+	events, err := c.QueryEvents()
+	assert.NoError(t, err)
+	assert.Equal(t, []db.Event{{ID: 1, Time: time.Unix(0, 100),
+		Type: db.MachineBooted, Message: "booted"}}, events)
+
+	apiClient = mockAPIClient{mockRows: &pb.TableRows{Statuses: []*pb.Status{
+		{ID: 1, Namespace: "ns", State: "Blocked",
+			UnschedulableContainers: []string{"1 (alpine)"}},
+	}}}
+	c = clientImpl{pbClient: apiClient}
+	statuses, err := c.QueryStatuses()
+	assert.NoError(t, err)
+	assert.Equal(t, []db.Status{{ID: 1, Namespace: "ns", State: db.Blocked,
+		UnschedulableContainers: []string{"1 (alpine)"}}}, statuses)
+}
+
+func TestDeploy(t *testing.T) {
+	t.Parallel()
+
+	deployed := &pb.DeployRequest{}
+	c := clientImpl{pbClient: mockAPIClient{deployed: deployed}}
+
+	deployment := `{"Machines":[{"Provider":"Amazon","Role":"Master"}]}`
+	assert.NoError(t, c.Deploy(deployment))
+	assert.Equal(t, deployment, deployed.Deployment)
+	assert.Equal(t, []*pb.Stitch_Machine{{Provider: "Amazon", Role: "Master",
+		CPU: &pb.Stitch_Range{}, RAM: &pb.Stitch_Range{}}},
+		deployed.Stitch.Machines)
+
+	assert.EqualError(t, c.Deploy("{"), "unexpected end of JSON input")
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	apiClient := mockAPIClient{mockRows: &pb.TableRows{
+		Machines: []*pb.Machine{{Role: "Master", Provider: "Amazon"}},
+	}}
+	c := clientImpl{pbClient: apiClient}
+	plan, err := c.Plan(`{"Namespace": "ns"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, db.Plan{BootMachines: []db.Machine{
+		{Role: db.Master, Provider: db.Amazon}}}, plan)
+
+	_, err = c.Plan("{")
+	assert.EqualError(t, err, "unexpected end of JSON input")
+}
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+
+	c := clientImpl{pbClient: mockAPIClient{}}
+	ver, schema, err := c.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", ver)
+	assert.Equal(t, 1, schema)
+
+	c = clientImpl{pbClient: mockAPIClient{mockError: errors.New("unimplemented")}}
+	_, _, err = c.Version()
+	assert.EqualError(t, err, "unimplemented")
+}
+
+func TestTokens(t *testing.T) {
+	t.Parallel()
+
+	c := clientImpl{pbClient: mockAPIClient{}}
+	id, token, err := c.CreateToken("ci", db.Deployer)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+	assert.Equal(t, "ci-deployer", token)
+
+	tokens, err := c.ListTokens()
+	assert.NoError(t, err)
+	assert.Equal(t, []db.Token{{ID: 1, Name: "ci", Role: db.Deployer,
+		Created: time.Unix(0, 100)}}, tokens)
+
+	assert.NoError(t, c.RevokeToken(1))
+
+	creds := tokenCredentials("secret")
+	md, err := creds.GetRequestMetadata(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"token": "secret"}, md)
+	assert.True(t, creds.RequireTransportSecurity())
+
+	c = clientImpl{pbClient: mockAPIClient{mockError: errors.New("denied")}}
+	_, _, err = c.CreateToken("ci", db.Deployer)
+	assert.EqualError(t, err, "denied")
+	_, err = c.ListTokens()
+	assert.EqualError(t, err, "denied")
+	assert.EqualError(t, c.RevokeToken(1), "denied")
+}
+
 func TestUnmarshalError(t *testing.T) {
 	t.Parallel()
 
@@ -130,3 +327,32 @@ func TestGrpcError(t *testing.T) {
 			exp.Error(), err.Error())
 	}
 }
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	apiClient := mockAPIClient{
+		mockResponse: `[{"ID":1,"Role":"Master"}]`,
+	}
+	c := clientImpl{pbClient: apiClient}
+
+	watcher, err := c.Watch(db.MachineTable)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer watcher.Stop()
+
+	update := <-watcher.C
+	exp := TableUpdate{db.MachineTable, []db.Machine{{ID: 1, Role: db.Master}}}
+	if !reflect.DeepEqual(exp, update) {
+		t.Errorf("Bad watch update: expected %v, got %v.", exp, update)
+	}
+
+	// The watch ends once the stream does.
+	if _, ok := <-watcher.C; ok {
+		t.Error("Expected the watch to end")
+	}
+	if watcher.Err() != io.EOF {
+		t.Errorf("Expected the watch to end with EOF, got %v", watcher.Err())
+	}
+}
@@ -3,17 +3,35 @@ package getter
 import (
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"strconv"
 
 	log "github.com/Sirupsen/logrus"
 
 	"github.com/NetSys/quilt/api"
 	"github.com/NetSys/quilt/api/client"
 	"github.com/NetSys/quilt/api/util"
+	"github.com/NetSys/quilt/certs"
+	"github.com/NetSys/quilt/version"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
-// New returns an implementation of the Getter interface.
-func New() client.Getter {
-	return clientGetterImpl{addrClientGetterImpl{}}
+// New returns an implementation of the Getter interface, whose clients present the
+// credentials stored in 'tlsDir'.
+func New(tlsDir string) client.Getter {
+	return clientGetterImpl{addrClientGetterImpl{tlsDir}}
+}
+
+// TLSDir returns the directory named by the api.TLSDirEnv environment variable, or
+// certs.DefaultDir() if it isn't set.
+func TLSDir() string {
+	if dir := os.Getenv(api.TLSDirEnv); dir != "" {
+		return dir
+	}
+	return certs.DefaultDir()
 }
 
 // We create a separate interface for getting a client given an address so that
@@ -22,23 +40,92 @@ type addrClientGetter interface {
 	Client(string) (client.Client, error)
 }
 
-type addrClientGetterImpl struct{}
+type addrClientGetterImpl struct {
+	tlsDir string
+}
 
 type clientGetterImpl struct {
 	addrClientGetter
 }
 
 func (getter addrClientGetterImpl) Client(host string) (client.Client, error) {
-	c, err := client.New(host)
+	name := serverName(host)
+	creds, err := certs.LoadClientCredentials(getter.tlsDir, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS credentials: %s", err)
+	}
+
+	c, err := client.New(host, creds, token(name))
 	if err != nil {
 		return nil, daemonConnectError{
 			host:         host,
 			connectError: err,
 		}
 	}
+
+	if err := checkVersion(c); err != nil {
+		c.Close()
+		return nil, err
+	}
 	return c, nil
 }
 
+// serverName returns the name that the server at 'host' must present a certificate
+// for.  The minions serve the API on api.DefaultRemotePort, and are certified for
+// their IP addresses, which gRPC checks against the host that's dialed.  Anything else
+// is the daemon.
+func serverName(host string) string {
+	_, addr, err := api.ParseListenAddress(host)
+	if err != nil {
+		return certs.DaemonName
+	}
+
+	_, port, err := net.SplitHostPort(addr)
+	if err == nil && port == strconv.Itoa(api.DefaultRemotePort) {
+		return ""
+	}
+	return certs.DaemonName
+}
+
+// token returns the API token to present to the server certified for 'name'.  Only
+// the daemon enforces tokens, so they're kept from the minions, which may not be
+// trusted with them.
+func token(name string) string {
+	if name != certs.DaemonName {
+		return ""
+	}
+	return os.Getenv(api.TokenEnv)
+}
+
+// checkVersion refuses daemons that speak a different schema than quiltctl, and warns
+// about daemons built from a different version of Quilt.  Daemons from before the
+// Version RPC are allowed, with a warning.
+func checkVersion(c client.Client) error {
+	daemonVersion, schema, err := c.Version()
+	if grpc.Code(err) == codes.Unimplemented {
+		log.WithField("host", c.Host()).Warn("The Quilt daemon doesn't report " +
+			"its version, and may be incompatible with quiltctl.")
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get the version of the Quilt daemon at "+
+			"%s: %s", c.Host(), err)
+	}
+
+	if err := version.Check(daemonVersion, schema); err != nil {
+		return fmt.Errorf("the Quilt daemon at %s is incompatible with "+
+			"quiltctl: %s", c.Host(), err)
+	}
+
+	if daemonVersion != version.Version {
+		log.WithFields(log.Fields{
+			"host":     c.Host(),
+			"daemon":   daemonVersion,
+			"quiltctl": version.Version,
+		}).Warn("The Quilt daemon is running a different version of Quilt.")
+	}
+	return nil
+}
+
 func (getter clientGetterImpl) LeaderClient(localClient client.Client) (
 	client.Client, error) {
 
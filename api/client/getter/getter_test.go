@@ -1,14 +1,20 @@
 package getter
 
 import (
+	"errors"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/NetSys/quilt/api"
 	"github.com/NetSys/quilt/api/client"
 	"github.com/NetSys/quilt/api/client/mocks"
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/version"
 )
 
 func TestGetLeaderClient(t *testing.T) {
@@ -161,3 +167,49 @@ type mockAddrClientGetter struct {
 func (mcg mockAddrClientGetter) Client(host string) (client.Client, error) {
 	return mcg.getter(host)
 }
+
+func TestCheckVersion(t *testing.T) {
+	t.Parallel()
+
+	c := &mocks.Client{VersionReturn: version.Version, SchemaReturn: version.Schema}
+	assert.NoError(t, checkVersion(c))
+
+	// Different builds that speak the same schema only warrant a warning.
+	c.VersionReturn = "other"
+	assert.NoError(t, checkVersion(c))
+
+	c.SchemaReturn = version.Schema + 1
+	assert.Error(t, checkVersion(c))
+
+	// Daemons from before the Version RPC are allowed.
+	c.VersionErr = grpc.Errorf(codes.Unimplemented, "unknown method Version")
+	assert.NoError(t, checkVersion(c))
+
+	c.VersionErr = errors.New("timeout")
+	assert.Error(t, checkVersion(c))
+}
+
+func TestServerName(t *testing.T) {
+	assert.Equal(t, certs.DaemonName, serverName(api.DefaultSocket))
+	assert.Equal(t, certs.DaemonName, serverName("tcp://127.0.0.1:9001"))
+	assert.Equal(t, "", serverName(api.RemoteAddress("8.8.8.8")))
+}
+
+func TestToken(t *testing.T) {
+	defer os.Setenv(api.TokenEnv, os.Getenv(api.TokenEnv))
+	os.Setenv(api.TokenEnv, "secret")
+
+	assert.Equal(t, "secret", token(serverName(api.DefaultSocket)))
+	assert.Equal(t, "secret", token(serverName("tcp://127.0.0.1:9001")))
+	assert.Equal(t, "", token(serverName(api.RemoteAddress("8.8.8.8"))))
+}
+
+func TestTLSDir(t *testing.T) {
+	defer os.Setenv(api.TLSDirEnv, os.Getenv(api.TLSDirEnv))
+
+	os.Setenv(api.TLSDirEnv, "")
+	assert.Equal(t, certs.DefaultDir(), TLSDir())
+
+	os.Setenv(api.TLSDirEnv, "/tmp/tls")
+	assert.Equal(t, "/tmp/tls", TLSDir())
+}
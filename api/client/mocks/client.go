@@ -1,6 +1,9 @@
 package mocks
 
 import (
+	"time"
+
+	"github.com/NetSys/quilt/api/client"
 	"github.com/NetSys/quilt/db"
 )
 
@@ -10,11 +13,36 @@ type Client struct {
 	ContainerReturn []db.Container
 	EtcdReturn      []db.Etcd
 	ClusterReturn   []db.Cluster
+	EventReturn     []db.Event
+	StatusReturn    []db.Status
 	HostReturn      string
 	DeployArg       string
 
+	AsOfReturn interface{}
+	AsOfTable  db.TableType
+	AsOfTime   time.Time
+
+	WatchReturn chan client.TableUpdate
+	WatchArg    []db.TableType
+
+	PlanReturn db.Plan
+	PlanArg    string
+
+	DeploymentsReturn []db.Deployment
+	RollbackArg       int
+
+	VersionReturn string
+	SchemaReturn  int
+
+	CreateTokenArgs   db.Token
+	CreateTokenReturn string
+	TokensReturn      []db.Token
+	RevokeTokenArg    int
+
 	MachineErr, ContainerErr, EtcdErr, ClusterErr, HostErr error
-	DeployErr, ConnectionErr                               error
+	DeployErr, ConnectionErr, AsOfErr, WatchErr, PlanErr   error
+	DeploymentsErr, RollbackErr, VersionErr, EventErr      error
+	StatusErr, TokenErr                                    error
 }
 
 // QueryMachines retrieves the machines tracked by the Quilt daemon.
@@ -63,6 +91,42 @@ func (c *Client) QueryClusters() ([]db.Cluster, error) {
 	return c.ClusterReturn, nil
 }
 
+// QueryEvents retrieves the events recorded by the Quilt daemon.
+func (c *Client) QueryEvents() ([]db.Event, error) {
+	if c.EventErr != nil {
+		return nil, c.EventErr
+	}
+	return c.EventReturn, nil
+}
+
+// QueryStatuses retrieves the status of each namespace's deployment.
+func (c *Client) QueryStatuses() ([]db.Status, error) {
+	if c.StatusErr != nil {
+		return nil, c.StatusErr
+	}
+	return c.StatusReturn, nil
+}
+
+// QueryAsOf retrieves the rows of `table` as they were at time `at`.
+func (c *Client) QueryAsOf(table db.TableType, at time.Time) (interface{}, error) {
+	c.AsOfTable = table
+	c.AsOfTime = at
+	if c.AsOfErr != nil {
+		return nil, c.AsOfErr
+	}
+	return c.AsOfReturn, nil
+}
+
+// Watch streams the contents of `tables` from the Quilt daemon.  The returned
+// Watcher delivers whatever is sent on WatchReturn.
+func (c *Client) Watch(tables ...db.TableType) (*client.Watcher, error) {
+	c.WatchArg = tables
+	if c.WatchErr != nil {
+		return nil, c.WatchErr
+	}
+	return &client.Watcher{C: c.WatchReturn}, nil
+}
+
 // Close the grpc connection.
 func (c *Client) Close() error {
 	return nil
@@ -77,7 +141,63 @@ func (c *Client) Deploy(depl string) error {
 	return nil
 }
 
+// ListDeployments retrieves the recent deployments, from oldest to newest.
+func (c *Client) ListDeployments() ([]db.Deployment, error) {
+	if c.DeploymentsErr != nil {
+		return nil, c.DeploymentsErr
+	}
+	return c.DeploymentsReturn, nil
+}
+
+// Rollback redeploys the stitch of the deployment with the given ID.
+func (c *Client) Rollback(id int) error {
+	c.RollbackArg = id
+	return c.RollbackErr
+}
+
+// Plan asks the Quilt daemon for the changes deploying the given deployment would
+// make.
+func (c *Client) Plan(depl string) (db.Plan, error) {
+	c.PlanArg = depl
+	if c.PlanErr != nil {
+		return db.Plan{}, c.PlanErr
+	}
+	return c.PlanReturn, nil
+}
+
 // Host returns the server address the Client is connected to.
 func (c *Client) Host() string {
 	return c.HostReturn
 }
+
+// Version retrieves the version of Quilt the daemon is running, and the schema
+// version it speaks.
+func (c *Client) Version() (string, int, error) {
+	if c.VersionErr != nil {
+		return "", 0, c.VersionErr
+	}
+	return c.VersionReturn, c.SchemaReturn, nil
+}
+
+// CreateToken creates an API token with the given name and role.
+func (c *Client) CreateToken(name string, role db.TokenRole) (int, string, error) {
+	c.CreateTokenArgs = db.Token{Name: name, Role: role}
+	if c.TokenErr != nil {
+		return 0, "", c.TokenErr
+	}
+	return 1, c.CreateTokenReturn, nil
+}
+
+// ListTokens retrieves the API tokens.
+func (c *Client) ListTokens() ([]db.Token, error) {
+	if c.TokenErr != nil {
+		return nil, c.TokenErr
+	}
+	return c.TokensReturn, nil
+}
+
+// RevokeToken revokes the API token with the given ID.
+func (c *Client) RevokeToken(id int) error {
+	c.RevokeTokenArg = id
+	return c.TokenErr
+}
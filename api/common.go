@@ -13,6 +13,18 @@ const DefaultSocket = "unix:///tmp/quilt.sock"
 // DefaultRemotePort is the port remote Quilt daemons (the minion) listen on by default.
 const DefaultRemotePort = 9000
 
+// TokenEnv is the environment variable from which quiltctl reads the API token it
+// presents to the daemon.
+const TokenEnv = "QUILT_TOKEN"
+
+// TLSDirEnv is the environment variable from which quiltctl reads the directory
+// holding the credentials it presents to the daemon and the minions.  It must be set
+// if the daemon was started with a non-default -tls-dir.
+const TLSDirEnv = "QUILT_TLS_DIR"
+
+// TokenMetadataKey is the gRPC metadata key under which clients send their API token.
+const TokenMetadataKey = "token"
+
 // ParseListenAddress validates and parses a socket address into the
 // protocol and address.
 func ParseListenAddress(lAddr string) (string, string, error) {
@@ -13,6 +13,36 @@ It has these top-level messages:
 	QueryReply
 	DeployRequest
 	DeployReply
+	ListDeploymentsRequest
+	ListDeploymentsReply
+	RollbackRequest
+	CreateTokenRequest
+	CreateTokenReply
+	ListTokensRequest
+	ListTokensReply
+	RevokeTokenRequest
+	RevokeTokenReply
+	PlanRequest
+	PlanReply
+	WatchRequest
+	WatchReply
+	TableRows
+	Machine
+	Container
+	Label
+	Connection
+	Etcd
+	Cluster
+	Placement
+	ACL
+	Minion
+	Deployment
+	Event
+	Status
+	Token
+	Stitch
+	VersionRequest
+	VersionReply
 */
 package pb
 
@@ -38,6 +68,8 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type DBQuery struct {
 	Table string `protobuf:"bytes,1,opt,name=Table,json=table" json:"Table,omitempty"`
+	// If non-zero, query the table as it was at this Unix time, in nanoseconds.
+	AsOf int64 `protobuf:"varint,2,opt,name=AsOf,json=asOf" json:"AsOf,omitempty"`
 }
 
 func (m *DBQuery) Reset()                    { *m = DBQuery{} }
@@ -52,51 +84,1701 @@ func (m *DBQuery) GetTable() string {
 	return ""
 }
 
+func (m *DBQuery) GetAsOf() int64 {
+	if m != nil {
+		return m.AsOf
+	}
+	return 0
+}
+
 type QueryReply struct {
-	TableContents string `protobuf:"bytes,1,opt,name=TableContents,json=tableContents" json:"TableContents,omitempty"`
+	// Deprecated: The JSON encoding of the rows, kept for clients that predate
+	// Rows.  It will be removed in the next release.
+	TableContents string     `protobuf:"bytes,1,opt,name=TableContents,json=tableContents" json:"TableContents,omitempty"`
+	Rows          *TableRows `protobuf:"bytes,2,opt,name=Rows,json=rows" json:"Rows,omitempty"`
+}
+
+func (m *QueryReply) Reset()                    { *m = QueryReply{} }
+func (m *QueryReply) String() string            { return proto.CompactTextString(m) }
+func (*QueryReply) ProtoMessage()               {}
+func (*QueryReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *QueryReply) GetTableContents() string {
+	if m != nil {
+		return m.TableContents
+	}
+	return ""
+}
+
+func (m *QueryReply) GetRows() *TableRows {
+	if m != nil {
+		return m.Rows
+	}
+	return nil
+}
+
+type DeployRequest struct {
+	// Deprecated: The JSON encoding of the stitch, kept for clients that predate
+	// Stitch.  It is ignored if Stitch is set, and will be removed in the next
+	// release.
+	Deployment string  `protobuf:"bytes,1,opt,name=Deployment,json=deployment" json:"Deployment,omitempty"`
+	Stitch     *Stitch `protobuf:"bytes,2,opt,name=Stitch,json=stitch" json:"Stitch,omitempty"`
+}
+
+func (m *DeployRequest) Reset()                    { *m = DeployRequest{} }
+func (m *DeployRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeployRequest) ProtoMessage()               {}
+func (*DeployRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *DeployRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *DeployRequest) GetStitch() *Stitch {
+	if m != nil {
+		return m.Stitch
+	}
+	return nil
+}
+
+type DeployReply struct {
+}
+
+func (m *DeployReply) Reset()                    { *m = DeployReply{} }
+func (m *DeployReply) String() string            { return proto.CompactTextString(m) }
+func (*DeployReply) ProtoMessage()               {}
+func (*DeployReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+type ListDeploymentsRequest struct {
+}
+
+func (m *ListDeploymentsRequest) Reset()                    { *m = ListDeploymentsRequest{} }
+func (m *ListDeploymentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListDeploymentsRequest) ProtoMessage()               {}
+func (*ListDeploymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type ListDeploymentsReply struct {
+	Deployments []*Deployment `protobuf:"bytes,1,rep,name=Deployments,json=deployments" json:"Deployments,omitempty"`
+}
+
+func (m *ListDeploymentsReply) Reset()                    { *m = ListDeploymentsReply{} }
+func (m *ListDeploymentsReply) String() string            { return proto.CompactTextString(m) }
+func (*ListDeploymentsReply) ProtoMessage()               {}
+func (*ListDeploymentsReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *ListDeploymentsReply) GetDeployments() []*Deployment {
+	if m != nil {
+		return m.Deployments
+	}
+	return nil
+}
+
+type RollbackRequest struct {
+	// The ID of the deployment to roll back to.
+	ID int32 `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+}
+
+func (m *RollbackRequest) Reset()                    { *m = RollbackRequest{} }
+func (m *RollbackRequest) String() string            { return proto.CompactTextString(m) }
+func (*RollbackRequest) ProtoMessage()               {}
+func (*RollbackRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *RollbackRequest) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+type CreateTokenRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=Name,json=name" json:"Name,omitempty"`
+	Role string `protobuf:"bytes,2,opt,name=Role,json=role" json:"Role,omitempty"`
+}
+
+func (m *CreateTokenRequest) Reset()                    { *m = CreateTokenRequest{} }
+func (m *CreateTokenRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateTokenRequest) ProtoMessage()               {}
+func (*CreateTokenRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *CreateTokenRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateTokenRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type CreateTokenReply struct {
+	// The new token.  It's only ever returned here, as the daemon only stores its
+	// hash.
+	Token string `protobuf:"bytes,1,opt,name=Token,json=token" json:"Token,omitempty"`
+	ID    int32  `protobuf:"varint,2,opt,name=ID,json=iD" json:"ID,omitempty"`
+}
+
+func (m *CreateTokenReply) Reset()                    { *m = CreateTokenReply{} }
+func (m *CreateTokenReply) String() string            { return proto.CompactTextString(m) }
+func (*CreateTokenReply) ProtoMessage()               {}
+func (*CreateTokenReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *CreateTokenReply) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *CreateTokenReply) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+type ListTokensRequest struct {
+}
+
+func (m *ListTokensRequest) Reset()                    { *m = ListTokensRequest{} }
+func (m *ListTokensRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListTokensRequest) ProtoMessage()               {}
+func (*ListTokensRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+type ListTokensReply struct {
+	Tokens []*Token `protobuf:"bytes,1,rep,name=Tokens,json=tokens" json:"Tokens,omitempty"`
+}
+
+func (m *ListTokensReply) Reset()                    { *m = ListTokensReply{} }
+func (m *ListTokensReply) String() string            { return proto.CompactTextString(m) }
+func (*ListTokensReply) ProtoMessage()               {}
+func (*ListTokensReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *ListTokensReply) GetTokens() []*Token {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type RevokeTokenRequest struct {
+	// The ID of the token to revoke.
+	ID int32 `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+}
+
+func (m *RevokeTokenRequest) Reset()                    { *m = RevokeTokenRequest{} }
+func (m *RevokeTokenRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeTokenRequest) ProtoMessage()               {}
+func (*RevokeTokenRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *RevokeTokenRequest) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+type RevokeTokenReply struct {
+}
+
+func (m *RevokeTokenReply) Reset()                    { *m = RevokeTokenReply{} }
+func (m *RevokeTokenReply) String() string            { return proto.CompactTextString(m) }
+func (*RevokeTokenReply) ProtoMessage()               {}
+func (*RevokeTokenReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+type PlanRequest struct {
+	Stitch *Stitch `protobuf:"bytes,1,opt,name=Stitch,json=stitch" json:"Stitch,omitempty"`
+}
+
+func (m *PlanRequest) Reset()                    { *m = PlanRequest{} }
+func (m *PlanRequest) String() string            { return proto.CompactTextString(m) }
+func (*PlanRequest) ProtoMessage()               {}
+func (*PlanRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *PlanRequest) GetStitch() *Stitch {
+	if m != nil {
+		return m.Stitch
+	}
+	return nil
+}
+
+// The changes deploying a stitch would make.
+type PlanReply struct {
+	BootMachines           []*Machine       `protobuf:"bytes,1,rep,name=BootMachines,json=bootMachines" json:"BootMachines,omitempty"`
+	TerminateMachines      []*Machine       `protobuf:"bytes,2,rep,name=TerminateMachines,json=terminateMachines" json:"TerminateMachines,omitempty"`
+	StartContainers        []*Container     `protobuf:"bytes,3,rep,name=StartContainers,json=startContainers" json:"StartContainers,omitempty"`
+	StopContainers         []*Container     `protobuf:"bytes,4,rep,name=StopContainers,json=stopContainers" json:"StopContainers,omitempty"`
+	AddConnections         []*Connection    `protobuf:"bytes,5,rep,name=AddConnections,json=addConnections" json:"AddConnections,omitempty"`
+	RemoveConnections      []*Connection    `protobuf:"bytes,6,rep,name=RemoveConnections,json=removeConnections" json:"RemoveConnections,omitempty"`
+	AddAdminACLs           []string         `protobuf:"bytes,7,rep,name=AddAdminACLs,json=addAdminACLs" json:"AddAdminACLs,omitempty"`
+	RemoveAdminACLs        []string         `protobuf:"bytes,8,rep,name=RemoveAdminACLs,json=removeAdminACLs" json:"RemoveAdminACLs,omitempty"`
+	AddApplicationPorts    []*ACL_PortRange `protobuf:"bytes,9,rep,name=AddApplicationPorts,json=addApplicationPorts" json:"AddApplicationPorts,omitempty"`
+	RemoveApplicationPorts []*ACL_PortRange `protobuf:"bytes,10,rep,name=RemoveApplicationPorts,json=removeApplicationPorts" json:"RemoveApplicationPorts,omitempty"`
+}
+
+func (m *PlanReply) Reset()                    { *m = PlanReply{} }
+func (m *PlanReply) String() string            { return proto.CompactTextString(m) }
+func (*PlanReply) ProtoMessage()               {}
+func (*PlanReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *PlanReply) GetBootMachines() []*Machine {
+	if m != nil {
+		return m.BootMachines
+	}
+	return nil
+}
+
+func (m *PlanReply) GetTerminateMachines() []*Machine {
+	if m != nil {
+		return m.TerminateMachines
+	}
+	return nil
+}
+
+func (m *PlanReply) GetStartContainers() []*Container {
+	if m != nil {
+		return m.StartContainers
+	}
+	return nil
+}
+
+func (m *PlanReply) GetStopContainers() []*Container {
+	if m != nil {
+		return m.StopContainers
+	}
+	return nil
+}
+
+func (m *PlanReply) GetAddConnections() []*Connection {
+	if m != nil {
+		return m.AddConnections
+	}
+	return nil
+}
+
+func (m *PlanReply) GetRemoveConnections() []*Connection {
+	if m != nil {
+		return m.RemoveConnections
+	}
+	return nil
+}
+
+func (m *PlanReply) GetAddAdminACLs() []string {
+	if m != nil {
+		return m.AddAdminACLs
+	}
+	return nil
+}
+
+func (m *PlanReply) GetRemoveAdminACLs() []string {
+	if m != nil {
+		return m.RemoveAdminACLs
+	}
+	return nil
+}
+
+func (m *PlanReply) GetAddApplicationPorts() []*ACL_PortRange {
+	if m != nil {
+		return m.AddApplicationPorts
+	}
+	return nil
+}
+
+func (m *PlanReply) GetRemoveApplicationPorts() []*ACL_PortRange {
+	if m != nil {
+		return m.RemoveApplicationPorts
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	Tables []string `protobuf:"bytes,1,rep,name=Tables,json=tables" json:"Tables,omitempty"`
+}
+
+func (m *WatchRequest) Reset()                    { *m = WatchRequest{} }
+func (m *WatchRequest) String() string            { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()               {}
+func (*WatchRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *WatchRequest) GetTables() []string {
+	if m != nil {
+		return m.Tables
+	}
+	return nil
+}
+
+// The contents of a watched table.  One is sent for each table when the watch
+// starts, and again each time the table changes.
+type WatchReply struct {
+	Table string `protobuf:"bytes,1,opt,name=Table,json=table" json:"Table,omitempty"`
+	// Deprecated: The JSON encoding of the rows, as in QueryReply.
+	TableContents string     `protobuf:"bytes,2,opt,name=TableContents,json=tableContents" json:"TableContents,omitempty"`
+	Rows          *TableRows `protobuf:"bytes,3,opt,name=Rows,json=rows" json:"Rows,omitempty"`
+}
+
+func (m *WatchReply) Reset()                    { *m = WatchReply{} }
+func (m *WatchReply) String() string            { return proto.CompactTextString(m) }
+func (*WatchReply) ProtoMessage()               {}
+func (*WatchReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *WatchReply) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *WatchReply) GetTableContents() string {
+	if m != nil {
+		return m.TableContents
+	}
+	return ""
+}
+
+func (m *WatchReply) GetRows() *TableRows {
+	if m != nil {
+		return m.Rows
+	}
+	return nil
+}
+
+// The rows of a table.  Only the field corresponding to the table is set.
+type TableRows struct {
+	Machines    []*Machine    `protobuf:"bytes,1,rep,name=Machines,json=machines" json:"Machines,omitempty"`
+	Containers  []*Container  `protobuf:"bytes,2,rep,name=Containers,json=containers" json:"Containers,omitempty"`
+	Labels      []*Label      `protobuf:"bytes,3,rep,name=Labels,json=labels" json:"Labels,omitempty"`
+	Connections []*Connection `protobuf:"bytes,4,rep,name=Connections,json=connections" json:"Connections,omitempty"`
+	Etcds       []*Etcd       `protobuf:"bytes,5,rep,name=Etcds,json=etcds" json:"Etcds,omitempty"`
+	Clusters    []*Cluster    `protobuf:"bytes,6,rep,name=Clusters,json=clusters" json:"Clusters,omitempty"`
+	Placements  []*Placement  `protobuf:"bytes,7,rep,name=Placements,json=placements" json:"Placements,omitempty"`
+	ACLs        []*ACL        `protobuf:"bytes,8,rep,name=ACLs,json=aCLs" json:"ACLs,omitempty"`
+	Minions     []*Minion     `protobuf:"bytes,9,rep,name=Minions,json=minions" json:"Minions,omitempty"`
+	Events      []*Event      `protobuf:"bytes,10,rep,name=Events,json=events" json:"Events,omitempty"`
+	Statuses    []*Status     `protobuf:"bytes,11,rep,name=Statuses,json=statuses" json:"Statuses,omitempty"`
+}
+
+func (m *TableRows) Reset()                    { *m = TableRows{} }
+func (m *TableRows) String() string            { return proto.CompactTextString(m) }
+func (*TableRows) ProtoMessage()               {}
+func (*TableRows) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *TableRows) GetMachines() []*Machine {
+	if m != nil {
+		return m.Machines
+	}
+	return nil
+}
+
+func (m *TableRows) GetContainers() []*Container {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+func (m *TableRows) GetLabels() []*Label {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *TableRows) GetConnections() []*Connection {
+	if m != nil {
+		return m.Connections
+	}
+	return nil
+}
+
+func (m *TableRows) GetEtcds() []*Etcd {
+	if m != nil {
+		return m.Etcds
+	}
+	return nil
+}
+
+func (m *TableRows) GetClusters() []*Cluster {
+	if m != nil {
+		return m.Clusters
+	}
+	return nil
+}
+
+func (m *TableRows) GetPlacements() []*Placement {
+	if m != nil {
+		return m.Placements
+	}
+	return nil
+}
+
+func (m *TableRows) GetACLs() []*ACL {
+	if m != nil {
+		return m.ACLs
+	}
+	return nil
+}
+
+func (m *TableRows) GetMinions() []*Minion {
+	if m != nil {
+		return m.Minions
+	}
+	return nil
+}
+
+func (m *TableRows) GetEvents() []*Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *TableRows) GetStatuses() []*Status {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+type Machine struct {
+	ID            int32    `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	StitchID      string   `protobuf:"bytes,2,opt,name=StitchID,json=stitchID" json:"StitchID,omitempty"`
+	Role          string   `protobuf:"bytes,3,opt,name=Role,json=role" json:"Role,omitempty"`
+	Provider      string   `protobuf:"bytes,4,opt,name=Provider,json=provider" json:"Provider,omitempty"`
+	Region        string   `protobuf:"bytes,5,opt,name=Region,json=region" json:"Region,omitempty"`
+	Size          string   `protobuf:"bytes,6,opt,name=Size,json=size" json:"Size,omitempty"`
+	DiskSize      int32    `protobuf:"varint,7,opt,name=DiskSize,json=diskSize" json:"DiskSize,omitempty"`
+	SSHKeys       []string `protobuf:"bytes,8,rep,name=SSHKeys,json=sSHKeys" json:"SSHKeys,omitempty"`
+	FloatingIP    string   `protobuf:"bytes,9,opt,name=FloatingIP,json=floatingIP" json:"FloatingIP,omitempty"`
+	CloudID       string   `protobuf:"bytes,10,opt,name=CloudID,json=cloudID" json:"CloudID,omitempty"`
+	PublicIP      string   `protobuf:"bytes,11,opt,name=PublicIP,json=publicIP" json:"PublicIP,omitempty"`
+	PrivateIP     string   `protobuf:"bytes,12,opt,name=PrivateIP,json=privateIP" json:"PrivateIP,omitempty"`
+	Connected     bool     `protobuf:"varint,13,opt,name=Connected,json=connected" json:"Connected,omitempty"`
+	Namespace     string   `protobuf:"bytes,14,opt,name=Namespace,json=namespace" json:"Namespace,omitempty"`
+	MinionVersion string   `protobuf:"bytes,15,opt,name=MinionVersion,json=minionVersion" json:"MinionVersion,omitempty"`
+	Preemptible   bool     `protobuf:"varint,16,opt,name=Preemptible,json=preemptible" json:"Preemptible,omitempty"`
+	SpotPrice     float64  `protobuf:"fixed64,17,opt,name=SpotPrice,json=spotPrice" json:"SpotPrice,omitempty"`
+	// The Unix time, in nanoseconds, until which the machine's provider is backing
+	// off after failures, or 0 if it isn't.
+	BackoffUntil int64 `protobuf:"varint,18,opt,name=BackoffUntil,json=backoffUntil" json:"BackoffUntil,omitempty"`
+	Terminating  bool  `protobuf:"varint,19,opt,name=Terminating,json=terminating" json:"Terminating,omitempty"`
+}
+
+func (m *Machine) Reset()                    { *m = Machine{} }
+func (m *Machine) String() string            { return proto.CompactTextString(m) }
+func (*Machine) ProtoMessage()               {}
+func (*Machine) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *Machine) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Machine) GetStitchID() string {
+	if m != nil {
+		return m.StitchID
+	}
+	return ""
+}
+
+func (m *Machine) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *Machine) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *Machine) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *Machine) GetSize() string {
+	if m != nil {
+		return m.Size
+	}
+	return ""
+}
+
+func (m *Machine) GetDiskSize() int32 {
+	if m != nil {
+		return m.DiskSize
+	}
+	return 0
+}
+
+func (m *Machine) GetSSHKeys() []string {
+	if m != nil {
+		return m.SSHKeys
+	}
+	return nil
+}
+
+func (m *Machine) GetFloatingIP() string {
+	if m != nil {
+		return m.FloatingIP
+	}
+	return ""
+}
+
+func (m *Machine) GetCloudID() string {
+	if m != nil {
+		return m.CloudID
+	}
+	return ""
+}
+
+func (m *Machine) GetPublicIP() string {
+	if m != nil {
+		return m.PublicIP
+	}
+	return ""
+}
+
+func (m *Machine) GetPrivateIP() string {
+	if m != nil {
+		return m.PrivateIP
+	}
+	return ""
+}
+
+func (m *Machine) GetConnected() bool {
+	if m != nil {
+		return m.Connected
+	}
+	return false
+}
+
+func (m *Machine) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Machine) GetMinionVersion() string {
+	if m != nil {
+		return m.MinionVersion
+	}
+	return ""
+}
+
+func (m *Machine) GetPreemptible() bool {
+	if m != nil {
+		return m.Preemptible
+	}
+	return false
+}
+
+func (m *Machine) GetSpotPrice() float64 {
+	if m != nil {
+		return m.SpotPrice
+	}
+	return 0
+}
+
+func (m *Machine) GetBackoffUntil() int64 {
+	if m != nil {
+		return m.BackoffUntil
+	}
+	return 0
+}
+
+func (m *Machine) GetTerminating() bool {
+	if m != nil {
+		return m.Terminating
+	}
+	return false
+}
+
+type Container struct {
+	ID         int32             `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	IP         string            `protobuf:"bytes,2,opt,name=IP,json=iP" json:"IP,omitempty"`
+	Minion     string            `protobuf:"bytes,3,opt,name=Minion,json=minion" json:"Minion,omitempty"`
+	EndpointID string            `protobuf:"bytes,4,opt,name=EndpointID,json=endpointID" json:"EndpointID,omitempty"`
+	StitchID   string            `protobuf:"bytes,5,opt,name=StitchID,json=stitchID" json:"StitchID,omitempty"`
+	DockerID   string            `protobuf:"bytes,6,opt,name=DockerID,json=dockerID" json:"DockerID,omitempty"`
+	Image      string            `protobuf:"bytes,7,opt,name=Image,json=image" json:"Image,omitempty"`
+	Status     string            `protobuf:"bytes,8,opt,name=Status,json=status" json:"Status,omitempty"`
+	Command    []string          `protobuf:"bytes,9,rep,name=Command,json=command" json:"Command,omitempty"`
+	Labels     []string          `protobuf:"bytes,10,rep,name=Labels,json=labels" json:"Labels,omitempty"`
+	Env        map[string]string `protobuf:"bytes,11,rep,name=Env,json=env" json:"Env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The Unix time at which the container was created, in nanoseconds.
+	Created int64 `protobuf:"varint,12,opt,name=Created,json=created" json:"Created,omitempty"`
+}
+
+func (m *Container) Reset()                    { *m = Container{} }
+func (m *Container) String() string            { return proto.CompactTextString(m) }
+func (*Container) ProtoMessage()               {}
+func (*Container) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *Container) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Container) GetIP() string {
+	if m != nil {
+		return m.IP
+	}
+	return ""
+}
+
+func (m *Container) GetMinion() string {
+	if m != nil {
+		return m.Minion
+	}
+	return ""
+}
+
+func (m *Container) GetEndpointID() string {
+	if m != nil {
+		return m.EndpointID
+	}
+	return ""
+}
+
+func (m *Container) GetStitchID() string {
+	if m != nil {
+		return m.StitchID
+	}
+	return ""
+}
+
+func (m *Container) GetDockerID() string {
+	if m != nil {
+		return m.DockerID
+	}
+	return ""
+}
+
+func (m *Container) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *Container) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Container) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *Container) GetLabels() []string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Container) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *Container) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+type Label struct {
+	ID           int32    `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Label        string   `protobuf:"bytes,2,opt,name=Label,json=label" json:"Label,omitempty"`
+	IP           string   `protobuf:"bytes,3,opt,name=IP,json=iP" json:"IP,omitempty"`
+	ContainerIPs []string `protobuf:"bytes,4,rep,name=ContainerIPs,json=containerIPs" json:"ContainerIPs,omitempty"`
+}
+
+func (m *Label) Reset()                    { *m = Label{} }
+func (m *Label) String() string            { return proto.CompactTextString(m) }
+func (*Label) ProtoMessage()               {}
+func (*Label) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *Label) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Label) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *Label) GetIP() string {
+	if m != nil {
+		return m.IP
+	}
+	return ""
+}
+
+func (m *Label) GetContainerIPs() []string {
+	if m != nil {
+		return m.ContainerIPs
+	}
+	return nil
+}
+
+type Connection struct {
+	ID      int32  `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	From    string `protobuf:"bytes,2,opt,name=From,json=from" json:"From,omitempty"`
+	To      string `protobuf:"bytes,3,opt,name=To,json=to" json:"To,omitempty"`
+	MinPort int32  `protobuf:"varint,4,opt,name=MinPort,json=minPort" json:"MinPort,omitempty"`
+	MaxPort int32  `protobuf:"varint,5,opt,name=MaxPort,json=maxPort" json:"MaxPort,omitempty"`
+}
+
+func (m *Connection) Reset()                    { *m = Connection{} }
+func (m *Connection) String() string            { return proto.CompactTextString(m) }
+func (*Connection) ProtoMessage()               {}
+func (*Connection) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *Connection) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Connection) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Connection) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *Connection) GetMinPort() int32 {
+	if m != nil {
+		return m.MinPort
+	}
+	return 0
+}
+
+func (m *Connection) GetMaxPort() int32 {
+	if m != nil {
+		return m.MaxPort
+	}
+	return 0
+}
+
+type Etcd struct {
+	ID       int32    `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	EtcdIPs  []string `protobuf:"bytes,2,rep,name=EtcdIPs,json=etcdIPs" json:"EtcdIPs,omitempty"`
+	Leader   bool     `protobuf:"varint,3,opt,name=Leader,json=leader" json:"Leader,omitempty"`
+	LeaderIP string   `protobuf:"bytes,4,opt,name=LeaderIP,json=leaderIP" json:"LeaderIP,omitempty"`
+}
+
+func (m *Etcd) Reset()                    { *m = Etcd{} }
+func (m *Etcd) String() string            { return proto.CompactTextString(m) }
+func (*Etcd) ProtoMessage()               {}
+func (*Etcd) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *Etcd) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Etcd) GetEtcdIPs() []string {
+	if m != nil {
+		return m.EtcdIPs
+	}
+	return nil
+}
+
+func (m *Etcd) GetLeader() bool {
+	if m != nil {
+		return m.Leader
+	}
+	return false
+}
+
+func (m *Etcd) GetLeaderIP() string {
+	if m != nil {
+		return m.LeaderIP
+	}
+	return ""
+}
+
+type Cluster struct {
+	ID        int32  `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=Namespace,json=namespace" json:"Namespace,omitempty"`
+	Spec      string `protobuf:"bytes,3,opt,name=Spec,json=spec" json:"Spec,omitempty"`
+}
+
+func (m *Cluster) Reset()                    { *m = Cluster{} }
+func (m *Cluster) String() string            { return proto.CompactTextString(m) }
+func (*Cluster) ProtoMessage()               {}
+func (*Cluster) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *Cluster) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Cluster) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Cluster) GetSpec() string {
+	if m != nil {
+		return m.Spec
+	}
+	return ""
+}
+
+type Placement struct {
+	ID          int32  `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	TargetLabel string `protobuf:"bytes,2,opt,name=TargetLabel,json=targetLabel" json:"TargetLabel,omitempty"`
+	Exclusive   bool   `protobuf:"varint,3,opt,name=Exclusive,json=exclusive" json:"Exclusive,omitempty"`
+	OtherLabel  string `protobuf:"bytes,4,opt,name=OtherLabel,json=otherLabel" json:"OtherLabel,omitempty"`
+	Provider    string `protobuf:"bytes,5,opt,name=Provider,json=provider" json:"Provider,omitempty"`
+	Size        string `protobuf:"bytes,6,opt,name=Size,json=size" json:"Size,omitempty"`
+	Region      string `protobuf:"bytes,7,opt,name=Region,json=region" json:"Region,omitempty"`
+	FloatingIP  string `protobuf:"bytes,8,opt,name=FloatingIP,json=floatingIP" json:"FloatingIP,omitempty"`
+}
+
+func (m *Placement) Reset()                    { *m = Placement{} }
+func (m *Placement) String() string            { return proto.CompactTextString(m) }
+func (*Placement) ProtoMessage()               {}
+func (*Placement) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *Placement) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Placement) GetTargetLabel() string {
+	if m != nil {
+		return m.TargetLabel
+	}
+	return ""
+}
+
+func (m *Placement) GetExclusive() bool {
+	if m != nil {
+		return m.Exclusive
+	}
+	return false
+}
+
+func (m *Placement) GetOtherLabel() string {
+	if m != nil {
+		return m.OtherLabel
+	}
+	return ""
+}
+
+func (m *Placement) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *Placement) GetSize() string {
+	if m != nil {
+		return m.Size
+	}
+	return ""
+}
+
+func (m *Placement) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *Placement) GetFloatingIP() string {
+	if m != nil {
+		return m.FloatingIP
+	}
+	return ""
+}
+
+type ACL struct {
+	ID               int32            `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Admin            []string         `protobuf:"bytes,2,rep,name=Admin,json=admin" json:"Admin,omitempty"`
+	ApplicationPorts []*ACL_PortRange `protobuf:"bytes,3,rep,name=ApplicationPorts,json=applicationPorts" json:"ApplicationPorts,omitempty"`
+	Namespace        string           `protobuf:"bytes,4,opt,name=Namespace,json=namespace" json:"Namespace,omitempty"`
+}
+
+func (m *ACL) Reset()                    { *m = ACL{} }
+func (m *ACL) String() string            { return proto.CompactTextString(m) }
+func (*ACL) ProtoMessage()               {}
+func (*ACL) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *ACL) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *ACL) GetAdmin() []string {
+	if m != nil {
+		return m.Admin
+	}
+	return nil
+}
+
+func (m *ACL) GetApplicationPorts() []*ACL_PortRange {
+	if m != nil {
+		return m.ApplicationPorts
+	}
+	return nil
+}
+
+func (m *ACL) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type ACL_PortRange struct {
+	MinPort int32 `protobuf:"varint,1,opt,name=MinPort,json=minPort" json:"MinPort,omitempty"`
+	MaxPort int32 `protobuf:"varint,2,opt,name=MaxPort,json=maxPort" json:"MaxPort,omitempty"`
+}
+
+func (m *ACL_PortRange) Reset()                    { *m = ACL_PortRange{} }
+func (m *ACL_PortRange) String() string            { return proto.CompactTextString(m) }
+func (*ACL_PortRange) ProtoMessage()               {}
+func (*ACL_PortRange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25, 0} }
+
+func (m *ACL_PortRange) GetMinPort() int32 {
+	if m != nil {
+		return m.MinPort
+	}
+	return 0
+}
+
+func (m *ACL_PortRange) GetMaxPort() int32 {
+	if m != nil {
+		return m.MaxPort
+	}
+	return 0
+}
+
+type Minion struct {
+	ID          int32  `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Role        string `protobuf:"bytes,6,opt,name=Role,json=role" json:"Role,omitempty"`
+	PrivateIP   string `protobuf:"bytes,7,opt,name=PrivateIP,json=privateIP" json:"PrivateIP,omitempty"`
+	Provider    string `protobuf:"bytes,8,opt,name=Provider,json=provider" json:"Provider,omitempty"`
+	Size        string `protobuf:"bytes,9,opt,name=Size,json=size" json:"Size,omitempty"`
+	Region      string `protobuf:"bytes,10,opt,name=Region,json=region" json:"Region,omitempty"`
+	FloatingIP  string `protobuf:"bytes,11,opt,name=FloatingIP,json=floatingIP" json:"FloatingIP,omitempty"`
+	Terminating bool   `protobuf:"varint,12,opt,name=Terminating,json=terminating" json:"Terminating,omitempty"`
+}
+
+func (m *Minion) Reset()                    { *m = Minion{} }
+func (m *Minion) String() string            { return proto.CompactTextString(m) }
+func (*Minion) ProtoMessage()               {}
+func (*Minion) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *Minion) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Minion) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *Minion) GetPrivateIP() string {
+	if m != nil {
+		return m.PrivateIP
+	}
+	return ""
+}
+
+func (m *Minion) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *Minion) GetSize() string {
+	if m != nil {
+		return m.Size
+	}
+	return ""
+}
+
+func (m *Minion) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *Minion) GetFloatingIP() string {
+	if m != nil {
+		return m.FloatingIP
+	}
+	return ""
+}
+
+func (m *Minion) GetTerminating() bool {
+	if m != nil {
+		return m.Terminating
+	}
+	return false
+}
+
+type Deployment struct {
+	ID   int32  `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Spec string `protobuf:"bytes,2,opt,name=Spec,json=spec" json:"Spec,omitempty"`
+	Hash string `protobuf:"bytes,3,opt,name=Hash,json=hash" json:"Hash,omitempty"`
+	// The Unix time at which the stitch was deployed, in nanoseconds.
+	Time      int64  `protobuf:"varint,4,opt,name=Time,json=time" json:"Time,omitempty"`
+	Namespace string `protobuf:"bytes,5,opt,name=Namespace,json=namespace" json:"Namespace,omitempty"`
+}
+
+func (m *Deployment) Reset()                    { *m = Deployment{} }
+func (m *Deployment) String() string            { return proto.CompactTextString(m) }
+func (*Deployment) ProtoMessage()               {}
+func (*Deployment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *Deployment) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Deployment) GetSpec() string {
+	if m != nil {
+		return m.Spec
+	}
+	return ""
+}
+
+func (m *Deployment) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Deployment) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Deployment) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type Event struct {
+	ID int32 `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	// The Unix time at which the event happened, in nanoseconds.
+	Time      int64  `protobuf:"varint,2,opt,name=Time,json=time" json:"Time,omitempty"`
+	Type      string `protobuf:"bytes,3,opt,name=Type,json=type" json:"Type,omitempty"`
+	Namespace string `protobuf:"bytes,4,opt,name=Namespace,json=namespace" json:"Namespace,omitempty"`
+	Machine   string `protobuf:"bytes,5,opt,name=Machine,json=machine" json:"Machine,omitempty"`
+	Message   string `protobuf:"bytes,6,opt,name=Message,json=message" json:"Message,omitempty"`
+}
+
+func (m *Event) Reset()                    { *m = Event{} }
+func (m *Event) String() string            { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()               {}
+func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *Event) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Event) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Event) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Event) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Event) GetMachine() string {
+	if m != nil {
+		return m.Machine
+	}
+	return ""
+}
+
+func (m *Event) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type Status struct {
+	ID                      int32    `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Namespace               string   `protobuf:"bytes,2,opt,name=Namespace,json=namespace" json:"Namespace,omitempty"`
+	State                   string   `protobuf:"bytes,3,opt,name=State,json=state" json:"State,omitempty"`
+	Warnings                []string `protobuf:"bytes,4,rep,name=Warnings,json=warnings" json:"Warnings,omitempty"`
+	UnsatisfiableMachines   []string `protobuf:"bytes,5,rep,name=UnsatisfiableMachines,json=unsatisfiableMachines" json:"UnsatisfiableMachines,omitempty"`
+	MinionWarnings          []string `protobuf:"bytes,6,rep,name=MinionWarnings,json=minionWarnings" json:"MinionWarnings,omitempty"`
+	UnschedulableContainers []string `protobuf:"bytes,7,rep,name=UnschedulableContainers,json=unschedulableContainers" json:"UnschedulableContainers,omitempty"`
+}
+
+func (m *Status) Reset()                    { *m = Status{} }
+func (m *Status) String() string            { return proto.CompactTextString(m) }
+func (*Status) ProtoMessage()               {}
+func (*Status) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *Status) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Status) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Status) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Status) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+func (m *Status) GetUnsatisfiableMachines() []string {
+	if m != nil {
+		return m.UnsatisfiableMachines
+	}
+	return nil
+}
+
+func (m *Status) GetMinionWarnings() []string {
+	if m != nil {
+		return m.MinionWarnings
+	}
+	return nil
+}
+
+func (m *Status) GetUnschedulableContainers() []string {
+	if m != nil {
+		return m.UnschedulableContainers
+	}
+	return nil
+}
+
+// An API token, as listed by the ListTokens RPC.  Its hash is never sent.
+type Token struct {
+	ID   int32  `protobuf:"varint,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=Name,json=name" json:"Name,omitempty"`
+	Role string `protobuf:"bytes,3,opt,name=Role,json=role" json:"Role,omitempty"`
+	// The Unix time at which the token was created, in nanoseconds.
+	Created int64 `protobuf:"varint,4,opt,name=Created,json=created" json:"Created,omitempty"`
+}
+
+func (m *Token) Reset()                    { *m = Token{} }
+func (m *Token) String() string            { return proto.CompactTextString(m) }
+func (*Token) ProtoMessage()               {}
+func (*Token) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *Token) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Token) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Token) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *Token) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+// A Stitch is a compiled stitch, as deployed by the Deploy RPC.  Connections and
+// placements leave their ID unset.
+type Stitch struct {
+	Containers  []*Stitch_Container `protobuf:"bytes,1,rep,name=Containers,json=containers" json:"Containers,omitempty"`
+	Labels      []*Stitch_Label     `protobuf:"bytes,2,rep,name=Labels,json=labels" json:"Labels,omitempty"`
+	Connections []*Connection       `protobuf:"bytes,3,rep,name=Connections,json=connections" json:"Connections,omitempty"`
+	Placements  []*Placement        `protobuf:"bytes,4,rep,name=Placements,json=placements" json:"Placements,omitempty"`
+	Machines    []*Stitch_Machine   `protobuf:"bytes,5,rep,name=Machines,json=machines" json:"Machines,omitempty"`
+	AdminACL    []string            `protobuf:"bytes,6,rep,name=AdminACL,json=adminACL" json:"AdminACL,omitempty"`
+	MaxPrice    float64             `protobuf:"fixed64,7,opt,name=MaxPrice,json=maxPrice" json:"MaxPrice,omitempty"`
+	Namespace   string              `protobuf:"bytes,8,opt,name=Namespace,json=namespace" json:"Namespace,omitempty"`
+	Invariants  []*Stitch_Invariant `protobuf:"bytes,9,rep,name=Invariants,json=invariants" json:"Invariants,omitempty"`
+}
+
+func (m *Stitch) Reset()                    { *m = Stitch{} }
+func (m *Stitch) String() string            { return proto.CompactTextString(m) }
+func (*Stitch) ProtoMessage()               {}
+func (*Stitch) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *Stitch) GetContainers() []*Stitch_Container {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+func (m *Stitch) GetLabels() []*Stitch_Label {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Stitch) GetConnections() []*Connection {
+	if m != nil {
+		return m.Connections
+	}
+	return nil
+}
+
+func (m *Stitch) GetPlacements() []*Placement {
+	if m != nil {
+		return m.Placements
+	}
+	return nil
+}
+
+func (m *Stitch) GetMachines() []*Stitch_Machine {
+	if m != nil {
+		return m.Machines
+	}
+	return nil
+}
+
+func (m *Stitch) GetAdminACL() []string {
+	if m != nil {
+		return m.AdminACL
+	}
+	return nil
+}
+
+func (m *Stitch) GetMaxPrice() float64 {
+	if m != nil {
+		return m.MaxPrice
+	}
+	return 0
+}
+
+func (m *Stitch) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Stitch) GetInvariants() []*Stitch_Invariant {
+	if m != nil {
+		return m.Invariants
+	}
+	return nil
+}
+
+type Stitch_Container struct {
+	ID      string            `protobuf:"bytes,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Image   string            `protobuf:"bytes,2,opt,name=Image,json=image" json:"Image,omitempty"`
+	Command []string          `protobuf:"bytes,3,rep,name=Command,json=command" json:"Command,omitempty"`
+	Env     map[string]string `protobuf:"bytes,4,rep,name=Env,json=env" json:"Env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *Stitch_Container) Reset()                    { *m = Stitch_Container{} }
+func (m *Stitch_Container) String() string            { return proto.CompactTextString(m) }
+func (*Stitch_Container) ProtoMessage()               {}
+func (*Stitch_Container) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31, 0} }
+
+func (m *Stitch_Container) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *Stitch_Container) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *Stitch_Container) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *Stitch_Container) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+type Stitch_Label struct {
+	Name        string   `protobuf:"bytes,1,opt,name=Name,json=name" json:"Name,omitempty"`
+	IDs         []string `protobuf:"bytes,2,rep,name=IDs,json=iDs" json:"IDs,omitempty"`
+	Annotations []string `protobuf:"bytes,3,rep,name=Annotations,json=annotations" json:"Annotations,omitempty"`
+}
+
+func (m *Stitch_Label) Reset()                    { *m = Stitch_Label{} }
+func (m *Stitch_Label) String() string            { return proto.CompactTextString(m) }
+func (*Stitch_Label) ProtoMessage()               {}
+func (*Stitch_Label) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31, 1} }
+
+func (m *Stitch_Label) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Stitch_Label) GetIDs() []string {
+	if m != nil {
+		return m.IDs
+	}
+	return nil
+}
+
+func (m *Stitch_Label) GetAnnotations() []string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+type Stitch_Range struct {
+	Min float64 `protobuf:"fixed64,1,opt,name=Min,json=min" json:"Min,omitempty"`
+	Max float64 `protobuf:"fixed64,2,opt,name=Max,json=max" json:"Max,omitempty"`
+}
+
+func (m *Stitch_Range) Reset()                    { *m = Stitch_Range{} }
+func (m *Stitch_Range) String() string            { return proto.CompactTextString(m) }
+func (*Stitch_Range) ProtoMessage()               {}
+func (*Stitch_Range) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31, 2} }
+
+func (m *Stitch_Range) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *Stitch_Range) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+type Stitch_Machine struct {
+	ID          string        `protobuf:"bytes,1,opt,name=ID,json=iD" json:"ID,omitempty"`
+	Provider    string        `protobuf:"bytes,2,opt,name=Provider,json=provider" json:"Provider,omitempty"`
+	Role        string        `protobuf:"bytes,3,opt,name=Role,json=role" json:"Role,omitempty"`
+	Size        string        `protobuf:"bytes,4,opt,name=Size,json=size" json:"Size,omitempty"`
+	CPU         *Stitch_Range `protobuf:"bytes,5,opt,name=CPU,json=cPU" json:"CPU,omitempty"`
+	RAM         *Stitch_Range `protobuf:"bytes,6,opt,name=RAM,json=rAM" json:"RAM,omitempty"`
+	DiskSize    int32         `protobuf:"varint,7,opt,name=DiskSize,json=diskSize" json:"DiskSize,omitempty"`
+	Region      string        `protobuf:"bytes,8,opt,name=Region,json=region" json:"Region,omitempty"`
+	SSHKeys     []string      `protobuf:"bytes,9,rep,name=SSHKeys,json=sSHKeys" json:"SSHKeys,omitempty"`
+	FloatingIP  string        `protobuf:"bytes,10,opt,name=FloatingIP,json=floatingIP" json:"FloatingIP,omitempty"`
+	Preemptible bool          `protobuf:"varint,11,opt,name=Preemptible,json=preemptible" json:"Preemptible,omitempty"`
+	SpotPrice   float64       `protobuf:"fixed64,12,opt,name=SpotPrice,json=spotPrice" json:"SpotPrice,omitempty"`
+}
+
+func (m *Stitch_Machine) Reset()                    { *m = Stitch_Machine{} }
+func (m *Stitch_Machine) String() string            { return proto.CompactTextString(m) }
+func (*Stitch_Machine) ProtoMessage()               {}
+func (*Stitch_Machine) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31, 3} }
+
+func (m *Stitch_Machine) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *Stitch_Machine) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
 }
 
-func (m *QueryReply) Reset()                    { *m = QueryReply{} }
-func (m *QueryReply) String() string            { return proto.CompactTextString(m) }
-func (*QueryReply) ProtoMessage()               {}
-func (*QueryReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (m *Stitch_Machine) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
 
-func (m *QueryReply) GetTableContents() string {
+func (m *Stitch_Machine) GetSize() string {
 	if m != nil {
-		return m.TableContents
+		return m.Size
 	}
 	return ""
 }
 
-type DeployRequest struct {
-	Deployment string `protobuf:"bytes,1,opt,name=Deployment,json=deployment" json:"Deployment,omitempty"`
+func (m *Stitch_Machine) GetCPU() *Stitch_Range {
+	if m != nil {
+		return m.CPU
+	}
+	return nil
 }
 
-func (m *DeployRequest) Reset()                    { *m = DeployRequest{} }
-func (m *DeployRequest) String() string            { return proto.CompactTextString(m) }
-func (*DeployRequest) ProtoMessage()               {}
-func (*DeployRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (m *Stitch_Machine) GetRAM() *Stitch_Range {
+	if m != nil {
+		return m.RAM
+	}
+	return nil
+}
 
-func (m *DeployRequest) GetDeployment() string {
+func (m *Stitch_Machine) GetDiskSize() int32 {
 	if m != nil {
-		return m.Deployment
+		return m.DiskSize
+	}
+	return 0
+}
+
+func (m *Stitch_Machine) GetRegion() string {
+	if m != nil {
+		return m.Region
 	}
 	return ""
 }
 
-type DeployReply struct {
+func (m *Stitch_Machine) GetSSHKeys() []string {
+	if m != nil {
+		return m.SSHKeys
+	}
+	return nil
 }
 
-func (m *DeployReply) Reset()                    { *m = DeployReply{} }
-func (m *DeployReply) String() string            { return proto.CompactTextString(m) }
-func (*DeployReply) ProtoMessage()               {}
-func (*DeployReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (m *Stitch_Machine) GetFloatingIP() string {
+	if m != nil {
+		return m.FloatingIP
+	}
+	return ""
+}
+
+func (m *Stitch_Machine) GetPreemptible() bool {
+	if m != nil {
+		return m.Preemptible
+	}
+	return false
+}
+
+func (m *Stitch_Machine) GetSpotPrice() float64 {
+	if m != nil {
+		return m.SpotPrice
+	}
+	return 0
+}
+
+type Stitch_Invariant struct {
+	Form   string   `protobuf:"bytes,1,opt,name=Form,json=form" json:"Form,omitempty"`
+	Target bool     `protobuf:"varint,2,opt,name=Target,json=target" json:"Target,omitempty"`
+	Nodes  []string `protobuf:"bytes,3,rep,name=Nodes,json=nodes" json:"Nodes,omitempty"`
+}
+
+func (m *Stitch_Invariant) Reset()                    { *m = Stitch_Invariant{} }
+func (m *Stitch_Invariant) String() string            { return proto.CompactTextString(m) }
+func (*Stitch_Invariant) ProtoMessage()               {}
+func (*Stitch_Invariant) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31, 4} }
+
+func (m *Stitch_Invariant) GetForm() string {
+	if m != nil {
+		return m.Form
+	}
+	return ""
+}
+
+func (m *Stitch_Invariant) GetTarget() bool {
+	if m != nil {
+		return m.Target
+	}
+	return false
+}
+
+func (m *Stitch_Invariant) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type VersionRequest struct {
+}
+
+func (m *VersionRequest) Reset()                    { *m = VersionRequest{} }
+func (m *VersionRequest) String() string            { return proto.CompactTextString(m) }
+func (*VersionRequest) ProtoMessage()               {}
+func (*VersionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+type VersionReply struct {
+	Version       string `protobuf:"bytes,1,opt,name=Version,json=version" json:"Version,omitempty"`
+	SchemaVersion int32  `protobuf:"varint,2,opt,name=SchemaVersion,json=schemaVersion" json:"SchemaVersion,omitempty"`
+}
+
+func (m *VersionReply) Reset()                    { *m = VersionReply{} }
+func (m *VersionReply) String() string            { return proto.CompactTextString(m) }
+func (*VersionReply) ProtoMessage()               {}
+func (*VersionReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *VersionReply) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *VersionReply) GetSchemaVersion() int32 {
+	if m != nil {
+		return m.SchemaVersion
+	}
+	return 0
+}
 
 func init() {
 	proto.RegisterType((*DBQuery)(nil), "DBQuery")
 	proto.RegisterType((*QueryReply)(nil), "QueryReply")
 	proto.RegisterType((*DeployRequest)(nil), "DeployRequest")
 	proto.RegisterType((*DeployReply)(nil), "DeployReply")
+	proto.RegisterType((*ListDeploymentsRequest)(nil), "ListDeploymentsRequest")
+	proto.RegisterType((*ListDeploymentsReply)(nil), "ListDeploymentsReply")
+	proto.RegisterType((*RollbackRequest)(nil), "RollbackRequest")
+	proto.RegisterType((*CreateTokenRequest)(nil), "CreateTokenRequest")
+	proto.RegisterType((*CreateTokenReply)(nil), "CreateTokenReply")
+	proto.RegisterType((*ListTokensRequest)(nil), "ListTokensRequest")
+	proto.RegisterType((*ListTokensReply)(nil), "ListTokensReply")
+	proto.RegisterType((*RevokeTokenRequest)(nil), "RevokeTokenRequest")
+	proto.RegisterType((*RevokeTokenReply)(nil), "RevokeTokenReply")
+	proto.RegisterType((*PlanRequest)(nil), "PlanRequest")
+	proto.RegisterType((*PlanReply)(nil), "PlanReply")
+	proto.RegisterType((*WatchRequest)(nil), "WatchRequest")
+	proto.RegisterType((*WatchReply)(nil), "WatchReply")
+	proto.RegisterType((*TableRows)(nil), "TableRows")
+	proto.RegisterType((*Machine)(nil), "Machine")
+	proto.RegisterType((*Container)(nil), "Container")
+	proto.RegisterType((*Label)(nil), "Label")
+	proto.RegisterType((*Connection)(nil), "Connection")
+	proto.RegisterType((*Etcd)(nil), "Etcd")
+	proto.RegisterType((*Cluster)(nil), "Cluster")
+	proto.RegisterType((*Placement)(nil), "Placement")
+	proto.RegisterType((*ACL)(nil), "ACL")
+	proto.RegisterType((*ACL_PortRange)(nil), "ACL.PortRange")
+	proto.RegisterType((*Minion)(nil), "Minion")
+	proto.RegisterType((*Deployment)(nil), "Deployment")
+	proto.RegisterType((*Event)(nil), "Event")
+	proto.RegisterType((*Status)(nil), "Status")
+	proto.RegisterType((*Token)(nil), "Token")
+	proto.RegisterType((*Stitch)(nil), "Stitch")
+	proto.RegisterType((*Stitch_Container)(nil), "Stitch.Container")
+	proto.RegisterType((*Stitch_Label)(nil), "Stitch.Label")
+	proto.RegisterType((*Stitch_Range)(nil), "Stitch.Range")
+	proto.RegisterType((*Stitch_Machine)(nil), "Stitch.Machine")
+	proto.RegisterType((*Stitch_Invariant)(nil), "Stitch.Invariant")
+	proto.RegisterType((*VersionRequest)(nil), "VersionRequest")
+	proto.RegisterType((*VersionReply)(nil), "VersionReply")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -112,6 +1794,14 @@ const _ = grpc.SupportPackageIsVersion4
 type APIClient interface {
 	Query(ctx context.Context, in *DBQuery, opts ...grpc.CallOption) (*QueryReply, error)
 	Deploy(ctx context.Context, in *DeployRequest, opts ...grpc.CallOption) (*DeployReply, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (API_WatchClient, error)
+	Plan(ctx context.Context, in *PlanRequest, opts ...grpc.CallOption) (*PlanReply, error)
+	ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsReply, error)
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*DeployReply, error)
+	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionReply, error)
+	CreateToken(ctx context.Context, in *CreateTokenRequest, opts ...grpc.CallOption) (*CreateTokenReply, error)
+	ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensReply, error)
+	RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenReply, error)
 }
 
 type aPIClient struct {
@@ -140,11 +1830,114 @@ func (c *aPIClient) Deploy(ctx context.Context, in *DeployRequest, opts ...grpc.
 	return out, nil
 }
 
+func (c *aPIClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (API_WatchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[0], c.cc, "/API/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_WatchClient interface {
+	Recv() (*WatchReply, error)
+	grpc.ClientStream
+}
+
+type aPIWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIWatchClient) Recv() (*WatchReply, error) {
+	m := new(WatchReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aPIClient) Plan(ctx context.Context, in *PlanRequest, opts ...grpc.CallOption) (*PlanReply, error) {
+	out := new(PlanReply)
+	err := grpc.Invoke(ctx, "/API/Plan", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsReply, error) {
+	out := new(ListDeploymentsReply)
+	err := grpc.Invoke(ctx, "/API/ListDeployments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*DeployReply, error) {
+	out := new(DeployReply)
+	err := grpc.Invoke(ctx, "/API/Rollback", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionReply, error) {
+	out := new(VersionReply)
+	err := grpc.Invoke(ctx, "/API/Version", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) CreateToken(ctx context.Context, in *CreateTokenRequest, opts ...grpc.CallOption) (*CreateTokenReply, error) {
+	out := new(CreateTokenReply)
+	err := grpc.Invoke(ctx, "/API/CreateToken", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListTokens(ctx context.Context, in *ListTokensRequest, opts ...grpc.CallOption) (*ListTokensReply, error) {
+	out := new(ListTokensReply)
+	err := grpc.Invoke(ctx, "/API/ListTokens", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenReply, error) {
+	out := new(RevokeTokenReply)
+	err := grpc.Invoke(ctx, "/API/RevokeToken", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for API service
 
 type APIServer interface {
 	Query(context.Context, *DBQuery) (*QueryReply, error)
 	Deploy(context.Context, *DeployRequest) (*DeployReply, error)
+	Watch(*WatchRequest, API_WatchServer) error
+	Plan(context.Context, *PlanRequest) (*PlanReply, error)
+	ListDeployments(context.Context, *ListDeploymentsRequest) (*ListDeploymentsReply, error)
+	Rollback(context.Context, *RollbackRequest) (*DeployReply, error)
+	Version(context.Context, *VersionRequest) (*VersionReply, error)
+	CreateToken(context.Context, *CreateTokenRequest) (*CreateTokenReply, error)
+	ListTokens(context.Context, *ListTokensRequest) (*ListTokensReply, error)
+	RevokeToken(context.Context, *RevokeTokenRequest) (*RevokeTokenReply, error)
 }
 
 func RegisterAPIServer(s *grpc.Server, srv APIServer) {
@@ -187,6 +1980,153 @@ func _API_Deploy_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).Watch(m, &aPIWatchServer{stream})
+}
+
+type API_WatchServer interface {
+	Send(*WatchReply) error
+	grpc.ServerStream
+}
+
+type aPIWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPIWatchServer) Send(m *WatchReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _API_Plan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).Plan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/Plan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Plan(ctx, req.(*PlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_ListDeployments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeploymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ListDeployments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/ListDeployments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ListDeployments(ctx, req.(*ListDeploymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/Rollback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Rollback(ctx, req.(*RollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Version(ctx, req.(*VersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_CreateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).CreateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/CreateToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).CreateToken(ctx, req.(*CreateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_ListTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ListTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/ListTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ListTokens(ctx, req.(*ListTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_RevokeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).RevokeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/RevokeToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).RevokeToken(ctx, req.(*RevokeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _API_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "API",
 	HandlerType: (*APIServer)(nil),
@@ -199,25 +2139,191 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Deploy",
 			Handler:    _API_Deploy_Handler,
 		},
+		{
+			MethodName: "Plan",
+			Handler:    _API_Plan_Handler,
+		},
+		{
+			MethodName: "ListDeployments",
+			Handler:    _API_ListDeployments_Handler,
+		},
+		{
+			MethodName: "Rollback",
+			Handler:    _API_Rollback_Handler,
+		},
+		{
+			MethodName: "Version",
+			Handler:    _API_Version_Handler,
+		},
+		{
+			MethodName: "CreateToken",
+			Handler:    _API_CreateToken_Handler,
+		},
+		{
+			MethodName: "ListTokens",
+			Handler:    _API_ListTokens_Handler,
+		},
+		{
+			MethodName: "RevokeToken",
+			Handler:    _API_RevokeToken_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _API_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pb/pb.proto",
 }
 
 func init() { proto.RegisterFile("pb/pb.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 189 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xe2, 0x2e, 0x48, 0xd2, 0x2f,
-	0x48, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x57, 0x92, 0xe7, 0x62, 0x77, 0x71, 0x0a, 0x2c, 0x4d,
-	0x2d, 0xaa, 0x14, 0x12, 0xe1, 0x62, 0x0d, 0x49, 0x4c, 0xca, 0x49, 0x95, 0x60, 0x54, 0x60, 0xd4,
-	0xe0, 0x0c, 0x62, 0x2d, 0x01, 0x71, 0x94, 0x8c, 0xb8, 0xb8, 0xc0, 0xd2, 0x41, 0xa9, 0x05, 0x39,
-	0x95, 0x42, 0x2a, 0x5c, 0xbc, 0x60, 0x35, 0xce, 0xf9, 0x79, 0x25, 0xa9, 0x79, 0x25, 0xc5, 0x50,
-	0xb5, 0xbc, 0x25, 0xc8, 0x82, 0x4a, 0xfa, 0x5c, 0xbc, 0x2e, 0xa9, 0x05, 0x39, 0xf9, 0x95, 0x41,
-	0xa9, 0x85, 0xa5, 0xa9, 0xc5, 0x25, 0x42, 0x72, 0x5c, 0x5c, 0x10, 0x81, 0xdc, 0xd4, 0xbc, 0x12,
-	0xa8, 0x1e, 0xae, 0x14, 0xb8, 0x88, 0x12, 0x2f, 0x17, 0x37, 0x4c, 0x43, 0x41, 0x4e, 0xa5, 0x51,
-	0x20, 0x17, 0xb3, 0x63, 0x80, 0xa7, 0x90, 0x02, 0x17, 0x2b, 0xc4, 0x65, 0x1c, 0x7a, 0x50, 0x37,
-	0x4a, 0x71, 0xeb, 0x21, 0x1c, 0xa3, 0xc4, 0x20, 0xa4, 0xc1, 0xc5, 0x06, 0xd1, 0x27, 0xc4, 0xa7,
-	0x87, 0x62, 0xa3, 0x14, 0x8f, 0x1e, 0x92, 0x81, 0x4a, 0x0c, 0x49, 0x6c, 0x60, 0xef, 0x1a, 0x03,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0x52, 0x06, 0x87, 0x98, 0xfd, 0x00, 0x00, 0x00,
+	// 2299 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0xdf, 0x6e, 0xdb, 0xc8,
+	0xf5, 0xb6, 0x44, 0x52, 0x22, 0x0f, 0x65, 0x59, 0x1e, 0x67, 0xb3, 0x84, 0x7e, 0x3f, 0x64, 0xbd,
+	0x6c, 0x76, 0x6b, 0x34, 0x29, 0xdb, 0x38, 0xc1, 0x36, 0x5d, 0x14, 0x68, 0x15, 0xdb, 0xc1, 0xaa,
+	0x1b, 0x27, 0xea, 0x38, 0xe9, 0xa2, 0x97, 0x63, 0x6a, 0x6c, 0x13, 0xe6, 0xbf, 0x92, 0x94, 0x12,
+	0xe7, 0xaa, 0x57, 0xbd, 0xef, 0x03, 0xf4, 0xaa, 0x4f, 0xd0, 0x77, 0x28, 0x7a, 0xd7, 0xa7, 0x28,
+	0xd0, 0x27, 0xe8, 0x03, 0x14, 0x67, 0x66, 0x48, 0x91, 0xa2, 0xec, 0x45, 0x7b, 0x25, 0x9d, 0x6f,
+	0xce, 0xfc, 0xe1, 0x9c, 0x6f, 0xbe, 0x39, 0x73, 0xc0, 0x4e, 0xcf, 0x7f, 0x92, 0x9e, 0x7b, 0x69,
+	0x96, 0x14, 0x89, 0xfb, 0x14, 0xfa, 0xc7, 0x2f, 0x7e, 0xb3, 0xe0, 0xd9, 0x0d, 0xb9, 0x07, 0xc6,
+	0x5b, 0x76, 0x1e, 0x72, 0xa7, 0xb3, 0xdf, 0x39, 0xb0, 0xa8, 0x51, 0xa0, 0x41, 0x08, 0xe8, 0x93,
+	0xfc, 0xcd, 0x85, 0xd3, 0xdd, 0xef, 0x1c, 0x68, 0x54, 0x67, 0xf9, 0x9b, 0x0b, 0x97, 0x02, 0x88,
+	0x2e, 0x94, 0xa7, 0xe1, 0x0d, 0x79, 0x08, 0xdb, 0xa2, 0xdf, 0x51, 0x12, 0x17, 0x3c, 0x2e, 0x72,
+	0xd5, 0x7f, 0xbb, 0xa8, 0x83, 0xe4, 0x01, 0xe8, 0x34, 0x79, 0x9f, 0x8b, 0x71, 0xec, 0x43, 0xf0,
+	0x44, 0x17, 0x44, 0xa8, 0x9e, 0x25, 0xef, 0x73, 0x77, 0x06, 0xdb, 0xc7, 0x3c, 0x0d, 0x93, 0x1b,
+	0xca, 0x7f, 0xbf, 0xe0, 0x79, 0x41, 0x1e, 0x00, 0x48, 0x20, 0xe2, 0x71, 0xa1, 0xc6, 0x84, 0x79,
+	0x85, 0x90, 0xcf, 0xa0, 0x77, 0x56, 0x04, 0x85, 0x7f, 0xa5, 0x86, 0xec, 0x7b, 0xd2, 0xa4, 0xbd,
+	0x5c, 0xfc, 0xba, 0xdb, 0x60, 0x97, 0x23, 0xa6, 0xe1, 0x8d, 0xeb, 0xc0, 0xfd, 0x57, 0x41, 0x5e,
+	0xac, 0xc6, 0xcc, 0xd5, 0x4c, 0xee, 0x09, 0xdc, 0x6b, 0xb5, 0xe0, 0x87, 0xfd, 0x18, 0xec, 0x1a,
+	0xe6, 0x74, 0xf6, 0xb5, 0x03, 0xfb, 0xd0, 0xf6, 0x56, 0x18, 0xb5, 0x57, 0xeb, 0xc9, 0xdd, 0xcf,
+	0x61, 0x87, 0x26, 0x61, 0x78, 0xce, 0xfc, 0xeb, 0xf2, 0x1b, 0x86, 0xd0, 0x9d, 0x1e, 0x8b, 0xb5,
+	0x1b, 0xb4, 0x1b, 0x1c, 0xbb, 0xbf, 0x00, 0x72, 0x94, 0x71, 0x56, 0xf0, 0xb7, 0xc9, 0x35, 0x8f,
+	0x4b, 0x2f, 0x02, 0xfa, 0x6b, 0x16, 0x95, 0xfb, 0xae, 0xc7, 0x2c, 0x12, 0xdb, 0x4e, 0x93, 0x90,
+	0x8b, 0x6f, 0xb3, 0x70, 0x8b, 0x42, 0xee, 0x3e, 0x87, 0x51, 0xa3, 0x77, 0x1a, 0xca, 0xa0, 0xa1,
+	0x55, 0x05, 0x0d, 0x0d, 0x35, 0x6f, 0xb7, 0x9a, 0x77, 0x0f, 0x76, 0xf1, 0x0b, 0x85, 0x67, 0xf5,
+	0xd9, 0x4f, 0x60, 0xa7, 0x0e, 0xe2, 0x68, 0x0f, 0xa0, 0x27, 0x4d, 0xf5, 0xb1, 0x3d, 0x4f, 0x4e,
+	0xd5, 0x13, 0xc3, 0xe6, 0xee, 0x43, 0x20, 0x94, 0x2f, 0x93, 0xeb, 0xe6, 0xfa, 0xd7, 0xbf, 0x92,
+	0xc0, 0xa8, 0xe1, 0x85, 0xbb, 0xef, 0x81, 0x3d, 0x0b, 0x59, 0xd5, 0x65, 0x15, 0xbc, 0xce, 0xe6,
+	0xe0, 0xfd, 0x45, 0x07, 0x4b, 0x76, 0xc0, 0x75, 0x3d, 0x86, 0xc1, 0x8b, 0x24, 0x29, 0x4e, 0x99,
+	0x7f, 0x15, 0xc4, 0xbc, 0x5c, 0x9d, 0xe9, 0x29, 0x80, 0x0e, 0xce, 0x6b, 0xad, 0xe4, 0x2b, 0xd8,
+	0x7d, 0xcb, 0xb3, 0x28, 0x88, 0x59, 0xc1, 0xab, 0x2e, 0xdd, 0xb5, 0x2e, 0xbb, 0xc5, 0xba, 0x0b,
+	0x79, 0x06, 0x3b, 0x67, 0x05, 0xcb, 0x0a, 0xe4, 0x2c, 0x0b, 0x62, 0x9e, 0xe5, 0x8e, 0x26, 0x7a,
+	0x81, 0x57, 0x41, 0x74, 0x27, 0x6f, 0xba, 0x90, 0x43, 0x18, 0x9e, 0x15, 0x49, 0x5a, 0xeb, 0xa4,
+	0xb7, 0x3a, 0x0d, 0xf3, 0x86, 0x07, 0x79, 0x0a, 0xc3, 0xc9, 0x7c, 0x7e, 0x94, 0xc4, 0x31, 0xf7,
+	0x8b, 0x20, 0x89, 0x73, 0xc7, 0x50, 0xe4, 0x5a, 0x61, 0x74, 0xc8, 0x1a, 0x2e, 0xe4, 0xe7, 0xb0,
+	0x4b, 0x79, 0x94, 0x2c, 0x79, 0xbd, 0x5f, 0xaf, 0xdd, 0x6f, 0x37, 0x5b, 0xf7, 0x22, 0x2e, 0x0c,
+	0x26, 0xf3, 0xf9, 0x64, 0x1e, 0x05, 0xf1, 0xe4, 0xe8, 0x55, 0xee, 0xf4, 0xf7, 0xb5, 0x03, 0x8b,
+	0x0e, 0x58, 0x0d, 0x23, 0x07, 0xb0, 0x23, 0x87, 0x5f, 0xb9, 0x99, 0xc2, 0x6d, 0x27, 0x6b, 0xc2,
+	0xe4, 0x57, 0xb0, 0x87, 0xa3, 0xa5, 0x69, 0x18, 0xf8, 0x0c, 0x27, 0x98, 0x25, 0x59, 0x91, 0x3b,
+	0x96, 0x58, 0xca, 0xd0, 0x9b, 0x1c, 0xbd, 0xf2, 0x10, 0xa1, 0x2c, 0xbe, 0xe4, 0x74, 0x8f, 0xb5,
+	0x5d, 0xc9, 0x4b, 0xb8, 0xaf, 0xe6, 0x5a, 0x1f, 0x04, 0x36, 0x0e, 0x72, 0x3f, 0xdb, 0xe8, 0xed,
+	0x7e, 0x09, 0x83, 0xef, 0x18, 0xd2, 0x46, 0xd1, 0xea, 0x3e, 0xf4, 0x84, 0xae, 0x48, 0x86, 0x58,
+	0xb4, 0x27, 0x34, 0x28, 0x77, 0xaf, 0x00, 0x94, 0x5f, 0x79, 0x66, 0xda, 0x42, 0xd7, 0x92, 0xb1,
+	0xee, 0x5d, 0x32, 0xa6, 0xdd, 0x22, 0x63, 0x7f, 0xd6, 0xc0, 0xaa, 0x30, 0xf2, 0x10, 0xcc, 0x5b,
+	0x39, 0x6b, 0x46, 0xaa, 0x85, 0xfc, 0x08, 0xa0, 0xc6, 0x9e, 0x6e, 0x8b, 0x3d, 0xe0, 0x57, 0xad,
+	0x78, 0x42, 0x5f, 0xb1, 0x73, 0x1e, 0x96, 0xd4, 0xec, 0x79, 0xc2, 0xa4, 0xbd, 0x50, 0xa0, 0xa8,
+	0x59, 0x75, 0x7a, 0xe8, 0x6d, 0x7a, 0xd8, 0xfe, 0xaa, 0x9d, 0xfc, 0x1f, 0x18, 0x27, 0x85, 0x3f,
+	0x2f, 0xf9, 0x67, 0x78, 0x68, 0x51, 0x83, 0x23, 0x86, 0xab, 0x3f, 0x0a, 0x17, 0x79, 0xc1, 0xb3,
+	0x92, 0x67, 0xa6, 0xa7, 0x00, 0x6a, 0xfa, 0xaa, 0x05, 0x57, 0x3f, 0x0b, 0x99, 0xcf, 0xa5, 0x48,
+	0xf6, 0xd5, 0xea, 0x2b, 0x88, 0x42, 0x5a, 0xb5, 0x12, 0x07, 0xf4, 0x8a, 0x58, 0xf6, 0xa1, 0x8e,
+	0x51, 0xa6, 0x3a, 0x43, 0x4e, 0x7d, 0x0e, 0xfd, 0xd3, 0x20, 0x16, 0x6b, 0x96, 0x3c, 0xea, 0x7b,
+	0xd2, 0xa6, 0xfd, 0x48, 0xe2, 0xf8, 0xe9, 0x27, 0x4b, 0x1e, 0x57, 0x24, 0xe9, 0x79, 0xc2, 0xa4,
+	0x3d, 0x2e, 0x50, 0xf2, 0x03, 0x30, 0xcf, 0x0a, 0x56, 0x2c, 0x72, 0x9e, 0x3b, 0xb6, 0x1a, 0x43,
+	0x02, 0xd4, 0xcc, 0x55, 0x03, 0xea, 0x4a, 0x5f, 0x45, 0x60, 0x5d, 0xb7, 0xc8, 0x18, 0x07, 0x40,
+	0xf5, 0x51, 0xda, 0x69, 0x61, 0x3f, 0x69, 0x57, 0x7a, 0xac, 0xad, 0xf4, 0x18, 0xfd, 0x67, 0x59,
+	0xb2, 0x0c, 0xe6, 0x3c, 0x73, 0x74, 0xe9, 0x9f, 0x2a, 0x1b, 0x99, 0x48, 0xf9, 0x65, 0x90, 0xc4,
+	0x8e, 0x21, 0x5a, 0x7a, 0x99, 0xb0, 0x70, 0x9c, 0xb3, 0xe0, 0x23, 0x77, 0x7a, 0x72, 0x9c, 0x3c,
+	0xf8, 0x28, 0xc6, 0x39, 0x0e, 0xf2, 0x6b, 0x81, 0xf7, 0xc5, 0x6a, 0xcc, 0xb9, 0xb2, 0x89, 0x03,
+	0xfd, 0xb3, 0xb3, 0x6f, 0xbe, 0xe5, 0x37, 0xe5, 0x69, 0xec, 0xe7, 0xd2, 0xc4, 0xfb, 0xf1, 0x65,
+	0x98, 0xb0, 0x22, 0x88, 0x2f, 0xa7, 0x33, 0xc7, 0x92, 0xf7, 0xe3, 0x45, 0x85, 0x60, 0xcf, 0xa3,
+	0x30, 0x59, 0xcc, 0xa7, 0xc7, 0x0e, 0x88, 0xc6, 0xbe, 0x2f, 0x4d, 0xb1, 0xee, 0xc5, 0x79, 0x18,
+	0xf8, 0xd3, 0x99, 0x63, 0xab, 0x75, 0x2b, 0x9b, 0xfc, 0x3f, 0x58, 0xb3, 0x2c, 0x58, 0xb2, 0x82,
+	0x4f, 0x67, 0xce, 0x40, 0x34, 0x5a, 0x69, 0x09, 0x60, 0xab, 0x62, 0x12, 0x9f, 0x3b, 0xdb, 0xfb,
+	0x9d, 0x03, 0x93, 0x5a, 0x7e, 0x09, 0x60, 0x2b, 0xde, 0x63, 0x79, 0xca, 0x7c, 0xee, 0x0c, 0x65,
+	0xdf, 0xb8, 0x04, 0xf0, 0x7c, 0xc9, 0x88, 0xfe, 0x96, 0x67, 0x39, 0x6e, 0xcc, 0x8e, 0x3c, 0x5f,
+	0x51, 0x1d, 0x24, 0xfb, 0x60, 0xcf, 0x32, 0xce, 0xa3, 0xb4, 0x08, 0xf0, 0x84, 0x8e, 0xc4, 0x1c,
+	0x76, 0xba, 0x82, 0x70, 0x96, 0xb3, 0x34, 0x29, 0x66, 0x59, 0xe0, 0x73, 0x67, 0x77, 0xbf, 0x73,
+	0xd0, 0xa1, 0x56, 0x5e, 0x02, 0xa8, 0x74, 0x2f, 0x98, 0x7f, 0x9d, 0x5c, 0x5c, 0xbc, 0x8b, 0x8b,
+	0x20, 0x74, 0x88, 0x48, 0x5b, 0x06, 0xe7, 0x35, 0x0c, 0xe7, 0x28, 0xef, 0x87, 0x20, 0xbe, 0x74,
+	0xf6, 0xe4, 0x1c, 0xc5, 0x0a, 0x72, 0xff, 0xdd, 0x15, 0x1f, 0x2a, 0x0f, 0x5d, 0x8b, 0x27, 0x68,
+	0xcf, 0x14, 0x43, 0xba, 0xc1, 0x0c, 0x63, 0x2d, 0xbf, 0x4c, 0xb1, 0xa3, 0x27, 0x3f, 0x09, 0x23,
+	0x74, 0x12, 0xcf, 0xd3, 0x24, 0x88, 0x8b, 0xe9, 0xb1, 0x62, 0x08, 0xf0, 0x0a, 0x69, 0xf0, 0xcd,
+	0x58, 0xe3, 0x1b, 0x72, 0x22, 0xf1, 0xaf, 0x79, 0x36, 0x3d, 0x56, 0x5c, 0x31, 0xe7, 0xca, 0x46,
+	0xfd, 0x9a, 0x46, 0xec, 0x52, 0x92, 0xc5, 0xa2, 0x46, 0x80, 0x06, 0xae, 0x42, 0xb2, 0xdd, 0x31,
+	0xe5, 0x2a, 0x24, 0xe7, 0x05, 0x0f, 0x92, 0x28, 0x62, 0xf1, 0x5c, 0x9c, 0x2c, 0xe4, 0x81, 0x34,
+	0xb1, 0x87, 0xd2, 0x12, 0x90, 0x6a, 0xa9, 0x34, 0xe4, 0x0b, 0xd0, 0x4e, 0xe2, 0xa5, 0x3a, 0x43,
+	0x7b, 0x2b, 0x21, 0xf2, 0x4e, 0xe2, 0xe5, 0x49, 0x5c, 0x64, 0x37, 0x54, 0xe3, 0xf1, 0x52, 0x0c,
+	0x2c, 0xd2, 0x91, 0xb9, 0x20, 0x8a, 0x46, 0xfb, 0xbe, 0x34, 0xc7, 0x5f, 0x81, 0x59, 0xba, 0x92,
+	0x11, 0x68, 0xd7, 0xfc, 0x46, 0x49, 0x2d, 0xfe, 0xc5, 0xe5, 0x2f, 0x59, 0xb8, 0x28, 0x73, 0x1b,
+	0x69, 0x7c, 0xdd, 0x7d, 0xde, 0x71, 0x19, 0x18, 0x62, 0x41, 0xad, 0x1d, 0xbf, 0xa7, 0x1a, 0xca,
+	0x2e, 0x61, 0xe5, 0x35, 0x73, 0xb4, 0x2a, 0x0e, 0x2e, 0x0c, 0xaa, 0xb5, 0x4e, 0x67, 0x52, 0xfc,
+	0x2c, 0x3a, 0xf0, 0x6b, 0x98, 0x5b, 0x08, 0xad, 0x55, 0xfa, 0xd7, 0x9a, 0x87, 0x80, 0xfe, 0x32,
+	0x4b, 0xa2, 0x32, 0xeb, 0xba, 0xc8, 0x92, 0x08, 0x7d, 0xde, 0x26, 0xe5, 0x2c, 0x45, 0x82, 0x9f,
+	0x7d, 0x1a, 0x88, 0xfb, 0x47, 0x84, 0xd4, 0x10, 0x02, 0x85, 0xa6, 0x68, 0x61, 0x1f, 0x44, 0x8b,
+	0xa1, 0x5a, 0xa4, 0xe9, 0xce, 0x41, 0x47, 0x61, 0x6d, 0xcd, 0xe7, 0x40, 0x1f, 0xf1, 0xe9, 0x4c,
+	0xca, 0xbe, 0x45, 0xfb, 0x5c, 0x9a, 0x22, 0x36, 0x9c, 0xa1, 0xb2, 0x68, 0x82, 0x9e, 0xbd, 0x50,
+	0x58, 0xc8, 0x0b, 0x89, 0x4f, 0x67, 0xa5, 0xe6, 0x84, 0xca, 0x76, 0xbf, 0xc5, 0x13, 0x2f, 0x54,
+	0xb9, 0x35, 0x51, 0xe3, 0x68, 0x76, 0xd7, 0x8f, 0x26, 0x8a, 0x52, 0xca, 0xfd, 0x52, 0xdc, 0xf2,
+	0x94, 0xfb, 0xee, 0x3f, 0x3b, 0x22, 0x01, 0x93, 0xca, 0xdd, 0x1a, 0x0f, 0x8f, 0x10, 0xcb, 0x2e,
+	0x79, 0x51, 0x0f, 0x8b, 0x5d, 0xac, 0x20, 0x9c, 0xf1, 0xe4, 0x03, 0x5e, 0x12, 0xc1, 0x92, 0xab,
+	0x6f, 0xb0, 0x78, 0x09, 0xe0, 0xd1, 0x78, 0x53, 0x5c, 0xf1, 0x4c, 0x76, 0x57, 0x47, 0x23, 0xa9,
+	0x90, 0x86, 0xb4, 0x1a, 0x6b, 0xd2, 0xba, 0x49, 0x42, 0x57, 0x72, 0xdb, 0x6f, 0xc8, 0x6d, 0x53,
+	0x24, 0xcd, 0x75, 0x91, 0x74, 0xff, 0xde, 0x01, 0x6d, 0x72, 0xf4, 0x6a, 0x13, 0xe1, 0x44, 0xbe,
+	0xa3, 0xc2, 0x62, 0x30, 0x34, 0xc8, 0xd7, 0x30, 0x6a, 0x25, 0x2c, 0xda, 0xc6, 0x84, 0x65, 0xc4,
+	0xd6, 0xfc, 0x9a, 0x11, 0xd0, 0xd7, 0x22, 0x30, 0xfe, 0x25, 0x58, 0x55, 0xe7, 0x3a, 0xc3, 0x3a,
+	0xb7, 0x32, 0xac, 0xdb, 0x64, 0xd8, 0x1f, 0xbb, 0xa5, 0x08, 0x6d, 0x22, 0xb5, 0xb8, 0xba, 0x7a,
+	0xb5, 0xab, 0xab, 0x21, 0xf3, 0xfd, 0x75, 0x99, 0xaf, 0xef, 0xbe, 0x79, 0xcb, 0xee, 0x5b, 0x1b,
+	0x77, 0x1f, 0xee, 0xd8, 0x7d, 0xbb, 0x75, 0x45, 0xad, 0x09, 0xf1, 0xa0, 0x25, 0xc4, 0xbf, 0xd6,
+	0xcd, 0xee, 0xa8, 0x47, 0xf5, 0x33, 0x1e, 0x5e, 0x50, 0xc1, 0x51, 0x3a, 0x9c, 0x2c, 0x8a, 0xab,
+	0x24, 0x0b, 0x3e, 0xf2, 0x39, 0x5e, 0x83, 0x74, 0x78, 0xb6, 0x48, 0x79, 0xb6, 0x0c, 0xf2, 0x24,
+	0x9b, 0xc6, 0x41, 0xe1, 0x2e, 0xeb, 0xcf, 0xc6, 0x4d, 0x7b, 0x21, 0x98, 0xde, 0x5d, 0x31, 0x1d,
+	0xb1, 0x6f, 0x58, 0x7e, 0x55, 0xb2, 0xff, 0x8a, 0xe5, 0x57, 0x88, 0xbd, 0x0d, 0x22, 0x19, 0x28,
+	0x8d, 0xea, 0x45, 0x10, 0xf1, 0x66, 0x04, 0x8d, 0xb5, 0x08, 0xba, 0x7f, 0xea, 0x80, 0x71, 0xb2,
+	0xbc, 0x65, 0x4e, 0x31, 0x56, 0xb7, 0x36, 0x16, 0x62, 0x37, 0x69, 0x95, 0x4e, 0x14, 0x37, 0x29,
+	0xbf, 0x9b, 0x21, 0xc4, 0xa9, 0xf2, 0x16, 0x35, 0x77, 0x5f, 0xe5, 0x8f, 0xa2, 0x85, 0xe7, 0x39,
+	0xbb, 0x2c, 0x43, 0xdc, 0x8f, 0xa4, 0xe9, 0xfe, 0xa1, 0x5b, 0xde, 0x09, 0xff, 0xa5, 0x20, 0xdc,
+	0x03, 0x03, 0xfb, 0x95, 0xeb, 0x33, 0xf0, 0x2a, 0x11, 0x79, 0xca, 0x77, 0x2c, 0x8b, 0x83, 0xf8,
+	0xb2, 0xd4, 0x56, 0xf3, 0xbd, 0xb2, 0xc9, 0x33, 0xf8, 0xe4, 0x5d, 0x9c, 0xb3, 0x22, 0xc8, 0x2f,
+	0x02, 0x4c, 0x7f, 0xab, 0xb4, 0xd7, 0x10, 0x8e, 0x9f, 0x2c, 0x36, 0x35, 0x92, 0x2f, 0x61, 0x28,
+	0x49, 0x5b, 0x8d, 0xdb, 0x13, 0xee, 0xc3, 0xa8, 0x81, 0x92, 0xe7, 0xf0, 0xe9, 0xbb, 0x38, 0xf7,
+	0xaf, 0xf8, 0x7c, 0x11, 0x96, 0xe9, 0xb8, 0x4a, 0x97, 0xe5, 0x53, 0xe6, 0xd3, 0xc5, 0xe6, 0x66,
+	0xf7, 0x77, 0xea, 0x7d, 0xbc, 0x29, 0x2a, 0xe2, 0xd1, 0xdd, 0xdd, 0xf0, 0xe8, 0xae, 0x27, 0x79,
+	0xb5, 0x5b, 0x4e, 0x6f, 0xdc, 0x72, 0xee, 0xdf, 0xcc, 0xf2, 0x11, 0x4b, 0x9e, 0x34, 0x32, 0x78,
+	0x99, 0xe9, 0xef, 0xaa, 0x27, 0xed, 0x2d, 0x89, 0xfc, 0x17, 0xd5, 0xe5, 0x2b, 0x13, 0xfe, 0xed,
+	0xd2, 0xfd, 0xce, 0x7c, 0x5e, 0xfb, 0x9e, 0x7c, 0xbe, 0x99, 0x8c, 0xeb, 0x77, 0x26, 0xe3, 0x8f,
+	0xc0, 0x6c, 0x44, 0xc9, 0x3e, 0xdc, 0x29, 0xd7, 0xd0, 0x7e, 0xa3, 0x8c, 0xc1, 0x2c, 0x1f, 0x80,
+	0x2a, 0x46, 0x26, 0x53, 0x36, 0xb6, 0xa1, 0x2a, 0x89, 0x84, 0xac, 0x2f, 0x12, 0x32, 0x33, 0x52,
+	0x76, 0x93, 0x67, 0xe6, 0x3a, 0xcf, 0x9e, 0x00, 0x4c, 0xe3, 0x25, 0xcb, 0x02, 0x16, 0x57, 0x0f,
+	0xc8, 0x6a, 0xdf, 0xaa, 0x16, 0x0a, 0x41, 0xe5, 0x34, 0xfe, 0x6b, 0x67, 0x73, 0x6a, 0x66, 0x95,
+	0xba, 0x2d, 0x53, 0xa3, 0x6e, 0x3d, 0x35, 0xaa, 0xa5, 0x40, 0x5a, 0x33, 0x05, 0x7a, 0x2c, 0x53,
+	0x1d, 0xb9, 0x51, 0xe3, 0x56, 0xc4, 0x9a, 0x19, 0xcf, 0xff, 0x9a, 0xd7, 0x8c, 0xdf, 0x94, 0x79,
+	0xcd, 0xa6, 0x4a, 0xcf, 0x08, 0xb4, 0xe9, 0x71, 0x79, 0xff, 0x6b, 0xc1, 0x71, 0x8e, 0xb2, 0x38,
+	0x89, 0xe3, 0xa4, 0x60, 0xab, 0x98, 0x5b, 0xd4, 0x66, 0x2b, 0x68, 0xfc, 0x08, 0x0c, 0x79, 0x55,
+	0x8c, 0x40, 0x3b, 0x0d, 0x64, 0xf1, 0xa7, 0x43, 0x35, 0xbc, 0xa3, 0x10, 0x61, 0x1f, 0x9c, 0xae,
+	0x42, 0xd8, 0x87, 0xf1, 0x3f, 0xba, 0x9b, 0x9e, 0x3c, 0x56, 0xf9, 0xe4, 0xa9, 0x94, 0xbe, 0xdb,
+	0x56, 0xfa, 0xd6, 0x69, 0x28, 0xd5, 0x5f, 0xaf, 0xa9, 0xff, 0x67, 0xa0, 0x1d, 0xcd, 0xde, 0x09,
+	0x55, 0xaa, 0xd1, 0x58, 0xde, 0x83, 0x9a, 0x3f, 0x7b, 0x87, 0x0e, 0x74, 0x72, 0xea, 0xf4, 0x36,
+	0x3a, 0x64, 0x93, 0xd3, 0x3b, 0x1f, 0x40, 0xab, 0xbb, 0xc5, 0x6c, 0xdc, 0x2d, 0xb5, 0x87, 0x91,
+	0x75, 0xd7, 0xc3, 0x08, 0x36, 0xdd, 0x3a, 0xf5, 0x27, 0x86, 0xfd, 0x3d, 0x4f, 0x8c, 0xc1, 0xda,
+	0x13, 0x63, 0x7c, 0x0a, 0x56, 0x45, 0x4d, 0x91, 0x31, 0x26, 0x59, 0x54, 0x46, 0xf4, 0x22, 0xc9,
+	0x22, 0x59, 0x85, 0xc0, 0x4c, 0x48, 0x6c, 0xa9, 0x89, 0x55, 0x08, 0xb4, 0x90, 0x20, 0xaf, 0x93,
+	0x39, 0x2f, 0x23, 0x6a, 0xc4, 0x68, 0xb8, 0x23, 0x18, 0xaa, 0xc7, 0x4f, 0x59, 0x98, 0x7b, 0x0d,
+	0x83, 0x0a, 0xc1, 0x7a, 0x85, 0x03, 0x7d, 0x65, 0xab, 0x69, 0xfa, 0x4b, 0x69, 0xe2, 0x9b, 0xea,
+	0xcc, 0xbf, 0xe2, 0x11, 0x2b, 0xdb, 0x65, 0x56, 0xb0, 0x9d, 0xd7, 0xc1, 0xc3, 0x7f, 0x69, 0xa0,
+	0x4d, 0x66, 0x53, 0xb2, 0x0f, 0x86, 0xac, 0xf4, 0x9a, 0x9e, 0xaa, 0xf9, 0x8e, 0x6d, 0x6f, 0x55,
+	0xc8, 0x75, 0xb7, 0xc8, 0x01, 0xf4, 0xe4, 0xe5, 0x49, 0x86, 0x5e, 0xa3, 0x1a, 0x3b, 0x1e, 0x78,
+	0xf5, 0x5a, 0xea, 0x16, 0xf9, 0x21, 0x18, 0xa2, 0xa2, 0x42, 0xb6, 0xbd, 0x7a, 0x05, 0x66, 0x6c,
+	0x7b, 0xab, 0x42, 0x8b, 0xbb, 0xf5, 0xd3, 0x0e, 0x71, 0x41, 0xc7, 0x3a, 0x1e, 0x19, 0x78, 0xb5,
+	0xfa, 0xdf, 0x18, 0xbc, 0xaa, 0xb8, 0xe7, 0x6e, 0x91, 0x23, 0x59, 0x89, 0xac, 0x15, 0x5b, 0xc9,
+	0xa7, 0xde, 0xe6, 0x62, 0xed, 0xf8, 0x13, 0x6f, 0x53, 0xad, 0xd6, 0xdd, 0x22, 0x8f, 0xc1, 0x2c,
+	0xcb, 0xaf, 0x64, 0xe4, 0xad, 0x55, 0x62, 0x5b, 0xeb, 0x7f, 0x54, 0xed, 0x29, 0xd9, 0xf1, 0x9a,
+	0xfb, 0x3f, 0xde, 0xf6, 0xea, 0xdb, 0xef, 0x6e, 0x91, 0x9f, 0x81, 0x5d, 0x2b, 0xbc, 0x92, 0x3d,
+	0xaf, 0x5d, 0xc4, 0x1d, 0xef, 0x7a, 0xeb, 0xb5, 0x59, 0x77, 0x8b, 0x3c, 0x03, 0x58, 0x95, 0x58,
+	0x09, 0xf1, 0x5a, 0x45, 0xd8, 0xf1, 0xc8, 0x5b, 0xab, 0xc1, 0xca, 0xe9, 0x6a, 0xf5, 0x53, 0xb2,
+	0xe7, 0xb5, 0x6b, 0xae, 0xe3, 0x5d, 0xaf, 0x55, 0x62, 0xdd, 0x3a, 0xef, 0x89, 0x9a, 0xfe, 0xd3,
+	0xff, 0x0c, 0x00, 0xc1, 0x85, 0xe9, 0x36, 0xe2, 0x17, 0x00, 0x00,
 }
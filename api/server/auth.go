@@ -0,0 +1,152 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/NetSys/quilt/api"
+	"github.com/NetSys/quilt/db"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// The API methods that may be called without a token.
+var unauthenticatedMethods = map[string]bool{
+	"/API/Version": true,
+}
+
+// The role a token needs to call each API method.  Methods that aren't listed here or
+// in unauthenticatedMethods require an admin token.
+var methodRoles = map[string]db.TokenRole{
+	"/API/Query":           db.ReadOnly,
+	"/API/Watch":           db.ReadOnly,
+	"/API/Plan":            db.ReadOnly,
+	"/API/ListDeployments": db.ReadOnly,
+	"/API/Deploy":          db.Deployer,
+	"/API/Rollback":        db.Deployer,
+	"/API/CreateToken":     db.Admin,
+	"/API/ListTokens":      db.Admin,
+	"/API/RevokeToken":     db.Admin,
+}
+
+// The only API methods the minions serve.  The minions have no API tokens, so they
+// serve only the read-only methods, and clients don't send them their tokens.
+var minionMethods = map[string]bool{
+	"/API/Query":   true,
+	"/API/Watch":   true,
+	"/API/Version": true,
+}
+
+// The API methods served by each path of the HTTP API.
+var httpMethods = map[string]string{
+	"/tables/": "/API/Query",
+	"/deploy":  "/API/Deploy",
+	"/watch":   "/API/Watch",
+}
+
+// An authorizer checks that callers present an API token whose role permits the
+// method they call.  Tokens are never required from callers on the local socket, so
+// that tokens may always be managed from the daemon's host.  Until the first token is
+// created, other callers may call any method but those that require an admin token,
+// so that only the daemon's host may create the first token.  The authorizers of
+// minions instead refuse every method but minionMethods.
+type authorizer struct {
+	conn   db.Conn
+	local  bool
+	minion bool
+}
+
+func (a authorizer) authorize(ctx context.Context, method string) error {
+	if a.minion && !minionMethods[method] {
+		return grpc.Errorf(codes.PermissionDenied,
+			"%s may only be called on the Quilt daemon", method)
+	}
+
+	if a.local || unauthenticatedMethods[method] {
+		return nil
+	}
+
+	required, ok := methodRoles[method]
+	if !ok {
+		required = db.Admin
+	}
+
+	tokens := a.conn.SelectFromToken(nil)
+	if len(tokens) == 0 {
+		if required == db.Admin {
+			return grpc.Errorf(codes.PermissionDenied, "until a token "+
+				"exists, %s may only be called on the daemon's host",
+				method)
+		}
+		return nil
+	}
+
+	md, _ := metadata.FromContext(ctx)
+	if len(md[api.TokenMetadataKey]) == 0 {
+		return grpc.Errorf(codes.Unauthenticated, "an API token is required")
+	}
+
+	hash := db.HashToken(md[api.TokenMetadataKey][0])
+	for _, token := range tokens {
+		if token.Hash != hash {
+			continue
+		}
+
+		if !token.Role.Allows(required) {
+			return grpc.Errorf(codes.PermissionDenied,
+				"%s tokens may not call %s", token.Role, method)
+		}
+		return nil
+	}
+	return grpc.Errorf(codes.Unauthenticated, "invalid API token")
+}
+
+func (a authorizer) unaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a authorizer) streamInterceptor(srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	if err := a.authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// httpHandler wraps `handler` to authorize HTTP requests, which carry their token as
+// a bearer token in the Authorization header.
+func (a authorizer) httpHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := httpMethods[r.URL.Path]
+		if strings.HasPrefix(r.URL.Path, "/tables/") {
+			method = httpMethods["/tables/"]
+		}
+
+		ctx := r.Context()
+		auth := r.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Bearer ") {
+			token := strings.TrimPrefix(auth, "Bearer ")
+			ctx = metadata.NewContext(ctx,
+				metadata.Pairs(api.TokenMetadataKey, token))
+		}
+
+		err := a.authorize(ctx, method)
+		switch grpc.Code(err) {
+		case codes.OK:
+			handler.ServeHTTP(w, r)
+		case codes.PermissionDenied:
+			http.Error(w, grpc.ErrorDesc(err), http.StatusForbidden)
+		default:
+			http.Error(w, grpc.ErrorDesc(err), http.StatusUnauthorized)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/NetSys/quilt/api/pb"
+	"github.com/NetSys/quilt/db"
+)
+
+func TestTokens(t *testing.T) {
+	t.Parallel()
+
+	s := server{db.New()}
+	_, err := s.CreateToken(context.Background(),
+		&pb.CreateTokenRequest{Name: "ci", Role: "root"})
+	assert.EqualError(t, err, "unknown token role: root")
+
+	reply, err := s.CreateToken(context.Background(),
+		&pb.CreateTokenRequest{Name: "ci", Role: "deployer"})
+	assert.NoError(t, err)
+	assert.Len(t, reply.Token, 64)
+
+	tokens := s.conn.SelectFromToken(nil)
+	assert.Len(t, tokens, 1)
+	assert.Equal(t, int(reply.ID), tokens[0].ID)
+	assert.Equal(t, "ci", tokens[0].Name)
+	assert.Equal(t, db.Deployer, tokens[0].Role)
+	assert.Equal(t, db.HashToken(reply.Token), tokens[0].Hash)
+
+	list, err := s.ListTokens(context.Background(), &pb.ListTokensRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.Token{db.TokenToPB(tokens[0])}, list.Tokens)
+	assert.NotContains(t, list.Tokens[0].String(), tokens[0].Hash)
+
+	_, err = s.RevokeToken(context.Background(), &pb.RevokeTokenRequest{ID: 100})
+	assert.EqualError(t, err, "no token with ID 100")
+
+	_, err = s.RevokeToken(context.Background(),
+		&pb.RevokeTokenRequest{ID: reply.ID})
+	assert.NoError(t, err)
+	assert.Empty(t, s.conn.SelectFromToken(nil))
+}
+
+func TestAuthorize(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	auth := authorizer{conn: conn}
+	withToken := func(token string) context.Context {
+		return metadata.NewContext(context.Background(),
+			metadata.Pairs("token", token))
+	}
+
+	// Until a token is created, anyone may call anything but the admin methods.
+	code := func(ctx context.Context, method string) codes.Code {
+		return grpc.Code(auth.authorize(ctx, method))
+	}
+	assert.Equal(t, codes.OK, code(context.Background(), "/API/Deploy"))
+	assert.Equal(t, codes.PermissionDenied,
+		code(context.Background(), "/API/CreateToken"))
+	assert.Equal(t, codes.PermissionDenied,
+		code(context.Background(), "/API/Unknown"))
+
+	tokens := map[db.TokenRole]string{}
+	for _, role := range []db.TokenRole{db.ReadOnly, db.Deployer, db.Admin} {
+		reply, err := server{conn}.CreateToken(context.Background(),
+			&pb.CreateTokenRequest{Role: string(role)})
+		assert.NoError(t, err)
+		tokens[role] = reply.Token
+	}
+
+	assert.Equal(t, codes.OK, code(context.Background(), "/API/Version"))
+	assert.Equal(t, codes.Unauthenticated,
+		code(context.Background(), "/API/Query"))
+	assert.Equal(t, codes.Unauthenticated, code(withToken("bad"), "/API/Query"))
+
+	reader := withToken(tokens[db.ReadOnly])
+	assert.Equal(t, codes.OK, code(reader, "/API/Query"))
+	assert.Equal(t, codes.OK, code(reader, "/API/Watch"))
+	assert.Equal(t, codes.PermissionDenied, code(reader, "/API/Deploy"))
+
+	deployer := withToken(tokens[db.Deployer])
+	assert.Equal(t, codes.OK, code(deployer, "/API/Deploy"))
+	assert.Equal(t, codes.OK, code(deployer, "/API/Rollback"))
+	assert.Equal(t, codes.PermissionDenied, code(deployer, "/API/CreateToken"))
+	assert.Equal(t, codes.PermissionDenied, code(deployer, "/API/Unknown"))
+
+	admin := withToken(tokens[db.Admin])
+	assert.Equal(t, codes.OK, code(admin, "/API/Deploy"))
+	assert.Equal(t, codes.OK, code(admin, "/API/RevokeToken"))
+	assert.Equal(t, codes.OK, code(admin, "/API/Unknown"))
+
+	// Callers on the local socket may always call anything.
+	local := authorizer{conn: db.New(), local: true}
+	assert.NoError(t, local.authorize(context.Background(), "/API/CreateToken"))
+	local.conn = conn
+	assert.NoError(t, local.authorize(context.Background(), "/API/CreateToken"))
+}
+
+func TestAuthorizeMinion(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	minion := authorizer{conn: conn, minion: true}
+	code := func(auth authorizer, method string) codes.Code {
+		return grpc.Code(auth.authorize(context.Background(), method))
+	}
+
+	// Minions serve only the methods that read their database.
+	for _, method := range []string{"/API/Query", "/API/Watch", "/API/Version"} {
+		assert.Equal(t, codes.OK, code(minion, method))
+	}
+	for _, method := range []string{"/API/Deploy", "/API/Rollback", "/API/Plan",
+		"/API/ListDeployments", "/API/CreateToken", "/API/Unknown"} {
+		assert.Equal(t, codes.PermissionDenied, code(minion, method))
+	}
+
+	// Once tokens exist, the daemon rejects remote queries without one, which is
+	// why clients only send their tokens to it.
+	_, err := server{conn}.CreateToken(context.Background(),
+		&pb.CreateTokenRequest{Role: string(db.ReadOnly)})
+	assert.NoError(t, err)
+	assert.Equal(t, codes.Unauthenticated, code(authorizer{conn: conn}, "/API/Query"))
+}
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	_, err := server{conn}.CreateToken(context.Background(),
+		&pb.CreateTokenRequest{Role: string(db.Admin)})
+	assert.NoError(t, err)
+	auth := authorizer{conn: conn}
+
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+	_, err = auth.unaryInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/API/Deploy"}, handler)
+	assert.Equal(t, codes.Unauthenticated, grpc.Code(err))
+	assert.False(t, called)
+
+	_, err = auth.unaryInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/API/Version"}, handler)
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	stream := &sseWatchServer{ctx: context.Background()}
+	streamHandler := func(srv interface{}, stream grpc.ServerStream) error {
+		t.Error("the handler shouldn't be called")
+		return nil
+	}
+	err = auth.streamInterceptor(nil, stream,
+		&grpc.StreamServerInfo{FullMethod: "/API/Watch"}, streamHandler)
+	assert.Equal(t, codes.Unauthenticated, grpc.Code(err))
+}
+
+func TestHTTPAuthorization(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	reply, err := server{conn}.CreateToken(context.Background(),
+		&pb.CreateTokenRequest{Role: string(db.ReadOnly)})
+	assert.NoError(t, err)
+
+	auth := authorizer{conn: conn}
+	ts := httptest.NewServer(auth.httpHandler(newHTTPHandler(server{conn})))
+	defer ts.Close()
+
+	do := func(method, path, token string) int {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader("{}"))
+		assert.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	tables := "/tables/" + string(db.EtcdTable)
+	assert.Equal(t, http.StatusUnauthorized, do("GET", tables, ""))
+	assert.Equal(t, http.StatusUnauthorized, do("GET", tables, "bad"))
+	assert.Equal(t, http.StatusOK, do("GET", tables, reply.Token))
+	assert.Equal(t, http.StatusForbidden, do("POST", "/deploy", reply.Token))
+}
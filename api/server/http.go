@@ -0,0 +1,172 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NetSys/quilt/api"
+	"github.com/NetSys/quilt/api/pb"
+	"github.com/NetSys/quilt/db"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// The largest request body the HTTP server accepts, which matches the largest
+// message the gRPC server accepts.
+const maxHTTPBodySize = 4 << 20
+
+// RunHTTP serves the API as JSON over HTTPS, for clients that can't speak gRPC.
+// Like the gRPC server, clients must present a certificate trusted by 'config', and
+// an API token as a bearer token once any exist.
+//
+// GET /tables/<table>[?asOf=<RFC 3339 time>] responds with the rows of a table, as
+// the Query RPC does.  POST /deploy deploys the stitch in the request body, as the
+// Deploy RPC does.  GET /watch?table=<table>[&table=<table>...] streams the contents
+// of the given tables as server-sent events, as the Watch RPC does.
+func RunHTTP(conn db.Conn, listenAddr string, config *tls.Config) error {
+	proto, addr, err := api.ParseListenAddress(listenAddr)
+	if err != nil {
+		return err
+	}
+
+	var sock net.Listener
+	for {
+		sock, err = net.Listen(proto, addr)
+		if err == nil {
+			break
+		}
+		log.WithError(err).Error("Failed to open HTTP socket.")
+
+		time.Sleep(30 * time.Second)
+	}
+
+	auth := authorizer{conn: conn, local: proto == "unix"}
+	return http.Serve(tls.NewListener(sock, config),
+		auth.httpHandler(newHTTPHandler(server{conn})))
+}
+
+func newHTTPHandler(s server) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tables/", s.httpQuery)
+	mux.HandleFunc("/deploy", s.httpDeploy)
+	mux.HandleFunc("/watch", s.httpWatch)
+	return mux
+}
+
+func (s server) httpQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := &pb.DBQuery{Table: strings.TrimPrefix(r.URL.Path, "/tables/")}
+	if asOf := r.URL.Query().Get("asOf"); asOf != "" {
+		t, err := time.Parse(time.RFC3339, asOf)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.AsOf = t.UnixNano()
+	}
+
+	reply, err := s.Query(r.Context(), query)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, reply.TableContents)
+}
+
+func (s server) httpDeploy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	_, err = s.Deploy(r.Context(), &pb.DeployRequest{Deployment: string(body)})
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s server) httpWatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	stream := &sseWatchServer{ctx: r.Context(), w: w, flusher: flusher}
+	err := s.Watch(&pb.WatchRequest{Tables: r.URL.Query()["table"]}, stream)
+	if err != nil && !stream.started {
+		httpError(w, err)
+	}
+}
+
+// httpError responds with the description of 'err', and the HTTP status that
+// corresponds to its gRPC code.  Errors that aren't the client's fault, including
+// those without a code, are internal server errors.
+func httpError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch grpc.Code(err) {
+	case codes.InvalidArgument:
+		status = http.StatusBadRequest
+	case codes.Unauthenticated:
+		status = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		status = http.StatusForbidden
+	}
+	http.Error(w, grpc.ErrorDesc(err), status)
+}
+
+// An sseWatchServer adapts the Watch RPC to deliver its replies as server-sent
+// events, named after the table whose contents they hold.
+type sseWatchServer struct {
+	grpc.ServerStream
+
+	ctx     context.Context
+	w       http.ResponseWriter
+	flusher http.Flusher
+	started bool
+}
+
+func (s *sseWatchServer) Send(reply *pb.WatchReply) error {
+	if !s.started {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.started = true
+	}
+
+	_, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", reply.Table,
+		reply.TableContents)
+	s.flusher.Flush()
+	return err
+}
+
+func (s *sseWatchServer) Context() context.Context {
+	return s.ctx
+}
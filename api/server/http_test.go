@@ -0,0 +1,155 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/stitch"
+)
+
+func TestHTTPQuery(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		etcd := view.InsertEtcd()
+		etcd.Leader = true
+		view.Commit(etcd)
+		return nil
+	})
+
+	ts := httptest.NewServer(newHTTPHandler(server{conn}))
+	defer ts.Close()
+
+	status, body := httpGet(t, ts.URL+"/tables/"+string(db.EtcdTable))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `[{"ID":1,"EtcdIPs":null,"Leader":true,"LeaderIP":""}]`, body)
+
+	status, body = httpGet(t, ts.URL+"/tables/foo")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "unrecognized table: foo\n", body)
+
+	status, body = httpGet(t, ts.URL+"/tables/"+string(db.EtcdTable)+
+		"?asOf=2017-01-01T00:00:00Z")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "database history is disabled\n", body)
+
+	resp, err := http.Post(ts.URL+"/tables/"+string(db.EtcdTable), "", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestHTTPDeploy(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	ts := httptest.NewServer(newHTTPHandler(server{conn}))
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/deploy", "application/json",
+		strings.NewReader(`{`))
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "unexpected end of JSON input\n", string(body))
+
+	resp, err = http.Post(ts.URL+"/deploy", "application/json",
+		strings.NewReader(`{"Namespace": "ns"}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	clusters := conn.SelectFromCluster(nil)
+	assert.Len(t, clusters, 1)
+	deployed, err := stitch.FromJSON(clusters[0].Spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns", deployed.Namespace)
+
+	resp, err = http.Post(ts.URL+"/deploy", "application/json",
+		strings.NewReader(strings.Repeat(" ", maxHTTPBodySize+1)))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestHTTPError(t *testing.T) {
+	t.Parallel()
+
+	test := func(err error, expStatus int, expBody string) {
+		w := httptest.NewRecorder()
+		httpError(w, err)
+		assert.Equal(t, expStatus, w.Code)
+		assert.Equal(t, expBody, w.Body.String())
+	}
+
+	test(grpc.Errorf(codes.InvalidArgument, "bad"), http.StatusBadRequest, "bad\n")
+	test(grpc.Errorf(codes.Unauthenticated, "who"), http.StatusUnauthorized,
+		"who\n")
+	test(grpc.Errorf(codes.PermissionDenied, "no"), http.StatusForbidden, "no\n")
+	test(grpc.Errorf(codes.Internal, "oops"), http.StatusInternalServerError,
+		"oops\n")
+	test(errors.New("oops"), http.StatusInternalServerError, "oops\n")
+}
+
+func TestHTTPWatch(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	ts := httptest.NewServer(newHTTPHandler(server{conn}))
+	defer ts.Close()
+
+	status, body := httpGet(t, ts.URL+"/watch?table=foo")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "unrecognized table: foo\n", body)
+
+	resp, err := http.Get(ts.URL + "/watch?table=" + string(db.EtcdTable))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := bufio.NewReader(resp.Body)
+	assert.Equal(t, "event: db.Etcd\ndata: []\n\n", readEvent(t, events))
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		view.InsertEtcd()
+		return nil
+	})
+	assert.Equal(t, "event: db.Etcd\n"+
+		`data: [{"ID":1,"EtcdIPs":null,"Leader":false,"LeaderIP":""}]`+"\n\n",
+		readEvent(t, events))
+}
+
+func httpGet(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	var event string
+	for {
+		line, err := r.ReadString('\n')
+		assert.NoError(t, err)
+
+		event += line
+		if line == "\n" {
+			return event
+		}
+	}
+}
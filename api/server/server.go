@@ -1,6 +1,8 @@
 package server
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,21 +15,46 @@ import (
 	"github.com/NetSys/quilt/api"
 	"github.com/NetSys/quilt/api/pb"
 	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/engine"
 	"github.com/NetSys/quilt/stitch"
+	"github.com/NetSys/quilt/version"
 
 	"github.com/docker/distribution/reference"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 
 	log "github.com/Sirupsen/logrus"
 )
 
+// The number of past deployments kept for each namespace, to be rolled back to.
+const deploymentHistorySize = 10
+
 type server struct {
 	conn db.Conn
 }
 
-// Run accepts incoming `quiltctl` connections and responds to them.
-func Run(conn db.Conn, listenAddr string) error {
+// Run accepts incoming `quiltctl` connections and responds to them.  Clients must
+// authenticate themselves with a certificate trusted by 'creds', and once any API
+// tokens exist, callers that aren't on a unix socket must also present a token whose
+// role permits the methods they call.  Until then, they may not manage tokens.
+func Run(conn db.Conn, listenAddr string, creds credentials.TransportCredentials) error {
+	return run(conn, listenAddr, creds, false)
+}
+
+// RunMinion serves the API of a minion.  Like Run, clients must present a certificate
+// trusted by 'creds', but as minions have no API tokens, only the methods that read
+// the minion's database are served.
+func RunMinion(conn db.Conn, listenAddr string,
+	creds credentials.TransportCredentials) error {
+
+	return run(conn, listenAddr, creds, true)
+}
+
+func run(conn db.Conn, listenAddr string, creds credentials.TransportCredentials,
+	minion bool) error {
+
 	proto, addr, err := api.ParseListenAddress(listenAddr)
 	if err != nil {
 		return err
@@ -56,7 +83,10 @@ func Run(conn db.Conn, listenAddr string) error {
 		os.Exit(0)
 	}(sigc)
 
-	s := grpc.NewServer()
+	auth := authorizer{conn: conn, local: proto == "unix", minion: minion}
+	s := grpc.NewServer(grpc.Creds(creds),
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor))
 	pb.RegisterAPIServer(s, apiServer)
 	s.Serve(sock)
 
@@ -65,62 +95,170 @@ func Run(conn db.Conn, listenAddr string) error {
 
 func (s server) Query(cts context.Context, query *pb.DBQuery) (*pb.QueryReply, error) {
 	var rows interface{}
-	switch db.TableType(query.Table) {
+	var err error
+	if query.AsOf == 0 {
+		err = s.conn.ReadTxn(db.AllTables...).Run(func(view db.Database) error {
+			rows, err = queryTable(view, db.TableType(query.Table))
+			return err
+		})
+	} else {
+		var view db.Database
+		view, err = s.conn.AsOf(time.Unix(0, query.AsOf))
+		if err != nil {
+			err = grpc.Errorf(codes.InvalidArgument, "%s", err)
+		} else {
+			rows, err = queryTable(view, db.TableType(query.Table))
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	json, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.QueryReply{
+		TableContents: string(json),
+		Rows:          db.RowsToPB(rows),
+	}, nil
+}
+
+func queryTable(view db.Database, table db.TableType) (interface{}, error) {
+	switch table {
 	case db.MachineTable:
-		rows = s.conn.SelectFromMachine(nil)
+		return view.SelectFromMachine(nil), nil
 	case db.ContainerTable:
-		rows = s.conn.SelectFromContainer(nil)
+		return view.SelectFromContainer(nil), nil
 	case db.EtcdTable:
-		rows = s.conn.SelectFromEtcd(nil)
+		return view.SelectFromEtcd(nil), nil
 	case db.ConnectionTable:
-		rows = s.conn.SelectFromConnection(nil)
+		return view.SelectFromConnection(nil), nil
 	case db.LabelTable:
-		rows = s.conn.SelectFromLabel(nil)
+		return view.SelectFromLabel(nil), nil
 	case db.ClusterTable:
-		rows = s.conn.SelectFromCluster(nil)
+		return view.SelectFromCluster(nil), nil
+	case db.PlacementTable:
+		return view.SelectFromPlacement(nil), nil
+	case db.ACLTable:
+		return view.SelectFromACL(nil), nil
+	case db.MinionTable:
+		return view.SelectFromMinion(nil), nil
+	case db.EventTable:
+		return view.SelectFromEvent(nil), nil
+	case db.StatusTable:
+		return view.SelectFromStatus(nil), nil
 	default:
-		return nil, fmt.Errorf("unrecognized table: %s", query.Table)
+		return nil, grpc.Errorf(codes.InvalidArgument, "unrecognized table: %s",
+			table)
 	}
+}
 
-	json, err := json.Marshal(rows)
+func (s server) Watch(req *pb.WatchRequest, stream pb.API_WatchServer) error {
+	var tables []db.TableType
+	for _, name := range req.Tables {
+		tables = append(tables, db.TableType(name))
+	}
+
+	// Reject unrecognized tables up front, as the database panics if asked to
+	// trigger on them.
+	err := s.conn.ReadTxn(db.AllTables...).Run(func(view db.Database) error {
+		for _, table := range tables {
+			if _, err := queryTable(view, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return &pb.QueryReply{TableContents: string(json)}, nil
-}
+	trigger := s.conn.Trigger(tables...)
+	defer trigger.Stop()
 
-func (s server) Deploy(cts context.Context, deployReq *pb.DeployRequest) (
-	*pb.DeployReply, error) {
+	// The contents most recently sent for each table, so that only tables that
+	// changed are resent.
+	sent := map[db.TableType]string{}
+	for {
+		var replies []*pb.WatchReply
+		err := s.conn.ReadTxn(tables...).Run(func(view db.Database) error {
+			for _, table := range tables {
+				rows, err := queryTable(view, table)
+				if err != nil {
+					return err
+				}
 
-	stitch, err := stitch.FromJSON(deployReq.Deployment)
-	if err != nil {
-		return &pb.DeployReply{}, err
-	}
+				contents, err := json.Marshal(rows)
+				if err != nil {
+					return err
+				}
 
-	for _, c := range stitch.Containers {
-		if _, err := reference.ParseAnyReference(c.Image); err != nil {
-			return &pb.DeployReply{}, fmt.Errorf("could not parse "+
-				"container image %s: %s", c.Image, err.Error())
+				prev, ok := sent[table]
+				if ok && prev == string(contents) {
+					continue
+				}
+				sent[table] = string(contents)
+
+				replies = append(replies, &pb.WatchReply{
+					Table:         string(table),
+					TableContents: string(contents),
+					Rows:          db.RowsToPB(rows),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, reply := range replies {
+			if err := stream.Send(reply); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-trigger.C:
+		case <-stream.Context().Done():
+			return nil
 		}
 	}
+}
+
+func (s server) Deploy(cts context.Context, deployReq *pb.DeployRequest) (
+	*pb.DeployReply, error) {
 
-	err = s.conn.Txn(db.ClusterTable).Run(func(view db.Database) error {
-		cluster, err := view.GetCluster()
+	var stc stitch.Stitch
+	if deployReq.Stitch != nil {
+		stc = stitch.FromPB(deployReq.Stitch)
+	} else {
+		// Clients from before the typed Stitch only send its JSON.
+		var err error
+		stc, err = stitch.FromJSON(deployReq.Deployment)
 		if err != nil {
-			cluster = view.InsertCluster()
+			return &pb.DeployReply{}, grpc.Errorf(codes.InvalidArgument,
+				"%s", err)
 		}
+	}
 
-		cluster.Spec = stitch.String()
-		view.Commit(cluster)
-		return nil
-	})
+	if err := validateImages(stc); err != nil {
+		return &pb.DeployReply{}, err
+	}
+
+	err := s.conn.Txn(db.ClusterTable, db.DeploymentTable).Run(
+		func(view db.Database) error {
+			deploy(view, stc)
+			return nil
+		})
 	if err != nil {
 		return &pb.DeployReply{}, err
 	}
 
 	// XXX: Remove this error when the Vagrant provider is done.
-	for _, machine := range stitch.Machines {
+	for _, machine := range stc.Machines {
 		if machine.Provider == db.Vagrant {
 			err = errors.New("The Vagrant provider is in development." +
 				" The stitch will continue to run, but" +
@@ -131,3 +269,169 @@ func (s server) Deploy(cts context.Context, deployReq *pb.DeployRequest) (
 
 	return &pb.DeployReply{}, nil
 }
+
+// Plan reports the changes deploying the requested stitch would make, without
+// deploying it.
+func (s server) Plan(cts context.Context, req *pb.PlanRequest) (*pb.PlanReply,
+	error) {
+
+	stc := stitch.FromPB(req.Stitch)
+	if err := validateImages(stc); err != nil {
+		return &pb.PlanReply{}, err
+	}
+	return db.PlanToPB(engine.NewPlan(s.conn, stc)), nil
+}
+
+// validateImages checks that the stitch's container images are valid references, so
+// that stitches Deploy would reject aren't planned either.
+func validateImages(stc stitch.Stitch) error {
+	for _, c := range stc.Containers {
+		if _, err := reference.ParseAnyReference(c.Image); err != nil {
+			return grpc.Errorf(codes.InvalidArgument,
+				"could not parse container image %s: %s", c.Image, err)
+		}
+	}
+	return nil
+}
+
+// deploy sets the spec of the stitch's cluster, creating the cluster if its namespace
+// is new, and records the stitch in the namespace's deployment history.  Redeploying
+// the namespace's most recent stitch isn't recorded again, and its oldest deployments
+// are evicted once there are more than deploymentHistorySize.
+func deploy(view db.Database, stc stitch.Stitch) {
+	spec := stc.String()
+	cluster, err := view.GetCluster(stc.Namespace)
+	if err != nil {
+		cluster = view.InsertCluster()
+		cluster.Namespace = stc.Namespace
+	}
+	cluster.Spec = spec
+	view.Commit(cluster)
+
+	hash := db.HashSpec(spec)
+	deployments := view.SelectFromDeployment(func(d db.Deployment) bool {
+		return d.Namespace == stc.Namespace
+	})
+	if len(deployments) > 0 && deployments[len(deployments)-1].Hash == hash {
+		return
+	}
+
+	deployment := view.InsertDeployment()
+	deployment.Namespace = stc.Namespace
+	deployment.Spec = spec
+	deployment.Hash = hash
+	deployment.Time = time.Now()
+	view.Commit(deployment)
+
+	deployments = append(deployments, deployment)
+	for len(deployments) > deploymentHistorySize {
+		view.Remove(deployments[0])
+		deployments = deployments[1:]
+	}
+}
+
+// ListDeployments returns the recent deployments of every namespace, from oldest to
+// newest.
+func (s server) ListDeployments(cts context.Context, req *pb.ListDeploymentsRequest) (
+	*pb.ListDeploymentsReply, error) {
+
+	reply := &pb.ListDeploymentsReply{}
+	for _, d := range s.conn.SelectFromDeployment(nil) {
+		reply.Deployments = append(reply.Deployments, db.DeploymentToPB(d))
+	}
+	return reply, nil
+}
+
+// Rollback redeploys the stitch of a recent deployment.
+func (s server) Rollback(cts context.Context, req *pb.RollbackRequest) (
+	*pb.DeployReply, error) {
+
+	err := s.conn.Txn(db.ClusterTable, db.DeploymentTable).Run(
+		func(view db.Database) error {
+			deployments := view.SelectFromDeployment(
+				func(d db.Deployment) bool {
+					return d.ID == int(req.ID)
+				})
+			if len(deployments) == 0 {
+				return fmt.Errorf("no deployment with ID %d", req.ID)
+			}
+
+			stc, err := stitch.FromJSON(deployments[0].Spec)
+			if err != nil {
+				return err
+			}
+
+			deploy(view, stc)
+			return nil
+		})
+	return &pb.DeployReply{}, err
+}
+
+// Version reports the version of Quilt the server is running, and the schema it
+// speaks.
+func (s server) Version(cts context.Context, req *pb.VersionRequest) (
+	*pb.VersionReply, error) {
+
+	return &pb.VersionReply{
+		Version:       version.Version,
+		SchemaVersion: version.Schema,
+	}, nil
+}
+
+// CreateToken creates an API token with the requested role.  Only the hash of the
+// token is stored, so the token in the reply is the only copy.
+func (s server) CreateToken(cts context.Context, req *pb.CreateTokenRequest) (
+	*pb.CreateTokenReply, error) {
+
+	role, err := db.ParseTokenRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	value := hex.EncodeToString(secret)
+
+	var token db.Token
+	s.conn.Txn(db.TokenTable).Run(func(view db.Database) error {
+		token = view.InsertToken()
+		token.Name = req.Name
+		token.Role = role
+		token.Hash = db.HashToken(value)
+		token.Created = time.Now()
+		view.Commit(token)
+		return nil
+	})
+	return &pb.CreateTokenReply{Token: value, ID: int32(token.ID)}, nil
+}
+
+// ListTokens returns the API tokens, from oldest to newest.
+func (s server) ListTokens(cts context.Context, req *pb.ListTokensRequest) (
+	*pb.ListTokensReply, error) {
+
+	reply := &pb.ListTokensReply{}
+	for _, t := range s.conn.SelectFromToken(nil) {
+		reply.Tokens = append(reply.Tokens, db.TokenToPB(t))
+	}
+	return reply, nil
+}
+
+// RevokeToken removes the API token with the requested ID.
+func (s server) RevokeToken(cts context.Context, req *pb.RevokeTokenRequest) (
+	*pb.RevokeTokenReply, error) {
+
+	err := s.conn.Txn(db.TokenTable).Run(func(view db.Database) error {
+		tokens := view.SelectFromToken(func(t db.Token) bool {
+			return t.ID == int(req.ID)
+		})
+		if len(tokens) == 0 {
+			return fmt.Errorf("no token with ID %d", req.ID)
+		}
+
+		view.Remove(tokens[0])
+		return nil
+	})
+	return &pb.RevokeTokenReply{}, err
+}
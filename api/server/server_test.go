@@ -3,12 +3,16 @@ package server
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/NetSys/quilt/api/pb"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/stitch"
+	"github.com/NetSys/quilt/version"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,10 +40,12 @@ func TestMachineResponse(t *testing.T) {
 		return nil
 	})
 
-	exp := `[{"ID":1,"StitchID":"","Role":"Master","Provider":"Amazon","Region":"",` +
-		`"Size":"size","DiskSize":0,"SSHKeys":null,"FloatingIP":"",` +
+	exp := `[{"ID":1,"Namespace":"","StitchID":"","Role":"Master",` +
+		`"Provider":"Amazon","Region":"","Size":"size","DiskSize":0,` +
+		`"SSHKeys":null,"FloatingIP":"","Preemptible":false,"SpotPrice":0,` +
 		`"CloudID":"","PublicIP":"8.8.8.8","PrivateIP":"9.9.9.9",` +
-		`"Connected":false}]`
+		`"BackoffUntil":"0001-01-01T00:00:00Z",` +
+		`"Connected":false,"MinionVersion":"","Terminating":false}]`
 
 	checkQuery(t, server{conn}, db.MachineTable, exp)
 }
@@ -65,6 +71,121 @@ func TestContainerResponse(t *testing.T) {
 	checkQuery(t, server{conn}, db.ContainerTable, exp)
 }
 
+func TestQueryAsOf(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	s := server{conn}
+
+	_, err := s.Query(context.Background(), &pb.DBQuery{
+		Table: string(db.EtcdTable), AsOf: time.Now().UnixNano()})
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	assert.Equal(t, "database history is disabled", grpc.ErrorDesc(err))
+
+	conn.EnableHistory(db.DefaultHistorySize)
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		etcd := view.InsertEtcd()
+		etcd.Leader = true
+		view.Commit(etcd)
+		return nil
+	})
+
+	before := time.Now()
+	time.Sleep(time.Millisecond)
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		etcd, _ := view.GetEtcd()
+		etcd.LeaderIP = "1.2.3.4"
+		view.Commit(etcd)
+		return nil
+	})
+
+	reply, err := s.Query(context.Background(), &pb.DBQuery{
+		Table: string(db.EtcdTable), AsOf: before.UnixNano()})
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"ID":1,"EtcdIPs":null,"Leader":true,"LeaderIP":""}]`,
+		reply.TableContents)
+
+	checkQuery(t, s, db.EtcdTable,
+		`[{"ID":1,"EtcdIPs":null,"Leader":true,"LeaderIP":"1.2.3.4"}]`)
+}
+
+func TestQueryRows(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		acl := view.InsertACL()
+		acl.Admin = []string{"1.2.3.4/32"}
+		acl.ApplicationPorts = []db.PortRange{{MinPort: 80, MaxPort: 80}}
+		view.Commit(acl)
+		return nil
+	})
+
+	reply, err := server{conn}.Query(context.Background(),
+		&pb.DBQuery{Table: string(db.ACLTable)})
+	assert.NoError(t, err)
+	assert.Equal(t, []db.ACL{{ID: 1, Admin: []string{"1.2.3.4/32"},
+		ApplicationPorts: []db.PortRange{{MinPort: 80, MaxPort: 80}}}},
+		db.PBToRows(db.ACLTable, reply.Rows))
+}
+
+type mockWatchServer struct {
+	grpc.ServerStream
+
+	ctx     context.Context
+	replies chan *pb.WatchReply
+}
+
+func (s mockWatchServer) Send(reply *pb.WatchReply) error {
+	s.replies <- reply
+	return nil
+}
+
+func (s mockWatchServer) Context() context.Context {
+	return s.ctx
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	conn := db.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := mockWatchServer{ctx: ctx, replies: make(chan *pb.WatchReply)}
+
+	done := make(chan error)
+	go func() {
+		done <- server{conn}.Watch(&pb.WatchRequest{
+			Tables: []string{string(db.EtcdTable), string(db.LabelTable)},
+		}, stream)
+	}()
+
+	// The initial contents of every table.
+	assert.Equal(t, &pb.WatchReply{Table: string(db.EtcdTable),
+		TableContents: "[]", Rows: &pb.TableRows{}}, <-stream.replies)
+	assert.Equal(t, &pb.WatchReply{Table: string(db.LabelTable),
+		TableContents: "null", Rows: &pb.TableRows{}}, <-stream.replies)
+
+	// Only the table that changed is resent.
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		etcd := view.InsertEtcd()
+		etcd.Leader = true
+		view.Commit(etcd)
+		return nil
+	})
+	assert.Equal(t, &pb.WatchReply{Table: string(db.EtcdTable),
+		TableContents: `[{"ID":1,"EtcdIPs":null,"Leader":true,"LeaderIP":""}]`,
+		Rows:          &pb.TableRows{Etcds: []*pb.Etcd{{ID: 1, Leader: true}}}},
+		<-stream.replies)
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	err := server{conn}.Watch(&pb.WatchRequest{Tables: []string{"foo"}}, stream)
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	assert.Equal(t, "unrecognized table: foo", grpc.ErrorDesc(err))
+}
+
 func TestBadDeployment(t *testing.T) {
 	conn := db.New()
 	s := server{conn: conn}
@@ -74,7 +195,8 @@ func TestBadDeployment(t *testing.T) {
 	_, err := s.Deploy(context.Background(),
 		&pb.DeployRequest{Deployment: badDeployment})
 
-	assert.EqualError(t, err, "unexpected end of JSON input")
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	assert.Equal(t, "unexpected end of JSON input", grpc.ErrorDesc(err))
 }
 func TestInvalidImage(t *testing.T) {
 	conn := db.New()
@@ -107,7 +229,14 @@ func testInvalidImage(t *testing.T, s server, img, expErr string) {
 
 	_, err := s.Deploy(context.Background(),
 		&pb.DeployRequest{Deployment: deployment})
-	assert.EqualError(t, err, expErr)
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	assert.Equal(t, expErr, grpc.ErrorDesc(err))
+
+	stc, err := stitch.FromJSON(deployment)
+	assert.NoError(t, err)
+	_, err = s.Plan(context.Background(), &pb.PlanRequest{Stitch: stc.ToPB()})
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	assert.Equal(t, expErr, grpc.ErrorDesc(err))
 }
 
 func TestDeploy(t *testing.T) {
@@ -131,7 +260,7 @@ func TestDeploy(t *testing.T) {
 
 	var spec string
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		clst, err := view.GetCluster()
+		clst, err := view.GetCluster("")
 		assert.NoError(t, err)
 		spec = clst.Spec
 		return nil
@@ -146,6 +275,157 @@ func TestDeploy(t *testing.T) {
 	assert.Equal(t, exp, actual)
 }
 
+func TestDeployStitch(t *testing.T) {
+	conn := db.New()
+	s := server{conn: conn}
+
+	// The typed Stitch takes precedence over the JSON deployment.
+	exp := stitch.Stitch{Namespace: "typed", Machines: []stitch.Machine{
+		{Provider: "Amazon", Role: "Master", Size: "m4.large"}}}
+	_, err := s.Deploy(context.Background(), &pb.DeployRequest{
+		Deployment: `{"Namespace": "json"}`,
+		Stitch:     exp.ToPB(),
+	})
+	assert.NoError(t, err)
+
+	clusters := conn.SelectFromCluster(nil)
+	assert.Len(t, clusters, 1)
+
+	actual, err := stitch.FromJSON(clusters[0].Spec)
+	assert.NoError(t, err)
+	assert.Equal(t, exp, actual)
+}
+
+func TestPlan(t *testing.T) {
+	conn := db.New()
+	s := server{conn: conn}
+
+	spec := stitch.Stitch{Machines: []stitch.Machine{
+		{Provider: "Amazon", Role: "Master", Size: "m4.large"},
+		{Provider: "Amazon", Role: "Worker", Size: "m4.large"}}}
+	reply, err := s.Plan(context.Background(),
+		&pb.PlanRequest{Stitch: spec.ToPB()})
+	assert.NoError(t, err)
+	assert.Len(t, reply.BootMachines, 2)
+	assert.Empty(t, reply.TerminateMachines)
+
+	// Planning doesn't deploy anything.
+	assert.Empty(t, conn.SelectFromMachine(nil))
+}
+
+func TestDeployNamespaces(t *testing.T) {
+	conn := db.New()
+	s := server{conn: conn}
+
+	deploy := func(stc stitch.Stitch) {
+		_, err := s.Deploy(context.Background(),
+			&pb.DeployRequest{Stitch: stc.ToPB()})
+		assert.NoError(t, err)
+	}
+
+	staging := stitch.Stitch{Namespace: "staging", AdminACL: []string{"a"}}
+	production := stitch.Stitch{Namespace: "production"}
+	deploy(staging)
+	deploy(production)
+
+	staging.AdminACL = []string{"b"}
+	deploy(staging)
+
+	clusters := conn.SelectFromCluster(nil)
+	assert.Len(t, clusters, 2)
+
+	for _, stc := range []stitch.Stitch{staging, production} {
+		clst, err := conn.GetCluster(stc.Namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, stc.String(), clst.Spec)
+	}
+}
+
+func TestDeploymentHistory(t *testing.T) {
+	conn := db.New()
+	s := server{conn: conn}
+
+	deployTo := func(namespace, acl string) {
+		spec := stitch.Stitch{Namespace: namespace, AdminACL: []string{acl}}
+		_, err := s.Deploy(context.Background(),
+			&pb.DeployRequest{Stitch: spec.ToPB()})
+		assert.NoError(t, err)
+	}
+	deploy := func(acl string) { deployTo("ns", acl) }
+
+	// Redeploying the most recent stitch isn't recorded again.
+	deploy("a")
+	deploy("a")
+	deploy("b")
+
+	reply, err := s.ListDeployments(context.Background(),
+		&pb.ListDeploymentsRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, reply.Deployments, 2)
+
+	first := reply.Deployments[0]
+	assert.Equal(t, stitch.Stitch{Namespace: "ns", AdminACL: []string{"a"}}.String(),
+		first.Spec)
+	assert.Equal(t, db.HashSpec(first.Spec), first.Hash)
+
+	_, err = s.Rollback(context.Background(), &pb.RollbackRequest{ID: first.ID})
+	assert.NoError(t, err)
+
+	clusters := conn.SelectFromCluster(nil)
+	assert.Len(t, clusters, 1)
+	assert.Equal(t, first.Spec, clusters[0].Spec)
+
+	// The rollback is itself the most recent deployment.
+	deployments := conn.SelectFromDeployment(nil)
+	assert.Len(t, deployments, 3)
+	assert.Equal(t, first.Spec, deployments[2].Spec)
+
+	_, err = s.Rollback(context.Background(), &pb.RollbackRequest{ID: 100})
+	assert.EqualError(t, err, "no deployment with ID 100")
+
+	// Only the most recent deployments are kept.
+	for i := 0; i < deploymentHistorySize; i++ {
+		deploy(fmt.Sprintf("acl-%d", i))
+	}
+	deployments = conn.SelectFromDeployment(nil)
+	assert.Len(t, deployments, deploymentHistorySize)
+	oldest := stitch.Stitch{Namespace: "ns", AdminACL: []string{"acl-0"}}
+	assert.Equal(t, oldest.String(), deployments[0].Spec)
+
+	// Each namespace has its own history.  Redeploying a namespace's most recent
+	// stitch isn't recorded again even if another namespace was deployed to since,
+	// and deployments to one namespace don't evict those of another.
+	last := fmt.Sprintf("acl-%d", deploymentHistorySize-1)
+	deployTo("other", last)
+	deploy(last)
+	for i := 0; i < deploymentHistorySize; i++ {
+		deployTo("other", fmt.Sprintf("other-%d", i))
+	}
+
+	deployments = conn.SelectFromDeployment(func(d db.Deployment) bool {
+		return d.Namespace == "ns"
+	})
+	assert.Len(t, deployments, deploymentHistorySize)
+	assert.Equal(t, oldest.String(), deployments[0].Spec)
+
+	deployments = conn.SelectFromDeployment(func(d db.Deployment) bool {
+		return d.Namespace == "other"
+	})
+	assert.Len(t, deployments, deploymentHistorySize)
+	for _, d := range deployments {
+		stc, err := stitch.FromJSON(d.Spec)
+		assert.NoError(t, err)
+		assert.Equal(t, "other", stc.Namespace)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	reply, err := server{}.Version(context.Background(), &pb.VersionRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, version.Version, reply.Version)
+	assert.Equal(t, int32(version.Schema), reply.SchemaVersion)
+}
+
 func TestVagrantDeployment(t *testing.T) {
 	conn := db.New()
 	s := server{conn: conn}
@@ -170,7 +450,7 @@ func TestVagrantDeployment(t *testing.T) {
 
 	var spec string
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		clst, err := view.GetCluster()
+		clst, err := view.GetCluster("")
 		assert.NoError(t, err)
 		spec = clst.Spec
 		return nil
@@ -0,0 +1,581 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"google.golang.org/grpc/credentials"
+)
+
+// DaemonName is the name the daemon's certificate is issued for.  The minions'
+// certificates are instead issued for their machines' IP addresses, so that none of
+// them may pose as the daemon, or as each other.
+const DaemonName = "quilt-daemon"
+
+// The names of the files in a credentials directory.
+const (
+	CACertFile = "ca.crt"
+	CertFile   = "quilt.crt"
+	KeyFile    = "quilt.key"
+
+	// Only the daemon's directory holds the key of the CA.
+	caKeyFile = "ca.key"
+)
+
+// MinionDir is the directory in which minions find their credentials.
+const MinionDir = "/etc/quilt/tls"
+
+// How long certificates remain valid.
+const validity = 10 * 365 * 24 * time.Hour
+
+// DefaultDir returns the directory in which the daemon keeps its CA and
+// credentials, and in which the CLI looks for them.
+func DefaultDir() string {
+	dir, err := homedir.Dir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, ".quilt", "tls")
+}
+
+// A Role is the side of a connection that a certificate may authenticate.
+type Role int
+
+const (
+	// Server certificates authenticate the services that peers connect to.
+	Server Role = 1 << iota
+
+	// Client certificates authenticate the peers that connect to services.
+	Client
+)
+
+// A CA is the certificate authority that signs the certificates of the daemon and
+// the machines it boots.
+type CA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+}
+
+// Credentials are the PEM encoded certificate and key of a single party, along with
+// the certificate of the CA that signed it.
+type Credentials struct {
+	CACert string
+	Cert   string
+	Key    string
+}
+
+// NewCA creates a new, self-signed, certificate authority.
+func NewCA() (CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return CA{}, err
+	}
+
+	template, err := newTemplate("Quilt CA")
+	if err != nil {
+		return CA{}, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	if err != nil {
+		return CA{}, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return CA{}, err
+	}
+
+	return CA{cert, key, encodePEM("CERTIFICATE", der)}, nil
+}
+
+// CertPEM returns the PEM encoded certificate of the CA.
+func (ca CA) CertPEM() string {
+	return ca.certPEM
+}
+
+// LoadOrCreateCA loads the CA stored in 'dir'.  If there is none, a new one is
+// created and saved there.
+func LoadOrCreateCA(dir string) (CA, error) {
+	certPEM, err := ioutil.ReadFile(filepath.Join(dir, CACertFile))
+	if os.IsNotExist(err) {
+		return createCA(dir)
+	} else if err != nil {
+		return CA{}, err
+	}
+
+	keyPEM, err := ioutil.ReadFile(filepath.Join(dir, caKeyFile))
+	if err != nil {
+		return CA{}, err
+	}
+
+	cert, err := parseCert(string(certPEM))
+	if err != nil {
+		return CA{}, err
+	}
+
+	key, err := parseKey(string(keyPEM))
+	if err != nil {
+		return CA{}, err
+	}
+
+	return CA{cert, key, string(certPEM)}, nil
+}
+
+func createCA(dir string) (CA, error) {
+	ca, err := NewCA()
+	if err != nil {
+		return CA{}, err
+	}
+
+	keyPEM, err := encodeKey(ca.key)
+	if err != nil {
+		return CA{}, err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return CA{}, err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, caKeyFile), []byte(keyPEM), 0600)
+	if err != nil {
+		return CA{}, err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, CACertFile), []byte(ca.certPEM),
+		0644)
+	return ca, err
+}
+
+// Issue creates a new key, and a certificate for it signed by the CA.  The
+// certificate is only valid for the sides of a connection given by 'role', and if
+// it's a Server certificate, for the server called 'name'.  The minions don't use
+// Issue, as their keys must never leave their machines.  See Certify() instead.
+func (ca CA) Issue(name string, role Role) (Credentials, error) {
+	if ca.key == nil {
+		return Credentials{}, errors.New("uninitialized CA")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	template, err := newTemplate(name)
+	if err != nil {
+		return Credentials{}, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	if role&Server != 0 {
+		template.DNSNames = []string{name}
+		template.ExtKeyUsage = append(template.ExtKeyUsage,
+			x509.ExtKeyUsageServerAuth)
+	}
+	if role&Client != 0 {
+		template.ExtKeyUsage = append(template.ExtKeyUsage,
+			x509.ExtKeyUsageClientAuth)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert,
+		&key.PublicKey, ca.key)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	return Credentials{
+		CACert: ca.certPEM,
+		Cert:   encodePEM("CERTIFICATE", der),
+		Key:    keyPEM,
+	}, nil
+}
+
+// Certify returns a certificate, signed by the CA, for the minion whose key is 'key'.
+// The certificate is only valid for the Server role, and for the IP addresses 'ips'.
+// The minions' credentials are readable by the containers on their machines, so
+// they're never issued the Client role, lest a compromised machine pose as a client
+// of the daemon or of the other minions.
+func (ca CA) Certify(key crypto.PublicKey, ips []string) (string, error) {
+	if ca.key == nil {
+		return "", errors.New("uninitialized CA")
+	}
+
+	template, err := newTemplate("minion")
+	if err != nil {
+		return "", err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	for _, ipStr := range ips {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return "", fmt.Errorf("malformed IP address: %s", ipStr)
+		}
+		template.IPAddresses = append(template.IPAddresses, ip)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key, ca.key)
+	if err != nil {
+		return "", err
+	}
+	return encodePEM("CERTIFICATE", der), nil
+}
+
+// VerifyMinion returns an error unless 'cert' was issued by Certify() for a minion
+// with the IP addresses 'ips'.
+func (ca CA) VerifyMinion(cert *x509.Certificate, ips []string) error {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:     pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, ip := range ips {
+		if err := cert.VerifyHostname(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads the credentials stored in 'dir'.
+func Load(dir string) (Credentials, error) {
+	var creds Credentials
+	for name, dst := range map[string]*string{
+		CACertFile: &creds.CACert,
+		CertFile:   &creds.Cert,
+		KeyFile:    &creds.Key,
+	} {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return Credentials{}, err
+		}
+		*dst = string(contents)
+	}
+	return creds, nil
+}
+
+// Save writes the credentials to 'dir', creating it if necessary.
+func (creds Credentials) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	for _, file := range []struct {
+		name     string
+		contents string
+		mode     os.FileMode
+	}{
+		{CACertFile, creds.CACert, 0644},
+		{CertFile, creds.Cert, 0644},
+		{KeyFile, creds.Key, 0600},
+	} {
+		path := filepath.Join(dir, file.name)
+		err := ioutil.WriteFile(path, []byte(file.contents), file.mode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServerCredentials returns gRPC credentials that present our certificate, and
+// require clients to present a certificate signed by our CA.
+func (creds Credentials) ServerCredentials() (credentials.TransportCredentials,
+	error) {
+
+	config, err := creds.ServerTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// ClientCredentials returns gRPC credentials that present our certificate, and
+// require servers to present a certificate signed by our CA for 'serverName'.  If
+// 'serverName' is empty, the server's certificate must be issued for the host that
+// was dialed, as is the case for the minions.
+func (creds Credentials) ClientCredentials(serverName string) (
+	credentials.TransportCredentials, error) {
+
+	config, err := creds.ClientTLSConfig(serverName)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// LoadClientCredentials loads the credentials stored in 'dir' for use by a gRPC
+// client of the server called 'serverName'.
+func LoadClientCredentials(dir, serverName string) (credentials.TransportCredentials,
+	error) {
+
+	creds, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	return creds.ClientCredentials(serverName)
+}
+
+// ServerTLSConfig returns the TLS configuration underlying ServerCredentials(), for
+// use by servers that don't speak gRPC.
+func (creds Credentials) ServerTLSConfig() (*tls.Config, error) {
+	cert, pool, err := creds.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientTLSConfig returns the TLS configuration underlying ClientCredentials().
+func (creds Credentials) ClientTLSConfig(serverName string) (*tls.Config, error) {
+	cert, pool, err := creds.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   serverName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// MinionCredentials are the credentials of a minion.  The minion generates its key
+// itself, so that it never leaves the machine, and the foreman then certifies it with
+// SetCertificate().  Until then, the minion presents a self-signed certificate, which
+// only the foreman accepts, and only to certify it.
+type MinionCredentials struct {
+	dir  string
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+
+	mutex sync.Mutex
+	cert  tls.Certificate
+}
+
+// LoadMinionCredentials loads the minion credentials stored in 'dir', which must hold
+// the certificate of the CA.  If there's no key yet, a new one is generated and saved
+// there.
+func LoadMinionCredentials(dir string) (*MinionCredentials, error) {
+	caPEM, err := ioutil.ReadFile(filepath.Join(dir, CACertFile))
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("malformed CA certificate")
+	}
+
+	key, err := loadOrCreateKey(filepath.Join(dir, KeyFile))
+	if err != nil {
+		return nil, err
+	}
+
+	mc := &MinionCredentials{dir: dir, key: key, pool: pool}
+	certPEM, err := ioutil.ReadFile(filepath.Join(dir, CertFile))
+	if err == nil {
+		mc.cert, err = mc.parseCert(string(certPEM))
+	}
+
+	// Without a valid certificate, the minion waits to be certified.
+	if err != nil {
+		mc.cert, err = selfSign(key)
+	}
+	return mc, err
+}
+
+// SetCertificate replaces the minion's certificate with 'certPEM', and saves it.  The
+// certificate must be signed by the CA for the minion's key.
+func (mc *MinionCredentials) SetCertificate(certPEM string) error {
+	cert, err := mc.parseCert(certPEM)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(mc.dir, CertFile), []byte(certPEM), 0644)
+	if err != nil {
+		return err
+	}
+
+	mc.mutex.Lock()
+	mc.cert = cert
+	mc.mutex.Unlock()
+	return nil
+}
+
+// ServerTLSConfig returns a TLS configuration that presents the minion's latest
+// certificate, and requires clients to present a certificate signed by the CA.
+func (mc *MinionCredentials) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			mc.mutex.Lock()
+			defer mc.mutex.Unlock()
+			cert := mc.cert
+			return &cert, nil
+		},
+		ClientCAs:  mc.pool,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// ServerCredentials returns the gRPC credentials underlying ServerTLSConfig().
+func (mc *MinionCredentials) ServerCredentials() credentials.TransportCredentials {
+	return credentials.NewTLS(mc.ServerTLSConfig())
+}
+
+func (mc *MinionCredentials) parseCert(certPEM string) (tls.Certificate, error) {
+	cert, err := parseCert(certPEM)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:     mc.pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || pub.X.Cmp(mc.key.X) != 0 || pub.Y.Cmp(mc.key.Y) != 0 {
+		return tls.Certificate{}, errors.New("certificate doesn't match key")
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{cert.Raw},
+		PrivateKey:  mc.key,
+		Leaf:        cert,
+	}, nil
+}
+
+func loadOrCreateKey(path string) (*ecdsa.PrivateKey, error) {
+	keyPEM, err := ioutil.ReadFile(path)
+	if err == nil {
+		return parseKey(string(keyPEM))
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return key, ioutil.WriteFile(path, []byte(encoded), 0600)
+}
+
+func selfSign(key *ecdsa.PrivateKey) (tls.Certificate, error) {
+	template, err := newTemplate("uncertified minion")
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+func (creds Credentials) parse() (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.X509KeyPair([]byte(creds.Cert), []byte(creds.Key))
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(creds.CACert)) {
+		return tls.Certificate{}, nil, errors.New("malformed CA certificate")
+	}
+	return cert, pool, nil
+}
+
+func newTemplate(name string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    now.Add(-time.Hour), // Tolerate clock skew.
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+func encodePEM(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func encodeKey(key *ecdsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return encodePEM("EC PRIVATE KEY", der), nil
+}
+
+func parseCert(certPEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return nil, fmt.Errorf("malformed certificate: %s", certPEM)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseKey(keyPEM string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, errors.New("malformed key")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
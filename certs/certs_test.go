@@ -0,0 +1,197 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadOrCreateCA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quilt-certs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca, err := LoadOrCreateCA(dir)
+	assert.NoError(t, err)
+
+	loaded, err := LoadOrCreateCA(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, ca.certPEM, loaded.certPEM)
+	assert.Equal(t, ca.key, loaded.key)
+
+	creds, err := loaded.Issue(DaemonName, Server|Client)
+	assert.NoError(t, err)
+	assert.Equal(t, ca.certPEM, creds.CACert)
+
+	assert.NoError(t, creds.Save(dir))
+	saved, err := Load(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, creds, saved)
+}
+
+func TestIssueUninitialized(t *testing.T) {
+	_, err := CA{}.Issue(DaemonName, Server)
+	assert.EqualError(t, err, "uninitialized CA")
+
+	_, err = CA{}.Certify(nil, nil)
+	assert.EqualError(t, err, "uninitialized CA")
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca, err := NewCA()
+	assert.NoError(t, err)
+
+	serverCreds, err := ca.Issue(DaemonName, Server)
+	assert.NoError(t, err)
+
+	clientCreds, err := ca.Issue("foreman", Client)
+	assert.NoError(t, err)
+
+	// Credentials from a different CA.
+	otherCA, err := NewCA()
+	assert.NoError(t, err)
+	otherCreds, err := otherCA.Issue(DaemonName, Server|Client)
+	assert.NoError(t, err)
+
+	assert.NoError(t, handshake(t, serverCreds, clientCreds))
+	assert.Error(t, handshake(t, serverCreds, otherCreds))
+	assert.Error(t, handshake(t, otherCreds, clientCreds))
+
+	// Certificates are only valid for the roles they were issued.
+	assert.Error(t, handshake(t, serverCreds, serverCreds))
+	assert.Error(t, handshake(t, clientCreds, clientCreds))
+
+	// Clients without a certificate are rejected.
+	serverConfig, err := serverCreds.ServerTLSConfig()
+	assert.NoError(t, err)
+	clientConfig, err := clientCreds.ClientTLSConfig(DaemonName)
+	assert.NoError(t, err)
+	clientConfig.Certificates = nil
+	assert.Error(t, handshakeConfig(serverConfig, clientConfig))
+
+	// Servers must present a certificate issued for the name the client expects.
+	clientConfig, err = clientCreds.ClientTLSConfig("other")
+	assert.NoError(t, err)
+	assert.Error(t, handshakeConfig(serverConfig, clientConfig))
+
+	// A certificate doesn't match another party's key.
+	mismatched := clientCreds
+	mismatched.Key = otherCreds.Key
+	_, err = mismatched.ClientCredentials(DaemonName)
+	assert.Error(t, err)
+}
+
+func TestMinionCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quilt-certs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca, err := NewCA()
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, CACertFile),
+		[]byte(ca.certPEM), 0644))
+
+	mc, err := LoadMinionCredentials(dir)
+	assert.NoError(t, err)
+
+	clientCreds, err := ca.Issue("foreman", Client)
+	assert.NoError(t, err)
+	clientConfig, err := clientCreds.ClientTLSConfig("")
+	assert.NoError(t, err)
+	clientConfig.ServerName = "8.8.8.8"
+
+	// Uncertified minions present a certificate that isn't signed by the CA.
+	assert.Error(t, handshakeConfig(mc.ServerTLSConfig(), clientConfig))
+	uncertified := parseLeaf(t, mc)
+	assert.Error(t, ca.VerifyMinion(uncertified, []string{"8.8.8.8"}))
+
+	// Certificates must be issued for the minion's own key.
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherCert, err := ca.Certify(&otherKey.PublicKey, []string{"8.8.8.8"})
+	assert.NoError(t, err)
+	assert.EqualError(t, mc.SetCertificate(otherCert),
+		"certificate doesn't match key")
+
+	certPEM, err := ca.Certify(uncertified.PublicKey,
+		[]string{"8.8.8.8", "10.0.0.1"})
+	assert.NoError(t, err)
+	assert.NoError(t, mc.SetCertificate(certPEM))
+	assert.NoError(t, handshakeConfig(mc.ServerTLSConfig(), clientConfig))
+
+	certified := parseLeaf(t, mc)
+	assert.NoError(t, ca.VerifyMinion(certified, []string{"8.8.8.8", "10.0.0.1"}))
+	assert.Error(t, ca.VerifyMinion(certified, []string{"8.8.4.4"}))
+
+	// The minion's certificate can't be used for any other address.
+	clientConfig.ServerName = "8.8.4.4"
+	assert.Error(t, handshakeConfig(mc.ServerTLSConfig(), clientConfig))
+
+	// The key and certificate persist across restarts.
+	reloaded, err := LoadMinionCredentials(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, mc.key, reloaded.key)
+	assert.Equal(t, certified.Raw, parseLeaf(t, reloaded).Raw)
+}
+
+func parseLeaf(t *testing.T, mc *MinionCredentials) *x509.Certificate {
+	cert, err := mc.ServerTLSConfig().GetCertificate(nil)
+	assert.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	return leaf
+}
+
+func handshake(t *testing.T, serverCreds, clientCreds Credentials) error {
+	serverConfig, err := serverCreds.ServerTLSConfig()
+	assert.NoError(t, err)
+
+	clientConfig, err := clientCreds.ClientTLSConfig(DaemonName)
+	assert.NoError(t, err)
+
+	return handshakeConfig(serverConfig, clientConfig)
+}
+
+func handshakeConfig(serverConfig, clientConfig *tls.Config) error {
+	// Unlike net.Pipe(), a loopback connection is buffered, so a party that rejects
+	// the other's certificate can send an alert while the other is still writing.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+
+	serverConn, err := listener.Accept()
+	if err != nil {
+		return err
+	}
+	defer serverConn.Close()
+
+	serverErr := make(chan error)
+	go func() {
+		serverErr <- tls.Server(serverConn, serverConfig).Handshake()
+		serverConn.Close()
+	}()
+
+	clientErr := tls.Client(clientConn, clientConfig).Handshake()
+	clientConn.Close()
+	if err := <-serverErr; err != nil {
+		return err
+	}
+	return clientErr
+}
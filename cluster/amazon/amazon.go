@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,8 +30,10 @@ type Cluster struct {
 	newClient func(string) client
 }
 
+// awsID identifies a machine: by its spot request ID if it's a spot instance, and by
+// its instance ID if it's an on-demand instance.
 type awsID struct {
-	spotID string
+	id     string
 	region string
 }
 
@@ -41,6 +44,7 @@ const DefaultRegion = "us-west-1"
 // Regions is the list of supported AWS regions.
 var Regions = []string{"ap-southeast-2", "us-west-1", "us-west-2"}
 
+// The default bid for spot instances.
 const spotPrice = "0.5"
 
 // Ubuntu 16.04, 64-bit hvm-ssd
@@ -83,22 +87,26 @@ func (clst *Cluster) Boot(bootSet []machine.Machine) error {
 	}
 
 	type bootReq struct {
-		cfg      string
-		size     string
-		diskSize int
+		cfg         string
+		size        string
+		diskSize    int
+		preemptible bool
+		spotPrice   float64
 	}
 
 	bootReqMap := make(map[bootReq]int64) // From boot request to an instance count.
 	for _, m := range bootSet {
 		br := bootReq{
-			cfg:      cloudcfg.Ubuntu(m.SSHKeys, "xenial"),
-			size:     m.Size,
-			diskSize: m.DiskSize,
+			cfg:         cloudcfg.Ubuntu(m.SSHKeys, "xenial", m.CACert),
+			size:        m.Size,
+			diskSize:    m.DiskSize,
+			preemptible: m.Preemptible,
+			spotPrice:   m.SpotPrice,
 		}
 		bootReqMap[br] = bootReqMap[br] + 1
 	}
 
-	var awsIDs []awsID
+	var spotIDs, instIDs []awsID
 	for br, count := range bootReqMap {
 		groupID, _, err := clst.getCreateSecurityGroup()
 		if err != nil {
@@ -106,9 +114,38 @@ func (clst *Cluster) Boot(bootSet []machine.Machine) error {
 		}
 
 		cloudConfig64 := base64.StdEncoding.EncodeToString([]byte(br.cfg))
+		if !br.preemptible {
+			resp, err := clst.client.RunInstances(&ec2.RunInstancesInput{
+				ImageId:          aws.String(amis[clst.region]),
+				InstanceType:     aws.String(br.size),
+				UserData:         &cloudConfig64,
+				SecurityGroupIds: []*string{aws.String(groupID)},
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+					blockDevice(br.diskSize),
+				},
+				MinCount: &count,
+				MaxCount: &count,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, inst := range resp.Instances {
+				instIDs = append(instIDs, awsID{
+					id:     *inst.InstanceId,
+					region: clst.region})
+			}
+			continue
+		}
+
+		price := spotPrice
+		if br.spotPrice != 0 {
+			price = strconv.FormatFloat(br.spotPrice, 'f', -1, 64)
+		}
+
 		resp, err := clst.client.RequestSpotInstances(
 			&ec2.RequestSpotInstancesInput{
-				SpotPrice: aws.String(spotPrice),
+				SpotPrice: aws.String(price),
 				LaunchSpecification: &ec2.RequestSpotLaunchSpecification{
 					ImageId:          aws.String(amis[clst.region]),
 					InstanceType:     aws.String(br.size),
@@ -126,17 +163,19 @@ func (clst *Cluster) Boot(bootSet []machine.Machine) error {
 		}
 
 		for _, request := range resp.SpotInstanceRequests {
-			awsIDs = append(awsIDs, awsID{
-				spotID: *request.SpotInstanceRequestId,
+			spotIDs = append(spotIDs, awsID{
+				id:     *request.SpotInstanceRequestId,
 				region: clst.region})
 		}
 	}
 
-	if err := clst.tagSpotRequests(awsIDs); err != nil {
-		return err
+	if len(spotIDs) > 0 {
+		if err := clst.tagSpotRequests(spotIDs); err != nil {
+			return err
+		}
 	}
 
-	return clst.wait(awsIDs, true)
+	return clst.wait(append(spotIDs, instIDs...), true)
 }
 
 // Stop shuts down `machines` in `clst.
@@ -144,31 +183,38 @@ func (clst *Cluster) Stop(machines []machine.Machine) error {
 	clst.connectClient()
 
 	var ids []awsID
+	var spotIDs, instIds []string
 	for _, m := range machines {
 		ids = append(ids, awsID{
 			region: m.Region,
-			spotID: m.ID,
+			id:     m.ID,
 		})
-	}
 
-	spotIDs := getSpotIDs(ids)
-	spots, err := clst.client.DescribeSpotInstanceRequests(
-		&ec2.DescribeSpotInstanceRequestsInput{
-			SpotInstanceRequestIds: aws.StringSlice(spotIDs),
-		})
-	if err != nil {
-		return err
+		if m.Preemptible {
+			spotIDs = append(spotIDs, m.ID)
+		} else {
+			instIds = append(instIds, m.ID)
+		}
 	}
 
-	instIds := []string{}
-	for _, spot := range spots.SpotInstanceRequests {
-		if spot.InstanceId != nil {
-			instIds = append(instIds, *spot.InstanceId)
+	if len(spotIDs) > 0 {
+		spots, err := clst.client.DescribeSpotInstanceRequests(
+			&ec2.DescribeSpotInstanceRequestsInput{
+				SpotInstanceRequestIds: aws.StringSlice(spotIDs),
+			})
+		if err != nil {
+			return err
+		}
+
+		for _, spot := range spots.SpotInstanceRequests {
+			if spot.InstanceId != nil {
+				instIds = append(instIds, *spot.InstanceId)
+			}
 		}
 	}
 
 	if len(instIds) > 0 {
-		_, err = clst.client.TerminateInstances(&ec2.TerminateInstancesInput{
+		_, err := clst.client.TerminateInstances(&ec2.TerminateInstancesInput{
 			InstanceIds: aws.StringSlice(instIds),
 		})
 		if err != nil {
@@ -176,12 +222,14 @@ func (clst *Cluster) Stop(machines []machine.Machine) error {
 		}
 	}
 
-	_, err = clst.client.CancelSpotInstanceRequests(
-		&ec2.CancelSpotInstanceRequestsInput{
-			SpotInstanceRequestIds: aws.StringSlice(spotIDs),
-		})
-	if err != nil {
-		return err
+	if len(spotIDs) > 0 {
+		_, err := clst.client.CancelSpotInstanceRequests(
+			&ec2.CancelSpotInstanceRequestsInput{
+				SpotInstanceRequestIds: aws.StringSlice(spotIDs),
+			})
+		if err != nil {
+			return err
+		}
 	}
 
 	if err := clst.wait(ids, false); err != nil {
@@ -271,63 +319,98 @@ func (clst *Cluster) List() ([]machine.Machine, error) {
 		}
 
 		machine := machine.Machine{
-			ID:       *spot.SpotInstanceRequestId,
-			Region:   clst.region,
-			Provider: db.Amazon,
+			ID:          *spot.SpotInstanceRequestId,
+			Region:      clst.region,
+			Provider:    db.Amazon,
+			Preemptible: true,
 		}
 
 		if inst != nil {
-			if *inst.State.Name != ec2.InstanceStateNamePending &&
-				*inst.State.Name != ec2.InstanceStateNameRunning {
+			if !isLive(inst) {
 				continue
 			}
 
-			if inst.PublicIpAddress != nil {
-				machine.PublicIP = *inst.PublicIpAddress
+			err := clst.describeInstance(&machine, inst, ipMap)
+			if err != nil {
+				return nil, err
 			}
+		}
 
-			if inst.PrivateIpAddress != nil {
-				machine.PrivateIP = *inst.PrivateIpAddress
+		machines = append(machines, machine)
+	}
+
+	// On-demand instances have no spot request, so they are identified by their
+	// instance ID.
+	for _, res := range insts.Reservations {
+		for _, inst := range res.Instances {
+			if inst.SpotInstanceRequestId != nil || !isLive(inst) {
+				continue
 			}
 
-			if inst.InstanceType != nil {
-				machine.Size = *inst.InstanceType
+			machine := machine.Machine{
+				ID:       *inst.InstanceId,
+				Region:   clst.region,
+				Provider: db.Amazon,
+			}
+			err := clst.describeInstance(&machine, inst, ipMap)
+			if err != nil {
+				return nil, err
 			}
+			machines = append(machines, machine)
+		}
+	}
 
-			if len(inst.BlockDeviceMappings) != 0 {
-				volumeID := inst.BlockDeviceMappings[0].
-					Ebs.VolumeId
-				filters := []*ec2.Filter{
-					{
-						Name: aws.String("volume-id"),
-						Values: []*string{
-							aws.String(*volumeID),
-						},
-					},
-				}
+	return machines, nil
+}
 
-				volumeInfo, err := clst.client.DescribeVolumes(
-					&ec2.DescribeVolumesInput{
-						Filters: filters,
-					})
-				if err != nil {
-					return nil, err
-				}
-				if len(volumeInfo.Volumes) == 1 {
-					machine.DiskSize = int(
-						*volumeInfo.Volumes[0].Size)
-				}
-			}
+// isLive returns whether `inst` is booting or running.
+func isLive(inst *ec2.Instance) bool {
+	return *inst.State.Name == ec2.InstanceStateNamePending ||
+		*inst.State.Name == ec2.InstanceStateNameRunning
+}
 
-			if ip := ipMap[*inst.InstanceId]; ip != nil {
-				machine.FloatingIP = *ip.PublicIp
-			}
+// describeInstance fills in the attributes of `m` that are reported by its instance,
+// `inst`.  `ipMap` maps instance IDs to their Elastic IPs.
+func (clst *Cluster) describeInstance(m *machine.Machine, inst *ec2.Instance,
+	ipMap map[string]*ec2.Address) error {
+
+	if inst.PublicIpAddress != nil {
+		m.PublicIP = *inst.PublicIpAddress
+	}
+
+	if inst.PrivateIpAddress != nil {
+		m.PrivateIP = *inst.PrivateIpAddress
+	}
+
+	if inst.InstanceType != nil {
+		m.Size = *inst.InstanceType
+	}
+
+	if len(inst.BlockDeviceMappings) != 0 {
+		volumeID := inst.BlockDeviceMappings[0].Ebs.VolumeId
+		filters := []*ec2.Filter{
+			{
+				Name:   aws.String("volume-id"),
+				Values: []*string{aws.String(*volumeID)},
+			},
 		}
 
-		machines = append(machines, machine)
+		volumeInfo, err := clst.client.DescribeVolumes(
+			&ec2.DescribeVolumesInput{
+				Filters: filters,
+			})
+		if err != nil {
+			return err
+		}
+		if len(volumeInfo.Volumes) == 1 {
+			m.DiskSize = int(*volumeInfo.Volumes[0].Size)
+		}
 	}
 
-	return machines, nil
+	if ip := ipMap[*inst.InstanceId]; ip != nil {
+		m.FloatingIP = *ip.PublicIp
+	}
+	return nil
 }
 
 // UpdateFloatingIPs updates Elastic IPs <> EC2 instance associations.
@@ -349,20 +432,36 @@ func (clst *Cluster) UpdateFloatingIPs(machines []machine.Machine) error {
 		}
 	}
 
-	// Map spot request ID to EC2 instance ID.
+	// Map spot request ID to EC2 instance ID.  On-demand machines are already
+	// identified by their instance ID.
 	var spotIDs []string
 	for _, machine := range machines {
-		spotIDs = append(spotIDs, machine.ID)
+		if machine.Preemptible {
+			spotIDs = append(spotIDs, machine.ID)
+		}
 	}
-	instances, err := clst.getInstances(clst.region, spotIDs)
-	if err != nil {
-		return err
+
+	instances := map[string]*ec2.Instance{}
+	if len(spotIDs) > 0 {
+		instances, err = clst.getInstances(clst.region, spotIDs)
+		if err != nil {
+			return err
+		}
 	}
 
 	for _, machine := range machines {
+		instanceID := aws.String(machine.ID)
+		if machine.Preemptible {
+			inst := instances[machine.ID]
+			if inst == nil {
+				return fmt.Errorf("spot request %s has no instance",
+					machine.ID)
+			}
+			instanceID = inst.InstanceId
+		}
+
 		if machine.FloatingIP == "" {
-			instanceID := *instances[machine.ID].InstanceId
-			associationID := associations[instanceID]
+			associationID := associations[*instanceID]
 			if associationID == nil {
 				continue
 			}
@@ -377,7 +476,7 @@ func (clst *Cluster) UpdateFloatingIPs(machines []machine.Machine) error {
 		} else {
 			allocationID := addresses[machine.FloatingIP]
 			input := ec2.AssociateAddressInput{
-				InstanceId:   instances[machine.ID].InstanceId,
+				InstanceId:   instanceID,
 				AllocationId: allocationID,
 			}
 			if _, err := clst.client.AssociateAddress(&input); err != nil {
@@ -437,7 +536,7 @@ func (clst Cluster) getInstances(region string, spotIDs []string) (
 
 func (clst *Cluster) tagSpotRequests(awsIDs []awsID) error {
 	var err error
-	spotIDs := getSpotIDs(awsIDs)
+	spotIDs := getIDs(awsIDs)
 	for i := 0; i < 30; i++ {
 		_, err = clst.client.CreateTags(&ec2.CreateTagsInput{
 			Tags: []*ec2.Tag{
@@ -485,7 +584,7 @@ func (clst *Cluster) wait(awsIDs []awsID, boot bool) error {
 			}
 
 			id := awsID{
-				spotID: inst.ID,
+				id:     inst.ID,
 				region: inst.Region,
 			}
 			exists[id] = struct{}{}
@@ -518,7 +617,7 @@ func (clst *Cluster) isDoneWaiting(awsIDs []awsID, boot bool) (bool, error) {
 		}
 
 		id := awsID{
-			spotID: inst.ID,
+			id:     inst.ID,
 			region: inst.Region,
 		}
 		exists[id] = struct{}{}
@@ -748,13 +847,13 @@ func blockDevice(diskSize int) *ec2.BlockDeviceMapping {
 	}
 }
 
-func getSpotIDs(ids []awsID) []string {
-	var spotIDs []string
+func getIDs(ids []awsID) []string {
+	var strs []string
 	for _, id := range ids {
-		spotIDs = append(spotIDs, id.spotID)
+		strs = append(strs, id.id)
 	}
 
-	return spotIDs
+	return strs
 }
 
 func groupByRegion(ids []awsID) map[string][]awsID {
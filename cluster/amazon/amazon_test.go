@@ -47,6 +47,24 @@ func TestList(t *testing.T) {
 				Name: aws.String(ec2.InstanceStateNameRunning),
 			},
 		},
+		// A booted on-demand instance.
+		{
+			InstanceId:       aws.String("inst3"),
+			PublicIpAddress:  aws.String("publicIP3"),
+			PrivateIpAddress: aws.String("privateIP3"),
+			InstanceType:     aws.String("size3"),
+			State: &ec2.InstanceState{
+				Name: aws.String(ec2.InstanceStateNamePending),
+			},
+		},
+		// A terminated on-demand instance.
+		{
+			InstanceId:   aws.String("inst4"),
+			InstanceType: aws.String("size4"),
+			State: &ec2.InstanceState{
+				Name: aws.String(ec2.InstanceStateNameTerminated),
+			},
+		},
 	}
 	mc.On("DescribeInstances", mock.Anything).Return(
 		&ec2.DescribeInstancesOutput{
@@ -140,24 +158,35 @@ func TestList(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, []machine.Machine{
 		{
-			ID:        "spot1",
-			Provider:  db.Amazon,
-			PublicIP:  "publicIP",
-			PrivateIP: "privateIP",
-			Size:      "size",
-			Region:    DefaultRegion,
+			ID:          "spot1",
+			Provider:    db.Amazon,
+			PublicIP:    "publicIP",
+			PrivateIP:   "privateIP",
+			Size:        "size",
+			Region:      DefaultRegion,
+			Preemptible: true,
 		},
 		{
-			ID:         "spot2",
-			Provider:   db.Amazon,
-			Region:     DefaultRegion,
-			Size:       "size2",
-			FloatingIP: "xx.xxx.xxx.xxx",
+			ID:          "spot2",
+			Provider:    db.Amazon,
+			Region:      DefaultRegion,
+			Size:        "size2",
+			FloatingIP:  "xx.xxx.xxx.xxx",
+			Preemptible: true,
 		},
 		{
-			ID:       "spot3",
-			Provider: db.Amazon,
-			Region:   DefaultRegion,
+			ID:          "spot3",
+			Provider:    db.Amazon,
+			Region:      DefaultRegion,
+			Preemptible: true,
+		},
+		{
+			ID:        "inst3",
+			Provider:  db.Amazon,
+			PublicIP:  "publicIP3",
+			PrivateIP: "privateIP3",
+			Size:      "size3",
+			Region:    DefaultRegion,
 		},
 	}, spots)
 }
@@ -418,19 +447,21 @@ func TestBoot(t *testing.T) {
 
 	err := amazonCluster.Boot([]machine.Machine{
 		{
-			Region:   DefaultRegion,
-			Size:     "m4.large",
-			DiskSize: 32,
+			Region:      DefaultRegion,
+			Size:        "m4.large",
+			DiskSize:    32,
+			Preemptible: true,
 		},
 		{
-			Region:   DefaultRegion,
-			Size:     "m4.large",
-			DiskSize: 32,
+			Region:      DefaultRegion,
+			Size:        "m4.large",
+			DiskSize:    32,
+			Preemptible: true,
 		},
 	})
 	assert.Nil(t, err)
 
-	cfg := cloudcfg.Ubuntu(nil, "xenial")
+	cfg := cloudcfg.Ubuntu(nil, "xenial", "")
 	mc.AssertCalled(t, "RequestSpotInstances",
 		&ec2.RequestSpotInstancesInput{
 			SpotPrice: aws.String(spotPrice),
@@ -459,6 +490,127 @@ func TestBoot(t *testing.T) {
 	)
 }
 
+func TestBootOnDemand(t *testing.T) {
+	t.Parallel()
+
+	mc := new(mockClient)
+	mc.On("DescribeSecurityGroups", mock.Anything).Return(
+		&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{
+				{
+					GroupId: aws.String("groupId"),
+				},
+			},
+		}, nil,
+	)
+	mc.On("RunInstances", mock.Anything).Return(
+		&ec2.Reservation{
+			Instances: []*ec2.Instance{
+				{
+					InstanceId: aws.String("inst1"),
+				},
+			},
+		}, nil,
+	)
+	mc.On("RequestSpotInstances", mock.Anything).Return(
+		&ec2.RequestSpotInstancesOutput{
+			SpotInstanceRequests: []*ec2.SpotInstanceRequest{
+				{
+					SpotInstanceRequestId: aws.String("spot1"),
+				},
+			},
+		}, nil,
+	)
+	mc.On("CreateTags", mock.Anything).Return(
+		&ec2.CreateTagsOutput{}, nil,
+	)
+	running := &ec2.InstanceState{
+		Name: aws.String(ec2.InstanceStateNameRunning),
+	}
+	instances := []*ec2.Instance{
+		{
+			InstanceId:   aws.String("inst1"),
+			InstanceType: aws.String("m4.large"),
+			State:        running,
+		},
+		{
+			InstanceId:            aws.String("inst2"),
+			SpotInstanceRequestId: aws.String("spot1"),
+			InstanceType:          aws.String("m4.large"),
+			State:                 running,
+		},
+	}
+	mc.On("DescribeInstances", mock.Anything).Return(
+		&ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{
+					Instances: instances,
+				},
+			},
+		}, nil,
+	)
+	mc.On("DescribeAddresses", mock.Anything).Return(
+		&ec2.DescribeAddressesOutput{}, nil,
+	)
+	mc.On("DescribeSpotInstanceRequests", mock.Anything).Return(
+		&ec2.DescribeSpotInstanceRequestsOutput{
+			SpotInstanceRequests: []*ec2.SpotInstanceRequest{
+				{
+					InstanceId:            aws.String("inst2"),
+					SpotInstanceRequestId: aws.String("spot1"),
+					State: aws.String(ec2.SpotInstanceStateActive),
+				},
+			},
+		}, nil,
+	)
+
+	amazonCluster := newAmazon(testNamespace, DefaultRegion)
+	amazonCluster.newClient = func(region string) client {
+		return mc
+	}
+
+	err := amazonCluster.Boot([]machine.Machine{
+		{
+			Region:   DefaultRegion,
+			Size:     "m4.large",
+			DiskSize: 32,
+		},
+		{
+			Region:      DefaultRegion,
+			Size:        "m4.large",
+			DiskSize:    32,
+			Preemptible: true,
+			SpotPrice:   0.25,
+		},
+	})
+	assert.Nil(t, err)
+
+	cfg64 := base64.StdEncoding.EncodeToString([]byte(
+		cloudcfg.Ubuntu(nil, "xenial", "")))
+	mc.AssertCalled(t, "RunInstances", &ec2.RunInstancesInput{
+		ImageId:             aws.String(amis[DefaultRegion]),
+		InstanceType:        aws.String("m4.large"),
+		UserData:            aws.String(cfg64),
+		SecurityGroupIds:    aws.StringSlice([]string{"groupId"}),
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{blockDevice(32)},
+		MinCount:            aws.Int64(1),
+		MaxCount:            aws.Int64(1),
+	})
+	mc.AssertCalled(t, "RequestSpotInstances", mock.MatchedBy(
+		func(input *ec2.RequestSpotInstancesInput) bool {
+			return *input.SpotPrice == "0.25"
+		}))
+	mc.AssertCalled(t, "CreateTags", &ec2.CreateTagsInput{
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String(testNamespace),
+				Value: aws.String(""),
+			},
+		},
+		Resources: aws.StringSlice([]string{"spot1"}),
+	})
+}
+
 func TestStop(t *testing.T) {
 	t.Parallel()
 
@@ -509,19 +661,25 @@ func TestStop(t *testing.T) {
 
 	err := amazonCluster.Stop([]machine.Machine{
 		{
-			Region: DefaultRegion,
-			ID:     toStopIDs[0],
+			Region:      DefaultRegion,
+			ID:          toStopIDs[0],
+			Preemptible: true,
+		},
+		{
+			Region:      DefaultRegion,
+			ID:          toStopIDs[1],
+			Preemptible: true,
 		},
 		{
 			Region: DefaultRegion,
-			ID:     toStopIDs[1],
+			ID:     "inst3",
 		},
 	})
 	assert.Nil(t, err)
 
 	mc.AssertCalled(t, "TerminateInstances",
 		&ec2.TerminateInstancesInput{
-			InstanceIds: aws.StringSlice([]string{"inst1"}),
+			InstanceIds: aws.StringSlice([]string{"inst3", "inst1"}),
 		},
 	)
 
@@ -780,19 +938,27 @@ func TestUpdateFloatingIPs(t *testing.T) {
 	mockMachines := []machine.Machine{
 		// Quilt should assign "x.x.x.x" to sir-1.
 		{
-			ID:         "sir-1",
-			FloatingIP: "x.x.x.x",
+			ID:          "sir-1",
+			FloatingIP:  "x.x.x.x",
+			Preemptible: true,
 		},
 		// Quilt should disassociate all floating IPs from spot instance sir-2.
 		{
-			ID:         "sir-2",
-			FloatingIP: "",
+			ID:          "sir-2",
+			FloatingIP:  "",
+			Preemptible: true,
 		},
 		// Quilt is asked to disassociate floating IPs from sir-3. sir-3 no longer
 		// has IP associations, but Quilt should not error.
 		{
-			ID:         "sir-3",
-			FloatingIP: "",
+			ID:          "sir-3",
+			FloatingIP:  "",
+			Preemptible: true,
+		},
+		// Quilt should assign "w.w.w.w" to the on-demand instance i-5.
+		{
+			ID:         "i-5",
+			FloatingIP: "w.w.w.w",
 		},
 	}
 
@@ -816,6 +982,11 @@ func TestUpdateFloatingIPs(t *testing.T) {
 					PublicIp:   aws.String("z.z.z.z"),
 					InstanceId: aws.String("i-4"),
 				},
+				// Quilt should assign w.w.w.w to i-5.
+				{
+					AllocationId: aws.String("alloc-5"),
+					PublicIp:     aws.String("w.w.w.w"),
+				},
 			},
 		}, nil)
 
@@ -874,10 +1045,16 @@ func TestUpdateFloatingIPs(t *testing.T) {
 		AllocationId: aws.String("alloc-1"),
 	}).Return(nil, nil)
 
+	mockClient.On("AssociateAddress", &ec2.AssociateAddressInput{
+		InstanceId:   aws.String("i-5"),
+		AllocationId: aws.String("alloc-5"),
+	}).Return(nil, nil)
+
 	mockClient.On("DisassociateAddress", &ec2.DisassociateAddressInput{
 		AssociationId: aws.String("assoc-2"),
 	}).Return(nil, nil)
 
 	err := amazonCluster.UpdateFloatingIPs(mockMachines)
 	assert.Nil(t, err)
+	mockClient.AssertExpectations(t)
 }
@@ -39,6 +39,8 @@ type client interface {
 	RequestSpotInstances(*ec2.RequestSpotInstancesInput) (
 		*ec2.RequestSpotInstancesOutput, error)
 
+	RunInstances(*ec2.RunInstancesInput) (*ec2.Reservation, error)
+
 	AssociateAddress(*ec2.AssociateAddressInput) (*ec2.AssociateAddressOutput, error)
 
 	DescribeAddresses(*ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput,
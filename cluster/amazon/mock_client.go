@@ -307,6 +307,29 @@ func (_m *mockClient) RevokeSecurityGroupIngress(_a0 *ec2.RevokeSecurityGroupIng
 	return r0, r1
 }
 
+// RunInstances provides a mock function with given fields: _a0
+func (_m *mockClient) RunInstances(_a0 *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *ec2.Reservation
+	if rf, ok := ret.Get(0).(func(*ec2.RunInstancesInput) *ec2.Reservation); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.Reservation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*ec2.RunInstancesInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TerminateInstances provides a mock function with given fields: _a0
 func (_m *mockClient) TerminateInstances(_a0 *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
 	ret := _m.Called(_a0)
@@ -0,0 +1,480 @@
+package azure
+
+////// SET UP API ACCESS:
+//
+// 1) Create a service principal with the "Contributor" role on your subscription:
+//    az ad sp create-for-rbac --role Contributor
+//
+// 2) Save its credentials as "~/.azure/quilt.json":
+//    {"subscriptionId": "...", "tenantId": "...",
+//     "clientId": "...", "clientSecret": "..."}
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NetSys/quilt/cluster/acl"
+	"github.com/NetSys/quilt/cluster/cloudcfg"
+	"github.com/NetSys/quilt/cluster/machine"
+	"github.com/NetSys/quilt/db"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/satori/go.uuid"
+)
+
+// DefaultRegion is the preferred location for machines which haven't a user specified
+// region preference.
+const DefaultRegion = "westus2"
+
+// Regions is the list of supported Azure regions.
+var Regions = []string{"eastus", "westus2", "westeurope"}
+
+// Ubuntu 16.04, 64-bit
+var image = imageReference{
+	Publisher: "Canonical",
+	Offer:     "UbuntuServer",
+	Sku:       "16.04-LTS",
+	Version:   "latest",
+}
+
+// Azure requires an administrator account for each virtual machine.  It must differ
+// from the "quilt" user created by the cloud config.
+const adminUser = "ubuntu"
+
+const (
+	networkName  = "quilt"
+	subnetName   = "default"
+	addressRange = "192.168.0.0/16"
+)
+
+// Azure evaluates the rules of a network security group in order of priority, which
+// must be between 100 and 4096.
+const (
+	minPriority = 100
+	maxPriority = 4096
+)
+
+// The Cluster object represents a connection to Azure.
+//
+// Each namespace and region has its own resource group, containing the namespace's
+// virtual network, the network security group that implements its ACLs, and its
+// machines.  Each machine is a virtual machine with a network interface and a public
+// IP address of the same name.
+type Cluster struct {
+	client client
+
+	subscription string
+	namespace    string
+	region       string
+	group        string
+}
+
+// New creates a new Azure cluster.
+func New(namespace, region string) (*Cluster, error) {
+	creds, err := readCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	clst := newAzure(newClient(creds), creds.SubscriptionID, namespace, region)
+	if _, err := clst.List(); err != nil {
+		return nil, errors.New("Azure failed to connect")
+	}
+	return clst, nil
+}
+
+func newAzure(c client, subscription, namespace, region string) *Cluster {
+	namespace = strings.ToLower(namespace)
+	return &Cluster{
+		client:       c,
+		subscription: subscription,
+		namespace:    namespace,
+		region:       region,
+		group:        fmt.Sprintf("quilt-%s-%s", namespace, region),
+	}
+}
+
+// netInit creates the resource group and virtual network of `clst`, and its network
+// security group if it doesn't exist yet.  Existing security groups are left alone,
+// as recreating them would clear their rules.  They're created when machines first
+// boot, so that regions that are never used don't accumulate empty resource groups.
+func (clst Cluster) netInit() error {
+	if err := clst.client.CreateResourceGroup(clst.group, clst.region); err != nil {
+		return err
+	}
+
+	_, err := clst.client.GetSecurityGroup(clst.group, networkName)
+	if err == errNotFound {
+		err = clst.client.CreateSecurityGroup(clst.group, securityGroup{
+			Name:     networkName,
+			Location: clst.region,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	vnet := virtualNetwork{Name: networkName, Location: clst.region}
+	vnet.Properties.AddressSpace.AddressPrefixes = []string{addressRange}
+
+	sn := subnet{Name: subnetName}
+	sn.Properties.AddressPrefix = addressRange
+	sn.Properties.NetworkSecurityGroup = &resourceRef{
+		ID: clst.networkID("networkSecurityGroups", networkName),
+	}
+	vnet.Properties.Subnets = []subnet{sn}
+
+	return clst.client.CreateVirtualNetwork(clst.group, vnet)
+}
+
+// List queries `clst` for the list of booted machines.
+func (clst Cluster) List() ([]machine.Machine, error) {
+	// The resource group doesn't exist until the first machine boots.
+	vms, err := clst.client.ListVirtualMachines(clst.group)
+	if err == errNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	nics, err := clst.client.ListNetworkInterfaces(clst.group)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := clst.client.ListPublicIPAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	nicMap := map[string]networkInterface{}
+	for _, nic := range nics {
+		nicMap[nic.Name] = nic
+	}
+
+	// Resource IDs are case insensitive, and Azure isn't consistent about their case.
+	addresses := map[string]string{}
+	for _, ip := range ips {
+		addresses[strings.ToLower(ip.ID)] = ip.Properties.IPAddress
+	}
+
+	var machines []machine.Machine
+	for _, vm := range vms {
+		if vm.Properties.ProvisioningState == "Deleting" {
+			continue
+		}
+
+		m := machine.Machine{
+			ID:       vm.Name,
+			Size:     vm.Properties.HardwareProfile.VMSize,
+			DiskSize: vm.Properties.StorageProfile.OSDisk.DiskSizeGB,
+			Provider: db.Azure,
+			Region:   clst.region,
+		}
+
+		nic, ok := nicMap[vm.Name]
+		if ok && len(nic.Properties.IPConfigurations) > 0 {
+			ipcfg := nic.Properties.IPConfigurations[0].Properties
+			m.PrivateIP = ipcfg.PrivateIPAddress
+
+			if ipcfg.PublicIPAddress != nil {
+				id := strings.ToLower(ipcfg.PublicIPAddress.ID)
+				m.PublicIP = addresses[id]
+				if id != strings.ToLower(
+					clst.networkID("publicIPAddresses", vm.Name)) {
+					m.FloatingIP = m.PublicIP
+				}
+			}
+		}
+		machines = append(machines, m)
+	}
+	return machines, nil
+}
+
+// Boot creates a virtual machine in `clst` for each machine in the `bootSet`, along
+// with its public IP and network interface, and blocks until they have booted.  The
+// network of `clst` is created first if it doesn't exist yet.
+func (clst Cluster) Boot(bootSet []machine.Machine) error {
+	if len(bootSet) == 0 {
+		return nil
+	}
+
+	if err := clst.netInit(); err != nil {
+		return err
+	}
+
+	for _, m := range bootSet {
+		if err := clst.boot(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// boot creates the resources of `m`.  If any of them fails to be created, those that
+// were are deleted, so that they aren't leaked.
+func (clst Cluster) boot(m machine.Machine) error {
+	if len(m.SSHKeys) == 0 {
+		return errors.New("Azure machines require at least one SSH key")
+	}
+
+	name := "quilt-" + uuid.NewV4().String()
+	err := clst.createResources(name, m)
+	if err != nil {
+		if stopErr := clst.Stop([]machine.Machine{{ID: name}}); stopErr != nil {
+			log.WithError(stopErr).WithField("name", name).Error(
+				"Failed to clean up the resources of a failed boot.")
+		}
+	}
+	return err
+}
+
+func (clst Cluster) createResources(name string, m machine.Machine) error {
+	ip := publicIPAddress{Name: name, Location: clst.region}
+	ip.Sku.Name = "Standard"
+	ip.Properties.PublicIPAllocationMethod = "Static"
+	if err := clst.client.CreatePublicIPAddress(clst.group, ip); err != nil {
+		return err
+	}
+
+	ipcfg := ipConfiguration{Name: "ipconfig"}
+	ipcfg.Properties.PrivateIPAllocationMethod = "Dynamic"
+	ipcfg.Properties.Subnet = &resourceRef{ID: clst.subnetID()}
+	ipcfg.Properties.PublicIPAddress = &resourceRef{
+		ID: clst.networkID("publicIPAddresses", name),
+	}
+
+	nic := networkInterface{Name: name, Location: clst.region}
+	nic.Properties.IPConfigurations = []ipConfiguration{ipcfg}
+	if err := clst.client.CreateNetworkInterface(clst.group, nic); err != nil {
+		return err
+	}
+
+	cfg := cloudcfg.Ubuntu(m.SSHKeys, "xenial", m.CACert)
+	profile := osProfile{
+		ComputerName:  name,
+		AdminUsername: adminUser,
+		CustomData:    base64.StdEncoding.EncodeToString([]byte(cfg)),
+	}
+	profile.LinuxConfiguration.DisablePasswordAuthentication = true
+	keyPath := fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUser)
+	for _, key := range m.SSHKeys {
+		profile.LinuxConfiguration.SSH.PublicKeys = append(
+			profile.LinuxConfiguration.SSH.PublicKeys,
+			sshPublicKey{Path: keyPath, KeyData: key})
+	}
+
+	vm := virtualMachine{Name: name, Location: clst.region}
+	vm.Properties = vmProperties{
+		HardwareProfile: hardwareProfile{VMSize: m.Size},
+		StorageProfile: storageProfile{
+			ImageReference: &image,
+			OSDisk: osDisk{
+				CreateOption: "FromImage",
+				DeleteOption: "Delete",
+				DiskSizeGB:   m.DiskSize,
+			},
+		},
+		OSProfile: &profile,
+		NetworkProfile: networkProfile{NetworkInterfaces: []resourceRef{
+			{ID: clst.networkID("networkInterfaces", name)},
+		}},
+	}
+	return clst.client.CreateVirtualMachine(clst.group, vm)
+}
+
+// Stop deletes the virtual machines of `machines`, along with their network
+// interfaces and public IPs, and blocks until they are gone.
+func (clst Cluster) Stop(machines []machine.Machine) error {
+	for _, m := range machines {
+		// Each resource is in use by the one before it, so they must be
+		// deleted in order.
+		for _, del := range []func(group, name string) error{
+			clst.client.DeleteVirtualMachine,
+			clst.client.DeleteNetworkInterface,
+			clst.client.DeletePublicIPAddress,
+		} {
+			if err := del(clst.group, m.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateFloatingIPs associates the network interfaces of `machines` with the public
+// IP resources that hold their floating IPs.  Machines without a floating IP are
+// associated with their own public IP.
+func (clst Cluster) UpdateFloatingIPs(machines []machine.Machine) error {
+	nics, err := clst.client.ListNetworkInterfaces(clst.group)
+	if err != nil {
+		return err
+	}
+
+	ips, err := clst.client.ListPublicIPAddresses()
+	if err != nil {
+		return err
+	}
+
+	nicMap := map[string]networkInterface{}
+	for _, nic := range nics {
+		nicMap[nic.Name] = nic
+	}
+
+	ipIDs := map[string]string{}
+	for _, ip := range ips {
+		if ip.Properties.IPAddress != "" {
+			ipIDs[ip.Properties.IPAddress] = ip.ID
+		}
+	}
+
+	for _, m := range machines {
+		nic, ok := nicMap[m.ID]
+		if !ok || len(nic.Properties.IPConfigurations) == 0 {
+			return fmt.Errorf("no network interface for machine %s", m.ID)
+		}
+
+		desired := clst.networkID("publicIPAddresses", m.ID)
+		if m.FloatingIP != "" {
+			if desired, ok = ipIDs[m.FloatingIP]; !ok {
+				return fmt.Errorf("no public IP resource holds %s",
+					m.FloatingIP)
+			}
+		}
+
+		ipcfgs := append([]ipConfiguration{}, nic.Properties.IPConfigurations...)
+		ipcfg := &ipcfgs[0].Properties
+		if ipcfg.PublicIPAddress != nil &&
+			strings.EqualFold(ipcfg.PublicIPAddress.ID, desired) {
+			continue
+		}
+
+		ipcfg.PublicIPAddress = &resourceRef{ID: desired}
+		nic.Properties.IPConfigurations = ipcfgs
+		err := clst.client.CreateNetworkInterface(clst.group, nic)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetACLs updates the network security group of `clst` so that it admits exactly
+// `acls`.  Traffic within the virtual network is admitted by Azure's default rules.
+func (clst Cluster) SetACLs(acls []acl.ACL) error {
+	// Until the first machine boots, there is no network security group, and no
+	// machines for it to protect.  Its rules are set the next time the ACLs are.
+	nsg, err := clst.client.GetSecurityGroup(clst.group, networkName)
+	if err == errNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	desired, err := securityRules(acls)
+	if err != nil {
+		return err
+	}
+
+	current := nsg.Properties.SecurityRules
+	sort.Sort(rulesByPriority(current))
+	if len(current) == 0 && len(desired) == 0 ||
+		reflect.DeepEqual(current, desired) {
+		return nil
+	}
+
+	log.WithField("ACLs", acls).Debug("Azure: Update network security group")
+	nsg.Properties.SecurityRules = desired
+	return clst.client.CreateSecurityGroup(clst.group, nsg)
+}
+
+// securityRules returns the inbound security rules that implement `acls`, in order of
+// priority.
+func securityRules(acls []acl.ACL) ([]securityRule, error) {
+	acls = append([]acl.ACL{}, acls...)
+	sort.Sort(aclSlice(acls))
+
+	var rules []securityRule
+	for i, a := range acls {
+		if i > 0 && a == acls[i-1] {
+			continue
+		}
+
+		priority := minPriority + len(rules)
+		if priority > maxPriority {
+			return nil, errors.New(
+				"too many ACLs for a network security group")
+		}
+
+		ports := strconv.Itoa(a.MinPort)
+		if a.MinPort != a.MaxPort {
+			ports = fmt.Sprintf("%d-%d", a.MinPort, a.MaxPort)
+		}
+
+		rules = append(rules, securityRule{
+			Name: fmt.Sprintf("quilt-%d", priority),
+			Properties: securityRuleProperties{
+				Priority:                 priority,
+				Direction:                "Inbound",
+				Access:                   "Allow",
+				Protocol:                 "*",
+				SourceAddressPrefix:      a.CidrIP,
+				SourcePortRange:          "*",
+				DestinationAddressPrefix: "*",
+				DestinationPortRange:     ports,
+			},
+		})
+	}
+	return rules, nil
+}
+
+func (clst Cluster) networkID(kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s", resourceID(clst.subscription, clst.group,
+		"Microsoft.Network"), kind, name)
+}
+
+func (clst Cluster) subnetID() string {
+	return fmt.Sprintf("%s/subnets/%s", clst.networkID("virtualNetworks",
+		networkName), subnetName)
+}
+
+type aclSlice []acl.ACL
+
+func (acls aclSlice) Len() int {
+	return len(acls)
+}
+
+func (acls aclSlice) Swap(i, j int) {
+	acls[i], acls[j] = acls[j], acls[i]
+}
+
+func (acls aclSlice) Less(i, j int) bool {
+	l, r := acls[i], acls[j]
+	switch {
+	case l.CidrIP != r.CidrIP:
+		return l.CidrIP < r.CidrIP
+	case l.MinPort != r.MinPort:
+		return l.MinPort < r.MinPort
+	default:
+		return l.MaxPort < r.MaxPort
+	}
+}
+
+type rulesByPriority []securityRule
+
+func (rules rulesByPriority) Len() int {
+	return len(rules)
+}
+
+func (rules rulesByPriority) Swap(i, j int) {
+	rules[i], rules[j] = rules[j], rules[i]
+}
+
+func (rules rulesByPriority) Less(i, j int) bool {
+	return rules[i].Properties.Priority < rules[j].Properties.Priority
+}
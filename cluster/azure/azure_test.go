@@ -0,0 +1,352 @@
+package azure
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/NetSys/quilt/cluster/acl"
+	"github.com/NetSys/quilt/cluster/machine"
+	"github.com/NetSys/quilt/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testGroup  = "quilt-namespace-westus2"
+	testPrefix = "/subscriptions/sub/resourceGroups/" + testGroup +
+		"/providers/Microsoft.Network"
+)
+
+func newTestVM(name, state string) virtualMachine {
+	vm := virtualMachine{Name: name, Location: "westus2"}
+	vm.Properties.ProvisioningState = state
+	vm.Properties.HardwareProfile.VMSize = "Standard_B1s"
+	vm.Properties.StorageProfile.OSDisk.DiskSizeGB = 32
+	return vm
+}
+
+func newTestNIC(name, private, publicID string) networkInterface {
+	ipcfg := ipConfiguration{Name: "ipconfig"}
+	ipcfg.Properties.PrivateIPAddress = private
+	ipcfg.Properties.PrivateIPAllocationMethod = "Dynamic"
+	if publicID != "" {
+		ipcfg.Properties.PublicIPAddress = &resourceRef{ID: publicID}
+	}
+
+	nic := networkInterface{Name: name, Location: "westus2"}
+	nic.Properties.IPConfigurations = []ipConfiguration{ipcfg}
+	return nic
+}
+
+func newTestIP(id, address string) publicIPAddress {
+	ip := publicIPAddress{ID: id}
+	ip.Properties.IPAddress = address
+	return ip
+}
+
+func TestNetInit(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newAzure(c, "sub", "Namespace", "westus2")
+	assert.Equal(t, testGroup, clst.group)
+
+	c.On("CreateResourceGroup", testGroup, "westus2").Return(nil)
+	c.On("GetSecurityGroup", testGroup, "quilt").Return(securityGroup{},
+		errNotFound).Once()
+	c.On("CreateSecurityGroup", testGroup, securityGroup{
+		Name:     "quilt",
+		Location: "westus2",
+	}).Return(nil).Once()
+	c.On("CreateVirtualNetwork", testGroup, mock.MatchedBy(
+		func(vnet virtualNetwork) bool {
+			sn := vnet.Properties.Subnets[0].Properties
+			return vnet.Name == "quilt" &&
+				sn.AddressPrefix == "192.168.0.0/16" &&
+				sn.NetworkSecurityGroup.ID ==
+					testPrefix+"/networkSecurityGroups/quilt"
+		})).Return(nil)
+
+	assert.NoError(t, clst.netInit())
+	c.AssertExpectations(t)
+
+	// Existing security groups aren't recreated.
+	c.On("GetSecurityGroup", testGroup, "quilt").Return(securityGroup{},
+		nil).Once()
+	assert.NoError(t, clst.netInit())
+	c.AssertNumberOfCalls(t, "CreateSecurityGroup", 1)
+
+	c.On("GetSecurityGroup", testGroup, "quilt").Return(securityGroup{},
+		errors.New("unauthorized")).Once()
+	assert.EqualError(t, clst.netInit(), "unauthorized")
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newAzure(c, "sub", "namespace", "westus2")
+
+	c.On("ListVirtualMachines", testGroup).Return([]virtualMachine{
+		newTestVM("a", "Succeeded"),
+		newTestVM("b", "Creating"),
+		newTestVM("c", "Deleting"),
+	}, nil).Once()
+	c.On("ListNetworkInterfaces", testGroup).Return([]networkInterface{
+		newTestNIC("a", "192.168.0.4", testPrefix+"/publicIPAddresses/a"),
+		newTestNIC("b", "192.168.0.5",
+			"/subscriptions/sub/resourceGroups/ips/providers/"+
+				"Microsoft.Network/publicIPAddresses/floating"),
+	}, nil)
+	c.On("ListPublicIPAddresses").Return([]publicIPAddress{
+		// Azure isn't consistent about the case of resource IDs.
+		newTestIP("/subscriptions/sub/resourcegroups/"+testGroup+
+			"/providers/Microsoft.Network/publicIPAddresses/a", "1.1.1.1"),
+		newTestIP(testPrefix+"/publicIPAddresses/b", "2.2.2.2"),
+		newTestIP("/subscriptions/sub/resourceGroups/ips/providers/"+
+			"Microsoft.Network/publicIPAddresses/floating", "5.5.5.5"),
+	}, nil)
+
+	machines, err := clst.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []machine.Machine{{
+		ID:        "a",
+		PublicIP:  "1.1.1.1",
+		PrivateIP: "192.168.0.4",
+		Size:      "Standard_B1s",
+		DiskSize:  32,
+		Provider:  db.Azure,
+		Region:    "westus2",
+	}, {
+		ID:         "b",
+		PublicIP:   "5.5.5.5",
+		PrivateIP:  "192.168.0.5",
+		FloatingIP: "5.5.5.5",
+		Size:       "Standard_B1s",
+		DiskSize:   32,
+		Provider:   db.Azure,
+		Region:     "westus2",
+	}}, machines)
+
+	c.On("ListVirtualMachines", testGroup).Return(nil,
+		errors.New("unauthorized")).Once()
+	_, err = clst.List()
+	assert.EqualError(t, err, "unauthorized")
+
+	// The resource group doesn't exist until the first machine boots.
+	c.On("ListVirtualMachines", testGroup).Return(nil, errNotFound).Once()
+	machines, err = clst.List()
+	assert.NoError(t, err)
+	assert.Empty(t, machines)
+}
+
+func TestBoot(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newAzure(c, "sub", "namespace", "westus2")
+
+	// Nothing is created if there's nothing to boot.
+	assert.NoError(t, clst.Boot(nil))
+	c.AssertNotCalled(t, "CreateResourceGroup", mock.Anything, mock.Anything)
+
+	// The network is created before the first machine.
+	c.On("CreateResourceGroup", testGroup, "westus2").Return(
+		errors.New("unauthorized")).Once()
+	err := clst.Boot([]machine.Machine{{SSHKeys: []string{"key"}}})
+	assert.EqualError(t, err, "unauthorized")
+	c.AssertNotCalled(t, "CreatePublicIPAddress", mock.Anything, mock.Anything)
+
+	c.On("CreateResourceGroup", testGroup, "westus2").Return(nil)
+	c.On("GetSecurityGroup", testGroup, "quilt").Return(securityGroup{}, nil)
+	c.On("CreateVirtualNetwork", testGroup, mock.Anything).Return(nil)
+
+	var name string
+	c.On("CreatePublicIPAddress", testGroup, mock.MatchedBy(
+		func(ip publicIPAddress) bool {
+			name = ip.Name
+			return ip.Location == "westus2" && ip.Sku.Name == "Standard"
+		})).Return(nil).Once()
+	c.On("CreateNetworkInterface", testGroup, mock.MatchedBy(
+		func(nic networkInterface) bool {
+			ipcfg := nic.Properties.IPConfigurations[0].Properties
+			return nic.Name == name &&
+				ipcfg.Subnet.ID == testPrefix+
+					"/virtualNetworks/quilt/subnets/default" &&
+				ipcfg.PublicIPAddress.ID == testPrefix+
+					"/publicIPAddresses/"+name
+		})).Return(nil).Once()
+	c.On("CreateVirtualMachine", testGroup, mock.MatchedBy(
+		func(vm virtualMachine) bool {
+			props := vm.Properties
+			cfg, err := base64.StdEncoding.DecodeString(
+				props.OSProfile.CustomData)
+			keys := props.OSProfile.LinuxConfiguration.SSH.PublicKeys
+			return vm.Name == name && err == nil && len(cfg) > 0 &&
+				props.HardwareProfile.VMSize == "Standard_B1s" &&
+				props.StorageProfile.OSDisk.DiskSizeGB == 32 &&
+				props.NetworkProfile.NetworkInterfaces[0].ID ==
+					testPrefix+"/networkInterfaces/"+name &&
+				len(keys) == 1 && keys[0].KeyData == "key"
+		})).Return(nil).Once()
+
+	err = clst.Boot([]machine.Machine{{
+		Size:     "Standard_B1s",
+		DiskSize: 32,
+		SSHKeys:  []string{"key"},
+	}})
+	assert.NoError(t, err)
+	c.AssertExpectations(t)
+
+	err = clst.Boot([]machine.Machine{{Size: "Standard_B1s"}})
+	assert.EqualError(t, err, "Azure machines require at least one SSH key")
+
+	// The resources of machines that fail to boot are deleted.
+	c.On("CreatePublicIPAddress", testGroup, mock.MatchedBy(
+		func(ip publicIPAddress) bool {
+			name = ip.Name
+			return true
+		})).Return(nil).Once()
+	c.On("CreateNetworkInterface", testGroup, mock.Anything).Return(nil).Once()
+	c.On("CreateVirtualMachine", testGroup, mock.Anything).Return(
+		errors.New("quota exceeded")).Once()
+	for _, del := range []string{"DeleteVirtualMachine", "DeleteNetworkInterface",
+		"DeletePublicIPAddress"} {
+		c.On(del, testGroup, mock.MatchedBy(func(n string) bool {
+			return n == name
+		})).Return(nil).Once()
+	}
+	err = clst.Boot([]machine.Machine{{SSHKeys: []string{"key"}}})
+	assert.EqualError(t, err, "quota exceeded")
+	c.AssertExpectations(t)
+}
+
+func TestStop(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newAzure(c, "sub", "namespace", "westus2")
+
+	c.On("DeleteVirtualMachine", testGroup, "a").Return(nil).Once()
+	c.On("DeleteNetworkInterface", testGroup, "a").Return(nil).Once()
+	c.On("DeletePublicIPAddress", testGroup, "a").Return(nil).Once()
+	assert.NoError(t, clst.Stop([]machine.Machine{{ID: "a"}}))
+	c.AssertExpectations(t)
+
+	c.On("DeleteVirtualMachine", testGroup, "b").Return(
+		errors.New("not authorized")).Once()
+	assert.EqualError(t, clst.Stop([]machine.Machine{{ID: "b"}}),
+		"not authorized")
+	c.AssertNotCalled(t, "DeleteNetworkInterface", testGroup, "b")
+}
+
+func TestUpdateFloatingIPs(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newAzure(c, "sub", "namespace", "westus2")
+
+	floatingID := "/subscriptions/sub/resourceGroups/ips/providers/" +
+		"Microsoft.Network/publicIPAddresses/floating"
+	c.On("ListNetworkInterfaces", testGroup).Return([]networkInterface{
+		newTestNIC("a", "192.168.0.4", testPrefix+"/publicIPAddresses/a"),
+		newTestNIC("b", "192.168.0.5", floatingID),
+		newTestNIC("c", "192.168.0.6", testPrefix+"/publicIPAddresses/c"),
+	}, nil)
+	c.On("ListPublicIPAddresses").Return([]publicIPAddress{
+		newTestIP(testPrefix+"/publicIPAddresses/a", "1.1.1.1"),
+		newTestIP(testPrefix+"/publicIPAddresses/c", "3.3.3.3"),
+		newTestIP(floatingID, "5.5.5.5"),
+		newTestIP("/subscriptions/sub/resourceGroups/ips/providers/"+
+			"Microsoft.Network/publicIPAddresses/unassigned", ""),
+	}, nil)
+
+	c.On("CreateNetworkInterface", testGroup,
+		newTestNIC("a", "192.168.0.4", floatingID)).Return(nil).Once()
+	c.On("CreateNetworkInterface", testGroup,
+		newTestNIC("b", "192.168.0.5", testPrefix+"/publicIPAddresses/b"),
+	).Return(nil).Once()
+
+	err := clst.UpdateFloatingIPs([]machine.Machine{
+		{ID: "a", FloatingIP: "5.5.5.5"},
+		{ID: "b"},
+		{ID: "c"},
+	})
+	assert.NoError(t, err)
+	c.AssertExpectations(t)
+
+	err = clst.UpdateFloatingIPs([]machine.Machine{{ID: "a", FloatingIP: "8.8.8.8"}})
+	assert.EqualError(t, err, "no public IP resource holds 8.8.8.8")
+
+	err = clst.UpdateFloatingIPs([]machine.Machine{{ID: "d"}})
+	assert.EqualError(t, err, "no network interface for machine d")
+}
+
+func TestSetACLs(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newAzure(c, "sub", "namespace", "westus2")
+
+	acls := []acl.ACL{
+		{CidrIP: "2.2.2.2/32", MinPort: 80, MaxPort: 80},
+		{CidrIP: "1.1.1.1/32", MinPort: 1, MaxPort: 65535},
+		{CidrIP: "2.2.2.2/32", MinPort: 80, MaxPort: 80},
+	}
+	exp, err := securityRules(acls)
+	assert.NoError(t, err)
+	assert.Equal(t, []securityRule{{
+		Name: "quilt-100",
+		Properties: securityRuleProperties{
+			Priority:                 100,
+			Direction:                "Inbound",
+			Access:                   "Allow",
+			Protocol:                 "*",
+			SourceAddressPrefix:      "1.1.1.1/32",
+			SourcePortRange:          "*",
+			DestinationAddressPrefix: "*",
+			DestinationPortRange:     "1-65535",
+		},
+	}, {
+		Name: "quilt-101",
+		Properties: securityRuleProperties{
+			Priority:                 101,
+			Direction:                "Inbound",
+			Access:                   "Allow",
+			Protocol:                 "*",
+			SourceAddressPrefix:      "2.2.2.2/32",
+			SourcePortRange:          "*",
+			DestinationAddressPrefix: "*",
+			DestinationPortRange:     "80",
+		},
+	}}, exp)
+
+	empty := securityGroup{Name: "quilt", Location: "westus2"}
+	empty.Properties.SecurityRules = []securityRule{}
+	c.On("GetSecurityGroup", testGroup, "quilt").Return(empty, nil).Once()
+
+	updated := empty
+	updated.Properties.SecurityRules = exp
+	c.On("CreateSecurityGroup", testGroup, updated).Return(nil).Once()
+	assert.NoError(t, clst.SetACLs(acls))
+	c.AssertExpectations(t)
+
+	// Nothing changes if the rules are already correct, regardless of the order
+	// Azure reports them in.
+	current := updated
+	current.Properties.SecurityRules = []securityRule{exp[1], exp[0]}
+	c.On("GetSecurityGroup", testGroup, "quilt").Return(current, nil).Once()
+	assert.NoError(t, clst.SetACLs(acls))
+
+	c.On("GetSecurityGroup", testGroup, "quilt").Return(empty, nil).Once()
+	assert.NoError(t, clst.SetACLs(nil))
+	c.AssertNumberOfCalls(t, "CreateSecurityGroup", 1)
+
+	// No machines have booted, so there's no security group yet.
+	c.On("GetSecurityGroup", testGroup, "quilt").Return(securityGroup{},
+		errNotFound).Once()
+	assert.NoError(t, clst.SetACLs(acls))
+	c.AssertNumberOfCalls(t, "CreateSecurityGroup", 1)
+}
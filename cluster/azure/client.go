@@ -0,0 +1,506 @@
+package azure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/NetSys/quilt/util"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+const (
+	managementURL = "https://management.azure.com"
+	loginURL      = "https://login.microsoftonline.com"
+
+	computeAPIVersion   = "2021-07-01"
+	networkAPIVersion   = "2021-05-01"
+	resourcesAPIVersion = "2021-04-01"
+)
+
+// The client's Create and Delete methods block until Azure has finished provisioning
+// or deleting the resource.
+type client interface {
+	CreateResourceGroup(name, location string) error
+
+	GetSecurityGroup(group, name string) (securityGroup, error)
+	CreateSecurityGroup(group string, nsg securityGroup) error
+
+	CreateVirtualNetwork(group string, vnet virtualNetwork) error
+
+	ListPublicIPAddresses() ([]publicIPAddress, error)
+	CreatePublicIPAddress(group string, ip publicIPAddress) error
+	DeletePublicIPAddress(group, name string) error
+
+	ListNetworkInterfaces(group string) ([]networkInterface, error)
+	CreateNetworkInterface(group string, nic networkInterface) error
+	DeleteNetworkInterface(group, name string) error
+
+	ListVirtualMachines(group string) ([]virtualMachine, error)
+	CreateVirtualMachine(group string, vm virtualMachine) error
+	DeleteVirtualMachine(group, name string) error
+}
+
+// errNotFound is returned by the client when the requested resource doesn't exist.
+var errNotFound = errors.New("resource not found")
+
+type resourceRef struct {
+	ID string `json:"id"`
+}
+
+/**
+ * Resource: Virtual Machines
+ */
+
+type virtualMachine struct {
+	Name       string       `json:"name"`
+	Location   string       `json:"location"`
+	Properties vmProperties `json:"properties"`
+}
+
+type vmProperties struct {
+	ProvisioningState string          `json:"provisioningState,omitempty"`
+	HardwareProfile   hardwareProfile `json:"hardwareProfile"`
+	StorageProfile    storageProfile  `json:"storageProfile"`
+	OSProfile         *osProfile      `json:"osProfile,omitempty"`
+	NetworkProfile    networkProfile  `json:"networkProfile"`
+}
+
+type hardwareProfile struct {
+	VMSize string `json:"vmSize"`
+}
+
+type storageProfile struct {
+	ImageReference *imageReference `json:"imageReference,omitempty"`
+	OSDisk         osDisk          `json:"osDisk"`
+}
+
+type imageReference struct {
+	Publisher string `json:"publisher"`
+	Offer     string `json:"offer"`
+	Sku       string `json:"sku"`
+	Version   string `json:"version"`
+}
+
+type osDisk struct {
+	CreateOption string `json:"createOption"`
+	DeleteOption string `json:"deleteOption,omitempty"`
+	DiskSizeGB   int    `json:"diskSizeGB,omitempty"`
+}
+
+type osProfile struct {
+	ComputerName       string             `json:"computerName"`
+	AdminUsername      string             `json:"adminUsername"`
+	CustomData         string             `json:"customData"`
+	LinuxConfiguration linuxConfiguration `json:"linuxConfiguration"`
+}
+
+type linuxConfiguration struct {
+	DisablePasswordAuthentication bool `json:"disablePasswordAuthentication"`
+	SSH                           struct {
+		PublicKeys []sshPublicKey `json:"publicKeys"`
+	} `json:"ssh"`
+}
+
+type sshPublicKey struct {
+	Path    string `json:"path"`
+	KeyData string `json:"keyData"`
+}
+
+type networkProfile struct {
+	NetworkInterfaces []resourceRef `json:"networkInterfaces"`
+}
+
+/**
+ * Resource: Network Interfaces
+ */
+
+type networkInterface struct {
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Properties struct {
+		IPConfigurations []ipConfiguration `json:"ipConfigurations"`
+	} `json:"properties"`
+}
+
+type ipConfiguration struct {
+	Name       string `json:"name"`
+	Properties struct {
+		PrivateIPAddress          string       `json:"privateIPAddress,omitempty"`
+		PrivateIPAllocationMethod string       `json:"privateIPAllocationMethod"`
+		Subnet                    *resourceRef `json:"subnet,omitempty"`
+		PublicIPAddress           *resourceRef `json:"publicIPAddress,omitempty"`
+	} `json:"properties"`
+}
+
+/**
+ * Resource: Public IP Addresses
+ */
+
+type publicIPAddress struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	Sku      struct {
+		Name string `json:"name"`
+	} `json:"sku"`
+	Properties struct {
+		PublicIPAllocationMethod string `json:"publicIPAllocationMethod"`
+		IPAddress                string `json:"ipAddress,omitempty"`
+	} `json:"properties"`
+}
+
+/**
+ * Resource: Network Security Groups
+ */
+
+type securityGroup struct {
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Properties struct {
+		SecurityRules []securityRule `json:"securityRules"`
+	} `json:"properties"`
+}
+
+type securityRule struct {
+	Name       string                 `json:"name"`
+	Properties securityRuleProperties `json:"properties"`
+}
+
+type securityRuleProperties struct {
+	Priority                 int    `json:"priority"`
+	Direction                string `json:"direction"`
+	Access                   string `json:"access"`
+	Protocol                 string `json:"protocol"`
+	SourceAddressPrefix      string `json:"sourceAddressPrefix"`
+	SourcePortRange          string `json:"sourcePortRange"`
+	DestinationAddressPrefix string `json:"destinationAddressPrefix"`
+	DestinationPortRange     string `json:"destinationPortRange"`
+}
+
+/**
+ * Resource: Virtual Networks
+ */
+
+type virtualNetwork struct {
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Properties struct {
+		AddressSpace struct {
+			AddressPrefixes []string `json:"addressPrefixes"`
+		} `json:"addressSpace"`
+		Subnets []subnet `json:"subnets"`
+	} `json:"properties"`
+}
+
+type subnet struct {
+	Name       string `json:"name"`
+	Properties struct {
+		AddressPrefix        string       `json:"addressPrefix"`
+		NetworkSecurityGroup *resourceRef `json:"networkSecurityGroup,omitempty"`
+	} `json:"properties"`
+}
+
+// credentials identify the service principal through which Quilt manages Azure.
+type credentials struct {
+	SubscriptionID string `json:"subscriptionId"`
+	TenantID       string `json:"tenantId"`
+	ClientID       string `json:"clientId"`
+	ClientSecret   string `json:"clientSecret"`
+}
+
+// readCredentials is a variable so it can be easily replaced while unit testing.
+var readCredentials = func() (credentials, error) {
+	var creds credentials
+	keyfile := filepath.Join(os.Getenv("HOME"), ".azure", "quilt.json")
+	key, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		return creds, err
+	}
+
+	err = json.Unmarshal(key, &creds)
+	return creds, err
+}
+
+type clientImpl struct {
+	http         *http.Client
+	subscription string
+}
+
+// newClient is a variable so it can be easily replaced while unit testing.
+var newClient = func(creds credentials) client {
+	src := oauth2.ReuseTokenSource(nil, tokenSource{creds})
+	return clientImpl{
+		http:         oauth2.NewClient(context.Background(), src),
+		subscription: creds.SubscriptionID,
+	}
+}
+
+// tokenSource retrieves access tokens for the service principal using the OAuth2
+// client credentials grant.
+type tokenSource struct {
+	creds credentials
+}
+
+func (ts tokenSource) Token() (*oauth2.Token, error) {
+	resp, err := http.PostForm(
+		fmt.Sprintf("%s/%s/oauth2/token", loginURL, ts.creds.TenantID),
+		url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {ts.creds.ClientID},
+			"client_secret": {ts.creds.ClientSecret},
+			"resource":      {managementURL + "/"},
+		})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"`
+		Error       string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("failed to authenticate with Azure: %s",
+			token.Error)
+	}
+
+	expiresIn, _ := strconv.Atoi(token.ExpiresIn)
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		Expiry:      time.Now().Add(time.Duration(expiresIn) * time.Second),
+	}, nil
+}
+
+func (c clientImpl) CreateResourceGroup(name, location string) error {
+	body := struct {
+		Location string `json:"location"`
+	}{location}
+	return c.do("PUT", fmt.Sprintf("/subscriptions/%s/resourcegroups/%s",
+		c.subscription, name), resourcesAPIVersion, body, nil)
+}
+
+func (c clientImpl) GetSecurityGroup(group, name string) (securityGroup, error) {
+	var nsg securityGroup
+	err := c.do("GET", c.networkPath(group, "networkSecurityGroups", name),
+		networkAPIVersion, nil, &nsg)
+	return nsg, err
+}
+
+func (c clientImpl) CreateSecurityGroup(group string, nsg securityGroup) error {
+	return c.create(c.networkPath(group, "networkSecurityGroups", nsg.Name),
+		networkAPIVersion, nsg)
+}
+
+func (c clientImpl) CreateVirtualNetwork(group string, vnet virtualNetwork) error {
+	return c.create(c.networkPath(group, "virtualNetworks", vnet.Name),
+		networkAPIVersion, vnet)
+}
+
+func (c clientImpl) ListPublicIPAddresses() ([]publicIPAddress, error) {
+	var ips []publicIPAddress
+	path := fmt.Sprintf(
+		"/subscriptions/%s/providers/Microsoft.Network/publicIPAddresses",
+		c.subscription)
+	err := c.list(path, networkAPIVersion, func(dec *json.Decoder) error {
+		var page []publicIPAddress
+		err := dec.Decode(&page)
+		ips = append(ips, page...)
+		return err
+	})
+	return ips, err
+}
+
+func (c clientImpl) CreatePublicIPAddress(group string, ip publicIPAddress) error {
+	return c.create(c.networkPath(group, "publicIPAddresses", ip.Name),
+		networkAPIVersion, ip)
+}
+
+func (c clientImpl) DeletePublicIPAddress(group, name string) error {
+	return c.delete(c.networkPath(group, "publicIPAddresses", name),
+		networkAPIVersion)
+}
+
+func (c clientImpl) ListNetworkInterfaces(group string) ([]networkInterface, error) {
+	var nics []networkInterface
+	err := c.list(c.networkPath(group, "networkInterfaces", ""), networkAPIVersion,
+		func(dec *json.Decoder) error {
+			var page []networkInterface
+			err := dec.Decode(&page)
+			nics = append(nics, page...)
+			return err
+		})
+	return nics, err
+}
+
+func (c clientImpl) CreateNetworkInterface(group string, nic networkInterface) error {
+	return c.create(c.networkPath(group, "networkInterfaces", nic.Name),
+		networkAPIVersion, nic)
+}
+
+func (c clientImpl) DeleteNetworkInterface(group, name string) error {
+	return c.delete(c.networkPath(group, "networkInterfaces", name),
+		networkAPIVersion)
+}
+
+func (c clientImpl) ListVirtualMachines(group string) ([]virtualMachine, error) {
+	var vms []virtualMachine
+	err := c.list(c.computePath(group, ""), computeAPIVersion,
+		func(dec *json.Decoder) error {
+			var page []virtualMachine
+			err := dec.Decode(&page)
+			vms = append(vms, page...)
+			return err
+		})
+	return vms, err
+}
+
+func (c clientImpl) CreateVirtualMachine(group string, vm virtualMachine) error {
+	return c.create(c.computePath(group, vm.Name), computeAPIVersion, vm)
+}
+
+func (c clientImpl) DeleteVirtualMachine(group, name string) error {
+	return c.delete(c.computePath(group, name), computeAPIVersion)
+}
+
+func (c clientImpl) networkPath(group, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s", resourceID(c.subscription, group,
+		"Microsoft.Network"), kind, name)
+}
+
+func (c clientImpl) computePath(group, name string) string {
+	return fmt.Sprintf("%s/virtualMachines/%s", resourceID(c.subscription, group,
+		"Microsoft.Compute"), name)
+}
+
+// resourceID returns the ID prefix of the resources in `group` managed by the
+// resource `provider`.
+func resourceID(subscription, group, provider string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/%s",
+		subscription, group, provider)
+}
+
+// create creates or updates the resource at `path`, and waits for Azure to finish
+// provisioning it.
+func (c clientImpl) create(path, apiVersion string, body interface{}) error {
+	if err := c.do("PUT", path, apiVersion, body, nil); err != nil {
+		return err
+	}
+
+	var provisionErr error
+	err := util.WaitFor(func() bool {
+		var resource struct {
+			Properties struct {
+				ProvisioningState string `json:"provisioningState"`
+			} `json:"properties"`
+		}
+		provisionErr = c.do("GET", path, apiVersion, nil, &resource)
+		if provisionErr == nil &&
+			resource.Properties.ProvisioningState == "Failed" {
+			provisionErr = fmt.Errorf("failed to provision %s", path)
+		}
+		return provisionErr != nil ||
+			resource.Properties.ProvisioningState == "Succeeded"
+	}, 5*time.Second, 10*time.Minute)
+	if err != nil {
+		return err
+	}
+	return provisionErr
+}
+
+// delete deletes the resource at `path`, and waits for it to disappear.
+func (c clientImpl) delete(path, apiVersion string) error {
+	err := c.do("DELETE", path, apiVersion, nil, nil)
+	if err != nil && err != errNotFound {
+		return err
+	}
+
+	return util.WaitFor(func() bool {
+		return c.do("GET", path, apiVersion, nil, nil) == errNotFound
+	}, 5*time.Second, 10*time.Minute)
+}
+
+// list calls `decode` on the "value" of each page of the resources at `path`.
+func (c clientImpl) list(path, apiVersion string,
+	decode func(*json.Decoder) error) error {
+
+	next := fmt.Sprintf("%s%s?api-version=%s", managementURL, path, apiVersion)
+	for next != "" {
+		var page struct {
+			Value    json.RawMessage `json:"value"`
+			NextLink string          `json:"nextLink"`
+		}
+		if err := c.request("GET", next, nil, &page); err != nil {
+			return err
+		}
+
+		err := decode(json.NewDecoder(bytes.NewReader(page.Value)))
+		if err != nil {
+			return err
+		}
+		next = page.NextLink
+	}
+	return nil
+}
+
+func (c clientImpl) do(method, path, apiVersion string, in, out interface{}) error {
+	return c.request(method, fmt.Sprintf("%s%s?api-version=%s", managementURL,
+		path, apiVersion), in, out)
+}
+
+// request sends a request with the JSON encoding of `in` as its body, and decodes the
+// response into `out`.  Either may be nil.
+func (c clientImpl) request(method, url string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: %s (%s)", method, url, apiErr.Error.Message,
+			resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,226 @@
+package azure
+
+import mock "github.com/stretchr/testify/mock"
+
+// mockClient is an autogenerated mock type for the client type
+type mockClient struct {
+	mock.Mock
+}
+
+// CreateNetworkInterface provides a mock function with given fields: group, nic
+func (_m *mockClient) CreateNetworkInterface(group string, nic networkInterface) error {
+	ret := _m.Called(group, nic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, networkInterface) error); ok {
+		r0 = rf(group, nic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePublicIPAddress provides a mock function with given fields: group, ip
+func (_m *mockClient) CreatePublicIPAddress(group string, ip publicIPAddress) error {
+	ret := _m.Called(group, ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, publicIPAddress) error); ok {
+		r0 = rf(group, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateResourceGroup provides a mock function with given fields: name, location
+func (_m *mockClient) CreateResourceGroup(name string, location string) error {
+	ret := _m.Called(name, location)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(name, location)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSecurityGroup provides a mock function with given fields: group, nsg
+func (_m *mockClient) CreateSecurityGroup(group string, nsg securityGroup) error {
+	ret := _m.Called(group, nsg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, securityGroup) error); ok {
+		r0 = rf(group, nsg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateVirtualMachine provides a mock function with given fields: group, vm
+func (_m *mockClient) CreateVirtualMachine(group string, vm virtualMachine) error {
+	ret := _m.Called(group, vm)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, virtualMachine) error); ok {
+		r0 = rf(group, vm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateVirtualNetwork provides a mock function with given fields: group, vnet
+func (_m *mockClient) CreateVirtualNetwork(group string, vnet virtualNetwork) error {
+	ret := _m.Called(group, vnet)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, virtualNetwork) error); ok {
+		r0 = rf(group, vnet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteNetworkInterface provides a mock function with given fields: group, name
+func (_m *mockClient) DeleteNetworkInterface(group string, name string) error {
+	ret := _m.Called(group, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(group, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePublicIPAddress provides a mock function with given fields: group, name
+func (_m *mockClient) DeletePublicIPAddress(group string, name string) error {
+	ret := _m.Called(group, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(group, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteVirtualMachine provides a mock function with given fields: group, name
+func (_m *mockClient) DeleteVirtualMachine(group string, name string) error {
+	ret := _m.Called(group, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(group, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSecurityGroup provides a mock function with given fields: group, name
+func (_m *mockClient) GetSecurityGroup(group string, name string) (securityGroup, error) {
+	ret := _m.Called(group, name)
+
+	var r0 securityGroup
+	if rf, ok := ret.Get(0).(func(string, string) securityGroup); ok {
+		r0 = rf(group, name)
+	} else {
+		r0 = ret.Get(0).(securityGroup)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(group, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListNetworkInterfaces provides a mock function with given fields: group
+func (_m *mockClient) ListNetworkInterfaces(group string) ([]networkInterface, error) {
+	ret := _m.Called(group)
+
+	var r0 []networkInterface
+	if rf, ok := ret.Get(0).(func(string) []networkInterface); ok {
+		r0 = rf(group)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]networkInterface)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(group)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPublicIPAddresses provides a mock function with given fields:
+func (_m *mockClient) ListPublicIPAddresses() ([]publicIPAddress, error) {
+	ret := _m.Called()
+
+	var r0 []publicIPAddress
+	if rf, ok := ret.Get(0).(func() []publicIPAddress); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]publicIPAddress)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListVirtualMachines provides a mock function with given fields: group
+func (_m *mockClient) ListVirtualMachines(group string) ([]virtualMachine, error) {
+	ret := _m.Called(group)
+
+	var r0 []virtualMachine
+	if rf, ok := ret.Get(0).(func(string) []virtualMachine); ok {
+		r0 = rf(group)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]virtualMachine)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(group)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+var _ client = (*mockClient)(nil)
@@ -0,0 +1,85 @@
+package cluster
+
+import (
+	"math/rand"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// The delay before a failing provider instance is contacted again doubles with each
+// consecutive failure, from `minBackoff` up to `maxBackoff`.
+const (
+	minBackoff = time.Minute
+	maxBackoff = 30 * time.Minute
+)
+
+// Stored in variables so they may be mocked out.
+var now = time.Now
+var jitter = rand.Float64
+
+// backoff tracks the consecutive failures of a provider instance.
+type backoff struct {
+	failures int
+	until    time.Time
+
+	// Whether the instance failed since the last call to settle().
+	failed bool
+}
+
+// backoffs tracks the provider instances that are backing off after failures, so that
+// a broken provider is left alone for a while instead of holding up the others.
+type backoffs map[instance]*backoff
+
+// ready returns whether `inst` may be contacted.
+func (bs backoffs) ready(inst instance) bool {
+	b := bs[inst]
+	return b == nil || !now().Before(b.until)
+}
+
+// until returns the time until which `inst` is backing off, or the zero time if it
+// isn't.
+func (bs backoffs) until(inst instance) time.Time {
+	if b := bs[inst]; b != nil && now().Before(b.until) {
+		return b.until
+	}
+	return time.Time{}
+}
+
+// fail records that a request to `inst` failed with `err`, and backs it off.
+func (bs backoffs) fail(inst instance, err error) {
+	b := bs[inst]
+	if b == nil {
+		b = &backoff{}
+		bs[inst] = b
+	}
+
+	b.failures++
+	b.failed = true
+	b.until = now().Add(backoffDelay(b.failures))
+	log.WithError(err).WithField("until", b.until).Infof(
+		"Backing off %s in %s after %d consecutive failures.", inst.provider,
+		inst.region, b.failures)
+}
+
+// settle forgives the instances that were ready, yet haven't failed since the last
+// call.  Instances that are still backing off keep their failures.
+func (bs backoffs) settle() {
+	for inst, b := range bs {
+		if !b.failed && !now().Before(b.until) {
+			delete(bs, inst)
+		}
+		b.failed = false
+	}
+}
+
+// backoffDelay returns how long to wait after the given number of consecutive
+// failures.  The delay is jittered so that instances that failed together don't
+// retry in lockstep.
+func backoffDelay(failures int) time.Duration {
+	delay := maxBackoff
+	if shift := uint(failures - 1); shift < 16 && minBackoff<<shift < maxBackoff {
+		delay = minBackoff << shift
+	}
+	return delay/2 + time.Duration(jitter()*float64(delay/2))
+}
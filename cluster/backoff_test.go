@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	jitter = func() float64 { return 1 }
+	assert.Equal(t, time.Minute, backoffDelay(1))
+	assert.Equal(t, 2*time.Minute, backoffDelay(2))
+	assert.Equal(t, 16*time.Minute, backoffDelay(5))
+	assert.Equal(t, maxBackoff, backoffDelay(6))
+	assert.Equal(t, maxBackoff, backoffDelay(100))
+
+	jitter = func() float64 { return 0 }
+	assert.Equal(t, 30*time.Second, backoffDelay(1))
+	assert.Equal(t, maxBackoff/2, backoffDelay(100))
+}
+
+func TestBackoffs(t *testing.T) {
+	jitter = func() float64 { return 1 }
+	start := time.Unix(1000, 0)
+	now = func() time.Time { return start }
+	defer func() { now = time.Now }()
+
+	amzn := instance{FakeAmazon, "region"}
+	vgrnt := instance{FakeVagrant, ""}
+	bs := backoffs{}
+	assert.True(t, bs.ready(amzn))
+	assert.True(t, bs.until(amzn).IsZero())
+
+	bs.fail(amzn, errors.New("err"))
+	assert.False(t, bs.ready(amzn))
+	assert.Equal(t, start.Add(time.Minute), bs.until(amzn))
+	assert.True(t, bs.ready(vgrnt))
+
+	// Instances keep their failures while they're backing off.
+	bs.settle()
+	now = func() time.Time { return start.Add(time.Minute) }
+	assert.True(t, bs.ready(amzn))
+	assert.True(t, bs.until(amzn).IsZero())
+
+	// Consecutive failures back off exponentially.
+	bs.fail(amzn, errors.New("err"))
+	assert.Equal(t, start.Add(3*time.Minute), bs.until(amzn))
+	bs.settle()
+
+	// Instances that don't fail once they're ready are forgiven.
+	now = func() time.Time { return start.Add(3 * time.Minute) }
+	bs.settle()
+	assert.Empty(t, bs)
+
+	bs.fail(amzn, errors.New("err"))
+	assert.Equal(t, start.Add(4*time.Minute), bs.until(amzn))
+}
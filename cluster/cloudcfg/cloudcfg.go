@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"text/template"
+
+	"github.com/NetSys/quilt/certs"
 )
 
 const (
@@ -11,21 +13,46 @@ const (
 )
 
 // Ubuntu generates a cloud config file for the Ubuntu operating system with the
-// corresponding `version`.
-func Ubuntu(keys []string, version string) string {
-	t := template.Must(template.New("cloudConfig").Parse(cfgTemplate))
-
-	var cloudConfigBytes bytes.Buffer
-	err := t.Execute(&cloudConfigBytes, struct {
+// corresponding `version`.  The minion is provisioned with `caCert`, the certificate
+// of the CA that signs the certificates of its peers.  The cloud config is readable
+// by anything running on the machine, so it holds no keys.  The minion instead
+// generates its own, which the foreman certifies once it connects.
+func Ubuntu(keys []string, version string, caCert string) string {
+	return execute(cfgTemplate, struct {
 		QuiltImage    string
 		UbuntuVersion string
 		SSHKeys       string
+		TLSDir        string
+		CACertFile    string
+		CACert        string
 	}{
 		QuiltImage:    quiltImage,
 		UbuntuVersion: version,
 		SSHKeys:       strings.Join(keys, "\n"),
+		TLSDir:        certs.MinionDir,
+		CACertFile:    certs.CACertFile,
+		CACert:        caCert,
 	})
-	if err != nil {
+}
+
+// Local generates the script that boots a minion inside a Docker container.  The
+// script starts a nested Docker daemon for the minion's containers, and then runs the
+// minion with the CA certificate that was copied into the container's TLS directory.
+func Local() string {
+	return execute(localTemplate, struct {
+		QuiltImage string
+		TLSDir     string
+	}{
+		QuiltImage: quiltImage,
+		TLSDir:     certs.MinionDir,
+	})
+}
+
+func execute(tmpl string, data interface{}) string {
+	t := template.Must(template.New("cloudConfig").Parse(tmpl))
+
+	var cloudConfigBytes bytes.Buffer
+	if err := t.Execute(&cloudConfigBytes, data); err != nil {
 		panic(err)
 	}
 
@@ -1,12 +1,25 @@
 package cloudcfg
 
-import "testing"
+import (
+	"testing"
+)
 
 func TestCloudConfig(t *testing.T) {
-	cfgTemplate = "({{.QuiltImage}}) ({{.SSHKeys}}) ({{.UbuntuVersion}})"
+	cfgTemplate = "({{.QuiltImage}}) ({{.SSHKeys}}) ({{.UbuntuVersion}}) " +
+		"({{.TLSDir}}) ({{.CACertFile}}) ({{.CACert}})"
 
-	res := Ubuntu([]string{"a", "b"}, "1")
-	exp := "(quilt/quilt:latest) (a\nb) (1)"
+	res := Ubuntu([]string{"a", "b"}, "1", "ca")
+	exp := "(quilt/quilt:latest) (a\nb) (1) (/etc/quilt/tls) (ca.crt) (ca)"
+	if res != exp {
+		t.Errorf("res: %s\nexp: %s", res, exp)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	localTemplate = "({{.QuiltImage}}) ({{.TLSDir}})"
+
+	res := Local()
+	exp := "(quilt/quilt:latest) (/etc/quilt/tls)"
 	if res != exp {
 		t.Errorf("res: %s\nexp: %s", res, exp)
 	}
@@ -58,6 +58,7 @@ initialize_minion() {
 	-v /var/run/docker.sock:/var/run/docker.sock \
 	-v /etc/ssl/certs/ca-certificates.crt:/etc/ssl/certs/ca-certificates.crt \
 	-v /home/quilt/.ssh:/home/quilt/.ssh:rw \
+	-v {{.TLSDir}}:{{.TLSDir}}:rw \
 	-v /run/docker:/run/docker:rw {{.QuiltImage}} \
 	quilt minion
 	Restart=on-failure
@@ -67,6 +68,14 @@ initialize_minion() {
 	EOF
 }
 
+install_ca_cert() {
+	install -d -m 700 {{.TLSDir}}
+
+	cat <<- 'EOF' > {{.TLSDir}}/{{.CACertFile}}
+{{.CACert}}
+	EOF
+}
+
 install_docker() {
 	echo "deb https://apt.dockerproject.org/repo ubuntu-{{.UbuntuVersion}} main" > /etc/apt/sources.list.d/docker.list
 	apt-get update
@@ -102,6 +111,7 @@ setup_user quilt "$ssh_keys"
 sudo mkdir /run/docker/plugins
 sudo chmod -R /run/docker/plugins 0755
 
+install_ca_cert
 install_docker
 initialize_ovs
 initialize_docker
@@ -122,3 +132,29 @@ systemctl restart {docker,ovs,minion}.service
 echo -n "Completed Boot Script: " >> /var/log/bootscript.log
 date >> /var/log/bootscript.log
     `
+
+var localTemplate = `#!/bin/sh
+set -e
+
+chmod 700 {{.TLSDir}}
+
+dockerd --ip-forward=false --bridge=none -H unix:///var/run/docker.sock \
+	> /var/log/dockerd.log 2>&1 &
+until docker info > /dev/null 2>&1; do
+	sleep 1
+done
+
+# The machines share the host's kernel, so the modules may already be loaded.
+docker run --rm --privileged {{.QuiltImage}} \
+	bash -c "insmod /modules/openvswitch.ko \
+	         && insmod /modules/vport-geneve.ko \
+	         && insmod /modules/vport-stt.ko" || true
+
+mkdir -p /run/docker/plugins
+docker pull {{.QuiltImage}}
+exec docker run --net=host --name=minion --privileged \
+	-v /var/run/docker.sock:/var/run/docker.sock \
+	-v {{.TLSDir}}:{{.TLSDir}}:rw \
+	-v /run/docker:/run/docker:rw {{.QuiltImage}} \
+	quilt minion
+`
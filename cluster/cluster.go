@@ -1,13 +1,17 @@
 package cluster
 
 import (
-	"errors"
+	"fmt"
 	"time"
 
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/cluster/acl"
 	"github.com/NetSys/quilt/cluster/amazon"
+	"github.com/NetSys/quilt/cluster/azure"
+	"github.com/NetSys/quilt/cluster/digitalocean"
 	"github.com/NetSys/quilt/cluster/foreman"
 	"github.com/NetSys/quilt/cluster/google"
+	"github.com/NetSys/quilt/cluster/local"
 	"github.com/NetSys/quilt/cluster/machine"
 	"github.com/NetSys/quilt/cluster/vagrant"
 	"github.com/NetSys/quilt/db"
@@ -29,7 +33,8 @@ type provider interface {
 }
 
 // Store the providers in a variable so we can change it in the tests
-var allProviders = []db.Provider{db.Amazon, db.Google, db.Vagrant}
+var allProviders = []db.Provider{db.Amazon, db.Azure, db.DigitalOcean, db.Google,
+	db.Local, db.Vagrant}
 
 type instance struct {
 	provider db.Provider
@@ -40,6 +45,15 @@ type cluster struct {
 	namespace string
 	conn      db.Conn
 	providers map[instance]provider
+
+	// The provider instances that are backing off after failures.
+	backoffs backoffs
+
+	// The CA that the machines we boot trust, and that certifies their minions.
+	ca certs.CA
+
+	// Closed to stop the cluster's reconciler once its namespace is removed.
+	stop chan struct{}
 }
 
 var myIP = util.MyIP
@@ -54,42 +68,121 @@ const (
 	updateIPs
 )
 
-// Run continually checks 'conn' for cluster changes and recreates the cluster as
-// needed.
-func Run(conn db.Conn) {
-	var clst *cluster
-	for range conn.TriggerTick(30, db.ClusterTable, db.MachineTable, db.ACLTable).C {
-		clst = updateCluster(conn, clst)
+// Run continually checks 'conn' for new and removed namespaces, and runs a reconciler
+// for each that syncs its cluster with its cloud providers.  Booted machines are
+// provisioned with the certificate of 'ca', which the foreman then uses to certify
+// their minions.
+func Run(conn db.Conn, ca certs.CA) {
+	ready := make(chan string)
+	go runForeman(conn, ca, ready)
+
+	clusters := map[string]*cluster{}
+	for range conn.TriggerTick(30, db.ClusterTable).C {
+		updateClusters(conn, clusters, ca, ready)
+	}
+}
+
+// updateClusters starts a reconciler for each namespace in the cluster table that is
+// new since the last call, and stops those of the namespaces that were removed.
+// `clusters` is keyed by namespace, and is updated in place.  Once a reconciler has
+// first run, its namespace is sent on `ready`.
+func updateClusters(conn db.Conn, clusters map[string]*cluster, ca certs.CA,
+	ready chan<- string) {
+
+	namespaces := map[string]struct{}{}
+	for _, dbc := range conn.SelectFromCluster(nil) {
+		namespaces[dbc.Namespace] = struct{}{}
+	}
+
+	for namespace, clst := range clusters {
+		if _, ok := namespaces[namespace]; !ok {
+			close(clst.stop)
+			delete(clusters, namespace)
+		}
+	}
+
+	// The namespaces are independent, so each is reconciled on its own, and one
+	// slow cloud provider doesn't hold up the others.
+	for namespace := range namespaces {
+		if _, ok := clusters[namespace]; !ok {
+			clst := newCluster(conn, namespace, ca)
+			clusters[namespace] = clst
+			go clst.run(ready)
+		}
+	}
+}
+
+// run reconciles the cluster whenever the database changes, until it's stopped.  Once
+// the first run has added the machines it found to the database, the namespace is sent
+// on `ready`.
+func (clst cluster) run(ready chan<- string) {
+	trigger := clst.conn.TriggerTick(30, db.ClusterTable, db.MachineTable,
+		db.ACLTable)
+	defer trigger.Stop()
+
+	loopLog := util.NewEventTimer("Cluster-" + clst.namespace)
+	for first := true; ; first = false {
+		select {
+		case <-trigger.C:
+		case <-clst.stop:
+			return
+		}
+
+		loopLog.LogStart()
+		clst.runOnce()
+		loopLog.LogEnd()
+
+		if first {
+			// The foreman may be busy with the minions of other namespaces.
+			go func() { ready <- clst.namespace }()
+		}
 
 		// Somewhat of a crude rate-limit of once every five seconds to avoid
 		// stressing out the cloud providers with too many API calls.
-		sleep(5 * time.Second)
+		select {
+		case <-time.After(5 * time.Second):
+		case <-clst.stop:
+			return
+		}
 	}
 }
 
-func updateCluster(conn db.Conn, clst *cluster) *cluster {
-	namespace, err := conn.GetClusterNamespace()
+// runForeman runs the foreman on its own loop, so that minions are kept configured
+// however long the cloud providers take.  The foreman learns the roles of the machines
+// of each namespace sent on `ready`, which were booted by previous runs of the daemon,
+// and leaves their minions alone until then.
+func runForeman(conn db.Conn, ca certs.CA, ready <-chan string) {
+	creds, err := ca.Issue("foreman", certs.Client)
 	if err != nil {
-		return clst
+		log.WithError(err).Error("Failed to issue foreman credentials.")
+		return
 	}
 
-	if clst == nil || clst.namespace != namespace {
-		clst = newCluster(conn, namespace)
-		clst.runOnce()
-		foreman.Init(clst.conn)
-	}
+	loopLog := util.NewEventTimer("Foreman")
+	trigger := conn.TriggerTick(30, db.ClusterTable, db.MachineTable)
+	for {
+		select {
+		case namespace := <-ready:
+			foreman.Init(conn, namespace, ca, creds)
+		case <-trigger.C:
+		}
 
-	clst.runOnce()
-	foreman.RunOnce(clst.conn)
+		loopLog.LogStart()
+		foreman.RunOnce(conn)
+		loopLog.LogEnd()
 
-	return clst
+		sleep(5 * time.Second)
+	}
 }
 
-func newCluster(conn db.Conn, namespace string) *cluster {
+func newCluster(conn db.Conn, namespace string, ca certs.CA) *cluster {
 	clst := &cluster{
 		namespace: namespace,
 		conn:      conn,
 		providers: make(map[instance]provider),
+		backoffs:  backoffs{},
+		ca:        ca,
+		stop:      make(chan struct{}),
 	}
 
 	for _, p := range allProviders {
@@ -107,6 +200,22 @@ func newCluster(conn db.Conn, namespace string) *cluster {
 	return clst
 }
 
+// connect retries connecting to the provider instance `inst`, which failed when the
+// cluster was created, and backs it off if it fails again.  Nothing is done with it
+// until its machines have been listed, lest machines it already runs be booted again.
+func (clst cluster) connect(inst instance) {
+	prvdr, err := newProvider(inst.provider, clst.namespace, inst.region)
+	if err != nil {
+		log.WithError(err).Warnf("Provider %s is unavailable in %s.",
+			inst.provider, inst.region)
+		clst.backoffs.fail(inst, err)
+		return
+	}
+
+	log.Infof("Connected to provider %s in %s.", inst.provider, inst.region)
+	clst.providers[inst] = prvdr
+}
+
 func (clst cluster) runOnce() {
 	/* Each iteration of this loop does the following:
 	 *
@@ -119,7 +228,11 @@ func (clst cluster) runOnce() {
 	 * instances) that should be reflected in the database.  Therefore, if updates
 	 * are necessary the code loops so that database can be updated before the next
 	 * runOnce() call.  Once the loop as converged, it then updates the cluster ACLs
-	 * before finally exiting. */
+	 * before finally exiting.
+	 *
+	 * Provider instances that fail are left alone until their backoff expires, so
+	 * that the healthy ones carry on without them. */
+	defer clst.syncBackoffs()
 	for i := 0; i < 2; i++ {
 		jr, err := clst.join()
 		if err != nil {
@@ -161,14 +274,26 @@ func (clst cluster) updateCloud(machines []machine.Machine, act action) {
 	log.WithField("count", len(machines)).
 		Infof("Attempt to %s machines.", actionString)
 
+	if act == boot {
+		for i := range machines {
+			machines[i].CACert = clst.ca.CertPEM()
+		}
+	}
+
 	noFailures := true
 	groupedMachines := groupBy(machines)
 	for i, providerMachines := range groupedMachines {
+		if !clst.backoffs.ready(i) {
+			noFailures = false
+			log.Debugf("Skipping %s in %s while it backs off.", i.provider,
+				i.region)
+			continue
+		}
+
 		providerInst, ok := clst.providers[i]
 		if !ok {
 			noFailures = false
-			log.Warnf("Provider %s is unavailable in %s.", i.provider,
-				i.region)
+			clst.connect(i)
 			continue
 		}
 		var err error
@@ -184,6 +309,7 @@ func (clst cluster) updateCloud(machines []machine.Machine, act action) {
 
 		if err != nil {
 			noFailures = false
+			clst.backoffs.fail(i, err)
 			switch act {
 			case boot:
 				log.WithError(err).Warnf(
@@ -197,6 +323,7 @@ func (clst cluster) updateCloud(machines []machine.Machine, act action) {
 					i.provider)
 			}
 		}
+		clst.recordEvent(act, i, len(providerMachines), err)
 	}
 
 	if noFailures {
@@ -208,12 +335,42 @@ func (clst cluster) updateCloud(machines []machine.Machine, act action) {
 		case updateIPs:
 			log.Info("Successfully updated floating IPs")
 		}
-	} else {
-		log.Infof("Due to failures, sleeping for 1 minute")
-		sleep(60 * time.Second)
 	}
 }
 
+// recordEvent records the outcome of performing `act` on `count` machines of the
+// provider instance `inst`.
+func (clst cluster) recordEvent(act action, inst instance, count int, err error) {
+	where := string(inst.provider)
+	if inst.region != "" {
+		where += " in " + inst.region
+	}
+
+	machines := fmt.Sprintf("%d machines", count)
+	if count == 1 {
+		machines = "1 machine"
+	}
+
+	event := db.Event{Namespace: clst.namespace}
+	switch {
+	case err != nil:
+		verb := map[action]string{boot: "boot", stop: "stop",
+			updateIPs: "update the floating IPs of"}[act]
+		event.Type = db.ProviderError
+		event.Message = fmt.Sprintf("Failed to %s %s on %s: %s",
+			verb, machines, where, err)
+	case act == boot:
+		event.Type = db.MachineBooted
+		event.Message = fmt.Sprintf("Booted %s on %s.", machines, where)
+	case act == stop:
+		event.Type = db.MachineTerminated
+		event.Message = fmt.Sprintf("Stopped %s on %s.", machines, where)
+	default:
+		return
+	}
+	clst.conn.RecordEvent(event)
+}
+
 type joinResult struct {
 	machines []db.Machine
 	acl      db.ACL
@@ -226,38 +383,43 @@ type joinResult struct {
 func (clst cluster) join() (joinResult, error) {
 	res := joinResult{}
 
-	cloudMachines, err := clst.get()
-	if err != nil {
-		log.WithError(err).Error("Failed to list machines")
-		return res, err
-	}
-
-	err = clst.conn.Txn(db.ACLTable, db.ClusterTable,
+	cloudMachines := clst.get()
+	err := clst.conn.Txn(db.ACLTable, db.ClusterTable,
 		db.MachineTable).Run(func(view db.Database) error {
-		namespace, err := view.GetClusterNamespace()
-		if err != nil {
-			log.WithError(err).Error("Failed to get namespace")
-			return err
-		}
-
-		if clst.namespace != namespace {
-			err := errors.New("namespace change during a cluster run")
+		if _, err := view.GetCluster(clst.namespace); err != nil {
 			log.WithError(err).Debug("Cluster run abort")
 			return err
 		}
 
-		res.acl, err = view.GetACL()
+		var err error
+		res.acl, err = view.GetACL(clst.namespace)
 		if err != nil {
 			log.WithError(err).Error("Failed to get ACLs")
 		}
 
-		res.machines = view.SelectFromMachine(nil)
+		res.machines = view.SelectFromMachine(func(m db.Machine) bool {
+			return m.Namespace == clst.namespace
+		})
 
-		dbResult := syncDB(cloudMachines, res.machines)
+		// The cloud machines of instances that are backing off are unknown, so
+		// their database machines are left alone until they recover.
+		var readyMachines []db.Machine
+		for _, m := range res.machines {
+			if clst.backoffs.ready(instance{m.Provider, m.Region}) {
+				readyMachines = append(readyMachines, m)
+			}
+		}
+
+		dbResult := syncDB(cloudMachines, readyMachines)
 		res.boot = dbResult.boot
 		res.terminate = dbResult.stop
 		res.updateIPs = dbResult.updateIPs
 
+		for _, dbm := range dbResult.remove {
+			log.WithField("machine", dbm).Info("Terminating machine is gone.")
+			view.Remove(dbm)
+		}
+
 		for _, pair := range dbResult.pairs {
 			dbm := pair.L.(db.Machine)
 			m := pair.R.(machine.Machine)
@@ -329,6 +491,10 @@ func (clst cluster) syncACLs(adminACLs []string, appACLs []db.PortRange,
 	}
 
 	for inst, prvdr := range clst.providers {
+		if !clst.backoffs.ready(inst) {
+			continue
+		}
+
 		// For providers with no specified machines, we remove all ACLs.
 		// Otherwise we set acls to what's specified.
 		var setACLs []acl.ACL
@@ -339,15 +505,26 @@ func (clst cluster) syncACLs(adminACLs []string, appACLs []db.PortRange,
 		if err := prvdr.SetACLs(setACLs); err != nil {
 			log.WithError(err).Warnf("Could not update ACLs on %s in %s.",
 				inst.provider, inst.region)
+			clst.backoffs.fail(inst, err)
 		}
 	}
 }
 
+// preemptibleMatches returns whether `m` may serve as `dbm` given their
+// preemptibility.  Preemptible machines may serve as on-demand ones, because before
+// preemptibility was configurable, every Amazon machine was a spot instance, and
+// replacing them all at once would take down the cluster.  They're instead replaced
+// by on-demand machines as they're reclaimed.
+func preemptibleMatches(dbm db.Machine, m machine.Machine) bool {
+	return dbm.Preemptible == m.Preemptible || m.Preemptible
+}
+
 type syncDBResult struct {
 	pairs     []join.Pair
 	boot      []machine.Machine
 	stop      []machine.Machine
 	updateIPs []machine.Machine
+	remove    []db.Machine
 }
 
 func syncDB(cms []machine.Machine, dbms []db.Machine) syncDBResult {
@@ -359,7 +536,8 @@ func syncDB(cms []machine.Machine, dbms []db.Machine) syncDBResult {
 
 		if dbm.CloudID == m.ID && dbm.Provider == m.Provider &&
 			dbm.Region == m.Region && dbm.Size == m.Size &&
-			(m.DiskSize == 0 || dbm.DiskSize == m.DiskSize) {
+			(m.DiskSize == 0 || dbm.DiskSize == m.DiskSize) &&
+			preemptibleMatches(dbm, m) {
 			return 0
 		}
 
@@ -371,19 +549,33 @@ func syncDB(cms []machine.Machine, dbms []db.Machine) syncDBResult {
 		m := r.(machine.Machine)
 
 		switch {
+		case dbm.Terminating:
+			// Terminating machines are only matched by CloudID, lest they
+			// claim their own replacement.
+			return -1
 		case dbm.Provider != m.Provider ||
 			dbm.Region != m.Region ||
 			dbm.Size != m.Size ||
-			(m.DiskSize != 0 && dbm.DiskSize != m.DiskSize):
+			(m.DiskSize != 0 && dbm.DiskSize != m.DiskSize) ||
+			!preemptibleMatches(dbm, m):
 			return -1
 		case dbm.CloudID == m.ID:
 			panic("Not Reached") // Should have been hit by the first join.
+		}
+
+		// Prefer machines whose preemptibility agrees.
+		score := 1
+		if dbm.Preemptible != m.Preemptible {
+			score += 3
+		}
+
+		switch {
 		case dbm.FloatingIP == m.FloatingIP:
-			return 1
+			return score
 		case dbm.PublicIP == m.PublicIP || dbm.PrivateIP == m.PrivateIP:
-			return 2
+			return score + 1
 		default:
-			return 3
+			return score + 2
 		}
 	})
 
@@ -393,12 +585,22 @@ func syncDB(cms []machine.Machine, dbms []db.Machine) syncDBResult {
 
 	for _, dbm := range dbmis {
 		m := dbm.(db.Machine)
+
+		// The engine has already booted a replacement for terminating machines,
+		// so once the cloud provider has reclaimed one, it's simply forgotten.
+		if m.Terminating {
+			ret.remove = append(ret.remove, m)
+			continue
+		}
+
 		ret.boot = append(ret.boot, machine.Machine{
-			Size:     m.Size,
-			Provider: m.Provider,
-			Region:   m.Region,
-			DiskSize: m.DiskSize,
-			SSHKeys:  m.SSHKeys})
+			Size:        m.Size,
+			Provider:    m.Provider,
+			Region:      m.Region,
+			DiskSize:    m.DiskSize,
+			SSHKeys:     m.SSHKeys,
+			Preemptible: m.Preemptible,
+			SpotPrice:   m.SpotPrice})
 	}
 
 	for _, pair := range append(pair1, pair2...) {
@@ -416,16 +618,43 @@ func syncDB(cms []machine.Machine, dbms []db.Machine) syncDBResult {
 	return ret
 }
 
-func (clst cluster) get() ([]machine.Machine, error) {
+// get lists the machines of the provider instances that aren't backing off.  Those
+// that fail to list their machines are backed off.
+func (clst cluster) get() []machine.Machine {
 	var cloudMachines []machine.Machine
-	for _, p := range clst.providers {
+	for inst, p := range clst.providers {
+		if !clst.backoffs.ready(inst) {
+			continue
+		}
+
 		providerMachines, err := p.List()
 		if err != nil {
-			return []machine.Machine{}, err
+			log.WithError(err).Errorf("Failed to list machines on %s in %s.",
+				inst.provider, inst.region)
+			clst.backoffs.fail(inst, err)
+			continue
 		}
 		cloudMachines = append(cloudMachines, providerMachines...)
 	}
-	return cloudMachines, nil
+	return cloudMachines
+}
+
+// syncBackoffs forgives the provider instances that didn't fail during the run, and
+// records which of the namespace's machines have providers that are backing off.
+func (clst cluster) syncBackoffs() {
+	clst.backoffs.settle()
+	clst.conn.Txn(db.MachineTable).Run(func(view db.Database) error {
+		for _, m := range view.SelectFromMachine(func(m db.Machine) bool {
+			return m.Namespace == clst.namespace
+		}) {
+			until := clst.backoffs.until(instance{m.Provider, m.Region})
+			if !until.Equal(m.BackoffUntil) {
+				m.BackoffUntil = until
+				view.Commit(m)
+			}
+		}
+		return nil
+	})
 }
 
 func groupBy(machines []machine.Machine) map[instance][]machine.Machine {
@@ -439,26 +668,43 @@ func groupBy(machines []machine.Machine) map[instance][]machine.Machine {
 }
 
 func newProviderImpl(p db.Provider, namespace, region string) (provider, error) {
+	var prvdr provider
+	var err error
 	switch p {
 	case db.Amazon:
-		return amazon.New(namespace, region)
+		prvdr, err = amazon.New(namespace, region)
+	case db.Azure:
+		prvdr, err = azure.New(namespace, region)
+	case db.DigitalOcean:
+		prvdr, err = digitalocean.New(namespace, region)
 	case db.Google:
-		return google.New(namespace, region)
+		prvdr, err = google.New(namespace, region)
+	case db.Local:
+		prvdr, err = local.New(namespace)
 	case db.Vagrant:
-		return vagrant.New(namespace)
+		prvdr, err = vagrant.New(namespace)
 	default:
 		panic("Unimplemented")
 	}
+
+	if err != nil {
+		return nil, err
+	}
+	return instrumentedProvider{prvdr, instance{p, region}}, nil
 }
 
 func validRegionsImpl(p db.Provider) []string {
 	switch p {
 	case db.Amazon:
 		return amazon.Regions
+	case db.Azure:
+		return azure.Regions
+	case db.DigitalOcean:
+		return digitalocean.Regions
 	case db.Google:
 		return google.Zones
-	case db.Vagrant:
-		return []string{""} // Vagrant has no regions
+	case db.Local, db.Vagrant:
+		return []string{""} // Local and Vagrant machines have no regions
 	default:
 		panic("Unimplemented")
 	}
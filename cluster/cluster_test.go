@@ -1,11 +1,13 @@
 package cluster
 
 import (
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/cluster/acl"
 	"github.com/NetSys/quilt/cluster/machine"
 	"github.com/NetSys/quilt/db"
@@ -18,6 +20,7 @@ var FakeVagrant db.Provider = "FakeVagrant"
 var amazonCloudConfig = "Amazon Cloud Config"
 var vagrantCloudConfig = "Vagrant Cloud Config"
 var testRegion = "Fake region"
+var testCA, _ = certs.NewCA()
 
 type providerRequest struct {
 	request  machine.Machine
@@ -46,6 +49,11 @@ type fakeProvider struct {
 	stopRequests []string
 	updateIPs    []ipRequest
 	aclRequests  []acl.ACL
+
+	listError, bootError error
+
+	// If set, Boot waits for it to be closed.
+	block chan struct{}
 }
 
 func fakeValidRegions(p db.Provider) []string {
@@ -78,6 +86,10 @@ func (p *fakeProvider) clearLogs() {
 }
 
 func (p *fakeProvider) List() ([]machine.Machine, error) {
+	if p.listError != nil {
+		return nil, p.listError
+	}
+
 	var machines []machine.Machine
 	for _, machine := range p.machines {
 		machines = append(machines, machine)
@@ -86,6 +98,14 @@ func (p *fakeProvider) List() ([]machine.Machine, error) {
 }
 
 func (p *fakeProvider) Boot(bootSet []machine.Machine) error {
+	if p.block != nil {
+		<-p.block
+	}
+
+	if p.bootError != nil {
+		return p.bootError
+	}
+
 	for _, bootSet := range bootSet {
 		p.idCounter++
 		idStr := strconv.Itoa(p.idCounter)
@@ -135,19 +155,20 @@ func (p *fakeProvider) ChooseSize(ram stitch.Range, cpu stitch.Range,
 func newTestCluster(namespace string) *cluster {
 	sleep = func(t time.Duration) {}
 	mock()
-	return newCluster(db.New(), namespace)
+	return newCluster(db.New(), namespace, testCA)
 }
 
 func TestPanicBadProvider(t *testing.T) {
-	temp := allProviders
+	temp, tempNew, tempRegions := allProviders, newProvider, validRegions
 	defer func() {
 		r := recover()
 		assert.NotNil(t, r)
-		allProviders = temp
+		allProviders, newProvider, validRegions = temp, tempNew, tempRegions
 	}()
 	allProviders = []db.Provider{FakeAmazon}
+	newProvider, validRegions = newProviderImpl, validRegionsImpl
 	conn := db.New()
-	newCluster(conn, "test")
+	newCluster(conn, "test", testCA)
 }
 
 func TestSyncDB(t *testing.T) {
@@ -158,6 +179,7 @@ func TestSyncDB(t *testing.T) {
 		assert.Equal(t, expected.boot, dbRes.boot, "boot")
 		assert.Equal(t, expected.stop, dbRes.stop, "stop")
 		assert.Equal(t, expected.updateIPs, dbRes.updateIPs, "updateIPs")
+		assert.Equal(t, expected.remove, dbRes.remove, "remove")
 	}
 
 	var noMachines []machine.Machine
@@ -227,6 +249,38 @@ func TestSyncDB(t *testing.T) {
 			boot: []machine.Machine{{DiskSize: 4}},
 		})
 
+	// Test on-demand instances aren't used as spot instances
+	checkSyncDB([]machine.Machine{{}},
+		[]db.Machine{{Preemptible: true, SpotPrice: 0.25}}, syncDBResult{
+			stop: []machine.Machine{{}},
+			boot: []machine.Machine{{Preemptible: true, SpotPrice: 0.25}},
+		})
+	checkSyncDB([]machine.Machine{{Preemptible: true}},
+		[]db.Machine{{Preemptible: true, SpotPrice: 0.25}}, syncDBResult{})
+
+	// Test spot instances booted before preemptibility was configurable are kept
+	// for on-demand machines, though matching instances are preferred
+	checkSyncDB([]machine.Machine{{ID: "spot", Preemptible: true}},
+		[]db.Machine{{CloudID: "spot"}}, syncDBResult{})
+	checkSyncDB([]machine.Machine{{ID: "spot", Preemptible: true}},
+		[]db.Machine{{}}, syncDBResult{})
+	checkSyncDB([]machine.Machine{{ID: "spot", Preemptible: true}, {ID: "od"}},
+		[]db.Machine{{}}, syncDBResult{
+			stop: []machine.Machine{{ID: "spot", Preemptible: true}},
+		})
+
+	// Test terminating machines are kept until they're reclaimed, but never
+	// replaced or paired with their replacement
+	dbDoomed := db.Machine{CloudID: "doomed", Terminating: true}
+	checkSyncDB([]machine.Machine{{ID: "doomed"}, {ID: "new"}},
+		[]db.Machine{dbDoomed, {}}, syncDBResult{})
+	checkSyncDB([]machine.Machine{{ID: "new"}}, []db.Machine{dbDoomed, {}},
+		syncDBResult{remove: []db.Machine{dbDoomed}})
+	checkSyncDB([]machine.Machine{{ID: "new"}}, []db.Machine{dbDoomed},
+		syncDBResult{
+			stop:   []machine.Machine{{ID: "new"}},
+			remove: []db.Machine{dbDoomed},
+		})
 }
 
 func TestSync(t *testing.T) {
@@ -269,9 +323,10 @@ func TestSync(t *testing.T) {
 
 	// Test initial boot
 	clst := newTestCluster("ns")
-	setNamespace(clst.conn, "ns")
+	addCluster(clst.conn, "ns")
 	clst.conn.Txn(db.AllTables...).Run(func(view db.Database) error {
 		m := view.InsertMachine()
+		m.Namespace = "ns"
 		m.Role = db.Master
 		m.Provider = FakeAmazon
 		m.Region = testRegion
@@ -286,6 +341,7 @@ func TestSync(t *testing.T) {
 	// Test adding a machine with the same provider
 	clst.conn.Txn(db.AllTables...).Run(func(view db.Database) error {
 		m := view.InsertMachine()
+		m.Namespace = "ns"
 		m.Role = db.Master
 		m.Provider = FakeAmazon
 		m.Region = testRegion
@@ -300,6 +356,7 @@ func TestSync(t *testing.T) {
 	// Test adding a machine with a different provider
 	clst.conn.Txn(db.AllTables...).Run(func(view db.Database) error {
 		m := view.InsertMachine()
+		m.Namespace = "ns"
 		m.Role = db.Master
 		m.Provider = FakeVagrant
 		m.Region = testRegion
@@ -327,6 +384,7 @@ func TestSync(t *testing.T) {
 	// Test booting a machine with floating IP
 	clst.conn.Txn(db.AllTables...).Run(func(view db.Database) error {
 		m := view.InsertMachine()
+		m.Namespace = "ns"
 		m.Role = db.Master
 		m.Provider = FakeAmazon
 		m.Size = "m4.large"
@@ -393,6 +451,7 @@ func TestSync(t *testing.T) {
 		view.Remove(toRemove)
 
 		m := view.InsertMachine()
+		m.Namespace = "ns"
 		m.Role = db.Worker
 		m.Provider = FakeAmazon
 		m.Size = "m4.xlarge"
@@ -405,6 +464,25 @@ func TestSync(t *testing.T) {
 		boot: []bootRequest{amazonXLargeBoot},
 		stop: []string{toRemove.CloudID},
 	})
+
+	// Test a terminating machine is forgotten once the provider reclaims it
+	var doomed db.Machine
+	clst.conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		doomed = view.SelectFromMachine(func(m db.Machine) bool {
+			return m.Provider == FakeAmazon && m.Size == "m4.xlarge"
+		})[0]
+		doomed.Terminating = true
+		view.Commit(doomed)
+		return nil
+	})
+	checkSync(clst, FakeAmazon, testRegion, assertion{})
+
+	inst := instance{FakeAmazon, testRegion}
+	delete(clst.providers[inst].(*fakeProvider).machines, doomed.CloudID)
+	checkSync(clst, FakeAmazon, testRegion, assertion{})
+	assert.Empty(t, clst.conn.SelectFromMachine(func(m db.Machine) bool {
+		return m.ID == doomed.ID
+	}))
 }
 
 func TestACLs(t *testing.T) {
@@ -457,79 +535,231 @@ func TestACLs(t *testing.T) {
 	assert.Equal(t, exp, actual)
 }
 
-func TestUpdateCluster(t *testing.T) {
+func TestUpdateCloudEvents(t *testing.T) {
+	clst := newTestCluster("ns")
+	inst := instance{FakeAmazon, testRegion}
+	fake := clst.providers[inst].(*fakeProvider)
+
+	clst.updateCloud([]machine.Machine{
+		{Provider: FakeAmazon, Region: testRegion, Size: "m4.large"},
+		{Provider: FakeAmazon, Region: testRegion, Size: "m4.large"},
+	}, boot)
+
+	machines, _ := fake.List()
+	clst.providers[inst] = failingProvider{fake}
+	clst.updateCloud(machines[:1], stop)
+
+	var events []string
+	for _, e := range clst.conn.SelectFromEvent(nil) {
+		assert.Equal(t, "ns", e.Namespace)
+		events = append(events, string(e.Type)+": "+e.Message)
+	}
+	assert.Equal(t, []string{
+		"MachineBooted: Booted 2 machines on FakeAmazon in Fake region.",
+		"ProviderError: Failed to stop 1 machine on FakeAmazon in " +
+			"Fake region: stop failed",
+	}, events)
+}
+
+func TestUpdateClusters(t *testing.T) {
+	sleep = func(t time.Duration) {}
+	mock()
 	conn := db.New()
+	clusters := map[string]*cluster{}
+	ready := make(chan string)
+	defer stopClusters(clusters)
 
-	clst := updateCluster(conn, nil)
-	assert.Nil(t, clst)
+	updateClusters(conn, clusters, testCA, ready)
+	assert.Empty(t, clusters)
 
-	setNamespace(conn, "ns1")
-	clst = updateCluster(conn, clst)
-	assert.NotNil(t, clst)
-	assert.Equal(t, "ns1", clst.namespace)
+	addCluster(conn, "ns1")
+	addMachine(conn, "ns1", FakeAmazon)
+	updateClusters(conn, clusters, testCA, ready)
+	assert.Len(t, clusters, 1)
+	assert.Equal(t, "ns1", clusters["ns1"].namespace)
 
+	// The namespace is sent once its machines have been booted and listed.
 	inst := instance{FakeAmazon, testRegion}
-	amzn := clst.providers[inst].(*fakeProvider)
-	assert.Empty(t, amzn.bootRequests)
-	assert.Empty(t, amzn.stopRequests)
-	assert.Equal(t, "ns1", amzn.namespace)
+	amzn1 := clusters["ns1"].providers[inst].(*fakeProvider)
+	assert.Equal(t, "ns1", amzn1.namespace)
+	assert.Equal(t, "ns1", <-ready)
+	assert.Equal(t, map[string]bool{"ns1": true}, booted(conn))
 
+	// Pointers shouldn't have changed
+	oldClst := clusters["ns1"]
+	updateClusters(conn, clusters, testCA, ready)
+	assert.True(t, oldClst == clusters["ns1"])
+	assert.True(t, amzn1 == clusters["ns1"].providers[inst].(*fakeProvider))
+
+	// A second namespace gets its own cluster, and leaves the first alone.
+	addCluster(conn, "ns2")
+	addMachine(conn, "ns2", FakeAmazon)
+	updateClusters(conn, clusters, testCA, ready)
+	assert.Len(t, clusters, 2)
+	assert.True(t, oldClst == clusters["ns1"])
+
+	amzn2 := clusters["ns2"].providers[inst].(*fakeProvider)
+	assert.Equal(t, "ns2", amzn2.namespace)
+	assert.Equal(t, "ns2", <-ready)
+	assert.Equal(t, map[string]bool{"ns1": true, "ns2": true}, booted(conn))
+
+	// Clusters are stopped and dropped along with their namespace.
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		m := view.InsertMachine()
-		m.Provider = FakeAmazon
-		m.Size = "size1"
-		m.Region = testRegion
-		view.Commit(m)
+		clst, err := view.GetCluster("ns1")
+		assert.NoError(t, err)
+		view.Remove(clst)
 		return nil
 	})
+	updateClusters(conn, clusters, testCA, ready)
+	assert.Len(t, clusters, 1)
+	assert.NotNil(t, clusters["ns2"])
+
+	_, open := <-oldClst.stop
+	assert.False(t, open)
+}
 
-	oldClst := clst
-	oldAmzn := amzn
+func TestSlowNamespace(t *testing.T) {
+	mock()
 
-	clst = updateCluster(conn, clst)
-	assert.NotNil(t, clst)
+	// The slow namespace's cloud provider hangs when asked to boot machines.
+	unblock := make(chan struct{})
+	newProvider = func(p db.Provider, namespace, region string) (provider, error) {
+		prvdr, err := newFakeProvider(p, namespace, region)
+		if namespace == "slow" {
+			prvdr.(*fakeProvider).block = unblock
+		}
+		return prvdr, err
+	}
 
-	// Pointers shouldn't have changed
-	amzn = clst.providers[inst].(*fakeProvider)
-	assert.True(t, oldClst == clst)
-	assert.True(t, oldAmzn == amzn)
-
-	assert.Empty(t, amzn.stopRequests)
-	assert.Equal(t, []bootRequest{{
-		size:        "size1",
-		cloudConfig: amazonCloudConfig,
-	}}, amzn.bootRequests)
-	assert.Equal(t, "ns1", amzn.namespace)
+	conn := db.New()
+	clusters := map[string]*cluster{}
+	ready := make(chan string)
+	defer stopClusters(clusters)
+
+	for _, ns := range []string{"slow", "fast"} {
+		addCluster(conn, ns)
+		addMachine(conn, ns, FakeAmazon)
+	}
+	updateClusters(conn, clusters, testCA, ready)
+
+	// The other namespace converges regardless.
+	assert.Equal(t, "fast", <-ready)
+	assert.Equal(t, map[string]bool{"slow": false, "fast": true}, booted(conn))
+
+	close(unblock)
+	assert.Equal(t, "slow", <-ready)
+	assert.Equal(t, map[string]bool{"slow": true, "fast": true}, booted(conn))
+}
+
+func TestBackoff(t *testing.T) {
+	jitter = func() float64 { return 1 }
+	start := time.Unix(1000, 0)
+	now = func() time.Time { return start }
+	defer func() { now = time.Now }()
+
+	clst := newTestCluster("ns")
+	addCluster(clst.conn, "ns")
+	clst.conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		for _, p := range []db.Provider{FakeAmazon, FakeVagrant} {
+			m := view.InsertMachine()
+			m.Namespace = "ns"
+			m.Role = db.Worker
+			m.Provider = p
+			m.Region = testRegion
+			m.Size = "size"
+			view.Commit(m)
+		}
+		return nil
+	})
+
+	amzn := clst.providers[instance{FakeAmazon, testRegion}].(*fakeProvider)
+	vgrnt := clst.providers[instance{FakeVagrant, testRegion}].(*fakeProvider)
+	backoffUntil := func() map[db.Provider]time.Time {
+		res := map[db.Provider]time.Time{}
+		for _, m := range clst.conn.SelectFromMachine(nil) {
+			res[m.Provider] = m.BackoffUntil
+		}
+		return res
+	}
+
+	// A failing provider doesn't hold up the others.
+	amzn.bootError = errors.New("boot")
+	clst.runOnce()
+	assert.Empty(t, amzn.machines)
+	assert.Len(t, vgrnt.machines, 1)
+	assert.Equal(t, map[db.Provider]time.Time{
+		FakeAmazon:  start.Add(time.Minute),
+		FakeVagrant: {},
+	}, backoffUntil())
+
+	// It's left alone until its backoff expires, even if it's listing that fails.
+	amzn.listError = errors.New("list")
 	amzn.clearLogs()
+	clst.runOnce()
+	assert.Empty(t, amzn.bootRequests)
+	assert.Empty(t, amzn.aclRequests)
 
-	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		dbms := view.SelectFromMachine(nil)
-		dbms[0].Size = "size2"
-		view.Commit(dbms[0])
+	now = func() time.Time { return start.Add(time.Minute) }
+	clst.runOnce()
+	assert.Equal(t, start.Add(3*time.Minute), backoffUntil()[FakeAmazon])
+
+	// Once it recovers, it boots its machine and is forgiven.
+	amzn.listError = nil
+	amzn.bootError = nil
+	now = func() time.Time { return start.Add(3 * time.Minute) }
+	clst.runOnce()
+	assert.Len(t, amzn.machines, 1)
+	assert.Empty(t, clst.backoffs)
+	assert.Equal(t, map[db.Provider]time.Time{
+		FakeAmazon:  {},
+		FakeVagrant: {},
+	}, backoffUntil())
+}
+
+func TestReconnect(t *testing.T) {
+	jitter = func() float64 { return 1 }
+	start := time.Unix(1000, 0)
+	now = func() time.Time { return start }
+	defer func() { now = time.Now }()
+
+	// Amazon fails to connect when the cluster is created.
+	sleep = func(t time.Duration) {}
+	mock()
+	newProvider = func(p db.Provider, namespace, region string) (provider, error) {
+		if p == FakeAmazon {
+			return nil, errors.New("no credentials")
+		}
+		return newFakeProvider(p, namespace, region)
+	}
+	clst := newCluster(db.New(), "ns", testCA)
+	addCluster(clst.conn, "ns")
+	clst.conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.Namespace = "ns"
+		m.Role = db.Worker
+		m.Provider = FakeAmazon
+		m.Region = testRegion
+		m.Size = "size"
+		view.Commit(m)
 		return nil
 	})
 
-	oldClst = clst
-	oldAmzn = amzn
-	setNamespace(conn, "ns2")
-	clst = updateCluster(conn, clst)
-	assert.NotNil(t, clst)
-
-	// Pointers should have changed
-	amzn = clst.providers[inst].(*fakeProvider)
-	assert.True(t, oldClst != clst)
-	assert.True(t, oldAmzn != amzn)
-
-	assert.Equal(t, "ns1", oldAmzn.namespace)
-	assert.Empty(t, oldAmzn.bootRequests)
-	assert.Empty(t, oldAmzn.stopRequests)
-
-	assert.Equal(t, "ns2", amzn.namespace)
-	assert.Equal(t, []bootRequest{{
-		size:        "size2",
-		cloudConfig: amazonCloudConfig,
-	}}, amzn.bootRequests)
-	assert.Empty(t, amzn.stopRequests)
+	inst := instance{FakeAmazon, testRegion}
+	clst.runOnce()
+	assert.NotContains(t, clst.providers, inst)
+	assert.Equal(t, start.Add(time.Minute), clst.backoffs.until(inst))
+
+	// It isn't retried until its backoff expires.
+	mock()
+	clst.runOnce()
+	assert.NotContains(t, clst.providers, inst)
+
+	// Once it connects, it boots the machine, and is forgiven.
+	now = func() time.Time { return start.Add(time.Minute) }
+	clst.runOnce()
+	assert.Contains(t, clst.providers, inst)
+	assert.Len(t, clst.providers[inst].(*fakeProvider).machines, 1)
+	assert.Empty(t, clst.backoffs)
 }
 
 func TestMultiRegionDeploy(t *testing.T) {
@@ -540,6 +770,7 @@ func TestMultiRegionDeploy(t *testing.T) {
 		for _, p := range allProviders {
 			for _, r := range validRegions(p) {
 				m := view.InsertMachine()
+				m.Namespace = "ns"
 				m.Provider = p
 				m.Region = r
 				m.Size = "size1"
@@ -555,8 +786,7 @@ func TestMultiRegionDeploy(t *testing.T) {
 
 	for i := 0; i < 2; i++ {
 		clst.runOnce()
-		cloudMachines, err := clst.get()
-		assert.NoError(t, err)
+		cloudMachines := clst.get()
 		dbMachines := clst.conn.SelectFromMachine(nil)
 		joinResult := syncDB(cloudMachines, dbMachines)
 
@@ -578,16 +808,14 @@ func TestMultiRegionDeploy(t *testing.T) {
 	})
 
 	clst.runOnce()
-	machinesRemaining, err := clst.get()
-	assert.NoError(t, err)
+	machinesRemaining := clst.get()
 
 	assert.NotContains(t, machinesRemaining, machine.Machine{
 		Size:     "size1",
 		Provider: FakeAmazon,
 		Region:   validRegions(FakeAmazon)[0],
 	})
-	cloudMachines, err := clst.get()
-	assert.NoError(t, err)
+	cloudMachines := clst.get()
 	dbMachines := clst.conn.SelectFromMachine(nil)
 	joinResult := syncDB(cloudMachines, dbMachines)
 
@@ -596,19 +824,43 @@ func TestMultiRegionDeploy(t *testing.T) {
 	assert.Len(t, joinResult.pairs, len(dbMachines))
 }
 
-func setNamespace(conn db.Conn, ns string) {
+func addCluster(conn db.Conn, ns string) {
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		clst, err := view.GetCluster()
-		if err != nil {
-			clst = view.InsertCluster()
-		}
-
+		clst := view.InsertCluster()
 		clst.Namespace = ns
 		view.Commit(clst)
 		return nil
 	})
 }
 
+func addMachine(conn db.Conn, ns string, p db.Provider) {
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.Namespace = ns
+		m.Role = db.Worker
+		m.Provider = p
+		m.Region = testRegion
+		m.Size = "size"
+		view.Commit(m)
+		return nil
+	})
+}
+
+// booted returns whether the machines of each namespace have been booted.
+func booted(conn db.Conn) map[string]bool {
+	res := map[string]bool{}
+	for _, m := range conn.SelectFromMachine(nil) {
+		res[m.Namespace] = m.CloudID != ""
+	}
+	return res
+}
+
+func stopClusters(clusters map[string]*cluster) {
+	for _, clst := range clusters {
+		close(clst.stop)
+	}
+}
+
 func mock() {
 	newProvider = newFakeProvider
 	validRegions = fakeValidRegions
@@ -0,0 +1,260 @@
+package digitalocean
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+const apiURL = "https://api.digitalocean.com/v2"
+
+type client interface {
+	ListDroplets(tag string) ([]droplet, error)
+	CreateDroplet(req dropletRequest) (droplet, error)
+	DeleteDroplet(id int) error
+
+	CreateTag(name string) error
+
+	ListFirewalls() ([]firewall, error)
+	CreateFirewall(fw firewall) error
+	UpdateFirewall(fw firewall) error
+
+	ListReservedIPs() ([]reservedIP, error)
+	AssignReservedIP(ip string, dropletID int) error
+	UnassignReservedIP(ip string) error
+}
+
+type droplet struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	Size     string   `json:"size_slug"`
+	Tags     []string `json:"tags"`
+	Networks struct {
+		V4 []network `json:"v4"`
+	} `json:"networks"`
+}
+
+type network struct {
+	IPAddress string `json:"ip_address"`
+	Type      string `json:"type"`
+}
+
+type dropletRequest struct {
+	Name              string   `json:"name"`
+	Region            string   `json:"region"`
+	Size              string   `json:"size"`
+	Image             string   `json:"image"`
+	UserData          string   `json:"user_data"`
+	PrivateNetworking bool     `json:"private_networking"`
+	Tags              []string `json:"tags"`
+}
+
+type firewall struct {
+	ID            string         `json:"id,omitempty"`
+	Name          string         `json:"name"`
+	InboundRules  []inboundRule  `json:"inbound_rules"`
+	OutboundRules []outboundRule `json:"outbound_rules"`
+	Tags          []string       `json:"tags"`
+}
+
+type inboundRule struct {
+	Protocol string    `json:"protocol"`
+	Ports    string    `json:"ports,omitempty"`
+	Sources  endpoints `json:"sources"`
+}
+
+type outboundRule struct {
+	Protocol     string    `json:"protocol"`
+	Ports        string    `json:"ports,omitempty"`
+	Destinations endpoints `json:"destinations"`
+}
+
+type endpoints struct {
+	Addresses []string `json:"addresses,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+type reservedIP struct {
+	IP      string   `json:"ip"`
+	Droplet *droplet `json:"droplet"`
+}
+
+type clientImpl struct {
+	http *http.Client
+}
+
+// newClient is a variable so it can be easily replaced while unit testing.
+var newClient = func() (client, error) {
+	keyfile := filepath.Join(os.Getenv("HOME"), ".digitalocean", "key")
+	key, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &oauth2.Token{AccessToken: strings.TrimSpace(string(key))}
+	src := oauth2.StaticTokenSource(token)
+	return clientImpl{oauth2.NewClient(context.Background(), src)}, nil
+}
+
+/**
+ * Resource: Droplets
+ */
+
+func (c clientImpl) ListDroplets(tag string) ([]droplet, error) {
+	var droplets []droplet
+	url := fmt.Sprintf("%s/droplets?tag_name=%s&per_page=200", apiURL, tag)
+	for url != "" {
+		var resp struct {
+			Droplets []droplet `json:"droplets"`
+			Links    links     `json:"links"`
+		}
+		if err := c.do("GET", url, nil, &resp); err != nil {
+			return nil, err
+		}
+		droplets = append(droplets, resp.Droplets...)
+		url = resp.Links.Pages.Next
+	}
+	return droplets, nil
+}
+
+func (c clientImpl) CreateDroplet(req dropletRequest) (droplet, error) {
+	var resp struct {
+		Droplet droplet `json:"droplet"`
+	}
+	err := c.do("POST", apiURL+"/droplets", req, &resp)
+	return resp.Droplet, err
+}
+
+func (c clientImpl) DeleteDroplet(id int) error {
+	return c.do("DELETE", fmt.Sprintf("%s/droplets/%d", apiURL, id), nil, nil)
+}
+
+/**
+ * Resource: Tags
+ */
+
+func (c clientImpl) CreateTag(name string) error {
+	req := struct {
+		Name string `json:"name"`
+	}{name}
+	return c.do("POST", apiURL+"/tags", req, nil)
+}
+
+/**
+ * Resource: Firewalls
+ */
+
+func (c clientImpl) ListFirewalls() ([]firewall, error) {
+	var firewalls []firewall
+	url := apiURL + "/firewalls?per_page=200"
+	for url != "" {
+		var resp struct {
+			Firewalls []firewall `json:"firewalls"`
+			Links     links      `json:"links"`
+		}
+		if err := c.do("GET", url, nil, &resp); err != nil {
+			return nil, err
+		}
+		firewalls = append(firewalls, resp.Firewalls...)
+		url = resp.Links.Pages.Next
+	}
+	return firewalls, nil
+}
+
+func (c clientImpl) CreateFirewall(fw firewall) error {
+	return c.do("POST", apiURL+"/firewalls", fw, nil)
+}
+
+func (c clientImpl) UpdateFirewall(fw firewall) error {
+	return c.do("PUT", fmt.Sprintf("%s/firewalls/%s", apiURL, fw.ID), fw, nil)
+}
+
+/**
+ * Resource: Reserved IPs
+ */
+
+func (c clientImpl) ListReservedIPs() ([]reservedIP, error) {
+	var ips []reservedIP
+	url := apiURL + "/reserved_ips?per_page=200"
+	for url != "" {
+		var resp struct {
+			ReservedIPs []reservedIP `json:"reserved_ips"`
+			Links       links        `json:"links"`
+		}
+		if err := c.do("GET", url, nil, &resp); err != nil {
+			return nil, err
+		}
+		ips = append(ips, resp.ReservedIPs...)
+		url = resp.Links.Pages.Next
+	}
+	return ips, nil
+}
+
+func (c clientImpl) AssignReservedIP(ip string, dropletID int) error {
+	req := struct {
+		Type      string `json:"type"`
+		DropletID int    `json:"droplet_id"`
+	}{"assign", dropletID}
+	return c.do("POST", fmt.Sprintf("%s/reserved_ips/%s/actions", apiURL, ip),
+		req, nil)
+}
+
+func (c clientImpl) UnassignReservedIP(ip string) error {
+	req := struct {
+		Type string `json:"type"`
+	}{"unassign"}
+	return c.do("POST", fmt.Sprintf("%s/reserved_ips/%s/actions", apiURL, ip),
+		req, nil)
+}
+
+type links struct {
+	Pages struct {
+		Next string `json:"next"`
+	} `json:"pages"`
+}
+
+// do sends a request with the JSON encoding of `in` as its body, and decodes the
+// response into `out`.  Either may be nil.
+func (c clientImpl) do(method, url string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: %s (%s)", method, url, apiErr.Message,
+			resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,376 @@
+package digitalocean
+
+////// SET UP API ACCESS:
+//
+// 1) In the DigitalOcean control panel navigate to:
+//    API > Tokens/Keys
+//
+// 2) Generate a new personal access token with read and write scopes.
+//
+// 3) Save the token as "~/.digitalocean/key".
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NetSys/quilt/cluster/acl"
+	"github.com/NetSys/quilt/cluster/cloudcfg"
+	"github.com/NetSys/quilt/cluster/machine"
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/util"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/satori/go.uuid"
+)
+
+// DefaultRegion is the preferred location for machines which haven't a user specified
+// region preference.
+const DefaultRegion = "sfo2"
+
+// Regions is the list of supported DigitalOcean regions.
+var Regions = []string{"nyc3", "sfo2", "lon1"}
+
+// Ubuntu 16.04, 64-bit
+const image = "ubuntu-16-04-x64"
+
+var allAddresses = []string{"0.0.0.0/0", "::/0"}
+
+var timeout = 5 * time.Minute
+
+// The Cluster object represents a connection to DigitalOcean.
+//
+// The machines in a namespace are tagged with the namespace and region, and the same
+// tag names the cloud firewall that implements the namespace's ACLs in the region.
+type Cluster struct {
+	client client
+
+	namespace string
+	region    string
+	tag       string
+}
+
+// New creates a new DigitalOcean cluster.
+func New(namespace, region string) (*Cluster, error) {
+	c, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	clst := newDigitalOcean(c, namespace, region)
+	if _, err := clst.List(); err != nil {
+		return nil, errors.New("DigitalOcean failed to connect")
+	}
+	return clst, nil
+}
+
+func newDigitalOcean(c client, namespace, region string) *Cluster {
+	namespace = strings.ToLower(namespace)
+	return &Cluster{
+		client:    c,
+		namespace: namespace,
+		region:    region,
+		tag:       fmt.Sprintf("%s-%s", namespace, region),
+	}
+}
+
+// List queries `clst` for the list of booted machines.
+func (clst Cluster) List() ([]machine.Machine, error) {
+	droplets, err := clst.client.ListDroplets(clst.tag)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := clst.client.ListReservedIPs()
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := map[int]string{}
+	for _, ip := range ips {
+		if ip.Droplet != nil {
+			reserved[ip.Droplet.ID] = ip.IP
+		}
+	}
+
+	// Droplets are listed whatever their status, so that those that were powered
+	// off are still stopped.
+	var machines []machine.Machine
+	for _, d := range droplets {
+		m := machine.Machine{
+			ID:         strconv.Itoa(d.ID),
+			Size:       d.Size,
+			FloatingIP: reserved[d.ID],
+			Provider:   db.DigitalOcean,
+			Region:     clst.region,
+		}
+
+		for _, n := range d.Networks.V4 {
+			switch n.Type {
+			case "public":
+				m.PublicIP = n.IPAddress
+			case "private":
+				m.PrivateIP = n.IPAddress
+			}
+		}
+		machines = append(machines, m)
+	}
+	return machines, nil
+}
+
+// Boot creates droplets in `clst` configured according to the `bootSet`, and blocks
+// until they have booted.
+func (clst Cluster) Boot(bootSet []machine.Machine) error {
+	var ids []string
+	for _, m := range bootSet {
+		cfg := cloudcfg.Ubuntu(m.SSHKeys, "xenial", m.CACert)
+		d, err := clst.client.CreateDroplet(dropletRequest{
+			Name:              "quilt-" + uuid.NewV4().String(),
+			Region:            clst.region,
+			Size:              m.Size,
+			Image:             image,
+			UserData:          cfg,
+			PrivateNetworking: true,
+			Tags:              []string{clst.tag},
+		})
+		if err != nil {
+			return err
+		}
+		ids = append(ids, strconv.Itoa(d.ID))
+	}
+
+	return clst.wait(ids, true)
+}
+
+// Stop deletes the droplets of `machines`, and blocks until they are gone.
+func (clst Cluster) Stop(machines []machine.Machine) error {
+	var ids []string
+	for _, m := range machines {
+		id, err := strconv.Atoi(m.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := clst.client.DeleteDroplet(id); err != nil {
+			return err
+		}
+		ids = append(ids, m.ID)
+	}
+
+	return clst.wait(ids, false)
+}
+
+// wait blocks until the droplets `ids` have booted or terminated, depending on the
+// value of `boot`.  Droplets are considered booted once they have a public IP.
+func (clst Cluster) wait(ids []string, boot bool) error {
+	return util.WaitFor(func() bool {
+		machines, err := clst.List()
+		if err != nil {
+			log.WithError(err).Warn("Failed to get machines.")
+			return true
+		}
+
+		exists := map[string]struct{}{}
+		for _, m := range machines {
+			if !boot || m.PublicIP != "" {
+				exists[m.ID] = struct{}{}
+			}
+		}
+
+		for _, id := range ids {
+			if _, ok := exists[id]; ok != boot {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, timeout)
+}
+
+// UpdateFloatingIPs assigns the reserved IPs of `machines` to their droplets, and
+// unassigns the reserved IPs of those that shouldn't have one.
+func (clst Cluster) UpdateFloatingIPs(machines []machine.Machine) error {
+	ips, err := clst.client.ListReservedIPs()
+	if err != nil {
+		return err
+	}
+
+	reserved := map[int]string{}
+	for _, ip := range ips {
+		if ip.Droplet != nil {
+			reserved[ip.Droplet.ID] = ip.IP
+		}
+	}
+
+	for _, m := range machines {
+		id, err := strconv.Atoi(m.ID)
+		if err != nil {
+			return err
+		}
+
+		curr := reserved[id]
+		if curr == m.FloatingIP {
+			continue
+		}
+
+		// A droplet may only have one reserved IP, so the old one must be
+		// unassigned before the new one is assigned.
+		if curr != "" {
+			if err := clst.client.UnassignReservedIP(curr); err != nil {
+				return err
+			}
+		}
+
+		if m.FloatingIP != "" {
+			err := clst.client.AssignReservedIP(m.FloatingIP, id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetACLs updates the cloud firewall of `clst` so that it admits exactly `acls`, in
+// addition to traffic between the droplets in `clst`.
+func (clst Cluster) SetACLs(acls []acl.ACL) error {
+	firewalls, err := clst.client.ListFirewalls()
+	if err != nil {
+		return err
+	}
+
+	desired := clst.firewall(acls)
+	for _, fw := range firewalls {
+		if fw.Name != clst.tag {
+			continue
+		}
+
+		desired.ID = fw.ID
+		if reflect.DeepEqual(normalizeFirewall(fw), desired) {
+			return nil
+		}
+
+		log.WithField("ACLs", acls).Debug("DigitalOcean: Update firewall")
+		return clst.client.UpdateFirewall(desired)
+	}
+
+	// The cluster clears the ACLs of every region it has no machines in, which
+	// needn't create a firewall, or the tag it applies to.
+	if len(acls) == 0 {
+		return nil
+	}
+
+	// Firewalls may only be applied to tags that exist, and we may not have booted
+	// any droplets with the tag yet.
+	if err := clst.client.CreateTag(clst.tag); err != nil {
+		return err
+	}
+
+	log.WithField("ACLs", acls).Debug("DigitalOcean: Create firewall")
+	return clst.client.CreateFirewall(desired)
+}
+
+// firewall returns the cloud firewall that implements `acls`.  Each port range is
+// allowed from all of the addresses that need it, for TCP, UDP, and ICMP.
+func (clst Cluster) firewall(acls []acl.ACL) firewall {
+	grouped := map[string][]string{}
+	for _, a := range acls {
+		ports := strconv.Itoa(a.MinPort)
+		if a.MinPort != a.MaxPort {
+			ports = fmt.Sprintf("%d-%d", a.MinPort, a.MaxPort)
+		}
+		grouped[ports] = append(grouped[ports], a.CidrIP)
+	}
+
+	var allPorts []string
+	for ports := range grouped {
+		allPorts = append(allPorts, ports)
+	}
+	sort.Strings(allPorts)
+
+	cluster := endpoints{Tags: []string{clst.tag}}
+	inbound := []inboundRule{
+		{Protocol: "tcp", Ports: "0", Sources: cluster},
+		{Protocol: "udp", Ports: "0", Sources: cluster},
+		{Protocol: "icmp", Sources: cluster},
+	}
+
+	icmp := map[string]struct{}{}
+	for _, ports := range allPorts {
+		addrs := grouped[ports]
+		sort.Strings(addrs)
+		addrs = uniqueStrings(addrs)
+		inbound = append(inbound,
+			inboundRule{"tcp", ports, endpoints{Addresses: addrs}},
+			inboundRule{"udp", ports, endpoints{Addresses: addrs}})
+
+		for _, addr := range addrs {
+			icmp[addr] = struct{}{}
+		}
+	}
+
+	if len(icmp) > 0 {
+		var addrs []string
+		for addr := range icmp {
+			addrs = append(addrs, addr)
+		}
+		sort.Strings(addrs)
+		inbound = append(inbound, inboundRule{Protocol: "icmp",
+			Sources: endpoints{Addresses: addrs}})
+	}
+
+	everywhere := endpoints{Addresses: allAddresses}
+	return firewall{
+		Name:         clst.tag,
+		InboundRules: inbound,
+		OutboundRules: []outboundRule{
+			{Protocol: "tcp", Ports: "0", Destinations: everywhere},
+			{Protocol: "udp", Ports: "0", Destinations: everywhere},
+			{Protocol: "icmp", Destinations: everywhere},
+		},
+		Tags: []string{clst.tag},
+	}
+}
+
+// normalizeFirewall returns `fw` in the form produced by Cluster.firewall(), so that
+// the two may be compared.  DigitalOcean reports the ports of ICMP rules as "0",
+// while they must be omitted when creating the rules.
+func normalizeFirewall(fw firewall) firewall {
+	var inbound []inboundRule
+	for _, rule := range fw.InboundRules {
+		if rule.Protocol == "icmp" {
+			rule.Ports = ""
+		}
+		inbound = append(inbound, rule)
+	}
+
+	var outbound []outboundRule
+	for _, rule := range fw.OutboundRules {
+		if rule.Protocol == "icmp" {
+			rule.Ports = ""
+		}
+		outbound = append(outbound, rule)
+	}
+
+	return firewall{
+		ID:            fw.ID,
+		Name:          fw.Name,
+		InboundRules:  inbound,
+		OutboundRules: outbound,
+		Tags:          fw.Tags,
+	}
+}
+
+// uniqueStrings removes adjacent duplicates from the sorted `strs`.
+func uniqueStrings(strs []string) []string {
+	var unique []string
+	for i, s := range strs {
+		if i == 0 || s != strs[i-1] {
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
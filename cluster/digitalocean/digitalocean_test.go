@@ -0,0 +1,222 @@
+package digitalocean
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NetSys/quilt/cluster/acl"
+	"github.com/NetSys/quilt/cluster/machine"
+	"github.com/NetSys/quilt/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testTag = "namespace-sfo2"
+
+func newTestDroplet(id int, status, public, private string) droplet {
+	d := droplet{ID: id, Status: status, Size: "s-1vcpu-1gb",
+		Tags: []string{testTag}}
+	if public != "" {
+		d.Networks.V4 = append(d.Networks.V4, network{public, "public"})
+	}
+	if private != "" {
+		d.Networks.V4 = append(d.Networks.V4, network{private, "private"})
+	}
+	return d
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newDigitalOcean(c, "Namespace", "sfo2")
+	assert.Equal(t, testTag, clst.tag)
+
+	c.On("ListDroplets", testTag).Return([]droplet{
+		newTestDroplet(1, "active", "1.1.1.1", "10.0.0.1"),
+		newTestDroplet(2, "new", "", ""),
+		newTestDroplet(3, "off", "3.3.3.3", "10.0.0.3"),
+	}, nil).Once()
+	c.On("ListReservedIPs").Return([]reservedIP{
+		{IP: "5.5.5.5", Droplet: &droplet{ID: 1}},
+		{IP: "6.6.6.6"},
+	}, nil).Once()
+
+	machines, err := clst.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []machine.Machine{{
+		ID:         "1",
+		PublicIP:   "1.1.1.1",
+		PrivateIP:  "10.0.0.1",
+		FloatingIP: "5.5.5.5",
+		Size:       "s-1vcpu-1gb",
+		Provider:   db.DigitalOcean,
+		Region:     "sfo2",
+	}, {
+		ID:       "2",
+		Size:     "s-1vcpu-1gb",
+		Provider: db.DigitalOcean,
+		Region:   "sfo2",
+	}, {
+		ID:        "3",
+		PublicIP:  "3.3.3.3",
+		PrivateIP: "10.0.0.3",
+		Size:      "s-1vcpu-1gb",
+		Provider:  db.DigitalOcean,
+		Region:    "sfo2",
+	}}, machines)
+
+	c.On("ListDroplets", testTag).Return(nil, errors.New("unauthorized")).Once()
+	_, err = clst.List()
+	assert.EqualError(t, err, "unauthorized")
+}
+
+func TestBoot(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newDigitalOcean(c, "namespace", "sfo2")
+
+	c.On("CreateDroplet", mock.MatchedBy(func(req dropletRequest) bool {
+		return req.Region == "sfo2" && req.Size == "s-1vcpu-1gb" &&
+			req.Image == image && req.PrivateNetworking &&
+			assert.ObjectsAreEqual([]string{testTag}, req.Tags)
+	})).Return(droplet{ID: 1}, nil).Once()
+	c.On("ListDroplets", testTag).Return([]droplet{
+		newTestDroplet(1, "active", "1.1.1.1", "10.0.0.1"),
+	}, nil)
+	c.On("ListReservedIPs").Return(nil, nil)
+
+	err := clst.Boot([]machine.Machine{{Size: "s-1vcpu-1gb"}})
+	assert.NoError(t, err)
+	c.AssertExpectations(t)
+
+	c.On("CreateDroplet", mock.Anything).Return(droplet{},
+		errors.New("quota exceeded")).Once()
+	err = clst.Boot([]machine.Machine{{Size: "s-1vcpu-1gb"}})
+	assert.EqualError(t, err, "quota exceeded")
+}
+
+func TestStop(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newDigitalOcean(c, "namespace", "sfo2")
+
+	c.On("DeleteDroplet", 1).Return(nil).Once()
+	c.On("ListDroplets", testTag).Return([]droplet{
+		newTestDroplet(2, "active", "2.2.2.2", "10.0.0.2"),
+	}, nil)
+	c.On("ListReservedIPs").Return(nil, nil)
+
+	err := clst.Stop([]machine.Machine{{ID: "1"}})
+	assert.NoError(t, err)
+	c.AssertExpectations(t)
+
+	err = clst.Stop([]machine.Machine{{ID: "malformed"}})
+	assert.Error(t, err)
+}
+
+func TestUpdateFloatingIPs(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newDigitalOcean(c, "namespace", "sfo2")
+
+	c.On("ListReservedIPs").Return([]reservedIP{
+		{IP: "5.5.5.5", Droplet: &droplet{ID: 1}},
+		{IP: "6.6.6.6", Droplet: &droplet{ID: 2}},
+		{IP: "7.7.7.7"},
+	}, nil)
+	c.On("UnassignReservedIP", "5.5.5.5").Return(nil).Once()
+	c.On("UnassignReservedIP", "6.6.6.6").Return(nil).Once()
+	c.On("AssignReservedIP", "7.7.7.7", 2).Return(nil).Once()
+	c.On("AssignReservedIP", "6.6.6.6", 3).Return(nil).Once()
+
+	err := clst.UpdateFloatingIPs([]machine.Machine{
+		{ID: "1"},
+		{ID: "2", FloatingIP: "7.7.7.7"},
+		{ID: "3", FloatingIP: "6.6.6.6"},
+		{ID: "4"},
+	})
+	assert.NoError(t, err)
+	c.AssertExpectations(t)
+
+	c.On("AssignReservedIP", "7.7.7.7", 4).Return(errors.New("in use")).Once()
+	err = clst.UpdateFloatingIPs([]machine.Machine{{ID: "4", FloatingIP: "7.7.7.7"}})
+	assert.EqualError(t, err, "in use")
+}
+
+func TestSetACLs(t *testing.T) {
+	t.Parallel()
+
+	c := &mockClient{}
+	clst := newDigitalOcean(c, "namespace", "sfo2")
+
+	acls := []acl.ACL{
+		{CidrIP: "1.2.3.4/32", MinPort: 80, MaxPort: 80},
+		{CidrIP: "0.0.0.0/0", MinPort: 80, MaxPort: 80},
+		{CidrIP: "1.2.3.4/32", MinPort: 1, MaxPort: 65535},
+	}
+
+	cluster := endpoints{Tags: []string{testTag}}
+	everywhere := endpoints{Addresses: allAddresses}
+	fw := firewall{
+		Name: testTag,
+		InboundRules: []inboundRule{
+			{"tcp", "0", cluster},
+			{"udp", "0", cluster},
+			{"icmp", "", cluster},
+			{"tcp", "1-65535", endpoints{Addresses: []string{"1.2.3.4/32"}}},
+			{"udp", "1-65535", endpoints{Addresses: []string{"1.2.3.4/32"}}},
+			{"tcp", "80", endpoints{
+				Addresses: []string{"0.0.0.0/0", "1.2.3.4/32"}}},
+			{"udp", "80", endpoints{
+				Addresses: []string{"0.0.0.0/0", "1.2.3.4/32"}}},
+			{"icmp", "", endpoints{
+				Addresses: []string{"0.0.0.0/0", "1.2.3.4/32"}}},
+		},
+		OutboundRules: []outboundRule{
+			{"tcp", "0", everywhere},
+			{"udp", "0", everywhere},
+			{"icmp", "", everywhere},
+		},
+		Tags: []string{testTag},
+	}
+	assert.Equal(t, fw, clst.firewall(acls))
+
+	// There are no ACLs to enforce, and no firewall to clear.
+	c.On("ListFirewalls").Return([]firewall{{ID: "other", Name: "other"}},
+		nil).Once()
+	assert.NoError(t, clst.SetACLs(nil))
+	c.AssertExpectations(t)
+
+	// The firewall doesn't exist yet.
+	c.On("ListFirewalls").Return([]firewall{{ID: "other", Name: "other"}},
+		nil).Once()
+	c.On("CreateTag", testTag).Return(nil).Once()
+	c.On("CreateFirewall", fw).Return(nil).Once()
+	assert.NoError(t, clst.SetACLs(acls))
+	c.AssertExpectations(t)
+
+	// The firewall is already correct.  DigitalOcean reports ICMP ports as "0".
+	curr := fw
+	curr.ID = "id"
+	curr.InboundRules = append([]inboundRule{}, fw.InboundRules...)
+	curr.InboundRules[2].Ports = "0"
+	c.On("ListFirewalls").Return([]firewall{curr}, nil).Once()
+	assert.NoError(t, clst.SetACLs(acls))
+	c.AssertExpectations(t)
+
+	// The ACLs changed.
+	exp := clst.firewall(nil)
+	exp.ID = "id"
+	c.On("ListFirewalls").Return([]firewall{curr}, nil).Once()
+	c.On("UpdateFirewall", exp).Return(nil).Once()
+	assert.NoError(t, clst.SetACLs(nil))
+	c.AssertExpectations(t)
+
+	c.On("ListFirewalls").Return(nil, errors.New("unavailable")).Once()
+	assert.EqualError(t, clst.SetACLs(acls), "unavailable")
+}
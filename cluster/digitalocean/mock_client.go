@@ -0,0 +1,184 @@
+package digitalocean
+
+import mock "github.com/stretchr/testify/mock"
+
+// mockClient is an autogenerated mock type for the client type
+type mockClient struct {
+	mock.Mock
+}
+
+// AssignReservedIP provides a mock function with given fields: ip, dropletID
+func (_m *mockClient) AssignReservedIP(ip string, dropletID int) error {
+	ret := _m.Called(ip, dropletID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int) error); ok {
+		r0 = rf(ip, dropletID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateDroplet provides a mock function with given fields: req
+func (_m *mockClient) CreateDroplet(req dropletRequest) (droplet, error) {
+	ret := _m.Called(req)
+
+	var r0 droplet
+	if rf, ok := ret.Get(0).(func(dropletRequest) droplet); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Get(0).(droplet)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(dropletRequest) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateFirewall provides a mock function with given fields: fw
+func (_m *mockClient) CreateFirewall(fw firewall) error {
+	ret := _m.Called(fw)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(firewall) error); ok {
+		r0 = rf(fw)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTag provides a mock function with given fields: name
+func (_m *mockClient) CreateTag(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDroplet provides a mock function with given fields: id
+func (_m *mockClient) DeleteDroplet(id int) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListDroplets provides a mock function with given fields: tag
+func (_m *mockClient) ListDroplets(tag string) ([]droplet, error) {
+	ret := _m.Called(tag)
+
+	var r0 []droplet
+	if rf, ok := ret.Get(0).(func(string) []droplet); ok {
+		r0 = rf(tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]droplet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFirewalls provides a mock function with given fields:
+func (_m *mockClient) ListFirewalls() ([]firewall, error) {
+	ret := _m.Called()
+
+	var r0 []firewall
+	if rf, ok := ret.Get(0).(func() []firewall); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]firewall)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListReservedIPs provides a mock function with given fields:
+func (_m *mockClient) ListReservedIPs() ([]reservedIP, error) {
+	ret := _m.Called()
+
+	var r0 []reservedIP
+	if rf, ok := ret.Get(0).(func() []reservedIP); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]reservedIP)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnassignReservedIP provides a mock function with given fields: ip
+func (_m *mockClient) UnassignReservedIP(ip string) error {
+	ret := _m.Called(ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateFirewall provides a mock function with given fields: fw
+func (_m *mockClient) UpdateFirewall(fw firewall) error {
+	ret := _m.Called(fw)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(firewall) error); ok {
+		r0 = rf(fw)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+var _ client = (*mockClient)(nil)
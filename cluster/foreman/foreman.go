@@ -1,25 +1,46 @@
 package foreman
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
 	"reflect"
 	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 
 	"golang.org/x/net/context"
 
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/minion/pb"
+	"github.com/NetSys/quilt/version"
 
 	log "github.com/Sirupsen/logrus"
 )
 
 var minions map[string]*minion
 
+// The namespaces the foreman has been initialized for.  The minions of the others are
+// left alone until their roles have been learned.
+var namespaces map[string]struct{}
+
+// The CA that certifies the minions, and the credentials with which the foreman
+// authenticates itself to them.
+var ca certs.CA
+var creds certs.Credentials
+
 type client interface {
 	setMinion(pb.MinionConfig) error
 	getMinion() (pb.MinionConfig, error)
+	getVersion() (string, int, error)
+	getEvents(after time.Time) ([]db.Event, error)
+	getStatus() (db.Status, error)
 	Close()
 }
 
@@ -33,31 +54,57 @@ type minion struct {
 	connected bool
 
 	machine db.Machine
+	version int // The version of `machine` when it was read from the database.
 	config  pb.MinionConfig
 
+	// The version of Quilt the minion is running, and whether it's compatible with
+	// the daemon.  Incompatible minions aren't configured.
+	quiltVersion string
+	compatible   bool
+
+	// The time of the latest event retrieved from the minion, and the events that
+	// have been retrieved since, but not yet recorded.
+	lastEvent time.Time
+	events    []db.Event
+
+	// The problems the minion found with its spec.  Only the leader reports any.
+	status db.Status
+
 	mark bool /* Mark and sweep garbage collection. */
 }
 
-// Init the first time the foreman operates on a new namespace.  It queries the currently
-// running VMs for their previously assigned roles, and writes them to the database.
-func Init(conn db.Conn) {
-	for _, m := range minions {
-		m.client.Close()
+// Init the first time the foreman operates on a new namespace.  It queries the
+// namespace's currently running VMs for their previously assigned roles, and writes
+// them to the database.  The minions of other namespaces are left as they are.  The
+// minions are contacted using 'clientCreds', and certified by 'minionCA'.
+func Init(conn db.Conn, namespace string, minionCA certs.CA,
+	clientCreds certs.Credentials) {
+
+	ca = minionCA
+	creds = clientCreds
+	if minions == nil {
+		minions = map[string]*minion{}
+	}
+	if namespaces == nil {
+		namespaces = map[string]struct{}{}
 	}
-	minions = map[string]*minion{}
+	namespaces[namespace] = struct{}{}
 
 	conn.Txn(db.MachineTable).Run(func(view db.Database) error {
 		machines := view.SelectFromMachine(func(m db.Machine) bool {
-			return m.PublicIP != "" && m.PrivateIP != "" && m.CloudID != ""
+			return m.Namespace == namespace && m.PublicIP != "" &&
+				m.PrivateIP != "" && m.CloudID != ""
 		})
 
-		updateMinionMap(machines)
-		forEachMinion(updateConfig)
-		for _, m := range minions {
+		nsMinions := connectMinions(machines)
+		forEach(nsMinions, updateConfig)
+		for _, m := range nsMinions {
 			role := db.PBToRole(m.config.Role)
 			if m.connected && role != db.None {
 				m.machine.Role = role
 				m.machine.Connected = m.connected
+				m.machine.MinionVersion = m.quiltVersion
+				m.machine.Terminating = m.config.Terminating
 				view.Commit(m.machine)
 			}
 		}
@@ -66,59 +113,118 @@ func Init(conn db.Conn) {
 	})
 }
 
-// RunOnce should be called regularly to allow the foreman to update minion roles.
+// RunOnce should be called regularly to allow the foreman to update minion roles.  Only
+// the minions of namespaces the foreman has been initialized for are updated.
 func RunOnce(conn db.Conn) {
-	var spec string
+	specs := map[string]string{}
 	var machines []db.Machine
-	conn.Txn(db.ClusterTable,
+	versions := map[int]int{}
+	conn.ReadTxn(db.ClusterTable,
 		db.MachineTable).Run(func(view db.Database) error {
 
 		machines = view.SelectFromMachine(func(m db.Machine) bool {
-			return m.PublicIP != "" && m.PrivateIP != "" && m.CloudID != ""
+			_, ok := namespaces[m.Namespace]
+			return ok && m.PublicIP != "" && m.PrivateIP != "" &&
+				m.CloudID != ""
 		})
+		for _, m := range machines {
+			versions[m.ID] = view.Version(m)
+		}
 
-		clst, _ := view.GetCluster()
-		spec = clst.Spec
+		for _, clst := range view.SelectFromCluster(nil) {
+			specs[clst.Namespace] = clst.Spec
+		}
 
 		return nil
 	})
 
 	updateMinionMap(machines)
+	for _, m := range minions {
+		m.version = versions[m.machine.ID]
+	}
 
 	forEachMinion(updateConfig)
+	forEachMinion(getEvents)
+	forEachMinion(getStatus)
 	forEachMinion(func(m *minion) {
-		if m.connected != m.machine.Connected {
-			tr := conn.Txn(db.MachineTable)
+		// Once a machine is known to be terminating, it stays so even if its
+		// minion is no longer reachable.
+		terminating := m.machine.Terminating || m.config.Terminating
+		if m.connected != m.machine.Connected ||
+			m.quiltVersion != m.machine.MinionVersion ||
+			terminating != m.machine.Terminating {
+			tr := conn.Txn(db.EventTable, db.MachineTable)
 			tr.Run(func(view db.Database) error {
+				// The machine may have been updated, e.g. by the
+				// cluster join, while we were contacting its minion.
+				// If so, leave it be, the next run will see the new
+				// machine.
+				wasConnected := m.machine.Connected
 				m.machine.Connected = m.connected
-				view.Commit(m.machine)
+				m.machine.MinionVersion = m.quiltVersion
+				m.machine.Terminating = terminating
+				err := view.CommitIfVersion(m.machine, m.version)
+				if err != nil {
+					log.WithError(err).Debug(
+						"Skipping update of machine connection.")
+				} else if m.connected != wasConnected {
+					view.RecordEvent(connectionEvent(m))
+				}
 				return nil
 			})
 		}
 	})
 
-	var etcdIPs []string
+	conn.Txn(db.EventTable).Run(func(view db.Database) error {
+		// The event cursors live in memory, so after a restart the minions
+		// report again the events that were already recorded.
+		recorded := map[eventKey]struct{}{}
+		for _, e := range view.SelectFromEvent(nil) {
+			recorded[getEventKey(e)] = struct{}{}
+		}
+
+		for _, m := range minions {
+			for _, e := range m.events {
+				key := getEventKey(e)
+				if _, ok := recorded[key]; !ok {
+					recorded[key] = struct{}{}
+					view.RecordEvent(e)
+				}
+			}
+			m.events = nil
+		}
+		return nil
+	})
+
+	updateStatuses(conn)
+
+	// Each namespace is a separate cluster, with its own etcd.
+	etcdIPs := map[string][]string{}
 	for _, m := range minions {
 		if m.machine.Role == db.Master && m.machine.PrivateIP != "" {
-			etcdIPs = append(etcdIPs, m.machine.PrivateIP)
+			ns := m.machine.Namespace
+			etcdIPs[ns] = append(etcdIPs[ns], m.machine.PrivateIP)
 		}
 	}
 
 	// Assign all of the minions their new configs
 	forEachMinion(func(m *minion) {
-		if !m.connected {
+		if !m.connected || !m.compatible {
 			return
 		}
 
 		newConfig := pb.MinionConfig{
 			Role:           db.RoleToPB(m.machine.Role),
 			PrivateIP:      m.machine.PrivateIP,
-			Spec:           spec,
+			Spec:           specs[m.machine.Namespace],
 			Provider:       string(m.machine.Provider),
 			Size:           m.machine.Size,
 			Region:         m.machine.Region,
-			EtcdMembers:    etcdIPs,
+			EtcdMembers:    etcdIPs[m.machine.Namespace],
 			AuthorizedKeys: m.machine.SSHKeys,
+
+			// Reported by the minion, so it's no reason to reconfigure.
+			Terminating: m.config.Terminating,
 		}
 
 		if reflect.DeepEqual(newConfig, m.config) {
@@ -133,18 +239,7 @@ func RunOnce(conn db.Conn) {
 }
 
 func updateMinionMap(machines []db.Machine) {
-	for _, m := range machines {
-		min, ok := minions[m.PublicIP]
-		if !ok {
-			client, err := newClient(m.PublicIP)
-			if err != nil {
-				continue
-			}
-			min = &minion{client: client}
-			minions[m.PublicIP] = min
-		}
-
-		min.machine = m
+	for _, min := range connectMinions(machines) {
 		min.mark = true
 	}
 
@@ -158,10 +253,64 @@ func updateMinionMap(machines []db.Machine) {
 	}
 }
 
+// connectMinions returns the minions running on 'machines', keyed by public IP.  The
+// new minions are first certified, in parallel as those that are still booting take a
+// while to time out, and then connected to.  Machines whose minions can't be
+// certified are left out.
+func connectMinions(machines []db.Machine) map[string]*minion {
+	result := map[string]*minion{}
+	var newMachines []db.Machine
+	for _, m := range machines {
+		if min, ok := minions[m.PublicIP]; ok {
+			min.machine = m
+			result[m.PublicIP] = min
+		} else {
+			newMachines = append(newMachines, m)
+		}
+	}
+
+	certified := make([]bool, len(newMachines))
+	var wg sync.WaitGroup
+	wg.Add(len(newMachines))
+	for i, m := range newMachines {
+		go func(i int, m db.Machine) {
+			defer wg.Done()
+			if err := certify(m); err != nil {
+				log.WithError(err).WithField("machine", m).Debug(
+					"Failed to certify minion")
+				return
+			}
+			certified[i] = true
+		}(i, m)
+	}
+	wg.Wait()
+
+	for i, m := range newMachines {
+		min, ok := minions[m.PublicIP]
+		if !ok && certified[i] {
+			client, err := newClient(m.PublicIP)
+			if err != nil {
+				continue
+			}
+			min = &minion{client: client}
+			minions[m.PublicIP] = min
+		} else if !ok {
+			continue
+		}
+		min.machine = m
+		result[m.PublicIP] = min
+	}
+	return result
+}
+
 func forEachMinion(do func(minion *minion)) {
+	forEach(minions, do)
+}
+
+func forEach(ms map[string]*minion, do func(minion *minion)) {
 	var wg sync.WaitGroup
-	wg.Add(len(minions))
-	for _, m := range minions {
+	wg.Add(len(ms))
+	for _, m := range ms {
 		go func(m *minion) {
 			do(m)
 			wg.Done()
@@ -186,10 +335,221 @@ func updateConfig(m *minion) {
 		log.WithField("machine", m.machine).Debug("New connection")
 	}
 	m.connected = connected
+
+	// The minion may have been restarted with a different version since it was
+	// last connected.
+	if !connected {
+		m.quiltVersion = ""
+	} else if m.quiltVersion == "" {
+		m.quiltVersion, m.compatible = checkVersion(m)
+	}
+}
+
+// getEvents retrieves the events `m` recorded since they were last retrieved, and
+// attributes them to its machine.
+func getEvents(m *minion) {
+	if !m.connected || !m.compatible {
+		return
+	}
+
+	events, err := m.client.getEvents(m.lastEvent)
+	if err != nil {
+		// Minions from before the GetEvents RPC have no events to report.
+		if grpc.Code(err) != codes.Unimplemented {
+			log.WithError(err).Debug("Failed to get minion events")
+		}
+		return
+	}
+
+	for _, e := range events {
+		if e.Time.After(m.lastEvent) {
+			m.lastEvent = e.Time
+		}
+		e.Namespace = m.machine.Namespace
+		e.Machine = m.machine.StitchID
+		m.events = append(m.events, e)
+	}
+}
+
+// An eventKey identifies the events a minion reported, regardless of their IDs.
+type eventKey struct {
+	machine string
+	time    int64
+	typ     db.EventType
+	message string
+}
+
+func getEventKey(e db.Event) eventKey {
+	return eventKey{e.Machine, e.Time.UnixNano(), e.Type, e.Message}
+}
+
+// getStatus retrieves the problems `m` found with its spec.  Only masters implement
+// the spec, so only they are asked.
+func getStatus(m *minion) {
+	m.status = db.Status{}
+	if !m.connected || !m.compatible || db.PBToRole(m.config.Role) != db.Master {
+		return
+	}
+
+	status, err := m.client.getStatus()
+	if err != nil {
+		// Minions from before the GetStatus RPC have no status to report.
+		if grpc.Code(err) != codes.Unimplemented {
+			log.WithError(err).Debug("Failed to get minion status")
+		}
+		return
+	}
+	m.status = status
+}
+
+// updateStatuses records the problems reported by the minions in the status of their
+// namespace.  The engine creates the statuses, and derives their state.
+func updateStatuses(conn db.Conn) {
+	warnings := map[string][]string{}
+	unschedulable := map[string][]string{}
+	for _, m := range minions {
+		ns := m.machine.Namespace
+		warnings[ns] = append(warnings[ns], m.status.MinionWarnings...)
+		unschedulable[ns] = append(unschedulable[ns],
+			m.status.UnschedulableContainers...)
+	}
+
+	conn.Txn(db.StatusTable).Run(func(view db.Database) error {
+		for _, status := range view.SelectFromStatus(nil) {
+			ns := status.Namespace
+			if reflect.DeepEqual(status.MinionWarnings, warnings[ns]) &&
+				reflect.DeepEqual(status.UnschedulableContainers,
+					unschedulable[ns]) {
+				continue
+			}
+
+			status.MinionWarnings = warnings[ns]
+			status.UnschedulableContainers = unschedulable[ns]
+			view.Commit(status)
+		}
+		return nil
+	})
+}
+
+func connectionEvent(m *minion) db.Event {
+	event := db.Event{
+		Type:      db.MinionConnected,
+		Namespace: m.machine.Namespace,
+		Machine:   m.machine.StitchID,
+		Message:   "Connected to the minion on " + m.machine.PublicIP + ".",
+	}
+	if !m.connected {
+		event.Type = db.MinionDisconnected
+		event.Message = "Lost the connection to the minion on " +
+			m.machine.PublicIP + "."
+	}
+	return event
+}
+
+// checkVersion returns the version of Quilt `m` is running, and whether it's
+// compatible with the daemon.  Minions from before the Version RPC are assumed to be
+// compatible, and the version is left blank if it can't be retrieved.
+func checkVersion(m *minion) (string, bool) {
+	minionVersion, schema, err := m.client.getVersion()
+	if grpc.Code(err) == codes.Unimplemented {
+		log.WithField("machine", m.machine).Warn(
+			"Minion doesn't report its version, and may be incompatible.")
+		return "unknown", true
+	} else if err != nil {
+		log.WithError(err).Debug("Failed to get minion version")
+		return "", false
+	}
+
+	if err := version.Check(minionVersion, schema); err != nil {
+		log.WithError(err).WithField("machine", m.machine).Error(
+			"Refusing to configure incompatible minion.")
+		return minionVersion, false
+	}
+
+	if minionVersion != version.Version {
+		log.WithFields(log.Fields{
+			"machine": m.machine,
+			"minion":  minionVersion,
+			"daemon":  version.Version,
+		}).Warn("Minion is running a different version of Quilt.")
+	}
+	return minionVersion, true
+}
+
+// certifyImpl makes sure that the minion on 'm' presents a certificate for the
+// machine's IP addresses, and issues it one if it doesn't.  Minions generate their
+// keys themselves, so the key that's certified is whichever the minion at the public
+// IP reported by the cloud provider presents.  The minion, in turn, only accepts
+// certificates from the foreman.
+func certifyImpl(m db.Machine) error {
+	config, err := creds.ClientTLSConfig(m.PublicIP)
+	if err != nil {
+		return err
+	}
+
+	// Uncertified minions present a self-signed certificate, so it's verified by
+	// hand below instead.
+	var peer *x509.Certificate
+	config.InsecureSkipVerify = true
+	config.VerifyPeerCertificate = func(raw [][]byte, _ [][]*x509.Certificate) error {
+		if len(raw) == 0 {
+			return errors.New("minion presented no certificate")
+		}
+
+		var parseErr error
+		peer, parseErr = x509.ParseCertificate(raw[0])
+		return parseErr
+	}
+
+	addr := m.PublicIP + ":9999"
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, config)
+	if err != nil {
+		return err
+	}
+	conn.Close()
+
+	ips := []string{m.PublicIP, m.PrivateIP}
+	if ca.VerifyMinion(peer, ips) == nil {
+		return nil
+	}
+
+	cert, err := ca.Certify(peer.PublicKey, ips)
+	if err != nil {
+		return err
+	}
+
+	// The certificate is only handed to the minion whose key it certifies.
+	config.VerifyPeerCertificate = func(raw [][]byte, _ [][]*x509.Certificate) error {
+		if len(raw) == 0 || !bytes.Equal(raw[0], peer.Raw) {
+			return errors.New("minion presented a different certificate")
+		}
+		return nil
+	}
+
+	cc, err := grpc.Dial(addr, grpc.WithBlock(), grpc.WithTimeout(10*time.Second),
+		grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	_, err = pb.NewMinionClient(cc).SetCertificate(ctx, &pb.Certificate{Cert: cert})
+	if err == nil {
+		log.WithField("machine", m).Info("Certified minion.")
+	}
+	return err
 }
 
 func newClientImpl(ip string) (client, error) {
-	cc, err := grpc.Dial(ip+":9999", grpc.WithInsecure())
+	// The minions' certificates are checked against the IP that's dialed.
+	tc, err := creds.ClientCredentials("")
+	if err != nil {
+		return nil, err
+	}
+
+	cc, err := grpc.Dial(ip+":9999", grpc.WithTransportCredentials(tc))
 	if err != nil {
 		return nil, err
 	}
@@ -199,6 +559,7 @@ func newClientImpl(ip string) (client, error) {
 
 // Storing in a variable allows us to mock it out for unit tests
 var newClient = newClientImpl
+var certify = certifyImpl
 
 func (c clientImpl) getMinion() (pb.MinionConfig, error) {
 	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
@@ -210,6 +571,51 @@ func (c clientImpl) getMinion() (pb.MinionConfig, error) {
 	return *cfg, nil
 }
 
+func (c clientImpl) getVersion() (string, int, error) {
+	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	reply, err := c.Version(ctx, &pb.Request{})
+	if err != nil {
+		return "", 0, err
+	}
+	return reply.Version, int(reply.SchemaVersion), nil
+}
+
+func (c clientImpl) getEvents(after time.Time) ([]db.Event, error) {
+	req := &pb.EventRequest{}
+	if !after.IsZero() {
+		req.After = after.UnixNano()
+	}
+
+	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	reply, err := c.GetEvents(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []db.Event
+	for _, e := range reply.Events {
+		events = append(events, db.Event{
+			Time:    time.Unix(0, e.Time),
+			Type:    db.EventType(e.Type),
+			Message: e.Message,
+		})
+	}
+	return events, nil
+}
+
+func (c clientImpl) getStatus() (db.Status, error) {
+	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	reply, err := c.GetStatus(ctx, &pb.Request{})
+	if err != nil {
+		return db.Status{}, err
+	}
+
+	return db.Status{
+		MinionWarnings:          reply.Warnings,
+		UnschedulableContainers: reply.UnschedulableContainers,
+	}, nil
+}
+
 func (c clientImpl) setMinion(cfg pb.MinionConfig) error {
 	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
 	_, err := c.SetMinionConfig(ctx, &cfg)
@@ -1,12 +1,16 @@
 package foreman
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/minion/pb"
+	"github.com/NetSys/quilt/version"
 )
 
 type clients struct {
@@ -147,6 +151,236 @@ func TestBootEtcd(t *testing.T) {
 		clients.clients["w1-pub"].mc.EtcdMembers)
 }
 
+func TestNamespaces(t *testing.T) {
+	conn, clients := startTest()
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		for _, ns := range []string{"a", "b", "c"} {
+			clst := view.InsertCluster()
+			clst.Namespace = ns
+			clst.Spec = ns + " spec"
+			view.Commit(clst)
+
+			m := view.InsertMachine()
+			m.Namespace = ns
+			m.Role = db.Master
+			m.PublicIP = ns + "-pub"
+			m.PrivateIP = ns + "-priv"
+			m.CloudID = "ignored"
+			view.Commit(m)
+		}
+		return nil
+	})
+	Init(conn, "a", certs.CA{}, certs.Credentials{})
+	Init(conn, "b", certs.CA{}, certs.Credentials{})
+	RunOnce(conn)
+
+	// Each minion is configured with its own namespace's spec and etcd.
+	for _, ns := range []string{"a", "b"} {
+		mc := clients.clients[ns+"-pub"].mc
+		assert.Equal(t, ns+" spec", mc.Spec)
+		assert.Equal(t, []string{ns + "-priv"}, mc.EtcdMembers)
+	}
+
+	// The minions of namespaces the foreman hasn't been initialized for are left
+	// alone until it learns their roles.
+	assert.NotContains(t, clients.clients, "c-pub")
+
+	Init(conn, "c", certs.CA{}, certs.Credentials{})
+	RunOnce(conn)
+	assert.Equal(t, "c spec", clients.clients["c-pub"].mc.Spec)
+}
+
+func TestCertify(t *testing.T) {
+	conn, clients := startTest()
+	certified := map[string]bool{}
+	certify = func(m db.Machine) error {
+		if !certified[m.PublicIP] {
+			return errors.New("unreachable")
+		}
+		return nil
+	}
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.PublicIP = "1.1.1.1"
+		m.PrivateIP = "1.1.1.2"
+		m.CloudID = "ID"
+		view.Commit(m)
+		return nil
+	})
+
+	// Minions aren't contacted until they're certified.
+	RunOnce(conn)
+	assert.Zero(t, clients.newCalls)
+	assert.Empty(t, minions)
+
+	certified["1.1.1.1"] = true
+	RunOnce(conn)
+	assert.Equal(t, 1, clients.newCalls)
+	assert.Contains(t, minions, "1.1.1.1")
+
+	// Once connected, they aren't certified again.
+	certify = func(db.Machine) error {
+		t.Error("Unexpected certification")
+		return nil
+	}
+	RunOnce(conn)
+	assert.Equal(t, 1, clients.newCalls)
+}
+
+func TestMinionVersion(t *testing.T) {
+	conn, clients := startTest()
+	newClient = func(ip string) (client, error) {
+		fc := &fakeClient{clients, ip, pb.MinionConfig{}}
+		clients.clients[ip] = fc
+		if ip == "old-pub" {
+			return incompatibleClient{fc}, nil
+		}
+		return fc, nil
+	}
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		for _, name := range []string{"old", "new"} {
+			m := view.InsertMachine()
+			m.Role = db.Worker
+			m.PublicIP = name + "-pub"
+			m.PrivateIP = name + "-priv"
+			m.CloudID = "ignored"
+			view.Commit(m)
+		}
+		return nil
+	})
+	RunOnce(conn)
+
+	// Only the compatible minion is configured.
+	assert.Equal(t, "new-priv", clients.clients["new-pub"].mc.PrivateIP)
+	assert.Empty(t, clients.clients["old-pub"].mc.PrivateIP)
+
+	versions := map[string]string{}
+	for _, m := range conn.SelectFromMachine(nil) {
+		versions[m.PublicIP] = m.MinionVersion
+	}
+	assert.Equal(t, map[string]string{
+		"old-pub": "old",
+		"new-pub": version.Version,
+	}, versions)
+}
+
+func TestEvents(t *testing.T) {
+	conn, clients := startTest()
+	ec := eventClient{events: []db.Event{
+		{Time: time.Unix(0, 100), Type: db.PlacementFailed, Message: "a"},
+	}}
+	newClient = func(ip string) (client, error) {
+		ec.fakeClient = &fakeClient{clients, ip, pb.MinionConfig{}}
+		clients.clients[ip] = ec.fakeClient
+		return &ec, nil
+	}
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.StitchID = "1"
+		m.Namespace = "ns"
+		m.PublicIP = "pub"
+		m.PrivateIP = "priv"
+		m.CloudID = "ignored"
+		view.Commit(m)
+		return nil
+	})
+
+	events := func() []string {
+		var res []string
+		for _, e := range conn.SelectFromEvent(nil) {
+			assert.Equal(t, "ns", e.Namespace)
+			assert.Equal(t, "1", e.Machine)
+			res = append(res, string(e.Type)+": "+e.Message)
+		}
+		return res
+	}
+
+	RunOnce(conn)
+	assert.Equal(t, []string{
+		"MinionConnected: Connected to the minion on pub.",
+		"PlacementFailed: a",
+	}, events())
+
+	// Only new events are replicated.
+	ec.events = append(ec.events,
+		db.Event{Time: time.Unix(0, 200), Type: db.ImagePullFailed, Message: "b"})
+	RunOnce(conn)
+	RunOnce(conn)
+	assert.Equal(t, []string{
+		"MinionConnected: Connected to the minion on pub.",
+		"PlacementFailed: a",
+		"ImagePullFailed: b",
+	}, events())
+
+	// Events aren't duplicated when the daemon restarts and loses its cursors.
+	minions = map[string]*minion{}
+	Init(conn, "ns", certs.CA{}, certs.Credentials{})
+	RunOnce(conn)
+	assert.Equal(t, []string{
+		"MinionConnected: Connected to the minion on pub.",
+		"PlacementFailed: a",
+		"ImagePullFailed: b",
+	}, events())
+
+	minions["pub"].client = disconnectedClient{ec.fakeClient}
+	RunOnce(conn)
+	assert.Equal(t, "MinionDisconnected: Lost the connection to the minion on pub.",
+		events()[3])
+}
+
+func TestStatus(t *testing.T) {
+	conn, clients := startTest()
+	sc := statusClient{status: db.Status{
+		MinionWarnings:          []string{"warning"},
+		UnschedulableContainers: []string{"1 (alpine)"},
+	}}
+	newClient = func(ip string) (client, error) {
+		sc.fakeClient = &fakeClient{clients, ip,
+			pb.MinionConfig{Role: pb.MinionConfig_MASTER}}
+		clients.clients[ip] = sc.fakeClient
+		return &sc, nil
+	}
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.Namespace = "ns"
+		m.Role = db.Master
+		m.PublicIP = "pub"
+		m.PrivateIP = "priv"
+		m.CloudID = "ignored"
+		view.Commit(m)
+
+		status := view.InsertStatus()
+		status.Namespace = "ns"
+		status.Warnings = []string{"engine"}
+		view.Commit(status)
+		return nil
+	})
+
+	selectStatus := func() db.Status {
+		statuses := conn.SelectFromStatus(nil)
+		assert.Len(t, statuses, 1)
+		return statuses[0]
+	}
+
+	RunOnce(conn)
+	status := selectStatus()
+	assert.Equal(t, []string{"engine"}, status.Warnings)
+	assert.Equal(t, []string{"warning"}, status.MinionWarnings)
+	assert.Equal(t, []string{"1 (alpine)"}, status.UnschedulableContainers)
+
+	// Once the minion stops reporting problems, they're cleared.
+	sc.status = db.Status{}
+	RunOnce(conn)
+	status = selectStatus()
+	assert.Equal(t, []string{"engine"}, status.Warnings)
+	assert.Nil(t, status.MinionWarnings)
+	assert.Nil(t, status.UnschedulableContainers)
+}
+
 func TestInitForeman(t *testing.T) {
 	conn := startTestWithRole(pb.MinionConfig_WORKER)
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
@@ -158,18 +392,45 @@ func TestInitForeman(t *testing.T) {
 		return nil
 	})
 
-	Init(conn)
+	Init(conn, "", certs.CA{}, certs.Credentials{})
 	for _, m := range minions {
 		assert.Equal(t, db.Role(db.Worker), m.machine.Role)
 	}
 
 	conn = startTestWithRole(pb.MinionConfig_Role(-7))
-	Init(conn)
+	Init(conn, "", certs.CA{}, certs.Credentials{})
 	for _, m := range minions {
 		assert.Equal(t, db.None, m.machine.Role)
 	}
 }
 
+func TestInitNamespace(t *testing.T) {
+	conn, clients := startTest()
+	insert := func(namespace, ip string) {
+		conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+			m := view.InsertMachine()
+			m.Namespace = namespace
+			m.PublicIP = ip
+			m.PrivateIP = ip
+			m.CloudID = ip
+			view.Commit(m)
+			return nil
+		})
+	}
+
+	insert("a", "1.1.1.1")
+	Init(conn, "a", certs.CA{}, certs.Credentials{})
+	assert.Len(t, minions, 1)
+	a := minions["1.1.1.1"]
+
+	// Initializing a new namespace leaves the minions of the others be.
+	insert("b", "2.2.2.2")
+	Init(conn, "b", certs.CA{}, certs.Credentials{})
+	assert.Len(t, minions, 2)
+	assert.True(t, a == minions["1.1.1.1"])
+	assert.Equal(t, 2, clients.newCalls)
+}
+
 func TestConfigConsistency(t *testing.T) {
 	masterRole := db.RoleToPB(db.Master)
 	workerRole := db.RoleToPB(db.Worker)
@@ -190,7 +451,7 @@ func TestConfigConsistency(t *testing.T) {
 		return nil
 	})
 
-	Init(conn)
+	Init(conn, "", certs.CA{}, certs.Credentials{})
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
 		master.Role = db.Master
 		worker.Role = db.Worker
@@ -218,7 +479,7 @@ func TestConfigConsistency(t *testing.T) {
 	clients.clients["2.2.2.2"] = &fakeClient{clients, "2.2.2.2",
 		pb.MinionConfig{Role: workerRole}}
 
-	Init(conn)
+	Init(conn, "", certs.CA{}, certs.Credentials{})
 	RunOnce(conn)
 	checkRoles()
 
@@ -243,9 +504,72 @@ func TestConfigConsistency(t *testing.T) {
 	})
 }
 
+func TestTerminating(t *testing.T) {
+	conn, clients := startTest()
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.Role = db.Worker
+		m.PublicIP = "1.1.1.1"
+		m.PrivateIP = "1.1.1.1"
+		m.CloudID = "ID"
+		view.Commit(m)
+		return nil
+	})
+
+	RunOnce(conn)
+	assert.False(t, conn.SelectFromMachine(nil)[0].Terminating)
+
+	fc := clients.clients["1.1.1.1"]
+	fc.mc.Terminating = true
+	RunOnce(conn)
+	assert.True(t, conn.SelectFromMachine(nil)[0].Terminating)
+
+	// Configuring the minion doesn't clear its notice.
+	RunOnce(conn)
+	assert.True(t, fc.mc.Terminating)
+	assert.Equal(t, "1.1.1.1", fc.mc.PrivateIP)
+
+	// The machine remains terminating once its minion is unreachable.
+	minions["1.1.1.1"].client = disconnectedClient{fc}
+	RunOnce(conn)
+	m := conn.SelectFromMachine(nil)[0]
+	assert.True(t, m.Terminating)
+	assert.False(t, m.Connected)
+}
+
+func TestConcurrentMachineUpdate(t *testing.T) {
+	conn, _ := startTest()
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		m := view.InsertMachine()
+		m.PublicIP = "1.1.1.1"
+		m.PrivateIP = "1.1.1.1"
+		m.CloudID = "ID"
+		view.Commit(m)
+		return nil
+	})
+
+	// Modify the machine while the foreman is contacting its minion, as the
+	// cluster join might.
+	newClient = func(ip string) (client, error) {
+		return racingClient{&fakeClient{nil, ip, pb.MinionConfig{}}, conn}, nil
+	}
+
+	RunOnce(conn)
+	m := conn.SelectFromMachine(nil)[0]
+	assert.Equal(t, "size", m.Size)
+	assert.False(t, m.Connected)
+
+	RunOnce(conn)
+	m = conn.SelectFromMachine(nil)[0]
+	assert.Equal(t, "size", m.Size)
+	assert.True(t, m.Connected)
+}
+
 func startTest() (db.Conn, *clients) {
 	conn := db.New()
 	minions = map[string]*minion{}
+	namespaces = map[string]struct{}{"": {}, "ns": {}}
+	certify = func(db.Machine) error { return nil }
 	clients := &clients{make(map[string]*fakeClient), 0}
 	newClient = func(ip string) (client, error) {
 		if fc, ok := clients.clients[ip]; ok {
@@ -260,6 +584,9 @@ func startTest() (db.Conn, *clients) {
 }
 
 func startTestWithRole(role pb.MinionConfig_Role) db.Conn {
+	minions = map[string]*minion{}
+	namespaces = map[string]struct{}{}
+	certify = func(db.Machine) error { return nil }
 	clientInst := &clients{make(map[string]*fakeClient), 0}
 	newClient = func(ip string) (client, error) {
 		fc := &fakeClient{clientInst, ip, pb.MinionConfig{Role: role}}
@@ -285,6 +612,77 @@ func (fc *fakeClient) getMinion() (pb.MinionConfig, error) {
 	return fc.mc, nil
 }
 
+func (fc *fakeClient) getVersion() (string, int, error) {
+	return version.Version, version.Schema, nil
+}
+
+func (fc *fakeClient) getEvents(after time.Time) ([]db.Event, error) {
+	return nil, nil
+}
+
+func (fc *fakeClient) getStatus() (db.Status, error) {
+	return db.Status{}, nil
+}
+
 func (fc *fakeClient) Close() {
 	delete(fc.clients.clients, fc.ip)
 }
+
+type incompatibleClient struct {
+	*fakeClient
+}
+
+func (ic incompatibleClient) getVersion() (string, int, error) {
+	return "old", version.Schema - 1, nil
+}
+
+// eventClient reports the events in `events` that happened after the requested time.
+type eventClient struct {
+	*fakeClient
+	events []db.Event
+}
+
+func (ec eventClient) getEvents(after time.Time) ([]db.Event, error) {
+	var events []db.Event
+	for _, e := range ec.events {
+		if e.Time.After(after) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// statusClient reports `status` as the problems it found with its spec.
+type statusClient struct {
+	*fakeClient
+	status db.Status
+}
+
+func (sc statusClient) getStatus() (db.Status, error) {
+	return sc.status, nil
+}
+
+type disconnectedClient struct {
+	*fakeClient
+}
+
+func (dc disconnectedClient) getMinion() (pb.MinionConfig, error) {
+	return pb.MinionConfig{}, errors.New("disconnected")
+}
+
+type racingClient struct {
+	*fakeClient
+	conn db.Conn
+}
+
+func (rc racingClient) getMinion() (pb.MinionConfig, error) {
+	rc.conn.Txn(db.MachineTable).Run(func(view db.Database) error {
+		m := view.SelectFromMachine(nil)[0]
+		m.Size = "size"
+		view.Commit(m)
+		return nil
+	})
+	return rc.mc, nil
+}
+
+func (rc racingClient) Close() {}
@@ -119,6 +119,24 @@ func (clst *Cluster) List() ([]machine.Machine, error) {
 		return nil, err
 	}
 	for _, item := range list.Items {
+		// Preempted instances are stopped rather than deleted, and would
+		// otherwise pass for running machines forever, so they're deleted here
+		// and left out, which lets them be replaced.  Stopping instances become
+		// terminated once they've stopped.
+		switch item.Status {
+		case "STOPPING":
+			continue
+		case "TERMINATED":
+			_, err := clst.gce.DeleteInstance(clst.projID, clst.zone, item.Name)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+					"id":    item.Name,
+				}).Warn("Failed to delete terminated instance.")
+			}
+			continue
+		}
+
 		// XXX: This make some iffy assumptions about NetworkInterfaces
 		machineSplitURL := strings.Split(item.MachineType, "/")
 		mtype := machineSplitURL[len(machineSplitURL)-1]
@@ -129,14 +147,16 @@ func (clst *Cluster) List() ([]machine.Machine, error) {
 			floatingIP = accessConfig.NatIP
 		}
 
+		preemptible := item.Scheduling != nil && item.Scheduling.Preemptible
 		mList = append(mList, machine.Machine{
-			ID:         item.Name,
-			PublicIP:   accessConfig.NatIP,
-			FloatingIP: floatingIP,
-			PrivateIP:  item.NetworkInterfaces[0].NetworkIP,
-			Size:       mtype,
-			Region:     clst.zone,
-			Provider:   db.Google,
+			ID:          item.Name,
+			PublicIP:    accessConfig.NatIP,
+			FloatingIP:  floatingIP,
+			PrivateIP:   item.NetworkInterfaces[0].NetworkIP,
+			Size:        mtype,
+			Region:      clst.zone,
+			Provider:    db.Google,
+			Preemptible: preemptible,
 		})
 	}
 	return mList, nil
@@ -148,8 +168,8 @@ func (clst *Cluster) Boot(bootSet []machine.Machine) error {
 	var names []string
 	for _, m := range bootSet {
 		name := "quilt-" + uuid.NewV4().String()
-		_, err := clst.instanceNew(name, m.Size,
-			cloudcfg.Ubuntu(m.SSHKeys, "xenial"))
+		_, err := clst.instanceNew(name, m.Size, m.Preemptible,
+			cloudcfg.Ubuntu(m.SSHKeys, "xenial", m.CACert))
 		if err != nil {
 			log.WithFields(log.Fields{
 				"error": err,
@@ -276,13 +296,14 @@ func (clst *Cluster) operationWait(ops []*compute.Operation, domain int) error {
 	}
 }
 
-// Create new GCE instance.
+// Create new GCE instance.  Preemptible instances may be stopped by Google at any
+// time, and can't be restarted automatically or live migrated.
 //
 // Does not check if the operation succeeds.
 //
 // XXX: all kinds of hardcoded junk in here
 // XXX: currently only defines the bare minimum
-func (clst *Cluster) instanceNew(name string, size string,
+func (clst *Cluster) instanceNew(name string, size string, preemptible bool,
 	cloudConfig string) (*compute.Operation, error) {
 	instance := &compute.Instance{
 		Name:        name,
@@ -323,6 +344,15 @@ func (clst *Cluster) instanceNew(name string, size string,
 		},
 	}
 
+	if preemptible {
+		instance.Scheduling = &compute.Scheduling{
+			Preemptible:       true,
+			AutomaticRestart:  false,
+			OnHostMaintenance: "TERMINATE",
+			ForceSendFields:   []string{"AutomaticRestart"},
+		}
+	}
+
 	return clst.gce.InsertInstance(clst.projID, clst.zone, instance)
 }
 
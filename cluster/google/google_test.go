@@ -1,10 +1,12 @@
 package google
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/NetSys/quilt/cluster/machine"
 	"github.com/NetSys/quilt/db"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	compute "google.golang.org/api/compute/v1"
@@ -46,12 +48,27 @@ func (s *GoogleTestSuite) TestList() {
 					},
 				},
 			},
+			{
+				MachineType: "machine/split/type-2",
+				Name:        "name-2",
+				NetworkInterfaces: []*compute.NetworkInterface{
+					{
+						AccessConfigs: []*compute.AccessConfig{
+							{
+								NatIP: "z.z.z.z",
+							},
+						},
+						NetworkIP: "w.w.w.w",
+					},
+				},
+				Scheduling: &compute.Scheduling{Preemptible: true},
+			},
 		},
 	}, nil)
 
 	machines, err := s.clst.List()
 	s.NoError(err)
-	s.Len(machines, 1)
+	s.Len(machines, 2)
 	s.Equal(machines[0], machine.Machine{
 		ID:        "name-1",
 		PublicIP:  "x.x.x.x",
@@ -60,6 +77,77 @@ func (s *GoogleTestSuite) TestList() {
 		Region:    "zone-1",
 		Provider:  db.Google,
 	})
+	s.Equal(machines[1], machine.Machine{
+		ID:          "name-2",
+		PublicIP:    "z.z.z.z",
+		PrivateIP:   "w.w.w.w",
+		Size:        "type-2",
+		Region:      "zone-1",
+		Provider:    db.Google,
+		Preemptible: true,
+	})
+}
+
+func (s *GoogleTestSuite) TestListReclaimed() {
+	running := &compute.Instance{
+		MachineType: "machine/split/type-1",
+		Name:        "running",
+		Status:      "RUNNING",
+		NetworkInterfaces: []*compute.NetworkInterface{{
+			AccessConfigs: []*compute.AccessConfig{{NatIP: "x.x.x.x"}},
+			NetworkIP:     "y.y.y.y",
+		}},
+	}
+	s.gce.On("ListInstances", "project", "zone-1", apiOptions{
+		filter: "description eq namespace",
+	}).Return(&compute.InstanceList{Items: []*compute.Instance{
+		running,
+		{Name: "stopping", Status: "STOPPING"},
+		{Name: "terminated", Status: "TERMINATED"},
+	}}, nil)
+	s.gce.On("DeleteInstance", "project", "zone-1", "terminated").Return(
+		&compute.Operation{}, nil).Once()
+
+	// Preempted instances are deleted, so that they're replaced.
+	machines, err := s.clst.List()
+	s.NoError(err)
+	s.Equal([]machine.Machine{{
+		ID:        "running",
+		PublicIP:  "x.x.x.x",
+		PrivateIP: "y.y.y.y",
+		Size:      "type-1",
+		Region:    "zone-1",
+		Provider:  db.Google,
+	}}, machines)
+	s.gce.AssertExpectations(s.T())
+
+	// Failing to delete them doesn't prevent the rest from being listed.
+	s.gce.On("DeleteInstance", "project", "zone-1", "terminated").Return(
+		nil, errors.New("unavailable")).Once()
+	machines, err = s.clst.List()
+	s.NoError(err)
+	s.Len(machines, 1)
+}
+
+func (s *GoogleTestSuite) TestInstanceNew() {
+	var inserted []*compute.Instance
+	s.gce.On("InsertInstance", "project", "zone-1", mock.Anything).Run(
+		func(args mock.Arguments) {
+			inserted = append(inserted, args.Get(2).(*compute.Instance))
+		}).Return(&compute.Operation{}, nil)
+
+	_, err := s.clst.instanceNew("standard", "size", false, "cloudcfg")
+	s.NoError(err)
+	_, err = s.clst.instanceNew("preemptible", "size", true, "cloudcfg")
+	s.NoError(err)
+
+	s.Len(inserted, 2)
+	s.Nil(inserted[0].Scheduling)
+	s.Equal(&compute.Scheduling{
+		Preemptible:       true,
+		OnHostMaintenance: "TERMINATE",
+		ForceSendFields:   []string{"AutomaticRestart"},
+	}, inserted[1].Scheduling)
 }
 
 func TestGoogleTestSuite(t *testing.T) {
@@ -0,0 +1,143 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/NetSys/quilt/certs"
+	"github.com/NetSys/quilt/cluster/acl"
+	"github.com/NetSys/quilt/cluster/cloudcfg"
+	"github.com/NetSys/quilt/cluster/machine"
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/minion/docker"
+
+	dkc "github.com/fsouza/go-dockerclient"
+	"github.com/satori/go.uuid"
+)
+
+// Each machine is a privileged container running its own Docker daemon, in which the
+// minion runs its containers.
+const machineImage = "docker:17.03-dind"
+
+const (
+	namespaceLabel = "quilt.local.namespace"
+	sizeLabel      = "quilt.local.size"
+)
+
+// The Cluster object represents the machines booted as containers by the Docker
+// daemon on the local host.
+type Cluster struct {
+	dk        docker.Client
+	namespace string
+	network   string
+}
+
+// newDocker is a variable so it can be easily replaced while unit testing.
+var newDocker = func() docker.Client {
+	return docker.New("unix:///var/run/docker.sock")
+}
+
+// New creates a new local cluster.  The machines in a namespace share a Docker
+// network, through which they reach each other and the daemon.
+func New(namespace string) (*Cluster, error) {
+	clst := Cluster{
+		dk:        newDocker(),
+		namespace: namespace,
+		network:   fmt.Sprintf("quilt-%s", namespace),
+	}
+
+	if _, err := clst.List(); err != nil {
+		return nil, errors.New("local Docker daemon failed to connect")
+	}
+	return &clst, nil
+}
+
+// Boot starts a machine container for each machine in `bootSet`, creating the
+// namespace's network if it doesn't exist yet.
+func (clst Cluster) Boot(bootSet []machine.Machine) error {
+	if len(bootSet) == 0 {
+		return nil
+	}
+
+	_, err := clst.dk.CreateNetwork(dkc.CreateNetworkOptions{
+		Name:           clst.network,
+		Driver:         "bridge",
+		CheckDuplicate: true,
+	})
+	if err != nil && err != dkc.ErrNetworkAlreadyExists {
+		return err
+	}
+
+	for _, m := range bootSet {
+		_, err := clst.dk.Run(docker.RunOptions{
+			Name:  "quilt-" + uuid.NewV4().String(),
+			Image: machineImage,
+			Args:  []string{"sh", "-c", cloudcfg.Local()},
+			Labels: map[string]string{
+				namespaceLabel: clst.namespace,
+				sizeLabel:      m.Size,
+			},
+			NetworkMode: clst.network,
+			Privileged:  true,
+			Files:       caCertFiles(m.CACert),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// caCertFiles returns the files in which the minion of a machine container finds
+// `caCert`.  The minion generates its own key in the same directory, which the
+// foreman then certifies.
+func caCertFiles(caCert string) []docker.File {
+	return []docker.File{
+		{Path: filepath.Join(certs.MinionDir, certs.CACertFile),
+			Contents: caCert, Mode: 0644},
+	}
+}
+
+// List queries `clst` for the list of booted machines.
+func (clst Cluster) List() ([]machine.Machine, error) {
+	containers, err := clst.dk.List(map[string][]string{
+		"label": {fmt.Sprintf("%s=%s", namespaceLabel, clst.namespace)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var machines []machine.Machine
+	for _, c := range containers {
+		machines = append(machines, machine.Machine{
+			ID:        c.ID,
+			PublicIP:  c.IP,
+			PrivateIP: c.IP,
+			Size:      c.Labels[sizeLabel],
+			Provider:  db.Local,
+		})
+	}
+	return machines, nil
+}
+
+// Stop removes the containers of `machines`.
+func (clst Cluster) Stop(machines []machine.Machine) error {
+	for _, m := range machines {
+		if err := clst.dk.RemoveID(m.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetACLs is a noop for the local provider.  The machines are only reachable from
+// the host.
+func (clst Cluster) SetACLs(acls []acl.ACL) error {
+	return nil
+}
+
+// UpdateFloatingIPs is not supported.
+func (clst Cluster) UpdateFloatingIPs([]machine.Machine) error {
+	return errors.New("local provider does not support floating IPs")
+}
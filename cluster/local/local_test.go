@@ -0,0 +1,77 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/NetSys/quilt/cluster/machine"
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/minion/docker"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal(t *testing.T) {
+	md, dk := docker.NewMock()
+	newDocker = func() docker.Client { return dk }
+
+	clst, err := New("ns")
+	assert.NoError(t, err)
+	assert.Empty(t, md.Networks)
+
+	machines, err := clst.List()
+	assert.NoError(t, err)
+	assert.Empty(t, machines)
+
+	err = clst.Boot([]machine.Machine{{
+		Size:     "1,2",
+		Provider: db.Local,
+		CACert:   "ca",
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, "quilt-ns", md.Networks["bridge"].Name)
+
+	// Machines in other namespaces aren't listed.
+	other := Cluster{dk: dk, namespace: "other", network: "quilt-other"}
+	assert.NoError(t, other.Boot([]machine.Machine{{Size: "1,1"}}))
+
+	var id string
+	for cid, c := range md.Containers {
+		if c.Config.Labels[namespaceLabel] != "ns" {
+			continue
+		}
+		id = cid
+
+		assert.True(t, c.HostConfig.Privileged)
+		assert.Equal(t, "quilt-ns", c.HostConfig.NetworkMode)
+		assert.Equal(t, machineImage, c.Config.Image)
+		assert.Equal(t, map[string]string{
+			"/etc/quilt/tls/ca.crt": "ca",
+		}, c.Files)
+		c.NetworkSettings.IPAddress = "172.17.0.2"
+	}
+
+	machines, err = clst.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []machine.Machine{{
+		ID:        id,
+		PublicIP:  "172.17.0.2",
+		PrivateIP: "172.17.0.2",
+		Size:      "1,2",
+		Provider:  db.Local,
+	}}, machines)
+
+	assert.NoError(t, clst.Stop(machines))
+	machines, err = clst.List()
+	assert.NoError(t, err)
+	assert.Empty(t, machines)
+
+	machines, err = other.List()
+	assert.NoError(t, err)
+	assert.Len(t, machines, 1)
+
+	assert.Error(t, clst.UpdateFloatingIPs(nil))
+
+	md.ListError = true
+	_, err = New("ns")
+	assert.EqualError(t, err, "local Docker daemon failed to connect")
+}
@@ -0,0 +1,27 @@
+package machine
+
+// azureDescriptions enumerates the Azure virtual machine sizes.  The prices are
+// hourly, for Linux machines in West US 2.
+var azureDescriptions = []Description{
+	{Size: "Standard_B1s", CPU: 1, RAM: 1, Disk: "4 SSD", Price: 0.0104},
+	{Size: "Standard_B1ms", CPU: 1, RAM: 2, Disk: "4 SSD", Price: 0.0207},
+	{Size: "Standard_B2s", CPU: 2, RAM: 4, Disk: "8 SSD", Price: 0.0416},
+	{Size: "Standard_B2ms", CPU: 2, RAM: 8, Disk: "16 SSD", Price: 0.0832},
+	{Size: "Standard_B4ms", CPU: 4, RAM: 16, Disk: "32 SSD", Price: 0.166},
+	{Size: "Standard_B8ms", CPU: 8, RAM: 32, Disk: "64 SSD", Price: 0.333},
+	{Size: "Standard_D2s_v3", CPU: 2, RAM: 8, Disk: "16 SSD", Price: 0.096},
+	{Size: "Standard_D4s_v3", CPU: 4, RAM: 16, Disk: "32 SSD", Price: 0.192},
+	{Size: "Standard_D8s_v3", CPU: 8, RAM: 32, Disk: "64 SSD", Price: 0.384},
+	{Size: "Standard_D16s_v3", CPU: 16, RAM: 64, Disk: "128 SSD", Price: 0.768},
+	{Size: "Standard_D32s_v3", CPU: 32, RAM: 128, Disk: "256 SSD", Price: 1.536},
+	{Size: "Standard_F2s_v2", CPU: 2, RAM: 4, Disk: "16 SSD", Price: 0.0846},
+	{Size: "Standard_F4s_v2", CPU: 4, RAM: 8, Disk: "32 SSD", Price: 0.169},
+	{Size: "Standard_F8s_v2", CPU: 8, RAM: 16, Disk: "64 SSD", Price: 0.338},
+	{Size: "Standard_F16s_v2", CPU: 16, RAM: 32, Disk: "128 SSD", Price: 0.677},
+	{Size: "Standard_F32s_v2", CPU: 32, RAM: 64, Disk: "256 SSD", Price: 1.353},
+	{Size: "Standard_E2s_v3", CPU: 2, RAM: 16, Disk: "32 SSD", Price: 0.126},
+	{Size: "Standard_E4s_v3", CPU: 4, RAM: 32, Disk: "64 SSD", Price: 0.252},
+	{Size: "Standard_E8s_v3", CPU: 8, RAM: 64, Disk: "128 SSD", Price: 0.504},
+	{Size: "Standard_E16s_v3", CPU: 16, RAM: 128, Disk: "256 SSD", Price: 1.008},
+	{Size: "Standard_E32s_v3", CPU: 32, RAM: 256, Disk: "512 SSD", Price: 2.016},
+}
@@ -0,0 +1,28 @@
+package machine
+
+// digitalOceanDescriptions enumerates the DigitalOcean droplet sizes.  The prices are
+// hourly, and the same in every region.
+var digitalOceanDescriptions = []Description{
+	{Size: "s-1vcpu-512mb-10gb", CPU: 1, RAM: 0.5, Disk: "10 SSD", Price: 0.00595},
+	{Size: "s-1vcpu-1gb", CPU: 1, RAM: 1, Disk: "25 SSD", Price: 0.00893},
+	{Size: "s-1vcpu-2gb", CPU: 1, RAM: 2, Disk: "50 SSD", Price: 0.01786},
+	{Size: "s-2vcpu-2gb", CPU: 2, RAM: 2, Disk: "60 SSD", Price: 0.02679},
+	{Size: "s-2vcpu-4gb", CPU: 2, RAM: 4, Disk: "80 SSD", Price: 0.03571},
+	{Size: "s-4vcpu-8gb", CPU: 4, RAM: 8, Disk: "160 SSD", Price: 0.07143},
+	{Size: "s-8vcpu-16gb", CPU: 8, RAM: 16, Disk: "320 SSD", Price: 0.14286},
+	{Size: "c-2", CPU: 2, RAM: 4, Disk: "25 SSD", Price: 0.0625},
+	{Size: "c-4", CPU: 4, RAM: 8, Disk: "50 SSD", Price: 0.125},
+	{Size: "c-8", CPU: 8, RAM: 16, Disk: "100 SSD", Price: 0.25},
+	{Size: "c-16", CPU: 16, RAM: 32, Disk: "200 SSD", Price: 0.5},
+	{Size: "c-32", CPU: 32, RAM: 64, Disk: "400 SSD", Price: 1.0},
+	{Size: "g-2vcpu-8gb", CPU: 2, RAM: 8, Disk: "25 SSD", Price: 0.09375},
+	{Size: "g-4vcpu-16gb", CPU: 4, RAM: 16, Disk: "50 SSD", Price: 0.1875},
+	{Size: "g-8vcpu-32gb", CPU: 8, RAM: 32, Disk: "100 SSD", Price: 0.375},
+	{Size: "g-16vcpu-64gb", CPU: 16, RAM: 64, Disk: "200 SSD", Price: 0.75},
+	{Size: "g-32vcpu-128gb", CPU: 32, RAM: 128, Disk: "400 SSD", Price: 1.5},
+	{Size: "m-2vcpu-16gb", CPU: 2, RAM: 16, Disk: "50 SSD", Price: 0.125},
+	{Size: "m-4vcpu-32gb", CPU: 4, RAM: 32, Disk: "100 SSD", Price: 0.25},
+	{Size: "m-8vcpu-64gb", CPU: 8, RAM: 64, Disk: "200 SSD", Price: 0.5},
+	{Size: "m-16vcpu-128gb", CPU: 16, RAM: 128, Disk: "400 SSD", Price: 1.0},
+	{Size: "m-32vcpu-256gb", CPU: 32, RAM: 256, Disk: "800 SSD", Price: 2.0},
+}
@@ -1,40 +1,78 @@
 package machine
 
+// googleDescriptions enumerates the GCE machine types, with the hourly prices of both
+// standard and preemptible instances.
 var googleDescriptions = []Description{
-	{Size: "n1-standard-1", CPU: 1, RAM: 3.75, Price: 0.050},
-	{Size: "n1-standard-2", CPU: 2, RAM: 7.5, Price: 0.100},
-	{Size: "n1-standard-4", CPU: 4, RAM: 15, Price: 0.200},
-	{Size: "n1-standard-8", CPU: 8, RAM: 30, Price: 0.400},
-	{Size: "n1-standard-16", CPU: 16, RAM: 60, Price: 0.800},
-	{Size: "n1-standard-326", CPU: 32, RAM: 120, Price: 1.600},
-	{Size: "f1-micro", CPU: 1, RAM: 0.60, Price: 0.008},
-	{Size: "g1-small", CPU: 1, RAM: 1.70, Price: 0.027},
-	{Size: "n1-highmem-2", CPU: 2, RAM: 13, Price: 0.126},
-	{Size: "n1-highmem-4", CPU: 4, RAM: 26, Price: 0.252},
-	{Size: "n1-highmem-8", CPU: 8, RAM: 52, Price: 0.504},
-	{Size: "n1-highmem-16", CPU: 16, RAM: 104, Price: 1.008},
-	{Size: "n1-highmem-326", CPU: 32, RAM: 208, Price: 2.016},
-	{Size: "n1-highcpu-2", CPU: 2, RAM: 1.80, Price: 0.076},
-	{Size: "n1-highcpu-4", CPU: 4, RAM: 3.60, Price: 0.152},
-	{Size: "n1-highcpu-8", CPU: 8, RAM: 7.20, Price: 0.304},
-	{Size: "n1-highcpu-16", CPU: 16, RAM: 14.40, Price: 0.608},
-	{Size: "n1-highcpu-326", CPU: 32, RAM: 28.80, Price: 1.216},
-	{Size: "n1-standard-1", CPU: 1, RAM: 3.75, Price: 0.055},
-	{Size: "n1-standard-2", CPU: 2, RAM: 7.5, Price: 0.110},
-	{Size: "n1-standard-4", CPU: 4, RAM: 15, Price: 0.220},
-	{Size: "n1-standard-8", CPU: 8, RAM: 30, Price: 0.440},
-	{Size: "n1-standard-16", CPU: 16, RAM: 60, Price: 0.880},
-	{Size: "n1-standard-326", CPU: 32, RAM: 120, Price: 1.760},
-	{Size: "f1-micro", CPU: 1, RAM: 0.60, Price: 0.009},
-	{Size: "g1-small", CPU: 1, RAM: 1.70, Price: 0.030},
-	{Size: "n1-highmem-2", CPU: 2, RAM: 13, Price: 0.139},
-	{Size: "n1-highmem-4", CPU: 4, RAM: 26, Price: 0.278},
-	{Size: "n1-highmem-8", CPU: 8, RAM: 52, Price: 0.556},
-	{Size: "n1-highmem-16", CPU: 16, RAM: 104, Price: 1.112},
-	{Size: "n1-highmem-326", CPU: 32, RAM: 208, Price: 2.224},
-	{Size: "n1-highcpu-2", CPU: 2, RAM: 1.80, Price: 0.084},
-	{Size: "n1-highcpu-4", CPU: 4, RAM: 3.60, Price: 0.168},
-	{Size: "n1-highcpu-8", CPU: 8, RAM: 7.20, Price: 0.336},
-	{Size: "n1-highcpu-16", CPU: 16, RAM: 14.40, Price: 0.672},
-	{Size: "n1-highcpu-326", CPU: 32, RAM: 28.80, Price: 1.344},
+	{Size: "n1-standard-1", CPU: 1, RAM: 3.75, Price: 0.050,
+		PreemptiblePrice: 0.010},
+	{Size: "n1-standard-2", CPU: 2, RAM: 7.5, Price: 0.100,
+		PreemptiblePrice: 0.020},
+	{Size: "n1-standard-4", CPU: 4, RAM: 15, Price: 0.200,
+		PreemptiblePrice: 0.040},
+	{Size: "n1-standard-8", CPU: 8, RAM: 30, Price: 0.400,
+		PreemptiblePrice: 0.080},
+	{Size: "n1-standard-16", CPU: 16, RAM: 60, Price: 0.800,
+		PreemptiblePrice: 0.160},
+	{Size: "n1-standard-326", CPU: 32, RAM: 120, Price: 1.600,
+		PreemptiblePrice: 0.320},
+	{Size: "f1-micro", CPU: 1, RAM: 0.60, Price: 0.008,
+		PreemptiblePrice: 0.0035},
+	{Size: "g1-small", CPU: 1, RAM: 1.70, Price: 0.027,
+		PreemptiblePrice: 0.007},
+	{Size: "n1-highmem-2", CPU: 2, RAM: 13, Price: 0.126,
+		PreemptiblePrice: 0.025},
+	{Size: "n1-highmem-4", CPU: 4, RAM: 26, Price: 0.252,
+		PreemptiblePrice: 0.050},
+	{Size: "n1-highmem-8", CPU: 8, RAM: 52, Price: 0.504,
+		PreemptiblePrice: 0.100},
+	{Size: "n1-highmem-16", CPU: 16, RAM: 104, Price: 1.008,
+		PreemptiblePrice: 0.200},
+	{Size: "n1-highmem-326", CPU: 32, RAM: 208, Price: 2.016,
+		PreemptiblePrice: 0.400},
+	{Size: "n1-highcpu-2", CPU: 2, RAM: 1.80, Price: 0.076,
+		PreemptiblePrice: 0.015},
+	{Size: "n1-highcpu-4", CPU: 4, RAM: 3.60, Price: 0.152,
+		PreemptiblePrice: 0.030},
+	{Size: "n1-highcpu-8", CPU: 8, RAM: 7.20, Price: 0.304,
+		PreemptiblePrice: 0.060},
+	{Size: "n1-highcpu-16", CPU: 16, RAM: 14.40, Price: 0.608,
+		PreemptiblePrice: 0.120},
+	{Size: "n1-highcpu-326", CPU: 32, RAM: 28.80, Price: 1.216,
+		PreemptiblePrice: 0.240},
+	{Size: "n1-standard-1", CPU: 1, RAM: 3.75, Price: 0.055,
+		PreemptiblePrice: 0.011},
+	{Size: "n1-standard-2", CPU: 2, RAM: 7.5, Price: 0.110,
+		PreemptiblePrice: 0.022},
+	{Size: "n1-standard-4", CPU: 4, RAM: 15, Price: 0.220,
+		PreemptiblePrice: 0.044},
+	{Size: "n1-standard-8", CPU: 8, RAM: 30, Price: 0.440,
+		PreemptiblePrice: 0.088},
+	{Size: "n1-standard-16", CPU: 16, RAM: 60, Price: 0.880,
+		PreemptiblePrice: 0.176},
+	{Size: "n1-standard-326", CPU: 32, RAM: 120, Price: 1.760,
+		PreemptiblePrice: 0.352},
+	{Size: "f1-micro", CPU: 1, RAM: 0.60, Price: 0.009,
+		PreemptiblePrice: 0.0039},
+	{Size: "g1-small", CPU: 1, RAM: 1.70, Price: 0.030,
+		PreemptiblePrice: 0.0077},
+	{Size: "n1-highmem-2", CPU: 2, RAM: 13, Price: 0.139,
+		PreemptiblePrice: 0.028},
+	{Size: "n1-highmem-4", CPU: 4, RAM: 26, Price: 0.278,
+		PreemptiblePrice: 0.055},
+	{Size: "n1-highmem-8", CPU: 8, RAM: 52, Price: 0.556,
+		PreemptiblePrice: 0.110},
+	{Size: "n1-highmem-16", CPU: 16, RAM: 104, Price: 1.112,
+		PreemptiblePrice: 0.220},
+	{Size: "n1-highmem-326", CPU: 32, RAM: 208, Price: 2.224,
+		PreemptiblePrice: 0.440},
+	{Size: "n1-highcpu-2", CPU: 2, RAM: 1.80, Price: 0.084,
+		PreemptiblePrice: 0.017},
+	{Size: "n1-highcpu-4", CPU: 4, RAM: 3.60, Price: 0.168,
+		PreemptiblePrice: 0.033},
+	{Size: "n1-highcpu-8", CPU: 8, RAM: 7.20, Price: 0.336,
+		PreemptiblePrice: 0.066},
+	{Size: "n1-highcpu-16", CPU: 16, RAM: 14.40, Price: 0.672,
+		PreemptiblePrice: 0.132},
+	{Size: "n1-highcpu-326", CPU: 32, RAM: 28.80, Price: 1.344,
+		PreemptiblePrice: 0.264},
 }
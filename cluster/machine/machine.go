@@ -15,6 +15,10 @@ type Description struct {
 	CPU    int
 	Disk   string
 	Region string
+
+	// The price of preemptible machines of this size, if it's fixed.  Otherwise,
+	// `Price` serves as an upper bound.
+	PreemptiblePrice float64
 }
 
 // Machine represents an instance of a machine booted by a Provider.
@@ -28,17 +32,30 @@ type Machine struct {
 	SSHKeys    []string
 	Provider   db.Provider
 	Region     string
+
+	Preemptible bool
+	SpotPrice   float64
+
+	// The certificate of the CA with which the machine's minion is provisioned at
+	// boot.
+	CACert string
 }
 
 // ChooseSize returns an acceptable machine size for the given provider that fits the
-// provided ram, cpu, and price constraints.
-func ChooseSize(provider db.Provider, ram, cpu stitch.Range, maxPrice float64) string {
+// provided ram, cpu, and price constraints.  Preemptible machines are priced as such.
+func ChooseSize(provider db.Provider, ram, cpu stitch.Range, maxPrice float64,
+	preemptible bool) string {
 	switch provider {
 	case db.Amazon:
-		return chooseBestSize(amazonDescriptions, ram, cpu, maxPrice)
+		return chooseBestSize(amazonDescriptions, ram, cpu, maxPrice, preemptible)
+	case db.Azure:
+		return chooseBestSize(azureDescriptions, ram, cpu, maxPrice, preemptible)
+	case db.DigitalOcean:
+		return chooseBestSize(digitalOceanDescriptions, ram, cpu, maxPrice,
+			preemptible)
 	case db.Google:
-		return chooseBestSize(googleDescriptions, ram, cpu, maxPrice)
-	case db.Vagrant:
+		return chooseBestSize(googleDescriptions, ram, cpu, maxPrice, preemptible)
+	case db.Local, db.Vagrant:
 		return vagrantSize(ram, cpu)
 	default:
 		panic(fmt.Sprintf("Unknown Cloud Provider: %s", provider))
@@ -60,17 +77,24 @@ func GroupByRegion(machines []Machine) map[string][]Machine {
 }
 
 func chooseBestSize(descriptions []Description, ram, cpu stitch.Range,
-	maxPrice float64) string {
-	var best Description
+	maxPrice float64, preemptible bool) string {
+	var best string
+	var bestPrice float64
 	for _, d := range descriptions {
+		price := d.Price
+		if preemptible && d.PreemptiblePrice != 0 {
+			price = d.PreemptiblePrice
+		}
+
 		if ram.Accepts(d.RAM) &&
 			cpu.Accepts(float64(d.CPU)) &&
-			(best.Size == "" || d.Price < best.Price) {
-			best = d
+			(best == "" || price < bestPrice) {
+			best = d.Size
+			bestPrice = price
 		}
 	}
-	if maxPrice == 0 || best.Price <= maxPrice {
-		return best.Size
+	if maxPrice == 0 || bestPrice <= maxPrice {
+		return best
 	}
 	return ""
 }
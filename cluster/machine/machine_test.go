@@ -9,7 +9,7 @@ import (
 func TestConstraints(t *testing.T) {
 	checkConstraint := func(descriptions []Description, ram stitch.Range,
 		cpu stitch.Range, maxPrice float64, exp string) {
-		resSize := chooseBestSize(descriptions, ram, cpu, maxPrice)
+		resSize := chooseBestSize(descriptions, ram, cpu, maxPrice, false)
 		if resSize != exp {
 			t.Errorf("bad size picked. Expected %s, got %s", exp, resSize)
 		}
@@ -60,4 +60,27 @@ func TestConstraints(t *testing.T) {
 		stitch.Range{}, 0, "size3")
 	checkConstraint(testDescriptions, stitch.Range{Min: 3},
 		stitch.Range{}, 0, "size4")
+
+	// Test preemptible prices are used for preemptible machines
+	testDescriptions = []Description{
+		{Size: "size5", Price: 2, RAM: 4, CPU: 4, PreemptiblePrice: 0.4},
+		{Size: "size6", Price: 1, RAM: 4, CPU: 4, PreemptiblePrice: 0.5},
+		{Size: "size7", Price: 0.3, RAM: 4, CPU: 4},
+	}
+	checkPreemptible := func(preemptible bool, maxPrice float64, exp string) {
+		resSize := chooseBestSize(testDescriptions, stitch.Range{},
+			stitch.Range{}, maxPrice, preemptible)
+		if resSize != exp {
+			t.Errorf("bad size picked. Expected %s, got %s", exp, resSize)
+		}
+	}
+	checkPreemptible(false, 0, "size7")
+	checkPreemptible(true, 0, "size7")
+
+	testDescriptions[2].Price = 3
+	checkPreemptible(false, 0, "size6")
+	checkPreemptible(false, 0.5, "")
+	checkPreemptible(true, 0, "size5")
+	checkPreemptible(true, 0.4, "size5")
+	checkPreemptible(true, 0.3, "")
 }
@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"time"
+
+	"github.com/NetSys/quilt/cluster/acl"
+	"github.com/NetSys/quilt/cluster/machine"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	providerLabels = []string{"provider", "region", "method"}
+
+	providerCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "quilt_provider_call_duration_seconds",
+		Help: "How long calls to the cloud provider APIs took.",
+	}, providerLabels)
+
+	providerCallErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "quilt_provider_call_errors_total",
+		Help: "The number of calls to the cloud provider APIs that failed.",
+	}, providerLabels)
+)
+
+func init() {
+	prometheus.MustRegister(providerCallDuration, providerCallErrors)
+}
+
+// instrumentedProvider records the latency and failures of each call to the
+// provider it wraps.
+type instrumentedProvider struct {
+	provider
+	inst instance
+}
+
+func (p instrumentedProvider) List() (machines []machine.Machine, err error) {
+	p.observe("List", func() error {
+		machines, err = p.provider.List()
+		return err
+	})
+	return machines, err
+}
+
+func (p instrumentedProvider) Boot(machines []machine.Machine) error {
+	return p.observe("Boot", func() error {
+		return p.provider.Boot(machines)
+	})
+}
+
+func (p instrumentedProvider) Stop(machines []machine.Machine) error {
+	return p.observe("Stop", func() error {
+		return p.provider.Stop(machines)
+	})
+}
+
+func (p instrumentedProvider) SetACLs(acls []acl.ACL) error {
+	return p.observe("SetACLs", func() error {
+		return p.provider.SetACLs(acls)
+	})
+}
+
+func (p instrumentedProvider) UpdateFloatingIPs(machines []machine.Machine) error {
+	return p.observe("UpdateFloatingIPs", func() error {
+		return p.provider.UpdateFloatingIPs(machines)
+	})
+}
+
+func (p instrumentedProvider) observe(method string, call func() error) error {
+	labels := prometheus.Labels{
+		"provider": string(p.inst.provider),
+		"region":   p.inst.region,
+		"method":   method,
+	}
+
+	start := time.Now()
+	err := call()
+	providerCallDuration.With(labels).Observe(time.Since(start).Seconds())
+	if err != nil {
+		providerCallErrors.With(labels).Inc()
+	}
+	return err
+}
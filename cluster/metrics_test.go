@@ -0,0 +1,65 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NetSys/quilt/cluster/machine"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingProvider struct {
+	*fakeProvider
+}
+
+func (p failingProvider) Stop(machines []machine.Machine) error {
+	return errors.New("stop failed")
+}
+
+func TestInstrumentedProvider(t *testing.T) {
+	inst := instance{FakeAmazon, "metrics-region"}
+	fake, _ := newFakeProvider(FakeAmazon, "ns", inst.region)
+	prvdr := instrumentedProvider{failingProvider{fake.(*fakeProvider)}, inst}
+
+	labels := func(method string) prometheus.Labels {
+		return prometheus.Labels{
+			"provider": string(FakeAmazon),
+			"region":   inst.region,
+			"method":   method,
+		}
+	}
+
+	// The collectors are global, so only the changes made by this test are checked.
+	calls := func(method string) uint64 {
+		var m dto.Metric
+		providerCallDuration.With(labels(method)).(prometheus.Histogram).Write(&m)
+		return m.GetHistogram().GetSampleCount()
+	}
+	failures := func(method string) float64 {
+		var m dto.Metric
+		providerCallErrors.With(labels(method)).Write(&m)
+		return m.GetCounter().GetValue()
+	}
+
+	methods := []string{"Boot", "List", "Stop"}
+	callsBefore := map[string]uint64{}
+	failuresBefore := map[string]float64{}
+	for _, method := range methods {
+		callsBefore[method] = calls(method)
+		failuresBefore[method] = failures(method)
+	}
+
+	assert.NoError(t, prvdr.Boot([]machine.Machine{{Size: "m4.large"}}))
+	machines, err := prvdr.List()
+	assert.NoError(t, err)
+	assert.Len(t, machines, 1)
+	assert.Error(t, prvdr.Stop(machines))
+
+	for _, method := range methods {
+		assert.Equal(t, uint64(1), calls(method)-callsBefore[method], method)
+	}
+	assert.Equal(t, 0.0, failures("Boot")-failuresBefore["Boot"])
+	assert.Equal(t, 1.0, failures("Stop")-failuresBefore["Stop"])
+}
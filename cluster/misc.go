@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/NetSys/quilt/cluster/amazon"
+	"github.com/NetSys/quilt/cluster/azure"
+	"github.com/NetSys/quilt/cluster/digitalocean"
 	"github.com/NetSys/quilt/cluster/google"
 	"github.com/NetSys/quilt/cluster/machine"
 	"github.com/NetSys/quilt/db"
@@ -20,9 +22,13 @@ func DefaultRegion(m db.Machine) db.Machine {
 	switch m.Provider {
 	case db.Amazon:
 		m.Region = amazon.DefaultRegion
+	case db.Azure:
+		m.Region = azure.DefaultRegion
+	case db.DigitalOcean:
+		m.Region = digitalocean.DefaultRegion
 	case db.Google:
 		m.Region = google.DefaultRegion
-	case db.Vagrant:
+	case db.Local, db.Vagrant:
 	default:
 		panic(fmt.Sprintf("Unknown Cloud Provider: %s", m.Provider))
 	}
@@ -30,6 +36,12 @@ func DefaultRegion(m db.Machine) db.Machine {
 	return m
 }
 
+// SupportsPreemptible returns whether the provider `p` can boot preemptible
+// machines.
+func SupportsPreemptible(p db.Provider) bool {
+	return p == db.Amazon || p == db.Google
+}
+
 // ChooseSize returns an acceptable machine size for the given provider that fits the
 // provided ram, cpu, and price constraints.
 var ChooseSize = machine.ChooseSize
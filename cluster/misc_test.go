@@ -30,6 +30,22 @@ func TestDefaultRegion(t *testing.T) {
 		t.Errorf("expected %s, found %s", exp, m.Region)
 	}
 
+	m.Region = ""
+	m.Provider = "Azure"
+	exp = "westus2"
+	m = DefaultRegion(m)
+	if m.Region != exp {
+		t.Errorf("expected %s, found %s", exp, m.Region)
+	}
+
+	m.Region = ""
+	m.Provider = "DigitalOcean"
+	exp = "sfo2"
+	m = DefaultRegion(m)
+	if m.Region != exp {
+		t.Errorf("expected %s, found %s", exp, m.Region)
+	}
+
 	m.Region = ""
 	m.Provider = "Vagrant"
 	exp = ""
@@ -57,7 +57,8 @@ func (clst Cluster) Boot(bootSet []machine.Machine) error {
 func bootMachine(m machine.Machine) error {
 	id := uuid.NewV4().String()
 
-	err := initMachine(cloudcfg.Ubuntu(m.SSHKeys, "xenial"), m.Size, id)
+	cfg := cloudcfg.Ubuntu(m.SSHKeys, "xenial", m.CACert)
+	err := initMachine(cfg, m.Size, id)
 	if err == nil {
 		err = up(id)
 	}
@@ -1,7 +1,6 @@
 package db
 
 import (
-	"errors"
 	"fmt"
 	"log"
 )
@@ -10,6 +9,8 @@ import (
 type ACL struct {
 	ID int
 
+	Namespace string // The namespace of the cluster the ACL applies to.
+
 	Admin            []string
 	ApplicationPorts []PortRange
 }
@@ -47,17 +48,20 @@ func (db Database) SelectFromACL(check func(ACL) bool) []ACL {
 	return result
 }
 
-// GetACL gets the ACL row from the database. There should only ever be a single
-// ACL row.
-func (db Database) GetACL() (ACL, error) {
-	aclRows := db.SelectFromACL(nil)
+// GetACL gets the ACL row of the given namespace from the database. There should
+// only ever be a single ACL row per namespace.
+func (db Database) GetACL(namespace string) (ACL, error) {
+	aclRows := db.SelectFromACL(func(acl ACL) bool {
+		return acl.Namespace == namespace
+	})
 	numACLs := len(aclRows)
 	if numACLs == 1 {
 		return aclRows[0], nil
 	} else if numACLs > 1 {
-		log.Panicf("Found %d ACL rows, there should be 1", numACLs)
+		log.Panicf("Found %d ACL rows in namespace %s, there should be 1",
+			numACLs, namespace)
 	}
-	return ACL{}, errors.New("no ACL rows found")
+	return ACL{}, fmt.Errorf("no ACL rows in namespace %s", namespace)
 }
 
 func (acl ACL) getID() int {
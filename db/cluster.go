@@ -1,11 +1,12 @@
 package db
 
 import (
-	"errors"
+	"fmt"
 	"log"
 )
 
-// A Cluster is a group of Machines which can operate containers.
+// A Cluster is a group of Machines which can operate containers.  Clusters are keyed
+// by their namespace, and a daemon may manage several of them at once.
 type Cluster struct {
 	ID int
 
@@ -35,41 +36,33 @@ func (db Database) SelectFromCluster(check func(Cluster) bool) []Cluster {
 // SelectFromCluster gets all clusters in the database that satisfy 'check'.
 func (conn Conn) SelectFromCluster(check func(Cluster) bool) []Cluster {
 	var clusters []Cluster
-	conn.Txn(ClusterTable).Run(func(view Database) error {
+	conn.ReadTxn(ClusterTable).Run(func(view Database) error {
 		clusters = view.SelectFromCluster(check)
 		return nil
 	})
 	return clusters
 }
 
-// GetCluster gets the cluster from the database. There should only ever be a single
-// cluster.
-func (db Database) GetCluster() (Cluster, error) {
-	clusters := db.SelectFromCluster(nil)
+// GetCluster gets the cluster with the given namespace from the database. There should
+// only ever be a single cluster per namespace.
+func (db Database) GetCluster(namespace string) (Cluster, error) {
+	clusters := db.SelectFromCluster(func(c Cluster) bool {
+		return c.Namespace == namespace
+	})
 	numClusters := len(clusters)
 	if numClusters == 1 {
 		return clusters[0], nil
 	} else if numClusters > 1 {
-		log.Panicf("Found %d clusters, there should be 1", numClusters)
-	}
-	return Cluster{}, errors.New("no clusters found")
-}
-
-// GetClusterNamespace returns the namespace of the single cluster object in the cluster
-// table.  Otherwise it returns an error.
-func (db Database) GetClusterNamespace() (string, error) {
-	clst, err := db.GetCluster()
-	if err != nil {
-		return "", err
+		log.Panicf("Found %d clusters in namespace %s, there should be 1",
+			numClusters, namespace)
 	}
-	return clst.Namespace, nil
+	return Cluster{}, fmt.Errorf("no cluster in namespace %s", namespace)
 }
 
-// GetClusterNamespace returns the namespace of the single cluster object in the cluster
-// table.  Otherwise it returns an error.
-func (conn Conn) GetClusterNamespace() (namespace string, err error) {
-	conn.Txn(ClusterTable).Run(func(db Database) error {
-		namespace, err = db.GetClusterNamespace()
+// GetCluster gets the cluster with the given namespace from the database.
+func (conn Conn) GetCluster(namespace string) (clst Cluster, err error) {
+	conn.ReadTxn(ClusterTable).Run(func(db Database) error {
+		clst, err = db.GetCluster(namespace)
 		return nil
 	})
 	return
@@ -43,7 +43,7 @@ func (c Connection) getID() int {
 // the 'check'.
 func (conn Conn) SelectFromConnection(check func(Connection) bool) []Connection {
 	var connections []Connection
-	conn.Txn(ConnectionTable).Run(func(view Database) error {
+	conn.ReadTxn(ConnectionTable).Run(func(view Database) error {
 		connections = view.SelectFromConnection(check)
 		return nil
 	})
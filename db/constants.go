@@ -61,12 +61,21 @@ const (
 
 	// Vagrant implements local virtual machines.
 	Vagrant = "Vagrant"
+
+	// Azure implements Microsoft Azure virtual machines.
+	Azure = "Azure"
+
+	// DigitalOcean implements DigitalOcean droplets.
+	DigitalOcean = "DigitalOcean"
+
+	// Local implements machines as Docker containers on the daemon's host.
+	Local = "Local"
 )
 
 // ParseProvider returns the Provider represented by 'name' or an error.
 func ParseProvider(name string) (Provider, error) {
 	switch name {
-	case "Amazon", "Google", "Vagrant":
+	case "Amazon", "Azure", "DigitalOcean", "Google", "Local", "Vagrant":
 		return Provider(name), nil
 	default:
 		return "", errors.New("unknown provider")
@@ -53,13 +53,71 @@ func (db Database) SelectFromContainer(check func(Container) bool) []Container {
 // SelectFromContainer gets all containers in the database that satisfy the 'check'.
 func (conn Conn) SelectFromContainer(check func(Container) bool) []Container {
 	var containers []Container
-	conn.Txn(ContainerTable).Run(func(view Database) error {
+	conn.ReadTxn(ContainerTable).Run(func(view Database) error {
 		containers = view.SelectFromContainer(check)
 		return nil
 	})
 	return containers
 }
 
+// SelectFromContainerByMinion gets all containers in the database scheduled on the
+// minion with IP 'minion', sorted by ID.  Unlike SelectFromContainer(), it uses an
+// index rather than scanning every row.
+func (db Database) SelectFromContainerByMinion(minion string) []Container {
+	return db.selectContainerIndex("Minion", minion)
+}
+
+// SelectFromContainerByStitchID gets all containers in the database with the given
+// 'stitchID'.
+func (db Database) SelectFromContainerByStitchID(stitchID string) []Container {
+	return db.selectContainerIndex("StitchID", stitchID)
+}
+
+// SelectFromContainerByIP gets all containers in the database with the given 'ip'.
+func (db Database) SelectFromContainerByIP(ip string) []Container {
+	return db.selectContainerIndex("IP", ip)
+}
+
+func (db Database) selectContainerIndex(name, value string) []Container {
+	var result []Container
+	for _, row := range db.accessTable(ContainerTable).lookup(name, value) {
+		result = append(result, row.(Container))
+	}
+	return result
+}
+
+// SelectFromContainerByMinion gets all containers in the database scheduled on the
+// minion with IP 'minion'.
+func (conn Conn) SelectFromContainerByMinion(minion string) []Container {
+	var containers []Container
+	conn.ReadTxn(ContainerTable).Run(func(view Database) error {
+		containers = view.SelectFromContainerByMinion(minion)
+		return nil
+	})
+	return containers
+}
+
+// SelectFromContainerByStitchID gets all containers in the database with the given
+// 'stitchID'.
+func (conn Conn) SelectFromContainerByStitchID(stitchID string) []Container {
+	var containers []Container
+	conn.ReadTxn(ContainerTable).Run(func(view Database) error {
+		containers = view.SelectFromContainerByStitchID(stitchID)
+		return nil
+	})
+	return containers
+}
+
+// SelectFromContainerByIP gets all containers in the database with the given 'ip'.
+func (conn Conn) SelectFromContainerByIP(ip string) []Container {
+	var containers []Container
+	conn.ReadTxn(ContainerTable).Run(func(view Database) error {
+		containers = view.SelectFromContainerByIP(ip)
+		return nil
+	})
+	return containers
+}
+
 func (c Container) getID() int {
 	return c.ID
 }
@@ -13,8 +13,11 @@ import (
 // engine populates the database with a preferred state of the world, while various
 // modules flesh out that policy with actual implementation details.
 type Database struct {
-	tables  map[TableType]*table
-	idAlloc *idCounter
+	tables   map[TableType]*table
+	idAlloc  *idCounter
+	journal  Journal
+	history  *history
+	readOnly bool
 }
 
 // A Trigger sends notifications when anything in their corresponding table changes.
@@ -43,25 +46,66 @@ type Transaction struct {
 type idCounter struct {
 	sync.Mutex
 	curID int
+
+	// The highest ID recorded by the database's journal, if it has one.
+	journaled int
 }
 
-// New creates a connection to a brand new database.
+// New creates a connection to a brand new database.  The database is held only in
+// memory, see NewWithJournal() for one that persists across restarts.
 func New() Conn {
-	db := Database{make(map[TableType]*table), &idCounter{}}
+	cn := newConn(nil)
+	cn.runLogger()
+	return cn
+}
+
+func newConn(journal Journal) Conn {
+	db := Database{make(map[TableType]*table), &idCounter{}, journal, &history{},
+		false}
 	for _, t := range AllTables {
-		db.tables[t] = newTable()
+		db.tables[t] = newTable(t)
 	}
+	return Conn{db: db}
+}
 
-	cn := Conn{db: db}
-	cn.runLogger()
-	return cn
+// Copy creates a connection to a new, in-memory, database holding the same rows as
+// this one.  Changes to either database aren't reflected in the other, so the copy
+// may be used to try out changes without affecting the rest of the system.
+func (cn Conn) Copy() Conn {
+	copied := newConn(nil)
+	cn.ReadTxn(AllTables...).Run(func(view Database) error {
+		for tt, table := range view.tables {
+			for _, r := range table.rows {
+				copied.db.tables[tt].set(r)
+			}
+		}
+
+		view.idAlloc.Lock()
+		copied.db.idAlloc.curID = view.idAlloc.curID
+		view.idAlloc.Unlock()
+		return nil
+	})
+	return copied
 }
 
 // Txn creates a new Transaction object connected to the same database, but with
 // restricted access to only the given tables.
 func (cn Conn) Txn(tables ...TableType) Transaction {
+	return cn.newTxn(false, tables)
+}
+
+// ReadTxn creates a new read-only Transaction restricted to the given tables.
+// Read-only transactions may run concurrently with each other, even if their tables
+// overlap, but never with a Transaction that writes to one of their tables.
+// Modifying the database in a read-only transaction panics.
+func (cn Conn) ReadTxn(tables ...TableType) Transaction {
+	return cn.newTxn(true, tables)
+}
+
+func (cn Conn) newTxn(readOnly bool, tables []TableType) Transaction {
 	// The Transaction has the same database data, just a subset of the tables.
-	db := Database{make(map[TableType]*table), cn.db.idAlloc}
+	db := Database{make(map[TableType]*table), cn.db.idAlloc, cn.db.journal,
+		cn.db.history, readOnly}
 	for _, t := range tables {
 		db.tables[t] = cn.db.accessTable(t)
 	}
@@ -78,6 +122,15 @@ func (tr Transaction) Run(do func(db Database) error) error {
 	defer tr.unlockTables()
 
 	err := do(tr.db)
+	if tr.db.readOnly {
+		return err
+	}
+
+	changes := tr.db.collectChanges()
+	tr.db.journalChanges(changes)
+	tr.db.history.record(changes)
+	tr.db.notifyWatches(changes)
+
 	var alertTables []*table
 	for _, table := range tr.db.tables {
 		if table.shouldAlert {
@@ -137,16 +190,14 @@ func (cn Conn) TriggerTick(seconds int, tt ...TableType) Trigger {
 
 // Lock all tables needed by the Transaction to perform a transact. Locking tables in
 // sorted order avoids deadlock between two transactionss requesting intersecting sets of
-// tables.
+// tables.  Read-only transactions share their locks with each other.
 func (tr Transaction) lockTables() {
-	tables := tableSlice{}
-	for tt := range tr.db.tables {
-		tables = append(tables, tt)
-	}
-	sort.Sort(tables)
-
-	for _, tt := range tables {
-		tr.db.tables[tt].Lock()
+	for _, tt := range tr.db.sortedTableTypes() {
+		if tr.db.readOnly {
+			tr.db.tables[tt].RLock()
+		} else {
+			tr.db.tables[tt].Lock()
+		}
 	}
 }
 
@@ -154,7 +205,11 @@ func (tr Transaction) lockTables() {
 // irrelevant.
 func (tr Transaction) unlockTables() {
 	for _, t := range tr.db.tables {
-		t.Unlock()
+		if tr.db.readOnly {
+			t.RUnlock()
+		} else {
+			t.Unlock()
+		}
 	}
 }
 
@@ -164,31 +219,71 @@ func (t Trigger) Stop() {
 }
 
 func (db Database) insert(r row) {
-	table := db.accessTable(getTableType(r))
+	table := db.writeTable(getTableType(r))
 	table.shouldAlert = true
-	table.rows[r.getID()] = r
+	table.recordChange(r.getID())
+	table.set(r)
 }
 
 // Commit updates the database with the data contained in row.
 func (db Database) Commit(r row) {
 	rid := r.getID()
-	table := db.accessTable(getTableType(r))
+	table := db.writeTable(getTableType(r))
 	old := table.rows[rid]
 
 	if reflect.TypeOf(old) != reflect.TypeOf(r) {
 		panic("Type Error")
 	}
 
-	if table.shouldAlert || !reflect.DeepEqual(r, old) {
-		table.rows[rid] = r
+	if !reflect.DeepEqual(r, old) {
+		table.recordChange(rid)
+		table.set(r)
 		table.shouldAlert = true
 	}
 }
 
+// A ConflictError is returned by CommitIfVersion when the row it was asked to
+// commit was modified, or removed, after the caller read it.
+type ConflictError struct {
+	Table    TableType
+	ID       int
+	Expected int // The version the caller read.
+	Actual   int // The version now stored, or zero if the row was removed.
+}
+
+func (err ConflictError) Error() string {
+	return fmt.Sprintf("%s-%d was modified concurrently: expected version %d, "+
+		"found %d", err.Table, err.ID, err.Expected, err.Actual)
+}
+
+// Version returns the version of the stored row with the same ID as 'r', or zero if
+// no such row exists.  A row's version increases every time it is modified.
+func (db Database) Version(r row) int {
+	return db.accessTable(getTableType(r)).versions[r.getID()]
+}
+
+// CommitIfVersion is like Commit, except that it only updates the database if the
+// stored row is still at 'version'.  This allows a row read in one transaction to be
+// safely written back in a later one, as the update fails with a ConflictError if
+// anything else modified the row in the meantime.  Rows that no longer exist always
+// conflict, whatever 'version' the caller read.
+func (db Database) CommitIfVersion(r row, version int) error {
+	tt := getTableType(r)
+	table := db.writeTable(tt)
+	_, ok := table.rows[r.getID()]
+	if actual := table.versions[r.getID()]; !ok || actual != version {
+		return ConflictError{tt, r.getID(), version, actual}
+	}
+
+	db.Commit(r)
+	return nil
+}
+
 // Remove deletes row from the database.
 func (db Database) Remove(r row) {
-	table := db.accessTable(getTableType(r))
-	delete(table.rows, r.getID())
+	table := db.writeTable(getTableType(r))
+	table.recordChange(r.getID())
+	table.delete(r.getID())
 	table.shouldAlert = true
 }
 
@@ -213,6 +308,24 @@ func (db Database) accessTable(tt TableType) *table {
 	return dbTable
 }
 
+func (db Database) sortedTableTypes() []TableType {
+	tables := tableSlice{}
+	for tt := range db.tables {
+		tables = append(tables, tt)
+	}
+	sort.Sort(tables)
+	return tables
+}
+
+// writeTable is like accessTable(), but additionally panics in read-only
+// transactions.
+func (db Database) writeTable(tt TableType) *table {
+	if db.readOnly {
+		panic("Write in read-only transaction: " + tt)
+	}
+	return db.accessTable(tt)
+}
+
 type tableSlice []TableType
 
 func (tables tableSlice) Len() int {
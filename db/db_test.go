@@ -98,6 +98,69 @@ func TestMachineSelect(t *testing.T) {
 	}
 }
 
+func TestMachineIndex(t *testing.T) {
+	conn := New()
+
+	var m Machine
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m = view.InsertMachine()
+		m.CloudID = "a"
+		view.Commit(m)
+		view.InsertMachine()
+		return nil
+	})
+	assert.Equal(t, []Machine{m}, conn.SelectFromMachineByCloudID("a"))
+	assert.Nil(t, conn.SelectFromMachineByCloudID("b"))
+
+	m.CloudID = "b"
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Commit(m)
+		return nil
+	})
+	assert.Empty(t, conn.SelectFromMachineByCloudID("a"))
+	assert.Equal(t, []Machine{m}, conn.SelectFromMachineByCloudID("b"))
+
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Remove(m)
+		return nil
+	})
+	assert.Empty(t, conn.SelectFromMachineByCloudID("b"))
+}
+
+func TestContainerIndexes(t *testing.T) {
+	conn := New()
+
+	var a, b Container
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		a = view.InsertContainer()
+		a.Minion = "1.1.1.1"
+		a.StitchID = "1"
+		a.IP = "10.0.0.1"
+		view.Commit(a)
+
+		b = view.InsertContainer()
+		b.Minion = "1.1.1.1"
+		b.StitchID = "2"
+		view.Commit(b)
+		return nil
+	})
+
+	assert.Equal(t, []Container{a, b}, conn.SelectFromContainerByMinion("1.1.1.1"))
+	assert.Equal(t, []Container{b}, conn.SelectFromContainerByStitchID("2"))
+	assert.Equal(t, []Container{a}, conn.SelectFromContainerByIP("10.0.0.1"))
+	assert.Equal(t, []Container{b}, conn.SelectFromContainerByIP(""))
+
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		b.Minion = "2.2.2.2"
+		view.Commit(b)
+		view.Remove(a)
+		return nil
+	})
+	assert.Nil(t, conn.SelectFromContainerByMinion("1.1.1.1"))
+	assert.Equal(t, []Container{b}, conn.SelectFromContainerByMinion("2.2.2.2"))
+	assert.Empty(t, conn.SelectFromContainerByIP("10.0.0.1"))
+}
+
 func TestMachineString(t *testing.T) {
 	m := Machine{}
 
@@ -373,6 +436,152 @@ func checkTxnSequential(t *testing.T) {
 	}
 }
 
+// Read-only transactions should run concurrently even if their tables overlap, but
+// must still exclude writers.
+func TestReadTxnConcurrent(t *testing.T) {
+	conn := New()
+
+	oneStarted := make(chan struct{})
+	twoDone := make(chan struct{})
+	oneDone := make(chan struct{})
+	go func() {
+		conn.ReadTxn(MachineTable).Run(func(view Database) error {
+			close(oneStarted)
+			<-twoDone
+			return nil
+		})
+		close(oneDone)
+	}()
+
+	<-oneStarted
+	go func() {
+		conn.ReadTxn(MachineTable, ClusterTable).Run(func(view Database) error {
+			return nil
+		})
+		close(twoDone)
+	}()
+
+	select {
+	case <-oneDone:
+	case <-time.After(time.Second):
+		t.Fatal("Read-only transactions deadlocked")
+	}
+
+	writeStarted := make(chan struct{})
+	readDone := make(chan struct{})
+	go func() {
+		conn.ReadTxn(MachineTable).Run(func(view Database) error {
+			close(writeStarted)
+			time.Sleep(100 * time.Millisecond)
+			close(readDone)
+			return nil
+		})
+	}()
+
+	<-writeStarted
+	conn.Txn(MachineTable).Run(func(view Database) error {
+		select {
+		case <-readDone:
+		default:
+			t.Error("Write ran concurrently with a read")
+		}
+		return nil
+	})
+}
+
+// Read-only transactions should panic when modifying the database.
+func TestReadTxnPanic(t *testing.T) {
+	conn := New()
+	conn.Txn(MachineTable).Run(func(view Database) error {
+		view.InsertMachine()
+		return nil
+	})
+
+	assert.Panics(t, func() {
+		conn.ReadTxn(MachineTable).Run(func(view Database) error {
+			view.InsertMachine()
+			return nil
+		})
+	})
+
+	assert.Panics(t, func() {
+		conn.ReadTxn(MachineTable).Run(func(view Database) error {
+			view.Remove(view.SelectFromMachine(nil)[0])
+			return nil
+		})
+	})
+}
+
+func TestCopy(t *testing.T) {
+	conn := New()
+
+	var m Machine
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m = view.InsertMachine()
+		m.Role = Master
+		view.Commit(m)
+		return nil
+	})
+
+	copied := conn.Copy()
+	assert.Equal(t, []Machine{m}, copied.SelectFromMachine(nil))
+
+	// Changes to the copy don't affect the original, and IDs aren't reused.
+	copied.Txn(AllTables...).Run(func(view Database) error {
+		view.Remove(m)
+		assert.Equal(t, m.ID+1, view.InsertMachine().ID)
+		return nil
+	})
+	assert.Equal(t, []Machine{m}, conn.SelectFromMachine(nil))
+}
+
+func TestCommitIfVersion(t *testing.T) {
+	conn := New()
+
+	var m Machine
+	var version int
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m = view.InsertMachine()
+		version = view.Version(m)
+		assert.Equal(t, 1, version)
+
+		// Committing an unmodified row doesn't change its version.
+		view.Commit(m)
+		assert.Equal(t, version, view.Version(m))
+		return nil
+	})
+
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m.Role = Master
+		assert.NoError(t, view.CommitIfVersion(m, version))
+		assert.Equal(t, version+1, view.Version(m))
+		return nil
+	})
+
+	// A stale version conflicts, and leaves the row untouched.
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		stale := m
+		stale.Role = Worker
+		assert.Equal(t, ConflictError{MachineTable, m.ID, version, version + 1},
+			view.CommitIfVersion(stale, version))
+		return nil
+	})
+	assert.Equal(t, []Machine{m}, conn.SelectFromMachine(nil))
+
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Remove(m)
+		assert.Zero(t, view.Version(m))
+		assert.Equal(t, ConflictError{MachineTable, m.ID, version + 1, 0},
+			view.CommitIfVersion(m, version+1))
+
+		// A missing row conflicts even with the zero version reported for it.
+		assert.Equal(t, ConflictError{MachineTable, m.ID, 0, 0},
+			view.CommitIfVersion(m, 0))
+		return nil
+	})
+	assert.Empty(t, conn.SelectFromMachine(nil))
+}
+
 func getRandomTransactions(conn Conn, tables ...TableType) (Transaction, Transaction) {
 	taken := map[TableType]struct{}{}
 	firstTables := pickTwoTables(taken)
@@ -481,6 +690,92 @@ func TestTriggerTickStop(t *testing.T) {
 	triggerNoRecv(t, mt)
 }
 
+func TestWatch(t *testing.T) {
+	conn := New()
+
+	var m Machine
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m = view.InsertMachine()
+		return nil
+	})
+
+	watch := conn.Watch(MachineTable, ClusterTable)
+	assert.Equal(t, []ChangeSet{{Table: MachineTable, Inserted: []interface{}{m}}},
+		watchRecv(t, watch))
+
+	var clst Cluster
+	var m2 Machine
+	old := m
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		clst = view.InsertCluster()
+
+		m.Role = Master
+		view.Commit(m)
+		m2 = view.InsertMachine()
+
+		// Changes to unwatched tables aren't delivered.
+		view.InsertEtcd()
+
+		// Rows inserted and removed in the same transaction never existed.
+		view.Remove(view.InsertMachine())
+		return nil
+	})
+
+	exp := []ChangeSet{
+		{Table: ClusterTable, Inserted: []interface{}{clst}},
+		{
+			Table:    MachineTable,
+			Inserted: []interface{}{m2},
+			Modified: []Modification{{old, m}},
+		},
+	}
+	assert.Equal(t, exp, watchRecv(t, watch))
+
+	// Committing an unchanged row isn't a modification.
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Commit(m)
+		return nil
+	})
+	watchNoRecv(t, watch)
+
+	// Consecutive transactions aren't coalesced.
+	for _, r := range []row{m, m2} {
+		conn.Txn(AllTables...).Run(func(view Database) error {
+			view.Remove(r)
+			return nil
+		})
+	}
+	assert.Equal(t, []ChangeSet{{Table: MachineTable, Removed: []interface{}{m}}},
+		watchRecv(t, watch))
+	assert.Equal(t, []ChangeSet{{Table: MachineTable, Removed: []interface{}{m2}}},
+		watchRecv(t, watch))
+
+	watch.Stop()
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.InsertMachine()
+		return nil
+	})
+	watchNoRecv(t, watch)
+}
+
+func watchRecv(t *testing.T, watch Watch) []ChangeSet {
+	select {
+	case changes := <-watch.C:
+		return changes
+	case <-time.Tick(5 * time.Second):
+		t.Error("Expected Receive")
+		return nil
+	}
+}
+
+func watchNoRecv(t *testing.T, watch Watch) {
+	select {
+	case <-watch.C:
+		t.Error("Unexpected Receive")
+	case <-time.Tick(25 * time.Millisecond):
+	}
+}
+
 func triggerRecv(t *testing.T, trig Trigger) {
 	select {
 	case <-trig.C:
@@ -592,23 +887,49 @@ func TestSliceHelpers(t *testing.T) {
 	assert.Equal(t, conns[0], ConnectionSlice(conns).Get(0))
 }
 
-func TestGetClusterNamespace(t *testing.T) {
+func TestGetCluster(t *testing.T) {
 	conn := New()
 
-	ns, err := conn.GetClusterNamespace()
-	assert.NotNil(t, err)
-	assert.Exactly(t, ns, "")
+	_, err := conn.GetCluster("test")
+	assert.EqualError(t, err, "no cluster in namespace test")
 
 	conn.Txn(AllTables...).Run(func(view Database) error {
-		clst := view.InsertCluster()
-		clst.Namespace = "test"
-		view.Commit(clst)
+		for _, namespace := range []string{"test", "other"} {
+			clst := view.InsertCluster()
+			clst.Namespace = namespace
+			clst.Spec = namespace + " spec"
+			view.Commit(clst)
+		}
 		return nil
 	})
 
-	ns, err = conn.GetClusterNamespace()
+	clst, err := conn.GetCluster("test")
 	assert.NoError(t, err)
-	assert.Exactly(t, ns, "test")
+	assert.Equal(t, "test", clst.Namespace)
+	assert.Equal(t, "test spec", clst.Spec)
+
+	_, err = conn.GetCluster("missing")
+	assert.Error(t, err)
+}
+
+func TestGetACL(t *testing.T) {
+	conn := New()
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		_, err := view.GetACL("test")
+		assert.EqualError(t, err, "no ACL rows in namespace test")
+
+		for _, namespace := range []string{"test", "other"} {
+			acl := view.InsertACL()
+			acl.Namespace = namespace
+			acl.Admin = []string{namespace}
+			view.Commit(acl)
+		}
+
+		acl, err := view.GetACL("other")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"other"}, acl.Admin)
+		return nil
+	})
 }
 
 type mSort []Machine
@@ -628,3 +949,69 @@ func (machines mSort) Swap(i, j int) {
 func (machines mSort) Less(i, j int) bool {
 	return machines[i].ID < machines[j].ID
 }
+
+func TestSelectFromDeployment(t *testing.T) {
+	conn := New()
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		for _, spec := range []string{"a", "b", "c"} {
+			d := view.InsertDeployment()
+			d.Spec = spec
+			d.Hash = HashSpec(spec)
+			view.Commit(d)
+		}
+		return nil
+	})
+
+	var specs []string
+	for _, d := range conn.SelectFromDeployment(nil) {
+		specs = append(specs, d.Spec)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, specs)
+
+	assert.Equal(t, "86f7e437faa5a7fce15d1ddcb9eaeaea377667b8", HashSpec("a"))
+}
+
+func TestRecordEvent(t *testing.T) {
+	conn := New()
+	at := time.Unix(0, 100)
+	conn.RecordEvent(Event{Time: at, Type: MachineBooted, Message: "first"})
+
+	events := conn.SelectFromEvent(nil)
+	assert.Len(t, events, 1)
+	assert.Equal(t, at, events[0].Time)
+	assert.Equal(t, "first", events[0].Message)
+
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		for i := 0; i < MaxEvents; i++ {
+			view.RecordEvent(Event{Type: ProviderError,
+				Message: fmt.Sprint(i)})
+		}
+		return nil
+	})
+
+	events = conn.SelectFromEvent(nil)
+	assert.Len(t, events, MaxEvents)
+	assert.Equal(t, "0", events[0].Message)
+	assert.False(t, events[0].Time.IsZero())
+	assert.Equal(t, fmt.Sprint(MaxEvents-1), events[MaxEvents-1].Message)
+}
+
+func TestTokenRoles(t *testing.T) {
+	for _, role := range []string{"read-only", "deployer", "admin"} {
+		parsed, err := ParseTokenRole(role)
+		assert.NoError(t, err)
+		assert.Equal(t, TokenRole(role), parsed)
+	}
+	_, err := ParseTokenRole("root")
+	assert.Error(t, err)
+
+	assert.True(t, Admin.Allows(Deployer))
+	assert.True(t, Deployer.Allows(Deployer))
+	assert.True(t, Deployer.Allows(ReadOnly))
+	assert.False(t, ReadOnly.Allows(Deployer))
+	assert.False(t, Deployer.Allows(Admin))
+	assert.False(t, TokenRole("root").Allows(ReadOnly))
+
+	assert.Equal(t, "ca978112ca1bbdcafac231b39a23dc4da786eff8"+
+		"147c4e72b9807785afee48bb", HashToken("a"))
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// A Deployment records a stitch that was deployed to a namespace, so that it may be
+// rolled back to later.
+type Deployment struct {
+	ID int
+
+	Namespace string
+	Spec      string    `rowStringer:"omit"`
+	Hash      string    // The SHA-1 hash of Spec.
+	Time      time.Time // When the stitch was deployed.
+}
+
+// InsertDeployment creates a new deployment row and inserts it into the database.
+func (db Database) InsertDeployment() Deployment {
+	result := Deployment{ID: db.nextID()}
+	db.insert(result)
+	return result
+}
+
+// SelectFromDeployment gets all deployments in the database that satisfy 'check',
+// from oldest to newest.
+func (db Database) SelectFromDeployment(check func(Deployment) bool) []Deployment {
+	deploymentTable := db.accessTable(DeploymentTable)
+	var rows []row
+	for _, r := range deploymentTable.rows {
+		if check == nil || check(r.(Deployment)) {
+			rows = append(rows, r)
+		}
+	}
+
+	sort.Sort(rowSlice(rows))
+
+	var result []Deployment
+	for _, r := range rows {
+		result = append(result, r.(Deployment))
+	}
+	return result
+}
+
+// SelectFromDeployment gets all deployments in the database that satisfy 'check',
+// from oldest to newest.
+func (conn Conn) SelectFromDeployment(check func(Deployment) bool) []Deployment {
+	var deployments []Deployment
+	conn.ReadTxn(DeploymentTable).Run(func(view Database) error {
+		deployments = view.SelectFromDeployment(check)
+		return nil
+	})
+	return deployments
+}
+
+// HashSpec returns the hash that identifies the contents of a deployed stitch.
+func HashSpec(spec string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(spec)))
+}
+
+func (d Deployment) getID() int {
+	return d.ID
+}
+
+func (d Deployment) tt() TableType {
+	return DeploymentTable
+}
+
+func (d Deployment) String() string {
+	return defaultString(d)
+}
+
+func (d Deployment) less(r row) bool {
+	return d.ID < r.(Deployment).ID
+}
@@ -51,7 +51,7 @@ func (db Database) SelectFromEtcd(check func(Etcd) bool) []Etcd {
 // EtcdLeader returns true if the minion is the lead master for the cluster.
 func (conn Conn) EtcdLeader() bool {
 	var leader bool
-	conn.Txn(EtcdTable).Run(func(view Database) error {
+	conn.ReadTxn(EtcdTable).Run(func(view Database) error {
 		leader = view.EtcdLeader()
 		return nil
 	})
@@ -62,7 +62,7 @@ func (conn Conn) EtcdLeader() bool {
 // 'check'.
 func (conn Conn) SelectFromEtcd(check func(Etcd) bool) []Etcd {
 	var etcdRows []Etcd
-	conn.Txn(EtcdTable).Run(func(view Database) error {
+	conn.ReadTxn(EtcdTable).Run(func(view Database) error {
 		etcdRows = view.SelectFromEtcd(check)
 		return nil
 	})
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"sort"
+	"time"
+)
+
+// An Event records something notable that happened to the cluster, such as a machine
+// booting or a container failing to start.
+type Event struct {
+	ID int
+
+	Time      time.Time // When the event happened.
+	Type      EventType
+	Namespace string // The namespace of the cluster the event happened in, if any.
+	Machine   string // The StitchID of the machine the event concerns, if any.
+	Message   string
+}
+
+// EventType describes what happened in an Event.
+type EventType string
+
+const (
+	// MachineBooted events are recorded when the cloud providers boot machines.
+	MachineBooted EventType = "MachineBooted"
+
+	// MachineTerminated events are recorded when the cloud providers stop
+	// machines.
+	MachineTerminated EventType = "MachineTerminated"
+
+	// ProviderError events are recorded when a cloud provider fails to carry out
+	// a request.
+	ProviderError EventType = "ProviderError"
+
+	// MinionConnected events are recorded when the foreman connects to a minion.
+	MinionConnected EventType = "MinionConnected"
+
+	// MinionDisconnected events are recorded when the foreman loses its
+	// connection to a minion.
+	MinionDisconnected EventType = "MinionDisconnected"
+
+	// PlacementFailed events are recorded when the scheduler can't find a minion
+	// to run a container on.
+	PlacementFailed EventType = "PlacementFailed"
+
+	// ImagePullFailed events are recorded when a minion fails to pull the image
+	// of a container it's meant to run.
+	ImagePullFailed EventType = "ImagePullFailed"
+
+	// MachineTerminating events are recorded when a minion learns that the cloud
+	// provider is about to reclaim its machine.
+	MachineTerminating EventType = "MachineTerminating"
+)
+
+// MaxEvents is the number of events kept in the database.  Once there are more, the
+// oldest are removed.
+const MaxEvents = 1000
+
+// InsertEvent creates a new event row and inserts it into the database.
+func (db Database) InsertEvent() Event {
+	result := Event{ID: db.nextID()}
+	db.insert(result)
+	return result
+}
+
+// RecordEvent inserts `event` into the database, and removes the oldest events if
+// there are more than MaxEvents.  Its ID is assigned by the database, and its Time
+// defaults to now.
+func (db Database) RecordEvent(event Event) {
+	event.ID = db.InsertEvent().ID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	db.Commit(event)
+
+	events := db.SelectFromEvent(nil)
+	for len(events) > MaxEvents {
+		db.Remove(events[0])
+		events = events[1:]
+	}
+}
+
+// SelectFromEvent gets all events in the database that satisfy 'check', from oldest
+// to newest.
+func (db Database) SelectFromEvent(check func(Event) bool) []Event {
+	eventTable := db.accessTable(EventTable)
+	var rows []row
+	for _, r := range eventTable.rows {
+		if check == nil || check(r.(Event)) {
+			rows = append(rows, r)
+		}
+	}
+
+	sort.Sort(rowSlice(rows))
+
+	var result []Event
+	for _, r := range rows {
+		result = append(result, r.(Event))
+	}
+	return result
+}
+
+// RecordEvent records `event` in its own transaction.  See Database.RecordEvent.
+func (conn Conn) RecordEvent(event Event) {
+	conn.Txn(EventTable).Run(func(view Database) error {
+		view.RecordEvent(event)
+		return nil
+	})
+}
+
+// SelectFromEvent gets all events in the database that satisfy 'check', from oldest
+// to newest.
+func (conn Conn) SelectFromEvent(check func(Event) bool) []Event {
+	var events []Event
+	conn.ReadTxn(EventTable).Run(func(view Database) error {
+		events = view.SelectFromEvent(check)
+		return nil
+	})
+	return events
+}
+
+func (e Event) getID() int {
+	return e.ID
+}
+
+func (e Event) tt() TableType {
+	return EventTable
+}
+
+func (e Event) String() string {
+	return defaultString(e)
+}
+
+func (e Event) less(r row) bool {
+	return e.ID < r.(Event).ID
+}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is the number of row versions recorded by a database's history
+// unless configured otherwise.
+const DefaultHistorySize = 10000
+
+// A history is a bounded ring of the row versions committed to a database.  Each
+// version holds the row's value both before and after its transaction, so that
+// past states may be reconstructed by undoing versions, newest first, starting from
+// the current contents of the database.
+type history struct {
+	sync.Mutex
+
+	versions []version
+	next     int  // The index at which the next version will be written.
+	full     bool // Whether the ring has wrapped.
+
+	// The commit time of the newest version evicted from the ring.  States prior
+	// to it can't be reconstructed.
+	evicted time.Time
+}
+
+type version struct {
+	time   time.Time
+	table  TableType
+	id     int
+	before row // nil if the row was inserted.
+	after  row // nil if the row was removed.
+}
+
+// EnableHistory configures the database to remember the last 'size' versions of its
+// rows, so that they may later be queried with AsOf().  A 'size' of zero disables
+// the history.  Any previously recorded history is discarded.
+func (cn Conn) EnableHistory(size int) {
+	cn.db.history.Lock()
+	defer cn.db.history.Unlock()
+
+	cn.db.history.versions = make([]version, size)
+	cn.db.history.next = 0
+	cn.db.history.full = false
+	cn.db.history.evicted = time.Now()
+}
+
+// AsOf returns a read-only view of the database as it was at time 't'.  It returns
+// an error if the history is disabled, or if it doesn't reach back to 't'.
+func (cn Conn) AsOf(t time.Time) (Database, error) {
+	result := newConn(nil).db
+	result.readOnly = true
+
+	err := cn.ReadTxn(AllTables...).Run(func(view Database) error {
+		h := view.history
+		h.Lock()
+		defer h.Unlock()
+
+		if len(h.versions) == 0 {
+			return errors.New("database history is disabled")
+		}
+
+		if t.Before(h.evicted) {
+			return fmt.Errorf("database history only reaches back to %s",
+				h.evicted.Format(time.RFC3339))
+		}
+
+		rows := map[TableType]map[int]row{}
+		for tt, table := range view.tables {
+			rows[tt] = map[int]row{}
+			for id, r := range table.rows {
+				rows[tt][id] = r
+			}
+		}
+
+		// Walk backwards through the ring undoing versions committed after 't'.
+		for i := 0; i < h.len(); i++ {
+			v := h.versions[(h.next-1-i+len(h.versions))%len(h.versions)]
+			if !v.time.After(t) {
+				break
+			}
+
+			if v.before == nil {
+				delete(rows[v.table], v.id)
+			} else {
+				rows[v.table][v.id] = v.before
+			}
+		}
+
+		for tt, tableRows := range rows {
+			for _, r := range tableRows {
+				result.tables[tt].set(r)
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// record adds the versions committed by a single transaction to the history.  It
+// must be called while the transaction still holds its table locks.
+func (h *history) record(changes []ChangeSet) {
+	h.Lock()
+	defer h.Unlock()
+
+	if len(h.versions) == 0 || len(changes) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, cs := range changes {
+		for _, r := range cs.Inserted {
+			h.add(version{now, cs.Table, r.(row).getID(), nil, r.(row)})
+		}
+		for _, mod := range cs.Modified {
+			before, after := mod.Before.(row), mod.After.(row)
+			h.add(version{now, cs.Table, after.getID(), before, after})
+		}
+		for _, r := range cs.Removed {
+			h.add(version{now, cs.Table, r.(row).getID(), r.(row), nil})
+		}
+	}
+}
+
+func (h *history) add(v version) {
+	if h.full {
+		h.evicted = h.versions[h.next].time
+	}
+
+	h.versions[h.next] = v
+	h.next = (h.next + 1) % len(h.versions)
+	h.full = h.full || h.next == 0
+}
+
+func (h *history) len() int {
+	if h.full {
+		return len(h.versions)
+	}
+	return h.next
+}
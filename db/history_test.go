@@ -0,0 +1,105 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsOf(t *testing.T) {
+	conn := New()
+
+	_, err := conn.AsOf(time.Now())
+	assert.EqualError(t, err, "database history is disabled")
+
+	conn.EnableHistory(DefaultHistorySize)
+	start := time.Now()
+
+	var m Machine
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m = view.InsertMachine()
+		m.Role = Master
+		view.Commit(m)
+		return nil
+	})
+	inserted := tick()
+
+	old := m
+	m.PublicIP = "1.2.3.4"
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Commit(m)
+		view.InsertContainer()
+		return nil
+	})
+	modified := tick()
+
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Remove(m)
+		return nil
+	})
+
+	view, err := conn.AsOf(start)
+	assert.NoError(t, err)
+	assert.Empty(t, view.SelectFromMachine(nil))
+
+	view, err = conn.AsOf(inserted)
+	assert.NoError(t, err)
+	assert.Equal(t, []Machine{old}, view.SelectFromMachine(nil))
+	assert.Empty(t, view.SelectFromContainer(nil))
+
+	view, err = conn.AsOf(modified)
+	assert.NoError(t, err)
+	assert.Equal(t, []Machine{m}, view.SelectFromMachine(nil))
+	assert.Equal(t, []Machine{m}, view.SelectFromMachineByCloudID(""))
+	assert.Len(t, view.SelectFromContainer(nil), 1)
+
+	view, err = conn.AsOf(time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, view.SelectFromMachine(nil))
+
+	assert.Panics(t, func() { view.InsertMachine() })
+}
+
+func TestAsOfEvicted(t *testing.T) {
+	conn := New()
+	conn.EnableHistory(2)
+
+	start := tick()
+
+	var m Machine
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m = view.InsertMachine()
+		return nil
+	})
+	inserted := tick()
+
+	_, err := conn.AsOf(start)
+	assert.NoError(t, err)
+
+	for _, size := range []string{"a", "b"} {
+		m.Size = size
+		conn.Txn(AllTables...).Run(func(view Database) error {
+			view.Commit(m)
+			return nil
+		})
+	}
+
+	// The insertion's version was evicted, and thus the initial state is lost.
+	_, err = conn.AsOf(start)
+	assert.Error(t, err)
+
+	// But the state after the insertion can still be reconstructed.
+	view, err := conn.AsOf(inserted)
+	assert.NoError(t, err)
+	assert.Equal(t, "", view.SelectFromMachine(nil)[0].Size)
+}
+
+// tick returns the current time, making sure it is distinct from that of any
+// transaction committed before or after it.
+func tick() time.Time {
+	time.Sleep(time.Millisecond)
+	now := time.Now()
+	time.Sleep(time.Millisecond)
+	return now
+}
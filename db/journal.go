@@ -0,0 +1,350 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// A Change records the state of a single row at the end of a transaction.
+type Change struct {
+	Table TableType
+	ID    int
+	Row   interface{} // The new value of the row, or nil if it was removed.
+}
+
+// Changes to `idTable` don't hold a row, but instead record in their ID the highest ID
+// allocated so far.  This way the IDs of rows that were removed aren't handed out
+// again after a restart.
+const idTable TableType = ""
+
+// A Journal durably records the changes committed to a database so that they may be
+// replayed when the process restarts.
+type Journal interface {
+	// Load returns every change recorded by the journal, in the order it was
+	// recorded.
+	Load() ([]Change, error)
+
+	// Append records the changes committed by a single transaction.
+	Append([]Change) error
+
+	// Snapshot replaces the contents of the journal with 'rows', which
+	// represent every row in the database along with its ID allocator.
+	Snapshot(rows []Change) error
+}
+
+// How often the database is snapshotted to its journal.  Stored in a variable so it
+// may be changed by the unit tests.
+var snapshotInterval = 5 * time.Minute
+
+func init() {
+	for _, r := range []row{Cluster{}, Machine{}, Container{}, Minion{},
+		Connection{}, Label{}, Etcd{}, Placement{}, ACL{}, Deployment{},
+		Event{}, Status{}, Token{}} {
+		gob.Register(r)
+	}
+}
+
+// NewWithJournal creates a connection to a database that is restored from the
+// changes in 'journal', and which records all future changes to it.
+func NewWithJournal(journal Journal) (Conn, error) {
+	changes, err := journal.Load()
+	if err != nil {
+		return Conn{}, err
+	}
+
+	cn := newConn(journal)
+	for _, change := range changes {
+		if change.Table == idTable {
+			if change.ID > cn.db.idAlloc.curID {
+				cn.db.idAlloc.curID = change.ID
+			}
+			continue
+		}
+
+		table, ok := cn.db.tables[change.Table]
+		if !ok {
+			return Conn{}, fmt.Errorf("unknown table: %s", change.Table)
+		}
+
+		if change.Row == nil {
+			table.delete(change.ID)
+			continue
+		}
+
+		r, ok := change.Row.(row)
+		if !ok || r.getID() != change.ID {
+			return Conn{}, fmt.Errorf("malformed journal row: %v", change.Row)
+		}
+		table.set(r)
+	}
+
+	// Compact whatever was replayed before accepting new transactions.
+	if err := cn.snapshot(); err != nil {
+		return Conn{}, err
+	}
+
+	cn.runLogger()
+	go cn.runSnapshotter()
+	return cn, nil
+}
+
+func (cn Conn) runSnapshotter() {
+	for range time.Tick(snapshotInterval) {
+		if err := cn.snapshot(); err != nil {
+			log.WithError(err).Error("Failed to snapshot the database.")
+		}
+	}
+}
+
+// snapshot writes the entire database to the journal.  It holds a read lock on every
+// table while doing so, thus no transaction may be journaled concurrently.
+func (cn Conn) snapshot() error {
+	return cn.ReadTxn(AllTables...).Run(func(view Database) error {
+		var rows []Change
+		for tt, table := range view.tables {
+			for id, r := range table.rows {
+				rows = append(rows, Change{Table: tt, ID: id, Row: r})
+			}
+		}
+
+		view.idAlloc.Lock()
+		rows = append(rows, Change{Table: idTable, ID: view.idAlloc.curID})
+		view.idAlloc.journaled = view.idAlloc.curID
+		view.idAlloc.Unlock()
+		sortChanges(rows)
+		return cn.db.journal.Snapshot(rows)
+	})
+}
+
+// journalChanges hands the rows modified by a transaction to the journal, along with
+// the ID allocator if it advanced.  The allocator is journaled even if the change sets
+// are empty, as the IDs of rows inserted and removed within a single transaction must
+// not be reused either.  It must be called while the transaction still holds its
+// table locks.
+func (db Database) journalChanges(changeSets []ChangeSet) {
+	if db.journal == nil {
+		return
+	}
+
+	var changes []Change
+	db.idAlloc.Lock()
+	if db.idAlloc.curID > db.idAlloc.journaled {
+		changes = append(changes, Change{Table: idTable, ID: db.idAlloc.curID})
+		db.idAlloc.journaled = db.idAlloc.curID
+	}
+	db.idAlloc.Unlock()
+
+	for _, cs := range changeSets {
+		for _, r := range cs.Inserted {
+			changes = append(changes, Change{cs.Table, r.(row).getID(), r})
+		}
+		for _, mod := range cs.Modified {
+			after := mod.After.(row)
+			changes = append(changes, Change{cs.Table, after.getID(), after})
+		}
+		for _, r := range cs.Removed {
+			changes = append(changes, Change{cs.Table, r.(row).getID(), nil})
+		}
+	}
+
+	if len(changes) == 0 {
+		return
+	}
+
+	sortChanges(changes)
+	if err := db.journal.Append(changes); err != nil {
+		log.WithError(err).Error("Failed to journal transaction.")
+	}
+}
+
+func sortChanges(changes []Change) {
+	sort.Sort(changeSlice(changes))
+}
+
+type changeSlice []Change
+
+func (cs changeSlice) Len() int {
+	return len(cs)
+}
+
+func (cs changeSlice) Swap(i, j int) {
+	cs[i], cs[j] = cs[j], cs[i]
+}
+
+func (cs changeSlice) Less(i, j int) bool {
+	if cs[i].Table != cs[j].Table {
+		return cs[i].Table < cs[j].Table
+	}
+	return cs[i].ID < cs[j].ID
+}
+
+// A FileJournal is a Journal stored in a directory on the local filesystem.  It
+// consists of a snapshot of the database, and a write-ahead log of every transaction
+// committed since that snapshot was taken.
+type FileJournal struct {
+	dir string
+
+	sync.Mutex
+	wal *os.File
+}
+
+const (
+	snapshotFile = "snapshot"
+	walFile      = "wal"
+)
+
+// OpenFileJournal opens the journal stored in 'dir', creating it if it doesn't
+// exist.
+func OpenFileJournal(dir string) (*FileJournal, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFile),
+		os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileJournal{dir: dir, wal: wal}, nil
+}
+
+// Load returns the contents of the snapshot followed by the contents of the
+// write-ahead log.  A partially written record at the end of the log, as is left by
+// a crash, is discarded.
+func (j *FileJournal) Load() ([]Change, error) {
+	j.Lock()
+	defer j.Unlock()
+
+	var changes []Change
+	snap, err := os.Open(filepath.Join(j.dir, snapshotFile))
+	if err == nil {
+		changes, err = readRecords(snap)
+		snap.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if _, err := j.wal.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	walChanges, err := readRecords(j.wal)
+	if err == io.ErrUnexpectedEOF {
+		log.Warn("Discarding truncated database journal record.")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return append(changes, walChanges...), nil
+}
+
+// Append writes 'changes' to the write-ahead log and syncs it to disk.
+func (j *FileJournal) Append(changes []Change) error {
+	j.Lock()
+	defer j.Unlock()
+
+	if err := writeRecord(j.wal, changes); err != nil {
+		return err
+	}
+	return j.wal.Sync()
+}
+
+// Snapshot atomically replaces the snapshot with 'rows' and then truncates the
+// write-ahead log.  If a crash occurs between the two steps, replaying the old log
+// on top of the new snapshot still converges on the same state, as the log holds
+// every change made after the previous snapshot.
+func (j *FileJournal) Snapshot(rows []Change) error {
+	j.Lock()
+	defer j.Unlock()
+
+	tmpPath := filepath.Join(j.dir, snapshotFile+".tmp")
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	err = writeRecord(w, rows)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(j.dir, snapshotFile)); err != nil {
+		return err
+	}
+
+	return j.wal.Truncate(0)
+}
+
+// Close releases the file handles held by the journal.
+func (j *FileJournal) Close() error {
+	j.Lock()
+	defer j.Unlock()
+	return j.wal.Close()
+}
+
+// Records are stored as a big endian length, followed by that many bytes of gob.
+// Gob, unlike JSON, encodes fields such as Container.ID that the rows hide from JSON.
+func writeRecord(w io.Writer, changes []Change) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(changes); err != nil {
+		return err
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(buf.Len()))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func readRecords(r io.Reader) ([]Change, error) {
+	br := bufio.NewReader(r)
+
+	var result []Change
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(br, header[:]); err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return result, err
+		}
+
+		data := make([]byte, binary.BigEndian.Uint32(header[:]))
+		if _, err := io.ReadFull(br, data); err != nil {
+			return result, io.ErrUnexpectedEOF
+		}
+
+		var changes []Change
+		err := gob.NewDecoder(bytes.NewReader(data)).Decode(&changes)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, changes...)
+	}
+}
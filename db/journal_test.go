@@ -0,0 +1,176 @@
+package db
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memJournal struct {
+	changes [][]Change
+}
+
+func (j *memJournal) Load() ([]Change, error) {
+	var result []Change
+	for _, c := range j.changes {
+		result = append(result, c...)
+	}
+	return result, nil
+}
+
+func (j *memJournal) Append(changes []Change) error {
+	j.changes = append(j.changes, changes)
+	return nil
+}
+
+func (j *memJournal) Snapshot(rows []Change) error {
+	j.changes = [][]Change{rows}
+	return nil
+}
+
+func TestJournalAppend(t *testing.T) {
+	journal := &memJournal{}
+	conn, err := NewWithJournal(journal)
+	assert.NoError(t, err)
+
+	// The initial snapshot of an empty database.
+	assert.Equal(t, [][]Change{{{idTable, 0, nil}}}, journal.changes)
+
+	var m Machine
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m = view.InsertMachine()
+		m.Role = Master
+		view.Commit(m)
+		return nil
+	})
+	assert.Equal(t, []Change{{idTable, m.ID, nil}, {MachineTable, m.ID, m}},
+		journal.changes[1])
+
+	// Transactions that don't modify anything aren't journaled.
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Commit(m)
+		return nil
+	})
+	assert.Len(t, journal.changes, 2)
+
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Remove(m)
+		return nil
+	})
+	assert.Equal(t, []Change{{MachineTable, m.ID, nil}}, journal.changes[2])
+}
+
+func TestJournalRestore(t *testing.T) {
+	journal := &memJournal{}
+	conn, err := NewWithJournal(journal)
+	assert.NoError(t, err)
+
+	var clst Cluster
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		clst = view.InsertCluster()
+		clst.Namespace = "ns"
+		view.Commit(clst)
+		return nil
+	})
+
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		removed := view.InsertMachine()
+		view.Remove(removed)
+		return nil
+	})
+
+	conn, err = NewWithJournal(journal)
+	assert.NoError(t, err)
+	assert.Equal(t, []Cluster{clst}, conn.SelectFromCluster(nil))
+	assert.Empty(t, conn.SelectFromMachine(nil))
+
+	// IDs must not be reused after a restore.
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		assert.Equal(t, 3, view.InsertMachine().ID)
+		return nil
+	})
+}
+
+func TestJournalSnapshotRestore(t *testing.T) {
+	journal := &memJournal{}
+	conn, err := NewWithJournal(journal)
+	assert.NoError(t, err)
+
+	var removed Machine
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		removed = view.InsertMachine()
+		return nil
+	})
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		view.Remove(removed)
+		return nil
+	})
+
+	// Compact the journal, so that the removed row is forgotten.
+	assert.NoError(t, conn.snapshot())
+	assert.Equal(t, [][]Change{{{idTable, removed.ID, nil}}}, journal.changes)
+
+	conn, err = NewWithJournal(journal)
+	assert.NoError(t, err)
+	assert.Empty(t, conn.SelectFromMachine(nil))
+
+	// The ID of the removed row must not be reused.
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		assert.Equal(t, removed.ID+1, view.InsertMachine().ID)
+		return nil
+	})
+}
+
+func TestFileJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quilt-journal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	journal, err := OpenFileJournal(dir)
+	assert.NoError(t, err)
+
+	conn, err := NewWithJournal(journal)
+	assert.NoError(t, err)
+
+	var m Machine
+	var dbc Container
+	conn.Txn(AllTables...).Run(func(view Database) error {
+		m = view.InsertMachine()
+		m.Role = Worker
+		m.SSHKeys = []string{"key"}
+		view.Commit(m)
+
+		dbc = view.InsertContainer()
+		dbc.Image = "alpine"
+		dbc.Env = map[string]string{"a": "b"}
+		dbc.Created = time.Unix(100, 0).UTC()
+		view.Commit(dbc)
+		return nil
+	})
+	journal.Close()
+
+	// Simulate a crash in the middle of writing a record.
+	wal, err := os.OpenFile(filepath.Join(dir, walFile), os.O_WRONLY|os.O_APPEND,
+		0600)
+	assert.NoError(t, err)
+	wal.Write([]byte{0, 0, 0, 100, 1, 2})
+	wal.Close()
+
+	journal, err = OpenFileJournal(dir)
+	assert.NoError(t, err)
+	defer journal.Close()
+
+	conn, err = NewWithJournal(journal)
+	assert.NoError(t, err)
+	assert.Equal(t, []Machine{m}, conn.SelectFromMachine(nil))
+	assert.Equal(t, []Container{dbc}, conn.SelectFromContainer(nil))
+
+	// The restore compacted everything into the snapshot.
+	info, err := os.Stat(filepath.Join(dir, walFile))
+	assert.NoError(t, err)
+	assert.Zero(t, info.Size())
+}
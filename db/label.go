@@ -35,7 +35,7 @@ func (db Database) SelectFromLabel(check func(Label) bool) []Label {
 // SelectFromLabel gets all labels in the database connection that satisfy 'check'.
 func (conn Conn) SelectFromLabel(check func(Label) bool) []Label {
 	var result []Label
-	conn.Txn(LabelTable).Run(func(view Database) error {
+	conn.ReadTxn(LabelTable).Run(func(view Database) error {
 		result = view.SelectFromLabel(check)
 		return nil
 	})
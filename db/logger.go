@@ -10,8 +10,9 @@ import (
 func (conn Conn) runLogger() {
 	for _, t := range AllTables {
 		t := t
+		trigger := conn.Trigger(t)
 		go func() {
-			for range conn.Trigger(t).C {
+			for range trigger.C {
 				conn.logTable(t)
 			}
 		}()
@@ -21,7 +22,7 @@ func (conn Conn) runLogger() {
 func (conn Conn) logTable(t TableType) {
 	var truncated bool
 	var strs []string
-	conn.Txn(AllTables...).Run(func(view Database) error {
+	conn.ReadTxn(AllTables...).Run(func(view Database) error {
 		var rows []row
 		for _, v := range view.tables[t].rows {
 			if len(rows) > 50 {
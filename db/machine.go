@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 // Machine represents a physical or virtual machine operated by a cloud provider on
@@ -12,6 +13,7 @@ type Machine struct {
 	ID int //Database ID
 
 	/* Populated by the policy engine. */
+	Namespace  string // The namespace of the cluster the machine belongs to.
 	StitchID   string
 	Role       Role
 	Provider   Provider
@@ -21,13 +23,24 @@ type Machine struct {
 	SSHKeys    []string `rowStringer:"omit"`
 	FloatingIP string
 
+	// Preemptible machines may be reclaimed by the cloud provider.  SpotPrice is
+	// the bid for Amazon spot instances, or 0 for the default.
+	Preemptible bool
+	SpotPrice   float64
+
 	/* Populated by the cloud provider. */
 	CloudID   string //Cloud Provider ID
 	PublicIP  string
 	PrivateIP string
 
+	// The time until which the cluster won't contact the machine's provider, as
+	// it's backing off after failures.  Zero if the provider is healthy.
+	BackoffUntil time.Time
+
 	/* Populated by the foreman. */
-	Connected bool // Whether the minion on this machine has connected back.
+	Connected     bool   // Whether the minion on this machine has connected back.
+	MinionVersion string // The version of Quilt the minion is running.
+	Terminating   bool   // Whether the machine is about to be reclaimed.
 }
 
 // InsertMachine creates a new Machine and inserts it into 'db'.
@@ -52,13 +65,35 @@ func (db Database) SelectFromMachine(check func(Machine) bool) []Machine {
 // SelectFromMachine gets all machines in the database that satisfy 'check'.
 func (cn Conn) SelectFromMachine(check func(Machine) bool) []Machine {
 	var machines []Machine
-	cn.Txn(MachineTable).Run(func(view Database) error {
+	cn.ReadTxn(MachineTable).Run(func(view Database) error {
 		machines = view.SelectFromMachine(check)
 		return nil
 	})
 	return machines
 }
 
+// SelectFromMachineByCloudID gets all machines in the database with the given
+// 'cloudID', sorted by ID.  Unlike SelectFromMachine(), it uses an index rather than
+// scanning every row.
+func (db Database) SelectFromMachineByCloudID(cloudID string) []Machine {
+	var result []Machine
+	for _, row := range db.accessTable(MachineTable).lookup("CloudID", cloudID) {
+		result = append(result, row.(Machine))
+	}
+	return result
+}
+
+// SelectFromMachineByCloudID gets all machines in the database with the given
+// 'cloudID'.
+func (cn Conn) SelectFromMachineByCloudID(cloudID string) []Machine {
+	var machines []Machine
+	cn.ReadTxn(MachineTable).Run(func(view Database) error {
+		machines = view.SelectFromMachineByCloudID(cloudID)
+		return nil
+	})
+	return machines
+}
+
 func (m Machine) getID() int {
 	return m.ID
 }
@@ -96,10 +131,22 @@ func (m Machine) String() string {
 		tags = append(tags, fmt.Sprintf("Disk=%dGB", m.DiskSize))
 	}
 
+	if m.Preemptible {
+		tags = append(tags, "Preemptible")
+	}
+
+	if !m.BackoffUntil.IsZero() {
+		tags = append(tags, "BackoffUntil="+m.BackoffUntil.Format(time.RFC3339))
+	}
+
 	if m.Connected {
 		tags = append(tags, "Connected")
 	}
 
+	if m.Terminating {
+		tags = append(tags, "Terminating")
+	}
+
 	return fmt.Sprintf("Machine-%d{%s}", m.ID, strings.Join(tags, ", "))
 }
 
@@ -19,6 +19,10 @@ type Minion struct {
 	Size       string
 	Region     string
 	FloatingIP string
+
+	// Whether the cloud provider has given notice that it's about to reclaim the
+	// minion's machine.  The scheduler moves containers off terminating workers.
+	Terminating bool
 }
 
 // InsertMinion creates a new Minion and inserts it into 'db'.
@@ -64,7 +68,7 @@ func (conn Conn) MinionSelf() (Minion, error) {
 	var m Minion
 	var err error
 
-	conn.Txn(MinionTable).Run(func(view Database) error {
+	conn.ReadTxn(MinionTable).Run(func(view Database) error {
 		m, err = view.MinionSelf()
 		return nil
 	})
@@ -75,7 +79,7 @@ func (conn Conn) MinionSelf() (Minion, error) {
 // SelectFromMinion gets all minions in the database that satisfy the 'check'.
 func (conn Conn) SelectFromMinion(check func(Minion) bool) []Minion {
 	var minions []Minion
-	conn.Txn(MinionTable).Run(func(view Database) error {
+	conn.ReadTxn(MinionTable).Run(func(view Database) error {
 		minions = view.SelectFromMinion(check)
 		return nil
 	})
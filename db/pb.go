@@ -0,0 +1,579 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NetSys/quilt/api/pb"
+)
+
+// RowsToPB converts a slice of rows, e.g. a []Machine, to the protobuf
+// representation used by the API.
+func RowsToPB(rows interface{}) *pb.TableRows {
+	result := &pb.TableRows{}
+	switch rows := rows.(type) {
+	case []Machine:
+		for _, m := range rows {
+			result.Machines = append(result.Machines, MachineToPB(m))
+		}
+	case []Container:
+		for _, c := range rows {
+			result.Containers = append(result.Containers, ContainerToPB(c))
+		}
+	case []Label:
+		for _, l := range rows {
+			result.Labels = append(result.Labels, LabelToPB(l))
+		}
+	case []Connection:
+		for _, c := range rows {
+			result.Connections = append(result.Connections, ConnectionToPB(c))
+		}
+	case []Etcd:
+		for _, e := range rows {
+			result.Etcds = append(result.Etcds, EtcdToPB(e))
+		}
+	case []Cluster:
+		for _, c := range rows {
+			result.Clusters = append(result.Clusters, ClusterToPB(c))
+		}
+	case []Placement:
+		for _, p := range rows {
+			result.Placements = append(result.Placements, PlacementToPB(p))
+		}
+	case []ACL:
+		for _, acl := range rows {
+			result.ACLs = append(result.ACLs, ACLToPB(acl))
+		}
+	case []Minion:
+		for _, m := range rows {
+			result.Minions = append(result.Minions, MinionToPB(m))
+		}
+	case []Event:
+		for _, e := range rows {
+			result.Events = append(result.Events, EventToPB(e))
+		}
+	case []Status:
+		for _, s := range rows {
+			result.Statuses = append(result.Statuses, StatusToPB(s))
+		}
+	default:
+		panic(fmt.Sprintf("unsupported rows: %T", rows))
+	}
+	return result
+}
+
+// PBToRows converts the protobuf representation of the rows of `table` to a slice
+// of the table's type, e.g. a []Machine for the MachineTable.
+func PBToRows(table TableType, rows *pb.TableRows) interface{} {
+	switch table {
+	case MachineTable:
+		var machines []Machine
+		for _, m := range rows.GetMachines() {
+			machines = append(machines, PBToMachine(m))
+		}
+		return machines
+	case ContainerTable:
+		var containers []Container
+		for _, c := range rows.GetContainers() {
+			containers = append(containers, PBToContainer(c))
+		}
+		return containers
+	case LabelTable:
+		var labels []Label
+		for _, l := range rows.GetLabels() {
+			labels = append(labels, PBToLabel(l))
+		}
+		return labels
+	case ConnectionTable:
+		var connections []Connection
+		for _, c := range rows.GetConnections() {
+			connections = append(connections, PBToConnection(c))
+		}
+		return connections
+	case EtcdTable:
+		var etcds []Etcd
+		for _, e := range rows.GetEtcds() {
+			etcds = append(etcds, PBToEtcd(e))
+		}
+		return etcds
+	case ClusterTable:
+		var clusters []Cluster
+		for _, c := range rows.GetClusters() {
+			clusters = append(clusters, PBToCluster(c))
+		}
+		return clusters
+	case PlacementTable:
+		var placements []Placement
+		for _, p := range rows.GetPlacements() {
+			placements = append(placements, PBToPlacement(p))
+		}
+		return placements
+	case ACLTable:
+		var acls []ACL
+		for _, acl := range rows.GetACLs() {
+			acls = append(acls, PBToACL(acl))
+		}
+		return acls
+	case MinionTable:
+		var minions []Minion
+		for _, m := range rows.GetMinions() {
+			minions = append(minions, PBToMinion(m))
+		}
+		return minions
+	case EventTable:
+		var events []Event
+		for _, e := range rows.GetEvents() {
+			events = append(events, PBToEvent(e))
+		}
+		return events
+	case StatusTable:
+		var statuses []Status
+		for _, s := range rows.GetStatuses() {
+			statuses = append(statuses, PBToStatus(s))
+		}
+		return statuses
+	default:
+		panic(fmt.Sprintf("unsupported table type: %s", table))
+	}
+}
+
+// MachineToPB converts a Machine to its protobuf representation.
+func MachineToPB(m Machine) *pb.Machine {
+	// The zero time can't be represented in nanoseconds since the epoch.
+	var backoffUntil int64
+	if !m.BackoffUntil.IsZero() {
+		backoffUntil = m.BackoffUntil.UnixNano()
+	}
+
+	return &pb.Machine{
+		ID:            int32(m.ID),
+		Namespace:     m.Namespace,
+		StitchID:      m.StitchID,
+		Role:          string(m.Role),
+		Provider:      string(m.Provider),
+		Region:        m.Region,
+		Size:          m.Size,
+		DiskSize:      int32(m.DiskSize),
+		SSHKeys:       m.SSHKeys,
+		FloatingIP:    m.FloatingIP,
+		CloudID:       m.CloudID,
+		PublicIP:      m.PublicIP,
+		PrivateIP:     m.PrivateIP,
+		Connected:     m.Connected,
+		MinionVersion: m.MinionVersion,
+		Preemptible:   m.Preemptible,
+		SpotPrice:     m.SpotPrice,
+		BackoffUntil:  backoffUntil,
+		Terminating:   m.Terminating,
+	}
+}
+
+// PBToMachine converts the protobuf representation of a machine to a Machine.
+func PBToMachine(m *pb.Machine) Machine {
+	var backoffUntil time.Time
+	if m.BackoffUntil != 0 {
+		backoffUntil = time.Unix(0, m.BackoffUntil)
+	}
+
+	return Machine{
+		ID:            int(m.ID),
+		Namespace:     m.Namespace,
+		StitchID:      m.StitchID,
+		Role:          Role(m.Role),
+		Provider:      Provider(m.Provider),
+		Region:        m.Region,
+		Size:          m.Size,
+		DiskSize:      int(m.DiskSize),
+		SSHKeys:       m.SSHKeys,
+		FloatingIP:    m.FloatingIP,
+		CloudID:       m.CloudID,
+		PublicIP:      m.PublicIP,
+		PrivateIP:     m.PrivateIP,
+		Connected:     m.Connected,
+		MinionVersion: m.MinionVersion,
+		Preemptible:   m.Preemptible,
+		SpotPrice:     m.SpotPrice,
+		BackoffUntil:  backoffUntil,
+		Terminating:   m.Terminating,
+	}
+}
+
+// ContainerToPB converts a Container to its protobuf representation.
+func ContainerToPB(c Container) *pb.Container {
+	// The zero time can't be represented in nanoseconds since the epoch.
+	var created int64
+	if !c.Created.IsZero() {
+		created = c.Created.UnixNano()
+	}
+
+	return &pb.Container{
+		ID:         int32(c.ID),
+		IP:         c.IP,
+		Minion:     c.Minion,
+		EndpointID: c.EndpointID,
+		StitchID:   c.StitchID,
+		DockerID:   c.DockerID,
+		Image:      c.Image,
+		Status:     c.Status,
+		Command:    c.Command,
+		Labels:     c.Labels,
+		Env:        c.Env,
+		Created:    created,
+	}
+}
+
+// PBToContainer converts the protobuf representation of a container to a Container.
+func PBToContainer(c *pb.Container) Container {
+	var created time.Time
+	if c.Created != 0 {
+		created = time.Unix(0, c.Created)
+	}
+
+	return Container{
+		ID:         int(c.ID),
+		IP:         c.IP,
+		Minion:     c.Minion,
+		EndpointID: c.EndpointID,
+		StitchID:   c.StitchID,
+		DockerID:   c.DockerID,
+		Image:      c.Image,
+		Status:     c.Status,
+		Command:    c.Command,
+		Labels:     c.Labels,
+		Env:        c.Env,
+		Created:    created,
+	}
+}
+
+// LabelToPB converts a Label to its protobuf representation.
+func LabelToPB(l Label) *pb.Label {
+	return &pb.Label{
+		ID:           int32(l.ID),
+		Label:        l.Label,
+		IP:           l.IP,
+		ContainerIPs: l.ContainerIPs,
+	}
+}
+
+// PBToLabel converts the protobuf representation of a label to a Label.
+func PBToLabel(l *pb.Label) Label {
+	return Label{
+		ID:           int(l.ID),
+		Label:        l.Label,
+		IP:           l.IP,
+		ContainerIPs: l.ContainerIPs,
+	}
+}
+
+// ConnectionToPB converts a Connection to its protobuf representation.
+func ConnectionToPB(c Connection) *pb.Connection {
+	return &pb.Connection{
+		ID:      int32(c.ID),
+		From:    c.From,
+		To:      c.To,
+		MinPort: int32(c.MinPort),
+		MaxPort: int32(c.MaxPort),
+	}
+}
+
+// PBToConnection converts the protobuf representation of a connection to a Connection.
+func PBToConnection(c *pb.Connection) Connection {
+	return Connection{
+		ID:      int(c.ID),
+		From:    c.From,
+		To:      c.To,
+		MinPort: int(c.MinPort),
+		MaxPort: int(c.MaxPort),
+	}
+}
+
+// EtcdToPB converts an Etcd to its protobuf representation.
+func EtcdToPB(e Etcd) *pb.Etcd {
+	return &pb.Etcd{
+		ID:       int32(e.ID),
+		EtcdIPs:  e.EtcdIPs,
+		Leader:   e.Leader,
+		LeaderIP: e.LeaderIP,
+	}
+}
+
+// PBToEtcd converts the protobuf representation of an etcd to an Etcd.
+func PBToEtcd(e *pb.Etcd) Etcd {
+	return Etcd{
+		ID:       int(e.ID),
+		EtcdIPs:  e.EtcdIPs,
+		Leader:   e.Leader,
+		LeaderIP: e.LeaderIP,
+	}
+}
+
+// ClusterToPB converts a Cluster to its protobuf representation.
+func ClusterToPB(c Cluster) *pb.Cluster {
+	return &pb.Cluster{ID: int32(c.ID), Namespace: c.Namespace, Spec: c.Spec}
+}
+
+// PBToCluster converts the protobuf representation of a cluster to a Cluster.
+func PBToCluster(c *pb.Cluster) Cluster {
+	return Cluster{ID: int(c.ID), Namespace: c.Namespace, Spec: c.Spec}
+}
+
+// PlacementToPB converts a Placement to its protobuf representation.
+func PlacementToPB(p Placement) *pb.Placement {
+	return &pb.Placement{
+		ID:          int32(p.ID),
+		TargetLabel: p.TargetLabel,
+		Exclusive:   p.Exclusive,
+		OtherLabel:  p.OtherLabel,
+		Provider:    p.Provider,
+		Size:        p.Size,
+		Region:      p.Region,
+		FloatingIP:  p.FloatingIP,
+	}
+}
+
+// PBToPlacement converts the protobuf representation of a placement to a Placement.
+func PBToPlacement(p *pb.Placement) Placement {
+	return Placement{
+		ID:          int(p.ID),
+		TargetLabel: p.TargetLabel,
+		Exclusive:   p.Exclusive,
+		OtherLabel:  p.OtherLabel,
+		Provider:    p.Provider,
+		Size:        p.Size,
+		Region:      p.Region,
+		FloatingIP:  p.FloatingIP,
+	}
+}
+
+// ACLToPB converts an ACL to its protobuf representation.
+func ACLToPB(acl ACL) *pb.ACL {
+	result := &pb.ACL{ID: int32(acl.ID), Namespace: acl.Namespace, Admin: acl.Admin}
+	for _, pr := range acl.ApplicationPorts {
+		result.ApplicationPorts = append(result.ApplicationPorts,
+			PortRangeToPB(pr))
+	}
+	return result
+}
+
+// PBToACL converts the protobuf representation of an ACL to an ACL.
+func PBToACL(acl *pb.ACL) ACL {
+	result := ACL{ID: int(acl.ID), Namespace: acl.Namespace, Admin: acl.Admin}
+	for _, pr := range acl.ApplicationPorts {
+		result.ApplicationPorts = append(result.ApplicationPorts,
+			PBToPortRange(pr))
+	}
+	return result
+}
+
+// PortRangeToPB converts a PortRange to its protobuf representation.
+func PortRangeToPB(pr PortRange) *pb.ACL_PortRange {
+	return &pb.ACL_PortRange{MinPort: int32(pr.MinPort), MaxPort: int32(pr.MaxPort)}
+}
+
+// PBToPortRange converts the protobuf representation of a port range to a
+// PortRange.
+func PBToPortRange(pr *pb.ACL_PortRange) PortRange {
+	return PortRange{MinPort: int(pr.MinPort), MaxPort: int(pr.MaxPort)}
+}
+
+// MinionToPB converts a Minion to its protobuf representation.  Like its JSON
+// encoding, the representation leaves out the fields only the minion itself uses,
+// such as its spec and authorized keys.
+func MinionToPB(m Minion) *pb.Minion {
+	return &pb.Minion{
+		ID:          int32(m.ID),
+		Role:        string(m.Role),
+		PrivateIP:   m.PrivateIP,
+		Provider:    m.Provider,
+		Size:        m.Size,
+		Region:      m.Region,
+		FloatingIP:  m.FloatingIP,
+		Terminating: m.Terminating,
+	}
+}
+
+// PBToMinion converts the protobuf representation of a minion to a Minion.
+func PBToMinion(m *pb.Minion) Minion {
+	return Minion{
+		ID:          int(m.ID),
+		Role:        Role(m.Role),
+		PrivateIP:   m.PrivateIP,
+		Provider:    m.Provider,
+		Size:        m.Size,
+		Region:      m.Region,
+		FloatingIP:  m.FloatingIP,
+		Terminating: m.Terminating,
+	}
+}
+
+// DeploymentToPB converts a Deployment to its protobuf representation.
+func DeploymentToPB(d Deployment) *pb.Deployment {
+	return &pb.Deployment{
+		ID:        int32(d.ID),
+		Namespace: d.Namespace,
+		Spec:      d.Spec,
+		Hash:      d.Hash,
+		Time:      d.Time.UnixNano(),
+	}
+}
+
+// PBToDeployment converts the protobuf representation of a deployment to a
+// Deployment.
+func PBToDeployment(d *pb.Deployment) Deployment {
+	return Deployment{
+		ID:        int(d.ID),
+		Namespace: d.Namespace,
+		Spec:      d.Spec,
+		Hash:      d.Hash,
+		Time:      time.Unix(0, d.Time),
+	}
+}
+
+// EventToPB converts an Event to its protobuf representation.
+func EventToPB(e Event) *pb.Event {
+	return &pb.Event{
+		ID:        int32(e.ID),
+		Time:      e.Time.UnixNano(),
+		Type:      string(e.Type),
+		Namespace: e.Namespace,
+		Machine:   e.Machine,
+		Message:   e.Message,
+	}
+}
+
+// PBToEvent converts the protobuf representation of an event to an Event.
+func PBToEvent(e *pb.Event) Event {
+	return Event{
+		ID:        int(e.ID),
+		Time:      time.Unix(0, e.Time),
+		Type:      EventType(e.Type),
+		Namespace: e.Namespace,
+		Machine:   e.Machine,
+		Message:   e.Message,
+	}
+}
+
+// StatusToPB converts a Status to its protobuf representation.
+func StatusToPB(s Status) *pb.Status {
+	return &pb.Status{
+		ID:                      int32(s.ID),
+		Namespace:               s.Namespace,
+		State:                   string(s.State),
+		Warnings:                s.Warnings,
+		UnsatisfiableMachines:   s.UnsatisfiableMachines,
+		MinionWarnings:          s.MinionWarnings,
+		UnschedulableContainers: s.UnschedulableContainers,
+	}
+}
+
+// PBToStatus converts the protobuf representation of a status to a Status.
+func PBToStatus(s *pb.Status) Status {
+	return Status{
+		ID:                      int(s.ID),
+		Namespace:               s.Namespace,
+		State:                   DeploymentState(s.State),
+		Warnings:                s.Warnings,
+		UnsatisfiableMachines:   s.UnsatisfiableMachines,
+		MinionWarnings:          s.MinionWarnings,
+		UnschedulableContainers: s.UnschedulableContainers,
+	}
+}
+
+// TokenToPB converts a Token to its protobuf representation.  The hash of the token
+// is left out.
+func TokenToPB(t Token) *pb.Token {
+	return &pb.Token{
+		ID:      int32(t.ID),
+		Name:    t.Name,
+		Role:    string(t.Role),
+		Created: t.Created.UnixNano(),
+	}
+}
+
+// PBToToken converts the protobuf representation of a token to a Token.
+func PBToToken(t *pb.Token) Token {
+	return Token{
+		ID:      int(t.ID),
+		Name:    t.Name,
+		Role:    TokenRole(t.Role),
+		Created: time.Unix(0, t.Created),
+	}
+}
+
+// PlanToPB converts a Plan to its protobuf representation.
+func PlanToPB(plan Plan) *pb.PlanReply {
+	reply := &pb.PlanReply{
+		AddAdminACLs:    plan.AddAdminACLs,
+		RemoveAdminACLs: plan.RemoveAdminACLs,
+	}
+	for _, m := range plan.BootMachines {
+		reply.BootMachines = append(reply.BootMachines, MachineToPB(m))
+	}
+	for _, m := range plan.TerminateMachines {
+		reply.TerminateMachines = append(reply.TerminateMachines,
+			MachineToPB(m))
+	}
+	for _, c := range plan.StartContainers {
+		reply.StartContainers = append(reply.StartContainers,
+			ContainerToPB(c))
+	}
+	for _, c := range plan.StopContainers {
+		reply.StopContainers = append(reply.StopContainers, ContainerToPB(c))
+	}
+	for _, c := range plan.AddConnections {
+		reply.AddConnections = append(reply.AddConnections,
+			ConnectionToPB(c))
+	}
+	for _, c := range plan.RemoveConnections {
+		reply.RemoveConnections = append(reply.RemoveConnections,
+			ConnectionToPB(c))
+	}
+	for _, pr := range plan.AddApplicationPorts {
+		reply.AddApplicationPorts = append(reply.AddApplicationPorts,
+			PortRangeToPB(pr))
+	}
+	for _, pr := range plan.RemoveApplicationPorts {
+		reply.RemoveApplicationPorts = append(reply.RemoveApplicationPorts,
+			PortRangeToPB(pr))
+	}
+	return reply
+}
+
+// PBToPlan converts the protobuf representation of a plan to a Plan.
+func PBToPlan(reply *pb.PlanReply) Plan {
+	plan := Plan{
+		AddAdminACLs:    reply.AddAdminACLs,
+		RemoveAdminACLs: reply.RemoveAdminACLs,
+	}
+	for _, m := range reply.BootMachines {
+		plan.BootMachines = append(plan.BootMachines, PBToMachine(m))
+	}
+	for _, m := range reply.TerminateMachines {
+		plan.TerminateMachines = append(plan.TerminateMachines,
+			PBToMachine(m))
+	}
+	for _, c := range reply.StartContainers {
+		plan.StartContainers = append(plan.StartContainers,
+			PBToContainer(c))
+	}
+	for _, c := range reply.StopContainers {
+		plan.StopContainers = append(plan.StopContainers, PBToContainer(c))
+	}
+	for _, c := range reply.AddConnections {
+		plan.AddConnections = append(plan.AddConnections,
+			PBToConnection(c))
+	}
+	for _, c := range reply.RemoveConnections {
+		plan.RemoveConnections = append(plan.RemoveConnections,
+			PBToConnection(c))
+	}
+	for _, pr := range reply.AddApplicationPorts {
+		plan.AddApplicationPorts = append(plan.AddApplicationPorts,
+			PBToPortRange(pr))
+	}
+	for _, pr := range reply.RemoveApplicationPorts {
+		plan.RemoveApplicationPorts = append(plan.RemoveApplicationPorts,
+			PBToPortRange(pr))
+	}
+	return plan
+}
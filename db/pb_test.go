@@ -0,0 +1,74 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPBRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tables := map[TableType]interface{}{
+		MachineTable: []Machine{{ID: 1, Namespace: "ns", StitchID: "1",
+			Role: Master, Provider: Amazon, Region: "us-west-1",
+			Size: "m4.large", DiskSize: 32, SSHKeys: []string{"key"},
+			FloatingIP: "1.2.3.4", CloudID: "i-1", PublicIP: "8.8.8.8",
+			PrivateIP: "9.9.9.9", Connected: true, MinionVersion: "1.0",
+			Preemptible: true, SpotPrice: 0.5,
+			BackoffUntil: time.Unix(0, 100), Terminating: true}, {ID: 15}},
+		ContainerTable: []Container{{ID: 2, IP: "10.0.0.2", Minion: "9.9.9.9",
+			EndpointID: "endpoint", StitchID: "2", DockerID: "docker",
+			Image: "alpine", Status: "running", Command: []string{"sh"},
+			Labels: []string{"red"}, Env: map[string]string{"k": "v"},
+			Created: time.Unix(0, 100)}, {ID: 3}},
+		LabelTable: []Label{{ID: 3, Label: "red", IP: "10.0.0.3",
+			ContainerIPs: []string{"10.0.0.2"}}},
+		ConnectionTable: []Connection{{ID: 4, From: "red", To: "blue",
+			MinPort: 80, MaxPort: 81}},
+		EtcdTable: []Etcd{{ID: 5, EtcdIPs: []string{"9.9.9.9"}, Leader: true,
+			LeaderIP: "9.9.9.9"}},
+		ClusterTable: []Cluster{{ID: 6, Namespace: "ns", Spec: "{}"}},
+		PlacementTable: []Placement{{ID: 7, TargetLabel: "red",
+			Exclusive: true, OtherLabel: "blue", Provider: "Amazon",
+			Size: "m4.large", Region: "us-west-1", FloatingIP: "1.2.3.4"}},
+		ACLTable: []ACL{{ID: 8, Namespace: "ns", Admin: []string{"local"},
+			ApplicationPorts: []PortRange{{MinPort: 80, MaxPort: 80}}}},
+		MinionTable: []Minion{{ID: 9, Role: Worker, PrivateIP: "9.9.9.9",
+			Provider: "Amazon", Size: "m4.large", Region: "us-west-1",
+			FloatingIP: "1.2.3.4", Terminating: true}},
+		EventTable: []Event{{ID: 11, Time: time.Unix(0, 100),
+			Type: MachineBooted, Namespace: "ns", Machine: "1",
+			Message: "booted"}},
+		StatusTable: []Status{{ID: 12, Namespace: "ns", State: Blocked,
+			Warnings:                []string{"warning"},
+			UnsatisfiableMachines:   []string{"machine"},
+			MinionWarnings:          []string{"minion warning"},
+			UnschedulableContainers: []string{"container"}}},
+	}
+
+	for table, rows := range tables {
+		assert.Equal(t, rows, PBToRows(table, RowsToPB(rows)), string(table))
+	}
+
+	// Like their JSON encoding, the protobufs of minions leave out the fields
+	// only the minion itself uses.
+	minion := Minion{ID: 9, Self: true, Spec: "{}", AuthorizedKeys: "key",
+		SupervisorInit: true, Role: Worker, PrivateIP: "9.9.9.9"}
+	assert.Equal(t, Minion{ID: 9, Role: Worker, PrivateIP: "9.9.9.9"},
+		PBToMinion(MinionToPB(minion)))
+
+	deployment := Deployment{ID: 10, Namespace: "ns", Spec: "{}",
+		Hash: HashSpec("{}"), Time: time.Unix(0, 100)}
+	assert.Equal(t, deployment, PBToDeployment(DeploymentToPB(deployment)))
+
+	token := Token{ID: 13, Name: "ci", Role: Deployer, Hash: HashToken("token"),
+		Created: time.Unix(0, 100)}
+	exp := token
+	exp.Hash = ""
+	assert.Equal(t, exp, PBToToken(TokenToPB(token)))
+
+	var noMachines []Machine
+	assert.Equal(t, noMachines, PBToRows(MachineTable, RowsToPB([]Machine{})))
+}
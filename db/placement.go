@@ -46,7 +46,7 @@ func (db Database) SelectFromPlacement(check func(Placement) bool) []Placement {
 // SelectFromPlacement gets all placements in the database that satisfy the 'check'.
 func (conn Conn) SelectFromPlacement(check func(Placement) bool) []Placement {
 	var placements []Placement
-	conn.Txn(PlacementTable).Run(func(view Database) error {
+	conn.ReadTxn(PlacementTable).Run(func(view Database) error {
 		placements = view.SelectFromPlacement(check)
 		return nil
 	})
@@ -0,0 +1,26 @@
+package db
+
+import "reflect"
+
+// A Plan describes the changes that deploying a stitch would make.
+type Plan struct {
+	BootMachines      []Machine
+	TerminateMachines []Machine
+
+	StartContainers []Container
+	StopContainers  []Container
+
+	AddConnections    []Connection
+	RemoveConnections []Connection
+
+	AddAdminACLs    []string
+	RemoveAdminACLs []string
+
+	AddApplicationPorts    []PortRange
+	RemoveApplicationPorts []PortRange
+}
+
+// Empty returns whether the plan makes no changes.
+func (plan Plan) Empty() bool {
+	return reflect.DeepEqual(plan, Plan{})
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"fmt"
+	"log"
+)
+
+// A Status summarizes how the deployment of a namespace's stitch is progressing, and
+// why it may be unable to converge.  The minions keep a Status of their own, with an
+// empty Namespace, describing the spec they were last given.
+type Status struct {
+	ID int
+
+	Namespace string
+	State     DeploymentState
+
+	/* Populated by the policy engine. */
+	Warnings              []string // Problems found while validating the stitch.
+	UnsatisfiableMachines []string // Machines in the stitch that can't be booted.
+
+	/* Populated by the foreman, as reported by the leading minion. */
+	MinionWarnings          []string // Problems the minion found with the stitch.
+	UnschedulableContainers []string // Containers no minion can run.
+}
+
+// DeploymentState describes whether a deployment has converged.
+type DeploymentState string
+
+const (
+	// Invalid deployments have a stitch that can't be parsed.
+	Invalid DeploymentState = "Invalid"
+
+	// Blocked deployments can't converge until either the stitch or the cluster
+	// changes, e.g. because some machines or containers can't be placed.
+	Blocked DeploymentState = "Blocked"
+
+	// Converging deployments still have machines that are booting, or whose
+	// minions haven't connected.
+	Converging DeploymentState = "Converging"
+
+	// Converged deployments have every machine in the stitch booted and connected,
+	// and every container placed.
+	Converged DeploymentState = "Converged"
+)
+
+// InsertStatus creates a new status row and inserts it into the database.
+func (db Database) InsertStatus() Status {
+	result := Status{ID: db.nextID()}
+	db.insert(result)
+	return result
+}
+
+// SelectFromStatus gets all statuses in the database that satisfy 'check'.
+func (db Database) SelectFromStatus(check func(Status) bool) []Status {
+	statusTable := db.accessTable(StatusTable)
+	result := []Status{}
+	for _, row := range statusTable.rows {
+		if check == nil || check(row.(Status)) {
+			result = append(result, row.(Status))
+		}
+	}
+	return result
+}
+
+// SelectFromStatus gets all statuses in the database that satisfy 'check'.
+func (conn Conn) SelectFromStatus(check func(Status) bool) []Status {
+	var statuses []Status
+	conn.ReadTxn(StatusTable).Run(func(view Database) error {
+		statuses = view.SelectFromStatus(check)
+		return nil
+	})
+	return statuses
+}
+
+// GetStatus gets the status of the given namespace from the database. There should
+// only ever be a single status per namespace.
+func (db Database) GetStatus(namespace string) (Status, error) {
+	statuses := db.SelectFromStatus(func(s Status) bool {
+		return s.Namespace == namespace
+	})
+	numStatuses := len(statuses)
+	if numStatuses == 1 {
+		return statuses[0], nil
+	} else if numStatuses > 1 {
+		log.Panicf("Found %d statuses in namespace %s, there should be 1",
+			numStatuses, namespace)
+	}
+	return Status{}, fmt.Errorf("no status in namespace %s", namespace)
+}
+
+func (s Status) getID() int {
+	return s.ID
+}
+
+func (s Status) tt() TableType {
+	return StatusTable
+}
+
+func (s Status) String() string {
+	return defaultString(s)
+}
+
+func (s Status) less(r row) bool {
+	return s.ID < r.(Status).ID
+}
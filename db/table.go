@@ -2,6 +2,7 @@ package db
 
 import (
 	"reflect"
+	"sort"
 	"sync"
 )
 
@@ -35,23 +36,73 @@ var PlacementTable = TableType(reflect.TypeOf(Placement{}).String())
 // ACLTable is the type of the ACL table.
 var ACLTable = TableType(reflect.TypeOf(ACL{}).String())
 
+// DeploymentTable is the type of the deployment table.
+var DeploymentTable = TableType(reflect.TypeOf(Deployment{}).String())
+
+// EventTable is the type of the event table.
+var EventTable = TableType(reflect.TypeOf(Event{}).String())
+
+// StatusTable is the type of the status table.
+var StatusTable = TableType(reflect.TypeOf(Status{}).String())
+
+// TokenTable is the type of the API token table.
+var TokenTable = TableType(reflect.TypeOf(Token{}).String())
+
 // AllTables is a slice of all the db TableTypes. It is used primarily for tests,
 // where there is no reason to put lots of thought into which tables a Transaction
 // should use.
 var AllTables = []TableType{ClusterTable, MachineTable, ContainerTable, MinionTable,
-	ConnectionTable, LabelTable, EtcdTable, PlacementTable, ACLTable}
+	ConnectionTable, LabelTable, EtcdTable, PlacementTable, ACLTable,
+	DeploymentTable, EventTable, StatusTable, TokenTable}
+
+// tableIndexes declares the secondary indexes maintained on each table.  Each index
+// is named by the field it covers, and maps to a function that extracts that field
+// from a row.
+var tableIndexes = map[TableType]map[string]func(row) string{
+	ContainerTable: {
+		"Minion":   func(r row) string { return r.(Container).Minion },
+		"StitchID": func(r row) string { return r.(Container).StitchID },
+		"IP":       func(r row) string { return r.(Container).IP },
+	},
+	MachineTable: {
+		"CloudID": func(r row) string { return r.(Machine).CloudID },
+	},
+}
 
 type table struct {
-	rows map[int]row
+	rows     map[int]row
+	versions map[int]int
+	indexes  map[string]*index
+
+	// The value, before the running transaction, of each row it modified.  Rows
+	// that didn't exist beforehand map to nil.
+	changed map[int]row
+
+	watches map[*watchQueue]struct{}
 
 	triggers    map[Trigger]struct{}
 	shouldAlert bool
-	sync.Mutex
+	sync.RWMutex
 }
 
-func newTable() *table {
+// An index maps each value of an indexed field to the IDs of the rows holding it.
+type index struct {
+	key  func(row) string
+	rows map[string]map[int]struct{}
+}
+
+func newTable(tt TableType) *table {
+	indexes := map[string]*index{}
+	for name, key := range tableIndexes[tt] {
+		indexes[name] = &index{key: key, rows: map[string]map[int]struct{}{}}
+	}
+
 	return &table{
 		rows:        make(map[int]row),
+		versions:    make(map[int]int),
+		indexes:     indexes,
+		changed:     make(map[int]row),
+		watches:     make(map[*watchQueue]struct{}),
 		triggers:    make(map[Trigger]struct{}),
 		shouldAlert: false,
 	}
@@ -72,3 +123,80 @@ func (t *table) alert() {
 		}
 	}
 }
+
+// recordChange notes that the row with 'id' is about to be modified, so that its
+// original value may be reported when the transaction completes.
+func (t *table) recordChange(id int) {
+	if _, ok := t.changed[id]; !ok {
+		t.changed[id] = t.rows[id]
+	}
+}
+
+// set stores 'r' in the table, keeping the indexes up to date and bumping its
+// version.
+func (t *table) set(r row) {
+	id := r.getID()
+	if old, ok := t.rows[id]; ok {
+		for _, idx := range t.indexes {
+			idx.remove(idx.key(old), id)
+		}
+	}
+
+	t.rows[id] = r
+	t.versions[id]++
+	for _, idx := range t.indexes {
+		idx.add(idx.key(r), id)
+	}
+}
+
+// delete removes the row with 'id' from the table, keeping the indexes up to date.
+func (t *table) delete(id int) {
+	old, ok := t.rows[id]
+	if !ok {
+		return
+	}
+
+	for _, idx := range t.indexes {
+		idx.remove(idx.key(old), id)
+	}
+	delete(t.rows, id)
+	delete(t.versions, id)
+}
+
+// lookup returns the rows whose field covered by the index 'name' equals 'value',
+// sorted by ID, or nil if there are none.
+func (t *table) lookup(name, value string) []row {
+	idx, ok := t.indexes[name]
+	if !ok {
+		panic("No index: " + name)
+	}
+
+	var ids []int
+	for id := range idx.rows[value] {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var result []row
+	for _, id := range ids {
+		result = append(result, t.rows[id])
+	}
+	return result
+}
+
+func (idx *index) add(value string, id int) {
+	ids, ok := idx.rows[value]
+	if !ok {
+		ids = map[int]struct{}{}
+		idx.rows[value] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+func (idx *index) remove(value string, id int) {
+	ids := idx.rows[value]
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(idx.rows, value)
+	}
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// A Token grants the holder access to the API with the permissions of its Role.  Only
+// the hash of the token is stored, so that the token itself can't be recovered from
+// the database.
+type Token struct {
+	ID int
+
+	Name    string // A description of who the token was issued to.
+	Role    TokenRole
+	Hash    string    `rowStringer:"omit"` // The SHA-256 hash of the token.
+	Created time.Time // When the token was created.
+}
+
+// A TokenRole determines which API calls a Token may make.
+type TokenRole string
+
+const (
+	// ReadOnly tokens may query the state of the cluster.
+	ReadOnly TokenRole = "read-only"
+
+	// Deployer tokens may additionally deploy and stop stitches.
+	Deployer TokenRole = "deployer"
+
+	// Admin tokens may additionally manage the API tokens.
+	Admin TokenRole = "admin"
+)
+
+var tokenRoleRanks = map[TokenRole]int{ReadOnly: 1, Deployer: 2, Admin: 3}
+
+// ParseTokenRole returns the TokenRole represented by 'role', or an error.
+func ParseTokenRole(role string) (TokenRole, error) {
+	if _, ok := tokenRoleRanks[TokenRole(role)]; !ok {
+		return "", fmt.Errorf("unknown token role: %s", role)
+	}
+	return TokenRole(role), nil
+}
+
+// Allows returns true if tokens with role 'r' may make calls that require 'required'.
+func (r TokenRole) Allows(required TokenRole) bool {
+	rank, ok := tokenRoleRanks[r]
+	return ok && rank >= tokenRoleRanks[required]
+}
+
+// HashToken returns the hash by which a token is stored.
+func HashToken(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// InsertToken creates a new token row and inserts it into the database.
+func (db Database) InsertToken() Token {
+	result := Token{ID: db.nextID()}
+	db.insert(result)
+	return result
+}
+
+// SelectFromToken gets all tokens in the database that satisfy 'check', from oldest
+// to newest.
+func (db Database) SelectFromToken(check func(Token) bool) []Token {
+	tokenTable := db.accessTable(TokenTable)
+	var rows []row
+	for _, r := range tokenTable.rows {
+		if check == nil || check(r.(Token)) {
+			rows = append(rows, r)
+		}
+	}
+
+	sort.Sort(rowSlice(rows))
+
+	var result []Token
+	for _, r := range rows {
+		result = append(result, r.(Token))
+	}
+	return result
+}
+
+// SelectFromToken gets all tokens in the database that satisfy 'check', from oldest
+// to newest.
+func (conn Conn) SelectFromToken(check func(Token) bool) []Token {
+	var tokens []Token
+	conn.ReadTxn(TokenTable).Run(func(view Database) error {
+		tokens = view.SelectFromToken(check)
+		return nil
+	})
+	return tokens
+}
+
+func (t Token) getID() int {
+	return t.ID
+}
+
+func (t Token) tt() TableType {
+	return TokenTable
+}
+
+func (t Token) String() string {
+	return defaultString(t)
+}
+
+func (t Token) less(r row) bool {
+	return t.ID < r.(Token).ID
+}
@@ -0,0 +1,221 @@
+package db
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// A ChangeSet describes how a single transaction modified a table.  Each row is an
+// instance of the table's type, e.g. a Machine for the MachineTable.
+type ChangeSet struct {
+	Table TableType
+
+	Inserted []interface{}
+	Removed  []interface{}
+	Modified []Modification
+}
+
+// A Modification holds the value of a row before and after a transaction.
+type Modification struct {
+	Before interface{}
+	After  interface{}
+}
+
+// A Watch delivers the ChangeSets of each transaction that modifies its tables.
+// Unlike a Trigger, notifications are never coalesced across transactions, so a
+// consumer sees every change to the rows it watches.
+type Watch struct {
+	// The channel on which changes are delivered.  Each element holds one
+	// ChangeSet for every watched table modified by a single transaction.
+	C <-chan []ChangeSet
+
+	queue *watchQueue
+}
+
+// A watchQueue buffers the ChangeSets destined for a Watch, so that transactions
+// never block on slow consumers.
+type watchQueue struct {
+	sync.Mutex
+	pending [][]ChangeSet
+
+	ready chan struct{}
+	out   chan []ChangeSet
+	stop  chan struct{}
+}
+
+// Watch registers a new Watch on the tables 'tt'.  So that clients properly
+// initialize, the first delivery reports every row already in those tables as
+// inserted.
+func (cn Conn) Watch(tt ...TableType) Watch {
+	queue := &watchQueue{
+		ready: make(chan struct{}, 1),
+		out:   make(chan []ChangeSet),
+		stop:  make(chan struct{}),
+	}
+	go queue.run()
+
+	cn.Txn(tt...).Run(func(view Database) error {
+		var initial []ChangeSet
+		for _, t := range view.sortedTableTypes() {
+			dbTable := view.accessTable(t)
+			dbTable.watches[queue] = struct{}{}
+
+			cs := ChangeSet{Table: t}
+			for _, r := range dbTable.rows {
+				cs.Inserted = append(cs.Inserted, r)
+			}
+
+			if len(cs.Inserted) > 0 {
+				sortByID(cs.Inserted)
+				initial = append(initial, cs)
+			}
+		}
+
+		if len(initial) > 0 {
+			queue.push(initial)
+		}
+		return nil
+	})
+
+	return Watch{C: queue.out, queue: queue}
+}
+
+// Stop a running Watch thus allowing resources to be deallocated.
+func (w Watch) Stop() {
+	close(w.queue.stop)
+}
+
+// collectChanges computes the ChangeSet of every table modified by the running
+// transaction, and resets their change tracking.
+func (db Database) collectChanges() []ChangeSet {
+	var result []ChangeSet
+	for _, tt := range db.sortedTableTypes() {
+		table := db.tables[tt]
+		if len(table.changed) == 0 {
+			continue
+		}
+
+		cs := ChangeSet{Table: tt}
+		for id, before := range table.changed {
+			after, exists := table.rows[id]
+			switch {
+			case before == nil && exists:
+				cs.Inserted = append(cs.Inserted, after)
+			case before != nil && !exists:
+				cs.Removed = append(cs.Removed, before)
+			case before != nil && !reflect.DeepEqual(before, after):
+				cs.Modified = append(cs.Modified,
+					Modification{before, after})
+			}
+		}
+		table.changed = make(map[int]row)
+
+		if len(cs.Inserted) == 0 && len(cs.Removed) == 0 &&
+			len(cs.Modified) == 0 {
+			continue
+		}
+
+		sortByID(cs.Inserted)
+		sortByID(cs.Removed)
+		sort.Sort(modificationSlice(cs.Modified))
+		result = append(result, cs)
+	}
+
+	return result
+}
+
+// notifyWatches delivers 'changes' to the watches registered on their tables.  Each
+// watch receives all of its ChangeSets for the transaction at once.
+func (db Database) notifyWatches(changes []ChangeSet) {
+	var order []*watchQueue
+	deliveries := map[*watchQueue][]ChangeSet{}
+	for _, cs := range changes {
+		table := db.tables[cs.Table]
+		for queue := range table.watches {
+			select {
+			case <-queue.stop:
+				delete(table.watches, queue)
+				continue
+			default:
+			}
+
+			if _, ok := deliveries[queue]; !ok {
+				order = append(order, queue)
+			}
+			deliveries[queue] = append(deliveries[queue], cs)
+		}
+	}
+
+	for _, queue := range order {
+		queue.push(deliveries[queue])
+	}
+}
+
+func (q *watchQueue) push(changes []ChangeSet) {
+	q.Lock()
+	q.pending = append(q.pending, changes)
+	q.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (q *watchQueue) run() {
+	for {
+		q.Lock()
+		if len(q.pending) == 0 {
+			q.Unlock()
+			select {
+			case <-q.ready:
+				continue
+			case <-q.stop:
+				return
+			}
+		}
+
+		next := q.pending[0]
+		q.pending = q.pending[1:]
+		q.Unlock()
+
+		select {
+		case q.out <- next:
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+func sortByID(rows []interface{}) {
+	sort.Sort(idSlice(rows))
+}
+
+type idSlice []interface{}
+
+func (rows idSlice) Len() int {
+	return len(rows)
+}
+
+func (rows idSlice) Swap(i, j int) {
+	rows[i], rows[j] = rows[j], rows[i]
+}
+
+func (rows idSlice) Less(i, j int) bool {
+	return rows[i].(row).getID() < rows[j].(row).getID()
+}
+
+type modificationSlice []Modification
+
+func (mods modificationSlice) Len() int {
+	return len(mods)
+}
+
+func (mods modificationSlice) Swap(i, j int) {
+	mods[i], mods[j] = mods[j], mods[i]
+}
+
+func (mods modificationSlice) Less(i, j int) bool {
+	return mods[i].After.(row).getID() < mods[j].After.(row).getID()
+}
@@ -1,6 +1,9 @@
 package engine
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/NetSys/quilt/cluster"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/join"
@@ -13,37 +16,100 @@ import (
 var myIP = util.MyIP
 var defaultDiskSize = 32
 
-// Run updates the database in response to stitch changes in the cluster table.
+// Run updates the database in response to stitch changes in the cluster table.  The
+// machines and ACLs of each cluster are kept in sync with its namespace's stitch.
 func Run(conn db.Conn) {
-	for range conn.TriggerTick(30, db.ClusterTable, db.MachineTable, db.ACLTable).C {
-		conn.Txn(db.ACLTable, db.ClusterTable,
-			db.MachineTable).Run(updateTxn)
+	loopLog := util.NewEventTimer("Engine")
+	for range conn.TriggerTick(30, db.ClusterTable, db.MachineTable, db.ACLTable,
+		db.StatusTable).C {
+		loopLog.LogStart()
+		conn.Txn(db.ACLTable, db.ClusterTable, db.MachineTable,
+			db.StatusTable).Run(updateTxn)
+		loopLog.LogEnd()
 	}
 }
 
 func updateTxn(view db.Database) error {
-	cluster, err := view.GetCluster()
-	if err != nil {
-		return err
+	namespaces := map[string]struct{}{}
+	for _, cluster := range view.SelectFromCluster(nil) {
+		namespaces[cluster.Namespace] = struct{}{}
+		status := db.Status{Namespace: cluster.Namespace}
+
+		stitch, err := stitch.FromJSON(cluster.Spec)
+		if err != nil {
+			log.WithError(err).WithField("namespace", cluster.Namespace).
+				Warn("Failed to parse stitch.")
+			status.State = db.Invalid
+			status.Warnings = []string{
+				"Failed to parse stitch: " + err.Error()}
+			statusTxn(view, status)
+			continue
+		}
+
+		status.Warnings, status.UnsatisfiableMachines = machineTxn(view,
+			cluster.Namespace, stitch)
+		aclTxn(view, cluster.Namespace, stitch)
+		statusTxn(view, status)
 	}
 
-	stitch, err := stitch.FromJSON(cluster.Spec)
+	for _, status := range view.SelectFromStatus(nil) {
+		if _, ok := namespaces[status.Namespace]; !ok {
+			view.Remove(status)
+		}
+	}
+	return nil
+}
+
+// statusTxn commits the status of a namespace's deployment, as validated by the
+// engine.  The fields populated by the foreman are kept, and the state of the
+// deployment is derived from them and the namespace's machines unless it's already
+// known to be invalid.
+func statusTxn(view db.Database, status db.Status) {
+	dbStatus, err := view.GetStatus(status.Namespace)
 	if err != nil {
-		return err
+		dbStatus = view.InsertStatus()
 	}
 
-	cluster.Namespace = stitch.Namespace
-	view.Commit(cluster)
+	status.ID = dbStatus.ID
+	status.MinionWarnings = dbStatus.MinionWarnings
+	status.UnschedulableContainers = dbStatus.UnschedulableContainers
+	if status.State != db.Invalid {
+		status.State = deploymentState(view, status)
+	}
 
-	machineTxn(view, stitch)
-	aclTxn(view, stitch)
-	return nil
+	// Committing triggers the engine, so only do so if something changed.
+	if !reflect.DeepEqual(status, dbStatus) {
+		view.Commit(status)
+	}
 }
 
-func aclTxn(view db.Database, specHandle stitch.Stitch) {
-	aclRow, err := view.GetACL()
+func deploymentState(view db.Database, status db.Status) db.DeploymentState {
+	if len(status.Warnings) > 0 || len(status.UnsatisfiableMachines) > 0 ||
+		len(status.MinionWarnings) > 0 {
+		return db.Blocked
+	}
+
+	for _, m := range view.SelectFromMachine(func(m db.Machine) bool {
+		return m.Namespace == status.Namespace
+	}) {
+		if !m.Connected {
+			return db.Converging
+		}
+	}
+
+	// Containers can't be placed until the workers connect, so unschedulable
+	// containers only block deployments whose machines are all up.
+	if len(status.UnschedulableContainers) > 0 {
+		return db.Blocked
+	}
+	return db.Converged
+}
+
+func aclTxn(view db.Database, namespace string, specHandle stitch.Stitch) {
+	aclRow, err := view.GetACL(namespace)
 	if err != nil {
 		aclRow = view.InsertACL()
+		aclRow.Namespace = namespace
 	}
 
 	aclRow.Admin = resolveACLs(specHandle.AdminACL)
@@ -66,16 +132,22 @@ func aclTxn(view db.Database, specHandle stitch.Stitch) {
 // be compared against what's already in the db.
 // Specifically, it sets the role of the db.Machine, the size (which may depend
 // on RAM and CPU constraints), and the provider.
-// Additionally, it skips machines with invalid roles, sizes or providers.
-func toDBMachine(machines []stitch.Machine, maxPrice float64) []db.Machine {
+// Additionally, it skips machines with invalid roles, sizes or providers.  The
+// problems with the stitch are returned as warnings, along with a description of each
+// machine for which no size could be found.
+func toDBMachine(machines []stitch.Machine, maxPrice float64) (
+	dbMachines []db.Machine, warnings, unsatisfiable []string) {
+
 	var hasMaster, hasWorker bool
-	var dbMachines []db.Machine
 	for _, stitchm := range machines {
 		var m db.Machine
 
 		role, err := db.ParseRole(stitchm.Role)
 		if err != nil {
 			log.WithError(err).Error("Error parsing role.")
+			warnings = append(warnings, fmt.Sprintf(
+				"Machine %s has an unknown role: %s", stitchm.ID,
+				stitchm.Role))
 			continue
 		}
 		m.Role = role
@@ -86,6 +158,9 @@ func toDBMachine(machines []stitch.Machine, maxPrice float64) []db.Machine {
 		p, err := db.ParseProvider(stitchm.Provider)
 		if err != nil {
 			log.WithError(err).Error("Error parsing provider.")
+			warnings = append(warnings, fmt.Sprintf(
+				"Machine %s has an unknown provider: %s", stitchm.ID,
+				stitchm.Provider))
 			continue
 		}
 		m.Provider = p
@@ -93,9 +168,11 @@ func toDBMachine(machines []stitch.Machine, maxPrice float64) []db.Machine {
 
 		if m.Size == "" {
 			m.Size = cluster.ChooseSize(p, stitchm.RAM, stitchm.CPU,
-				maxPrice)
+				maxPrice, stitchm.Preemptible)
 			if m.Size == "" {
 				log.Errorf("No valid size for %v, skipping.", m)
+				unsatisfiable = append(unsatisfiable,
+					describeUnsatisfiable(stitchm, maxPrice))
 				continue
 			}
 		}
@@ -105,6 +182,15 @@ func toDBMachine(machines []stitch.Machine, maxPrice float64) []db.Machine {
 			m.DiskSize = defaultDiskSize
 		}
 
+		if stitchm.Preemptible && !cluster.SupportsPreemptible(p) {
+			warnings = append(warnings, fmt.Sprintf(
+				"Machine %s: %s doesn't support preemptible machines",
+				stitchm.ID, p))
+			continue
+		}
+		m.Preemptible = stitchm.Preemptible
+		m.SpotPrice = stitchm.SpotPrice
+
 		m.StitchID = stitchm.ID
 		m.SSHKeys = stitchm.SSHKeys
 		m.Region = stitchm.Region
@@ -114,21 +200,55 @@ func toDBMachine(machines []stitch.Machine, maxPrice float64) []db.Machine {
 
 	if hasMaster && !hasWorker {
 		log.Warning("A Master was specified but no workers.")
-		return nil
+		warnings = append(warnings, "A Master was specified but no workers.")
+		return nil, warnings, unsatisfiable
 	} else if hasWorker && !hasMaster {
 		log.Warning("A Worker was specified but no masters.")
-		return nil
+		warnings = append(warnings, "A Worker was specified but no masters.")
+		return nil, warnings, unsatisfiable
+	}
+
+	return dbMachines, warnings, unsatisfiable
+}
+
+// describeUnsatisfiable explains why no size could be chosen for `m`.
+func describeUnsatisfiable(m stitch.Machine, maxPrice float64) string {
+	describe := func(r stitch.Range) string {
+		if r.Max == 0 {
+			return fmt.Sprintf("at least %v", r.Min)
+		}
+		return fmt.Sprintf("%v to %v", r.Min, r.Max)
 	}
 
-	return dbMachines
+	provider := m.Provider
+	if m.Preemptible {
+		provider = "preemptible " + provider
+	}
+
+	desc := fmt.Sprintf("Machine %s: no %s size has %s GB of RAM and %s CPUs",
+		m.ID, provider, describe(m.RAM), describe(m.CPU))
+	if maxPrice != 0 {
+		desc += fmt.Sprintf(" for at most $%v an hour", maxPrice)
+	}
+	return desc
 }
 
-func machineTxn(view db.Database, stitch stitch.Stitch) {
+// machineTxn updates the machines of `namespace` to match `stitch`, and returns the
+// problems found with the stitch's machines, as described by toDBMachine.
+func machineTxn(view db.Database, namespace string, stitch stitch.Stitch) (
+	warnings, unsatisfiable []string) {
+
 	// XXX: How best to deal with machines that don't specify enough information?
 	maxPrice := stitch.MaxPrice
-	stitchMachines := toDBMachine(stitch.Machines, maxPrice)
+	stitchMachines, warnings, unsatisfiable := toDBMachine(stitch.Machines,
+		maxPrice)
 
-	dbMachines := view.SelectFromMachine(nil)
+	// Terminating machines are about to be reclaimed by their cloud provider, so
+	// they're left out of the join to boot their replacements early.  The cluster
+	// removes them once they're gone.
+	dbMachines := view.SelectFromMachine(func(m db.Machine) bool {
+		return m.Namespace == namespace && !m.Terminating
+	})
 
 	scoreFun := func(left, right interface{}) int {
 		stitchMachine := left.(db.Machine)
@@ -151,6 +271,8 @@ func machineTxn(view db.Database, stitch stitch.Stitch) {
 			return -1
 		case dbMachine.DiskSize != stitchMachine.DiskSize:
 			return -1
+		case dbMachine.Preemptible != stitchMachine.Preemptible:
+			return -1
 		case dbMachine.PrivateIP == "":
 			return 2
 		case dbMachine.PublicIP == "":
@@ -177,6 +299,7 @@ func machineTxn(view db.Database, stitch stitch.Stitch) {
 		stitchMachine := pair.L.(db.Machine)
 		dbMachine := pair.R.(db.Machine)
 
+		dbMachine.Namespace = namespace
 		dbMachine.StitchID = stitchMachine.StitchID
 		dbMachine.Role = stitchMachine.Role
 		dbMachine.Size = stitchMachine.Size
@@ -185,8 +308,11 @@ func machineTxn(view db.Database, stitch stitch.Stitch) {
 		dbMachine.Region = stitchMachine.Region
 		dbMachine.SSHKeys = stitchMachine.SSHKeys
 		dbMachine.FloatingIP = stitchMachine.FloatingIP
+		dbMachine.Preemptible = stitchMachine.Preemptible
+		dbMachine.SpotPrice = stitchMachine.SpotPrice
 		view.Commit(dbMachine)
 	}
+	return warnings, unsatisfiable
 }
 
 func resolveACLs(acls []string) []string {
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/NetSys/quilt/db"
@@ -22,11 +23,11 @@ func TestEngine(t *testing.T) {
 		deployment.deploy(baseMachine.asWorker().replicate(3));`
 
 	updateStitch(t, conn, prog(t, code))
-	acl, err := selectACL(conn)
+	acl, err := selectACL(conn, "namespace")
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(acl.Admin))
 
-	masters, workers := selectMachines(conn)
+	masters, workers := selectMachines(conn, "namespace")
 	assert.Equal(t, 2, len(masters))
 	assert.Equal(t, 3, len(workers))
 
@@ -35,7 +36,7 @@ func TestEngine(t *testing.T) {
 		deployment.deploy(baseMachine.asWorker().replicate(5));`
 
 	updateStitch(t, conn, prog(t, code))
-	masters, workers = selectMachines(conn)
+	masters, workers = selectMachines(conn, "namespace")
 	assert.Equal(t, 4, len(masters))
 	assert.Equal(t, 5, len(workers))
 
@@ -70,7 +71,7 @@ func TestEngine(t *testing.T) {
 		deployment.deploy(baseMachine.asWorker());`
 	updateStitch(t, conn, prog(t, code))
 
-	masters, workers = selectMachines(conn)
+	masters, workers = selectMachines(conn, "namespace")
 
 	assert.Equal(t, 1, len(masters))
 	assert.Equal(t, "1", masters[0].CloudID)
@@ -82,12 +83,12 @@ func TestEngine(t *testing.T) {
 	assert.Equal(t, "2", workers[0].PublicIP)
 	assert.Equal(t, "3", workers[0].PrivateIP)
 
-	/* Empty Namespace does nothing. */
-	code = pre + `deployment.namespace = "";
+	/* Deploying to another namespace leaves this one alone. */
+	code = pre + `deployment.namespace = "other";
 		deployment.deploy(baseMachine.asMaster());
 		deployment.deploy(baseMachine.asWorker());`
 	updateStitch(t, conn, prog(t, code))
-	masters, workers = selectMachines(conn)
+	masters, workers = selectMachines(conn, "namespace")
 
 	assert.Equal(t, 1, len(masters))
 	assert.Equal(t, "1", masters[0].CloudID)
@@ -102,7 +103,7 @@ func TestEngine(t *testing.T) {
 	/* Verify things go to zero. */
 	code = pre + `deployment.deploy(baseMachine.asWorker())`
 	updateStitch(t, conn, prog(t, code))
-	masters, workers = selectMachines(conn)
+	masters, workers = selectMachines(conn, "namespace")
 	assert.Zero(t, len(masters))
 	assert.Zero(t, len(workers))
 
@@ -126,7 +127,7 @@ func TestEngine(t *testing.T) {
 		new Machine({provider: "Amazon", size: "m4.large", role: "Worker"}),
 		new Machine({provider: "Google", size: "g.large", role: "Worker"})]);`
 	updateStitch(t, conn, prog(t, code))
-	masters, workers = selectMachines(conn)
+	masters, workers = selectMachines(conn, "default-namespace")
 	assert.True(t, providersInSlice(masters,
 		db.ProviderSlice{db.Amazon, db.Vagrant}))
 	assert.True(t, providersInSlice(workers, db.ProviderSlice{db.Amazon, db.Google}))
@@ -136,7 +137,7 @@ func TestEngine(t *testing.T) {
 		new Machine({provider: "Amazon", size: "m4.large", role: "Master"}),
 		new Machine({provider: "Amazon", size: "m4.large", role: "Worker"})]);`
 	updateStitch(t, conn, prog(t, code))
-	masters, _ = selectMachines(conn)
+	masters, _ = selectMachines(conn, "default-namespace")
 	assert.True(t, providersInSlice(masters, db.ProviderSlice{db.Amazon}))
 }
 
@@ -185,6 +186,76 @@ func TestSort(t *testing.T) {
 	})
 }
 
+func TestTerminating(t *testing.T) {
+	pre := `var baseMachine = new Machine({provider: "Amazon", size: "m4.large"});
+	deployment.deploy(baseMachine.asMaster());`
+	conn := db.New()
+
+	updateStitch(t, conn, prog(t, pre+
+		`deployment.deploy(baseMachine.asWorker().replicate(2));`))
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		for _, m := range view.SelectFromMachine(nil) {
+			if m.Role == db.Worker {
+				m.CloudID = "doomed"
+				m.Terminating = true
+				view.Commit(m)
+				break
+			}
+		}
+		return nil
+	})
+
+	// A replacement is booted for the terminating worker, which is left alone.
+	updateStitch(t, conn, prog(t, pre+
+		`deployment.deploy(baseMachine.asWorker().replicate(2));`))
+	_, workers := selectMachines(conn, "default-namespace")
+	assert.Len(t, workers, 3)
+
+	var terminating []db.Machine
+	for _, m := range workers {
+		if m.Terminating {
+			terminating = append(terminating, m)
+		}
+	}
+	assert.Len(t, terminating, 1)
+	assert.Equal(t, "doomed", terminating[0].CloudID)
+
+	// Even if it's no longer in the stitch.
+	updateStitch(t, conn, prog(t, pre))
+	_, workers = selectMachines(conn, "default-namespace")
+	assert.Len(t, workers, 1)
+	assert.True(t, workers[0].Terminating)
+}
+
+func TestPreemptibleSize(t *testing.T) {
+	master := stitch.Machine{ID: "master", Provider: "Google", Role: "Master",
+		Size: "n1-standard-1"}
+	worker := stitch.Machine{ID: "worker", Provider: "Google", Role: "Worker",
+		RAM: stitch.Range{Min: 3}}
+
+	// Only preemptible machines fit within the budget.
+	_, warnings, unsatisfiable := toDBMachine(
+		[]stitch.Machine{master, worker}, 0.02)
+	assert.Empty(t, warnings)
+	assert.Equal(t, []string{"Machine worker: no Google size has at least 3 GB " +
+		"of RAM and at least 0 CPUs for at most $0.02 an hour"}, unsatisfiable)
+
+	worker.Preemptible = true
+	machines, warnings, unsatisfiable := toDBMachine(
+		[]stitch.Machine{master, worker}, 0.02)
+	assert.Empty(t, warnings)
+	assert.Empty(t, unsatisfiable)
+	assert.Len(t, machines, 2)
+	assert.Equal(t, "n1-standard-1", machines[1].Size)
+	assert.True(t, machines[1].Preemptible)
+
+	worker.RAM.Min = 8
+	_, _, unsatisfiable = toDBMachine([]stitch.Machine{master, worker}, 0.02)
+	assert.Equal(t, []string{"Machine worker: no preemptible Google size has " +
+		"at least 8 GB of RAM and at least 0 CPUs for at most $0.02 an hour"},
+		unsatisfiable)
+}
+
 func TestACLs(t *testing.T) {
 	conn := db.New()
 
@@ -198,7 +269,7 @@ func TestACLs(t *testing.T) {
 		return "5.6.7.8", nil
 	}
 	updateStitch(t, conn, prog(t, code))
-	acl, err := selectACL(conn)
+	acl, err := selectACL(conn, "default-namespace")
 	assert.Nil(t, err)
 	assert.Equal(t, []string{"1.2.3.4/32", "5.6.7.8/32"}, acl.Admin)
 
@@ -206,11 +277,49 @@ func TestACLs(t *testing.T) {
 		return "", errors.New("")
 	}
 	updateStitch(t, conn, prog(t, code))
-	acl, err = selectACL(conn)
+	acl, err = selectACL(conn, "default-namespace")
 	assert.Nil(t, err)
 	assert.Equal(t, []string{"1.2.3.4/32"}, acl.Admin)
 }
 
+func TestNamespaces(t *testing.T) {
+	conn := db.New()
+
+	deploy := func(namespace string, workers int) {
+		updateStitch(t, conn, prog(t, fmt.Sprintf(`
+		var deployment = createDeployment({
+			namespace: "%s",
+			adminACL: ["%s"],
+		});
+		var baseMachine = new Machine({provider: "Amazon", size: "m4.large"});
+		deployment.deploy(baseMachine.asMaster());
+		deployment.deploy(baseMachine.asWorker().replicate(%d));`,
+			namespace, namespace, workers)))
+	}
+
+	countMachines := func(namespace string) int {
+		return len(conn.SelectFromMachine(func(m db.Machine) bool {
+			return m.Namespace == namespace
+		}))
+	}
+
+	deploy("staging", 1)
+	deploy("production", 3)
+	assert.Equal(t, 2, countMachines("staging"))
+	assert.Equal(t, 4, countMachines("production"))
+
+	// Changing one namespace leaves the other alone.
+	deploy("staging", 2)
+	assert.Equal(t, 3, countMachines("staging"))
+	assert.Equal(t, 4, countMachines("production"))
+
+	for _, namespace := range []string{"staging", "production"} {
+		acl, err := selectACL(conn, namespace)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{namespace}, acl.Admin)
+	}
+}
+
 func prog(t *testing.T, code string) stitch.Stitch {
 	result, err := stitch.FromJavascript(code, stitch.DefaultImportGetter)
 	if err != nil {
@@ -221,22 +330,22 @@ func prog(t *testing.T, code string) stitch.Stitch {
 	return result
 }
 
-func selectMachines(conn db.Conn) (masters, workers []db.Machine) {
+func selectMachines(conn db.Conn, namespace string) (masters, workers []db.Machine) {
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
 		masters = view.SelectFromMachine(func(m db.Machine) bool {
-			return m.Role == db.Master
+			return m.Namespace == namespace && m.Role == db.Master
 		})
 		workers = view.SelectFromMachine(func(m db.Machine) bool {
-			return m.Role == db.Worker
+			return m.Namespace == namespace && m.Role == db.Worker
 		})
 		return nil
 	})
 	return
 }
 
-func selectACL(conn db.Conn) (acl db.ACL, err error) {
+func selectACL(conn db.Conn, namespace string) (acl db.ACL, err error) {
 	err = conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		acl, err = view.GetACL()
+		acl, err = view.GetACL(namespace)
 		return err
 	})
 	return
@@ -244,9 +353,10 @@ func selectACL(conn db.Conn) (acl db.ACL, err error) {
 
 func updateStitch(t *testing.T, conn db.Conn, stitch stitch.Stitch) {
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		cluster, err := view.GetCluster()
+		cluster, err := view.GetCluster(stitch.Namespace)
 		if err != nil {
 			cluster = view.InsertCluster()
+			cluster.Namespace = stitch.Namespace
 		}
 		cluster.Spec = stitch.String()
 		view.Commit(cluster)
@@ -254,3 +364,90 @@ func updateStitch(t *testing.T, conn db.Conn, stitch stitch.Stitch) {
 	})
 	assert.Nil(t, conn.Txn(db.AllTables...).Run(updateTxn))
 }
+
+func TestStatus(t *testing.T) {
+	conn := db.New()
+	pre := `var deployment = createDeployment({namespace: "ns"});
+	var baseMachine = new Machine({provider: "Amazon", size: "m4.large"});`
+
+	selectStatus := func() (status db.Status) {
+		conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+			var err error
+			status, err = view.GetStatus("ns")
+			assert.NoError(t, err)
+			return nil
+		})
+		return status
+	}
+
+	updateStitch(t, conn, prog(t, pre+`deployment.deploy(baseMachine.asMaster());`))
+	status := selectStatus()
+	assert.Equal(t, db.Blocked, status.State)
+	assert.Equal(t, []string{"A Master was specified but no workers."},
+		status.Warnings)
+
+	updateStitch(t, conn, prog(t, pre+`deployment.deploy(baseMachine.asMaster());
+		deployment.deploy(new Machine({provider: "DigitalOcean", role: "Worker",
+			size: "s-1vcpu-1gb", preemptible: true}));`))
+	status = selectStatus()
+	assert.Equal(t, db.Blocked, status.State)
+	assert.Len(t, status.Warnings, 1)
+	assert.Contains(t, status.Warnings[0],
+		"DigitalOcean doesn't support preemptible machines")
+
+	updateStitch(t, conn, prog(t, pre+`deployment.deploy(baseMachine.asMaster());
+		deployment.deploy(new Machine({provider: "Amazon", role: "Worker",
+			ram: new Range(100000)}));`))
+	status = selectStatus()
+	assert.Equal(t, db.Blocked, status.State)
+	assert.Nil(t, status.Warnings)
+	assert.Len(t, status.UnsatisfiableMachines, 1)
+	assert.Contains(t, status.UnsatisfiableMachines[0],
+		"no Amazon size has at least 100000 GB of RAM")
+
+	updateStitch(t, conn, prog(t, pre+`deployment.deploy(baseMachine.asMaster());
+		deployment.deploy(baseMachine.asWorker());`))
+	assert.Equal(t, db.Status{ID: status.ID, Namespace: "ns", State: db.Converging},
+		selectStatus())
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		for _, m := range view.SelectFromMachine(nil) {
+			m.Connected = true
+			view.Commit(m)
+		}
+		return nil
+	})
+	assert.Nil(t, conn.Txn(db.AllTables...).Run(updateTxn))
+	assert.Equal(t, db.Converged, selectStatus().State)
+
+	// The foreman's fields are kept, and taken into account.
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		status, _ := view.GetStatus("ns")
+		status.UnschedulableContainers = []string{"1 (alpine)"}
+		view.Commit(status)
+		return nil
+	})
+	assert.Nil(t, conn.Txn(db.AllTables...).Run(updateTxn))
+	status = selectStatus()
+	assert.Equal(t, db.Blocked, status.State)
+	assert.Equal(t, []string{"1 (alpine)"}, status.UnschedulableContainers)
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		cluster, _ := view.GetCluster("ns")
+		cluster.Spec = "invalid"
+		view.Commit(cluster)
+		return nil
+	})
+	assert.Nil(t, conn.Txn(db.AllTables...).Run(updateTxn))
+	status = selectStatus()
+	assert.Equal(t, db.Invalid, status.State)
+	assert.Len(t, status.Warnings, 1)
+
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		cluster, _ := view.GetCluster("ns")
+		view.Remove(cluster)
+		return nil
+	})
+	assert.Nil(t, conn.Txn(db.AllTables...).Run(updateTxn))
+	assert.Empty(t, conn.SelectFromStatus(nil))
+}
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"reflect"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/join"
+	"github.com/NetSys/quilt/minion/policy"
+	"github.com/NetSys/quilt/stitch"
+)
+
+// NewPlan computes the changes that deploying `spec` to its namespace would make,
+// without making them.  The engine, and the policy the minions apply to their
+// containers, are run against a scratch copy of the database, and the result compared
+// to what was there before.
+func NewPlan(conn db.Conn, spec stitch.Stitch) db.Plan {
+	var plan db.Plan
+	conn.Copy().Txn(db.AllTables...).Run(func(view db.Database) error {
+		// The daemon doesn't track the containers and connections implemented
+		// by the minions, so derive them from the currently deployed stitch.
+		if cluster, err := view.GetCluster(spec.Namespace); err == nil {
+			policy.Update(view, cluster.Spec)
+		}
+
+		inNamespace := func(m db.Machine) bool {
+			return m.Namespace == spec.Namespace
+		}
+		machines := view.SelectFromMachine(inNamespace)
+		containers := view.SelectFromContainer(nil)
+		connections := view.SelectFromConnection(nil)
+		acl, _ := view.GetACL(spec.Namespace)
+
+		machineTxn(view, spec.Namespace, spec)
+		aclTxn(view, spec.Namespace, spec)
+		policy.Update(view, spec.String())
+
+		add, remove := diff(view.SelectFromMachine(inNamespace), machines,
+			func(l, r interface{}) bool {
+				return l.(db.Machine).ID == r.(db.Machine).ID
+			})
+		for _, m := range add {
+			plan.BootMachines = append(plan.BootMachines, m.(db.Machine))
+		}
+		for _, m := range remove {
+			plan.TerminateMachines = append(plan.TerminateMachines,
+				m.(db.Machine))
+		}
+
+		// Minions restart containers whose image, command or environment
+		// changes.
+		add, remove = diff(view.SelectFromContainer(nil), containers,
+			func(l, r interface{}) bool {
+				lc, rc := l.(db.Container), r.(db.Container)
+				return lc.ID == rc.ID && lc.Image == rc.Image &&
+					reflect.DeepEqual(lc.Command, rc.Command) &&
+					reflect.DeepEqual(lc.Env, rc.Env)
+			})
+		for _, c := range add {
+			plan.StartContainers = append(plan.StartContainers,
+				c.(db.Container))
+		}
+		for _, c := range remove {
+			plan.StopContainers = append(plan.StopContainers,
+				c.(db.Container))
+		}
+
+		add, remove = diff(view.SelectFromConnection(nil), connections,
+			reflect.DeepEqual)
+		for _, c := range add {
+			plan.AddConnections = append(plan.AddConnections,
+				c.(db.Connection))
+		}
+		for _, c := range remove {
+			plan.RemoveConnections = append(plan.RemoveConnections,
+				c.(db.Connection))
+		}
+
+		newACL, _ := view.GetACL(spec.Namespace)
+		add, remove = diff(newACL.Admin, acl.Admin, reflect.DeepEqual)
+		for _, admin := range add {
+			plan.AddAdminACLs = append(plan.AddAdminACLs, admin.(string))
+		}
+		for _, admin := range remove {
+			plan.RemoveAdminACLs = append(plan.RemoveAdminACLs,
+				admin.(string))
+		}
+
+		add, remove = diff(newACL.ApplicationPorts, acl.ApplicationPorts,
+			reflect.DeepEqual)
+		for _, pr := range add {
+			plan.AddApplicationPorts = append(plan.AddApplicationPorts,
+				pr.(db.PortRange))
+		}
+		for _, pr := range remove {
+			plan.RemoveApplicationPorts = append(
+				plan.RemoveApplicationPorts, pr.(db.PortRange))
+		}
+		return nil
+	})
+	return plan
+}
+
+// diff returns the elements of `after` that don't match an element of `before`, and
+// the elements of `before` that don't match an element of `after`.
+func diff(after, before interface{}, match func(l, r interface{}) bool) (
+	added, removed []interface{}) {
+
+	_, added, removed = join.Join(after, before, func(l, r interface{}) int {
+		if match(l, r) {
+			return 0
+		}
+		return -1
+	})
+	return added, removed
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlan(t *testing.T) {
+	pre := `var deployment = createDeployment({adminACL: ["1.2.3.4/32"]});
+	var baseMachine = new Machine({provider: "Amazon", size: "m4.large"});
+	deployment.deploy(baseMachine.asMaster());`
+	conn := db.New()
+
+	code := pre + `deployment.deploy(baseMachine.asWorker());
+		var red = new Service("red", [new Container("alpine", ["tail"])]);
+		deployment.deploy(red);`
+	updateStitch(t, conn, prog(t, code))
+	before := conn.SelectFromMachine(nil)
+
+	// Nothing changes if the stitch doesn't.
+	assert.True(t, NewPlan(conn, prog(t, code)).Empty())
+
+	code = pre + `deployment.deploy(baseMachine.asWorker().replicate(2));
+		var blue = new Service("blue", [new Container("nginx")]);
+		publicInternet.connect(80, blue);
+		deployment.deploy(blue);`
+	plan := NewPlan(conn, prog(t, code))
+
+	assert.Len(t, plan.BootMachines, 1)
+	assert.Equal(t, db.Role(db.Worker), plan.BootMachines[0].Role)
+	assert.Empty(t, plan.TerminateMachines)
+
+	assert.Len(t, plan.StartContainers, 1)
+	assert.Equal(t, "nginx", plan.StartContainers[0].Image)
+	assert.Len(t, plan.StopContainers, 1)
+	assert.Equal(t, "alpine", plan.StopContainers[0].Image)
+
+	assert.Len(t, plan.AddConnections, 1)
+	assert.Equal(t, "public", plan.AddConnections[0].From)
+	assert.Empty(t, plan.RemoveConnections)
+
+	assert.Empty(t, plan.AddAdminACLs)
+	assert.Empty(t, plan.RemoveAdminACLs)
+	assert.Equal(t, []db.PortRange{{MinPort: 80, MaxPort: 80}},
+		plan.AddApplicationPorts)
+	assert.Empty(t, plan.RemoveApplicationPorts)
+
+	// The plan doesn't affect the real database.
+	assert.Equal(t, db.SortMachines(before),
+		db.SortMachines(conn.SelectFromMachine(nil)))
+	assert.Empty(t, conn.SelectFromContainer(nil))
+
+	assert.Equal(t, plan, db.PBToPlan(db.PlanToPB(plan)))
+}
@@ -0,0 +1,56 @@
+// Package metrics serves the Prometheus metrics collected by the Quilt daemon and
+// minions.  The metrics themselves are defined and registered by the packages that
+// collect them.
+package metrics
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/NetSys/quilt/api"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultPort is the port on which minions serve their metrics.
+const DefaultPort = 9099
+
+// DefaultMinionAddress is the address on which minions serve their metrics.
+var DefaultMinionAddress = fmt.Sprintf("tcp://0.0.0.0:%d", DefaultPort)
+
+// Run serves the registered metrics at /metrics on `listenAddr`, which has the same
+// form as the API's listen addresses, e.g. tcp://0.0.0.0:9099.  If `config` isn't nil,
+// they're served over TLS with that configuration.  Otherwise they're served in
+// plaintext, which is only appropriate for local addresses.
+func Run(listenAddr string, config *tls.Config) error {
+	proto, addr, err := api.ParseListenAddress(listenAddr)
+	if err != nil {
+		return err
+	}
+
+	var sock net.Listener
+	for {
+		sock, err = net.Listen(proto, addr)
+		if err == nil {
+			break
+		}
+		log.WithError(err).Error("Failed to open metrics socket.")
+
+		time.Sleep(30 * time.Second)
+	}
+
+	if config != nil {
+		sock = tls.NewListener(sock, config)
+	}
+	return http.Serve(sock, newHandler())
+}
+
+func newHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NetSys/quilt/util"
+)
+
+func TestHandler(t *testing.T) {
+	// The metrics are global, so each run of the test uses a loop of its own.
+	loop := fmt.Sprintf("Test%d", time.Now().UnixNano())
+	timer := util.NewEventTimer(loop)
+	timer.LogStart()
+	timer.LogEnd()
+
+	server := httptest.NewServer(newHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	for _, exp := range []string{
+		fmt.Sprintf(`quilt_loop_triggers_total{loop="%s"} 1`, loop),
+		fmt.Sprintf(`quilt_loop_duration_seconds_count{loop="%s"} 1`, loop),
+	} {
+		if !strings.Contains(string(body), exp) {
+			t.Errorf("Metrics missing %q:\n%s", exp, body)
+		}
+	}
+}
+
+func TestRunBadAddress(t *testing.T) {
+	if err := Run("9099", nil); err == nil {
+		t.Error("Expected an error for a malformed listen address")
+	}
+}
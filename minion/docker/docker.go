@@ -1,6 +1,8 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"errors"
 	"strings"
 	"sync"
@@ -67,6 +69,16 @@ type RunOptions struct {
 	PidMode     string
 	Privileged  bool
 	VolumesFrom []string
+
+	// Files are written into the container before it starts.
+	Files []File
+}
+
+// A File written into a container by Run.
+type File struct {
+	Path     string
+	Contents string
+	Mode     int64
 }
 
 type client interface {
@@ -132,6 +144,11 @@ func (dk Client) Run(opts RunOptions) (string, error) {
 		return "", err
 	}
 
+	if err = dk.upload(id, opts.Files); err != nil {
+		dk.RemoveID(id)
+		return "", err
+	}
+
 	if err = dk.StartContainer(id, hc); err != nil {
 		dk.RemoveID(id) // Remove the container to avoid a zombie.
 		return "", err
@@ -338,6 +355,37 @@ func (dk Client) create(name, image string, args []string, labels map[string]str
 	return container.ID, nil
 }
 
+// upload writes `files` into the container `id`.
+func (dk Client) upload(id string, files []File) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name: strings.TrimPrefix(f.Path, "/"),
+			Mode: f.Mode,
+			Size: int64(len(f.Contents)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write([]byte(f.Contents)); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return dk.UploadToContainer(id, dkc.UploadToContainerOptions{
+		InputStream: &buf,
+		Path:        "/",
+	})
+}
+
 func (dk Client) getID(name string) (string, error) {
 	containers, err := dk.list(map[string][]string{"name": {name}}, true)
 	if err != nil {
@@ -222,6 +222,20 @@ func TestRunEnv(t *testing.T) {
 	assert.Equal(t, env, container.Env)
 }
 
+func TestRunFiles(t *testing.T) {
+	t.Parallel()
+	md, dk := NewMock()
+
+	id, err := dk.Run(RunOptions{Name: "name1", Files: []File{
+		{Path: "/etc/a", Contents: "a", Mode: 0644},
+		{Path: "/etc/b/c", Contents: "c", Mode: 0600},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"/etc/a": "a", "/etc/b/c": "c"},
+		md.Containers[id].Files)
+	assert.True(t, md.Containers[id].Running)
+}
+
 func TestConfigureNetwork(t *testing.T) {
 	md, dk := NewMock()
 
@@ -1,7 +1,11 @@
 package docker
 
 import (
+	"archive/tar"
 	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -12,6 +16,9 @@ import (
 type mockContainer struct {
 	*dkc.Container
 	Running bool
+
+	// The contents of the files uploaded to the container, keyed by path.
+	Files map[string]string
 }
 
 // MockClient gives unit testers access to the internals of the mock docker client
@@ -130,11 +137,27 @@ func (dk MockClient) ListContainers(opts dkc.ListContainersOptions) ([]dkc.APICo
 			continue
 		}
 
+		labels := opts.Filters["label"]
+		if len(labels) > 0 && !hasLabels(container.Config.Labels, labels) {
+			continue
+		}
+
 		apics = append(apics, dkc.APIContainers{ID: id})
 	}
 	return apics, nil
 }
 
+// hasLabels returns true if `labels` matches each of the "key=value" `filters`.
+func hasLabels(labels map[string]string, filters []string) bool {
+	for _, filter := range filters {
+		kv := strings.SplitN(filter, "=", 2)
+		if len(kv) != 2 || labels[kv[0]] != kv[1] {
+			return false
+		}
+	}
+	return true
+}
+
 // CreateNetwork creates a network according to opts.
 func (dk MockClient) CreateNetwork(opts dkc.CreateNetworkOptions) (*dkc.Network, error) {
 	dk.Lock()
@@ -199,7 +222,7 @@ func (dk *MockClient) CreateContainer(opts dkc.CreateContainerOptions) (*dkc.Con
 		HostConfig:      opts.HostConfig,
 		NetworkSettings: &dkc.NetworkSettings{},
 	}
-	dk.Containers[id] = mockContainer{container, false}
+	dk.Containers[id] = mockContainer{Container: container}
 	return container, nil
 }
 
@@ -246,10 +269,40 @@ func (dk *MockClient) ResetExec() {
 	dk.Executions = map[string][]string{}
 }
 
-// UploadToContainer is not implemented.
+// UploadToContainer extracts the tar archive in `opts` into the container's Files.
 func (dk MockClient) UploadToContainer(id string,
 	opts dkc.UploadToContainerOptions) error {
-	panic("MockClient Not Implemented")
+	dk.Lock()
+	defer dk.Unlock()
+
+	container, ok := dk.Containers[id]
+	if !ok {
+		return ErrNoSuchContainer
+	}
+
+	if container.Files == nil {
+		container.Files = map[string]string{}
+	}
+
+	tr := tar.NewReader(opts.InputStream)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		contents, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(opts.Path, hdr.Name)
+		container.Files[path] = string(contents)
+	}
+
+	dk.Containers[id] = container
+	return nil
 }
 
 // DownloadFromContainer is not implemented.
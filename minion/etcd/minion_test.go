@@ -56,7 +56,8 @@ func TestWriteMinion(t *testing.T) {
     "Provider": "Amazon",
     "Size": "Big",
     "Region": "Somewhere",
-    "FloatingIP": ""
+    "FloatingIP": "",
+    "Terminating": false
 }`
 	assert.Equal(t, expVal, val)
 }
@@ -11,10 +11,20 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const dnsTTL = 60 // Seconds
 
+var dnsQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "quilt_dns_queries_total",
+	Help: "The number of DNS queries received, by how they were handled.",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(dnsQueries)
+}
+
 type dnsTable struct {
 	server dns.Server
 
@@ -82,6 +92,7 @@ func (table *dnsTable) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
 	log.Debug("DNS Request: ", req)
 
 	resp := table.genResponse(req)
+	dnsQueries.WithLabelValues(dnsResult(resp)).Inc()
 	if resp == nil {
 		return
 	}
@@ -132,6 +143,19 @@ func (table *dnsTable) genResponse(req *dns.Msg) *dns.Msg {
 	return resp
 }
 
+// dnsResult describes how a query was handled given the response to it, which is nil
+// if the query went unanswered.
+func dnsResult(resp *dns.Msg) string {
+	switch {
+	case resp == nil:
+		return "unanswered"
+	case resp.Rcode == dns.RcodeNotImplemented:
+		return "not_implemented"
+	default:
+		return "answered"
+	}
+}
+
 func (table *dnsTable) lookupA(name string) []net.IP {
 	if strings.HasSuffix(name, ".q.") {
 		table.recordLock.Lock()
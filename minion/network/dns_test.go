@@ -43,6 +43,7 @@ func TestGenResponse(t *testing.T) {
 	resp := table.genResponse(req)
 	assert.Equal(t, req.Id, resp.Id)
 	assert.Equal(t, resp.Rcode, dns.RcodeNotImplemented)
+	assert.Equal(t, "not_implemented", dnsResult(resp))
 
 	req.Question = nil
 	resp = table.genResponse(req)
@@ -52,6 +53,7 @@ func TestGenResponse(t *testing.T) {
 	req.SetQuestion("bad.q.", dns.TypeA)
 	resp = table.genResponse(req)
 	assert.Nil(t, resp)
+	assert.Equal(t, "unanswered", dnsResult(resp))
 
 	req.SetQuestion("a.q.", dns.TypeA)
 	resp = table.genResponse(req)
@@ -68,6 +70,7 @@ func TestGenResponse(t *testing.T) {
 		A: net.IPv4(1, 2, 3, 4),
 	}}
 	assert.Equal(t, &exp, resp)
+	assert.Equal(t, "answered", dnsResult(resp))
 
 }
 
@@ -6,9 +6,19 @@ import (
 	"math"
 	"reflect"
 
+	"github.com/prometheus/client_golang/prometheus"
 	ovs "github.com/socketplane/libovsdb"
 )
 
+var transactionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "quilt_ovsdb_transaction_errors_total",
+	Help: "The number of OVSDB transactions that failed, by database.",
+}, []string{"database"})
+
+func init() {
+	prometheus.MustRegister(transactionErrors)
+}
+
 // This is simply a wrapper around all the libovsdb calls we are currently using. This
 // makes it very easy to mock for testing.
 type ovsdbAPI interface {
@@ -34,6 +44,18 @@ func (client ovsdbClient) transact(db string, operation ...ovs.Operation) (
 	return client.Transact(db, operation...)
 }
 
+// transact runs `operation` against `db`, counting the transactions that fail either
+// outright or because one of their operations did.
+func (ovsdb Client) transact(db string, operation ...ovs.Operation) (
+	[]ovs.OperationResult, error) {
+
+	results, err := ovsdb.ovsdbAPI.transact(db, operation...)
+	if err != nil || errorCheck(results, len(operation)) != nil {
+		transactionErrors.WithLabelValues(db).Inc()
+	}
+	return results, err
+}
+
 // LPort is a logical port in OVN.
 type LPort struct {
 	uuid      ovs.UUID
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/NetSys/quilt/join"
+	dto "github.com/prometheus/client_model/go"
 	ovs "github.com/socketplane/libovsdb"
 	"github.com/stretchr/testify/assert"
 )
@@ -460,3 +461,36 @@ func (slc addressSlice) Len() int {
 func (slc addressSlice) Get(i int) interface{} {
 	return slc[i]
 }
+
+type failingOvsdbClient struct {
+	fakeOvsdbClient
+	err     error
+	results []ovs.OperationResult
+}
+
+func (client failingOvsdbClient) transact(database string,
+	operation ...ovs.Operation) ([]ovs.OperationResult, error) {
+	return client.results, client.err
+}
+
+func TestTransactionErrors(t *testing.T) {
+	errorCount := func() float64 {
+		var m dto.Metric
+		transactionErrors.WithLabelValues("Errors_Test").Write(&m)
+		return m.GetCounter().GetValue()
+	}
+	op := ovs.Operation{Op: "select", Table: "Logical_Switch"}
+
+	client := Client{failingOvsdbClient{results: []ovs.OperationResult{{}}}}
+	client.transact("Errors_Test", op)
+	assert.Equal(t, 0.0, errorCount())
+
+	client = Client{failingOvsdbClient{err: assert.AnError}}
+	client.transact("Errors_Test", op)
+	assert.Equal(t, 1.0, errorCount())
+
+	client = Client{failingOvsdbClient{
+		results: []ovs.OperationResult{{Error: "constraint violation"}}}}
+	client.transact("Errors_Test", op)
+	assert.Equal(t, 2.0, errorCount())
+}
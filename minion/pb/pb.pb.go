@@ -12,6 +12,12 @@ It has these top-level messages:
 	MinionConfig
 	Reply
 	Request
+	VersionInfo
+	EventRequest
+	EventReply
+	EventInfo
+	StatusInfo
+	Certificate
 */
 package pb
 
@@ -69,6 +75,9 @@ type MinionConfig struct {
 	Region         string            `protobuf:"bytes,7,opt,name=Region,json=region" json:"Region,omitempty"`
 	EtcdMembers    []string          `protobuf:"bytes,8,rep,name=EtcdMembers,json=etcdMembers" json:"EtcdMembers,omitempty"`
 	AuthorizedKeys []string          `protobuf:"bytes,9,rep,name=AuthorizedKeys,json=authorizedKeys" json:"AuthorizedKeys,omitempty"`
+	// Whether the cloud provider is about to reclaim the minion's machine.  Only
+	// reported by the minion, it's ignored by SetMinionConfig.
+	Terminating bool `protobuf:"varint,10,opt,name=Terminating,json=terminating" json:"Terminating,omitempty"`
 }
 
 func (m *MinionConfig) Reset()                    { *m = MinionConfig{} }
@@ -139,6 +148,13 @@ func (m *MinionConfig) GetAuthorizedKeys() []string {
 	return nil
 }
 
+func (m *MinionConfig) GetTerminating() bool {
+	if m != nil {
+		return m.Terminating
+	}
+	return false
+}
+
 type Reply struct {
 }
 
@@ -155,10 +171,149 @@ func (m *Request) String() string            { return proto.CompactTextString(m)
 func (*Request) ProtoMessage()               {}
 func (*Request) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
+type VersionInfo struct {
+	Version       string `protobuf:"bytes,1,opt,name=Version,json=version" json:"Version,omitempty"`
+	SchemaVersion int32  `protobuf:"varint,2,opt,name=SchemaVersion,json=schemaVersion" json:"SchemaVersion,omitempty"`
+}
+
+func (m *VersionInfo) Reset()                    { *m = VersionInfo{} }
+func (m *VersionInfo) String() string            { return proto.CompactTextString(m) }
+func (*VersionInfo) ProtoMessage()               {}
+func (*VersionInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *VersionInfo) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *VersionInfo) GetSchemaVersion() int32 {
+	if m != nil {
+		return m.SchemaVersion
+	}
+	return 0
+}
+
+type EventRequest struct {
+	// Only events that happened after this Unix time, in nanoseconds, are returned.
+	After int64 `protobuf:"varint,1,opt,name=After,json=after" json:"After,omitempty"`
+}
+
+func (m *EventRequest) Reset()                    { *m = EventRequest{} }
+func (m *EventRequest) String() string            { return proto.CompactTextString(m) }
+func (*EventRequest) ProtoMessage()               {}
+func (*EventRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *EventRequest) GetAfter() int64 {
+	if m != nil {
+		return m.After
+	}
+	return 0
+}
+
+type EventReply struct {
+	Events []*EventInfo `protobuf:"bytes,1,rep,name=Events,json=events" json:"Events,omitempty"`
+}
+
+func (m *EventReply) Reset()                    { *m = EventReply{} }
+func (m *EventReply) String() string            { return proto.CompactTextString(m) }
+func (*EventReply) ProtoMessage()               {}
+func (*EventReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *EventReply) GetEvents() []*EventInfo {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type EventInfo struct {
+	// The Unix time at which the event happened, in nanoseconds.
+	Time    int64  `protobuf:"varint,1,opt,name=Time,json=time" json:"Time,omitempty"`
+	Type    string `protobuf:"bytes,2,opt,name=Type,json=type" json:"Type,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=Message,json=message" json:"Message,omitempty"`
+}
+
+func (m *EventInfo) Reset()                    { *m = EventInfo{} }
+func (m *EventInfo) String() string            { return proto.CompactTextString(m) }
+func (*EventInfo) ProtoMessage()               {}
+func (*EventInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *EventInfo) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *EventInfo) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *EventInfo) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// The problems that prevent the minion's spec from being implemented.  Only the
+// leading minion reports them.
+type StatusInfo struct {
+	Warnings                []string `protobuf:"bytes,1,rep,name=Warnings,json=warnings" json:"Warnings,omitempty"`
+	UnschedulableContainers []string `protobuf:"bytes,2,rep,name=UnschedulableContainers,json=unschedulableContainers" json:"UnschedulableContainers,omitempty"`
+}
+
+func (m *StatusInfo) Reset()                    { *m = StatusInfo{} }
+func (m *StatusInfo) String() string            { return proto.CompactTextString(m) }
+func (*StatusInfo) ProtoMessage()               {}
+func (*StatusInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *StatusInfo) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+func (m *StatusInfo) GetUnschedulableContainers() []string {
+	if m != nil {
+		return m.UnschedulableContainers
+	}
+	return nil
+}
+
+// A certificate issued by the foreman for the minion's key.
+type Certificate struct {
+	Cert string `protobuf:"bytes,1,opt,name=Cert,json=cert" json:"Cert,omitempty"`
+}
+
+func (m *Certificate) Reset()                    { *m = Certificate{} }
+func (m *Certificate) String() string            { return proto.CompactTextString(m) }
+func (*Certificate) ProtoMessage()               {}
+func (*Certificate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *Certificate) GetCert() string {
+	if m != nil {
+		return m.Cert
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*MinionConfig)(nil), "MinionConfig")
 	proto.RegisterType((*Reply)(nil), "Reply")
 	proto.RegisterType((*Request)(nil), "Request")
+	proto.RegisterType((*VersionInfo)(nil), "VersionInfo")
+	proto.RegisterType((*EventRequest)(nil), "EventRequest")
+	proto.RegisterType((*EventReply)(nil), "EventReply")
+	proto.RegisterType((*EventInfo)(nil), "EventInfo")
+	proto.RegisterType((*StatusInfo)(nil), "StatusInfo")
+	proto.RegisterType((*Certificate)(nil), "Certificate")
 	proto.RegisterEnum("MinionConfig_Role", MinionConfig_Role_name, MinionConfig_Role_value)
 }
 
@@ -175,6 +330,10 @@ const _ = grpc.SupportPackageIsVersion4
 type MinionClient interface {
 	SetMinionConfig(ctx context.Context, in *MinionConfig, opts ...grpc.CallOption) (*Reply, error)
 	GetMinionConfig(ctx context.Context, in *Request, opts ...grpc.CallOption) (*MinionConfig, error)
+	Version(ctx context.Context, in *Request, opts ...grpc.CallOption) (*VersionInfo, error)
+	GetEvents(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*EventReply, error)
+	GetStatus(ctx context.Context, in *Request, opts ...grpc.CallOption) (*StatusInfo, error)
+	SetCertificate(ctx context.Context, in *Certificate, opts ...grpc.CallOption) (*Reply, error)
 }
 
 type minionClient struct {
@@ -203,11 +362,51 @@ func (c *minionClient) GetMinionConfig(ctx context.Context, in *Request, opts ..
 	return out, nil
 }
 
+func (c *minionClient) Version(ctx context.Context, in *Request, opts ...grpc.CallOption) (*VersionInfo, error) {
+	out := new(VersionInfo)
+	err := grpc.Invoke(ctx, "/Minion/Version", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minionClient) GetEvents(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*EventReply, error) {
+	out := new(EventReply)
+	err := grpc.Invoke(ctx, "/Minion/GetEvents", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minionClient) GetStatus(ctx context.Context, in *Request, opts ...grpc.CallOption) (*StatusInfo, error) {
+	out := new(StatusInfo)
+	err := grpc.Invoke(ctx, "/Minion/GetStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minionClient) SetCertificate(ctx context.Context, in *Certificate, opts ...grpc.CallOption) (*Reply, error) {
+	out := new(Reply)
+	err := grpc.Invoke(ctx, "/Minion/SetCertificate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Minion service
 
 type MinionServer interface {
 	SetMinionConfig(context.Context, *MinionConfig) (*Reply, error)
 	GetMinionConfig(context.Context, *Request) (*MinionConfig, error)
+	Version(context.Context, *Request) (*VersionInfo, error)
+	GetEvents(context.Context, *EventRequest) (*EventReply, error)
+	GetStatus(context.Context, *Request) (*StatusInfo, error)
+	SetCertificate(context.Context, *Certificate) (*Reply, error)
 }
 
 func RegisterMinionServer(s *grpc.Server, srv MinionServer) {
@@ -250,6 +449,78 @@ func _Minion_GetMinionConfig_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Minion_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinionServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Minion/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinionServer).Version(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Minion_GetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinionServer).GetEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Minion/GetEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinionServer).GetEvents(ctx, req.(*EventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Minion_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinionServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Minion/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinionServer).GetStatus(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Minion_SetCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Certificate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinionServer).SetCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Minion/SetCertificate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinionServer).SetCertificate(ctx, req.(*Certificate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Minion_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "Minion",
 	HandlerType: (*MinionServer)(nil),
@@ -262,6 +533,22 @@ var _Minion_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetMinionConfig",
 			Handler:    _Minion_GetMinionConfig_Handler,
 		},
+		{
+			MethodName: "Version",
+			Handler:    _Minion_Version_Handler,
+		},
+		{
+			MethodName: "GetEvents",
+			Handler:    _Minion_GetEvents_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _Minion_GetStatus_Handler,
+		},
+		{
+			MethodName: "SetCertificate",
+			Handler:    _Minion_SetCertificate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "minion/pb/pb.proto",
@@ -270,26 +557,42 @@ var _Minion_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("minion/pb/pb.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 321 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x5c, 0x91, 0xcf, 0x6a, 0xea, 0x40,
-	0x14, 0xc6, 0x4d, 0x8c, 0x31, 0x39, 0xde, 0x1b, 0xe5, 0x2c, 0x2e, 0x83, 0xdc, 0x45, 0xc8, 0x42,
-	0x42, 0x29, 0x11, 0xec, 0x13, 0x48, 0x0d, 0x45, 0xc4, 0x3f, 0x8c, 0x85, 0xae, 0x8d, 0x9e, 0xda,
-	0x01, 0xcd, 0x4c, 0x27, 0x51, 0xd0, 0xe7, 0xea, 0x03, 0x16, 0x47, 0x4b, 0x9b, 0xee, 0xe6, 0xfc,
-	0xce, 0xef, 0x1b, 0x98, 0x6f, 0x00, 0xf7, 0x22, 0x17, 0x32, 0xef, 0xab, 0xac, 0xaf, 0xb2, 0x44,
-	0x69, 0x59, 0xca, 0xe8, 0xc3, 0x86, 0x3f, 0x53, 0x83, 0x1f, 0x65, 0xfe, 0x2a, 0xb6, 0x18, 0x80,
-	0x3d, 0x1e, 0x31, 0x2b, 0xb4, 0x62, 0x9f, 0xdb, 0x62, 0x84, 0x3d, 0x70, 0xb4, 0xdc, 0x11, 0xb3,
-	0x43, 0x2b, 0x0e, 0x06, 0x98, 0xfc, 0x94, 0x13, 0x2e, 0x77, 0xc4, 0xcd, 0x1e, 0xff, 0x83, 0xbf,
-	0xd0, 0xe2, 0xb8, 0x2a, 0x69, 0xbc, 0x60, 0x75, 0x13, 0xf7, 0xd5, 0x17, 0x40, 0x04, 0x67, 0xa9,
-	0x68, 0xcd, 0x1c, 0xb3, 0x70, 0x0a, 0x45, 0x6b, 0xec, 0x82, 0xb7, 0xd0, 0xf2, 0x28, 0x36, 0xa4,
-	0x59, 0xc3, 0x70, 0x4f, 0xdd, 0x66, 0xe3, 0x8b, 0x33, 0x31, 0xf7, 0xe6, 0x8b, 0x33, 0xe1, 0x3f,
-	0x70, 0x39, 0x6d, 0x85, 0xcc, 0x59, 0xd3, 0x50, 0x57, 0x9b, 0x09, 0x43, 0x68, 0xa5, 0xe5, 0x7a,
-	0x33, 0xa5, 0x7d, 0x46, 0xba, 0x60, 0x5e, 0x58, 0x8f, 0x7d, 0xde, 0xa2, 0x6f, 0x84, 0x3d, 0x08,
-	0x86, 0x87, 0xf2, 0x4d, 0x6a, 0x71, 0xa6, 0xcd, 0x84, 0x4e, 0x05, 0xf3, 0x8d, 0x14, 0xac, 0x2a,
-	0x34, 0x8a, 0xc1, 0xb9, 0xbc, 0x08, 0x3d, 0x70, 0x66, 0xf3, 0x59, 0xda, 0xa9, 0x21, 0x80, 0xfb,
-	0x32, 0xe7, 0x93, 0x94, 0x77, 0xac, 0xcb, 0x79, 0x3a, 0x5c, 0x3e, 0xa7, 0xbc, 0x63, 0x47, 0x4d,
-	0x68, 0x70, 0x52, 0xbb, 0x53, 0xe4, 0x43, 0x93, 0xd3, 0xfb, 0x81, 0x8a, 0x72, 0x90, 0x81, 0x7b,
-	0x2d, 0x07, 0xef, 0xa0, 0xbd, 0xa4, 0xb2, 0x52, 0xeb, 0xdf, 0x4a, 0x71, 0x5d, 0x37, 0xb9, 0xc6,
-	0x6b, 0x78, 0x0f, 0xed, 0xa7, 0x5f, 0xae, 0x97, 0xdc, 0xae, 0xec, 0x56, 0x53, 0x51, 0x2d, 0x73,
-	0xcd, 0xaf, 0x3d, 0x7c, 0x06, 0x00, 0x00, 0xff, 0xff, 0xe1, 0x0f, 0x6b, 0x6a, 0xcb, 0x01, 0x00,
-	0x00,
+	// 590 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0xc1, 0x6e, 0xda, 0x40,
+	0x10, 0x05, 0x63, 0x8c, 0x3d, 0x06, 0x12, 0x8d, 0xaa, 0xc6, 0x42, 0x3d, 0xd0, 0x6d, 0x14, 0xb9,
+	0x55, 0xe5, 0x54, 0xe9, 0xa5, 0xd7, 0x28, 0x41, 0x51, 0x14, 0x91, 0x44, 0x4b, 0xda, 0x9c, 0x0d,
+	0x0c, 0x64, 0x25, 0xbc, 0x76, 0xd7, 0x0b, 0x15, 0x39, 0xf6, 0x23, 0xfb, 0x3d, 0x95, 0xd7, 0x4e,
+	0x30, 0x55, 0x6f, 0x7e, 0x6f, 0xde, 0x8e, 0x66, 0xde, 0x1b, 0x03, 0x26, 0x42, 0x8a, 0x54, 0x9e,
+	0x66, 0xd3, 0xd3, 0x6c, 0x1a, 0x65, 0x2a, 0xd5, 0x29, 0xfb, 0x63, 0x41, 0x77, 0x6c, 0xe8, 0x8b,
+	0x54, 0x2e, 0xc4, 0x12, 0xfb, 0x60, 0x5d, 0x5f, 0x06, 0xcd, 0x61, 0x33, 0xf4, 0xb8, 0x25, 0x2e,
+	0xf1, 0x04, 0x6c, 0x95, 0xae, 0x28, 0xb0, 0x86, 0xcd, 0xb0, 0x7f, 0x86, 0x51, 0x5d, 0x1c, 0xf1,
+	0x74, 0x45, 0xdc, 0xd4, 0xf1, 0x1d, 0x78, 0xf7, 0x4a, 0x6c, 0x62, 0x4d, 0xd7, 0xf7, 0x41, 0xcb,
+	0x3c, 0xf7, 0xb2, 0x17, 0x02, 0x11, 0xec, 0x49, 0x46, 0xb3, 0xc0, 0x36, 0x05, 0x3b, 0xcf, 0x68,
+	0x86, 0x03, 0x70, 0xef, 0x55, 0xba, 0x11, 0x73, 0x52, 0x41, 0xdb, 0xf0, 0x6e, 0x56, 0x61, 0xa3,
+	0x17, 0xcf, 0x14, 0x38, 0x95, 0x5e, 0x3c, 0x13, 0xbe, 0x05, 0x87, 0xd3, 0x52, 0xa4, 0x32, 0xe8,
+	0x18, 0xd6, 0x51, 0x06, 0xe1, 0x10, 0xfc, 0x91, 0x9e, 0xcd, 0xc7, 0x94, 0x4c, 0x49, 0xe5, 0x81,
+	0x3b, 0x6c, 0x85, 0x1e, 0xf7, 0x69, 0x47, 0xe1, 0x09, 0xf4, 0xcf, 0xd7, 0xfa, 0x29, 0x55, 0xe2,
+	0x99, 0xe6, 0x37, 0xb4, 0xcd, 0x03, 0xcf, 0x88, 0xfa, 0xf1, 0x1e, 0x5b, 0x74, 0x7a, 0x20, 0x95,
+	0x08, 0x19, 0x6b, 0x21, 0x97, 0x01, 0x0c, 0x9b, 0xa1, 0xcb, 0x7d, 0xbd, 0xa3, 0x58, 0x08, 0x76,
+	0xb1, 0x33, 0xba, 0x60, 0xdf, 0xde, 0xdd, 0x8e, 0x0e, 0x1b, 0x08, 0xe0, 0x3c, 0xde, 0xf1, 0x9b,
+	0x11, 0x3f, 0x6c, 0x16, 0xdf, 0xe3, 0xf3, 0xc9, 0xc3, 0x88, 0x1f, 0x5a, 0xac, 0x03, 0x6d, 0x4e,
+	0xd9, 0x6a, 0xcb, 0x3c, 0xe8, 0x70, 0xfa, 0xb9, 0xa6, 0x5c, 0xb3, 0x31, 0xf8, 0x3f, 0x48, 0xe5,
+	0x22, 0x95, 0xd7, 0x72, 0x91, 0x62, 0x00, 0x9d, 0x0a, 0x56, 0x7e, 0x77, 0x36, 0x25, 0xc4, 0x63,
+	0xe8, 0x4d, 0x66, 0x4f, 0x94, 0xc4, 0x2f, 0xf5, 0xc2, 0xfd, 0x36, 0xef, 0xe5, 0x75, 0x92, 0x1d,
+	0x43, 0x77, 0xb4, 0x21, 0xa9, 0xab, 0xf6, 0xf8, 0x06, 0xda, 0xe7, 0x0b, 0x4d, 0xca, 0x74, 0x6b,
+	0xf1, 0x76, 0x5c, 0x00, 0xf6, 0x05, 0xa0, 0x52, 0x65, 0xab, 0x2d, 0x32, 0x70, 0x0c, 0xca, 0x83,
+	0xe6, 0xb0, 0x15, 0xfa, 0x67, 0x10, 0x19, 0x58, 0xcc, 0xc3, 0x1d, 0x32, 0x15, 0x36, 0x06, 0xef,
+	0x95, 0x2c, 0x92, 0x78, 0x10, 0x09, 0x55, 0x3d, 0x6d, 0x2d, 0x12, 0x32, 0xdc, 0x36, 0x2b, 0x6f,
+	0xc2, 0xe3, 0xb6, 0xde, 0x66, 0x54, 0x2c, 0x33, 0xa6, 0x3c, 0x8f, 0x97, 0x54, 0xa5, 0xdf, 0x49,
+	0x4a, 0xc8, 0xa6, 0x00, 0x13, 0x1d, 0xeb, 0x75, 0x6e, 0xfa, 0x0d, 0xc0, 0x7d, 0x8c, 0x95, 0x14,
+	0x72, 0x59, 0x8e, 0xe0, 0x71, 0xf7, 0x57, 0x85, 0xf1, 0x1b, 0x1c, 0x7d, 0x97, 0xc5, 0x8e, 0xf3,
+	0xf5, 0x2a, 0x9e, 0xae, 0xe8, 0x22, 0x95, 0x3a, 0x16, 0xb2, 0x48, 0xd5, 0x32, 0xd2, 0xa3, 0xf5,
+	0xff, 0xcb, 0xec, 0x3d, 0xf8, 0x17, 0xa4, 0xb4, 0x58, 0x88, 0x59, 0xac, 0xcd, 0x80, 0x05, 0xac,
+	0x6c, 0xb5, 0x67, 0xa4, 0xf4, 0xd9, 0x6f, 0x0b, 0x9c, 0xf2, 0x78, 0xf1, 0x13, 0x1c, 0x4c, 0x48,
+	0xef, 0x9d, 0x7d, 0x6f, 0xef, 0xb0, 0x07, 0x4e, 0x54, 0x86, 0xd7, 0xc0, 0xcf, 0x70, 0x70, 0xf5,
+	0x8f, 0xd6, 0x8d, 0x2a, 0xc7, 0x07, 0xfb, 0xaf, 0x58, 0x03, 0x3f, 0xbc, 0x46, 0x5a, 0x53, 0x75,
+	0xa3, 0x5a, 0xea, 0xac, 0x81, 0x1f, 0xc1, 0xbb, 0x22, 0x5d, 0xc6, 0x80, 0xbd, 0xa8, 0x9e, 0xe1,
+	0xc0, 0x8f, 0x76, 0x61, 0xb1, 0x06, 0x1e, 0x1b, 0x69, 0x69, 0x5f, 0xad, 0xa3, 0x1f, 0xed, 0x1c,
+	0x65, 0x0d, 0x0c, 0xa1, 0x3f, 0x21, 0x5d, 0x37, 0xa0, 0x1b, 0xd5, 0xd0, 0x6e, 0x9b, 0xa9, 0x63,
+	0xfe, 0xfa, 0xaf, 0x7f, 0x07, 0x00, 0x63, 0xb4, 0xc3, 0x02, 0x0b, 0x04, 0x00, 0x00,
 }
@@ -1,6 +1,9 @@
-package minion
+// Package policy translates a stitch into the containers, placements and
+// connections a minion should implement.
+package policy
 
 import (
+	"reflect"
 	"sort"
 
 	"github.com/NetSys/quilt/db"
@@ -10,18 +13,36 @@ import (
 	log "github.com/Sirupsen/logrus"
 )
 
-func updatePolicy(view db.Database, spec string) {
+// Update synchronizes the container, placement and connection tables with the
+// stitch encoded in `spec`.
+// Problems with the spec are recorded in the minion's status.
+func Update(view db.Database, spec string) {
 	compiled, err := stitch.FromJSON(spec)
 	if err != nil {
 		log.WithError(err).Warn("Invalid spec.")
+		updateStatus(view, []string{"Invalid spec: " + err.Error()})
 		return
 	}
 
+	updateStatus(view, nil)
+
 	updateContainers(view, compiled)
 	updatePlacements(view, compiled)
 	updateConnections(view, compiled)
 }
 
+func updateStatus(view db.Database, warnings []string) {
+	status, err := view.GetStatus("")
+	if err != nil {
+		status = view.InsertStatus()
+	}
+
+	if !reflect.DeepEqual(status.Warnings, warnings) {
+		status.Warnings = warnings
+		view.Commit(status)
+	}
+}
+
 func updatePlacements(view db.Database, spec stitch.Stitch) {
 	var placements db.PlacementSlice
 	for _, sp := range spec.Placements {
@@ -1,4 +1,4 @@
-package minion
+package policy
 
 import (
 	"testing"
@@ -83,13 +83,34 @@ func TestContainerTxn(t *testing.T) {
 	assert.False(t, fired(trigg))
 }
 
+func TestInvalidSpec(t *testing.T) {
+	conn := db.New()
+
+	warnings := func(spec string) (warnings []string) {
+		conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+			Update(view, spec)
+			status, err := view.GetStatus("")
+			assert.NoError(t, err)
+			warnings = status.Warnings
+			return nil
+		})
+		return warnings
+	}
+
+	result := warnings("invalid")
+	assert.Len(t, result, 1)
+	assert.Contains(t, result[0], "Invalid spec")
+
+	assert.Nil(t, warnings("{}"))
+}
+
 func testContainerTxn(t *testing.T, conn db.Conn, spec string) {
 	compiled, err := stitch.FromJavascript(spec, stitch.DefaultImportGetter)
 	assert.Nil(t, err)
 
 	var containers []db.Container
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		updatePolicy(view, compiled.String())
+		Update(view, compiled.String())
 		containers = view.SelectFromContainer(nil)
 		return nil
 	})
@@ -161,7 +182,7 @@ func testConnectionTxn(t *testing.T, conn db.Conn, spec string) {
 
 	var connections []db.Connection
 	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-		updatePolicy(view, compiled.String())
+		Update(view, compiled.String())
 		connections = view.SelectFromConnection(nil)
 		return nil
 	})
@@ -204,7 +225,7 @@ func TestPlacementTxn(t *testing.T) {
 
 		placements := map[db.Placement]struct{}{}
 		conn.Txn(db.AllTables...).Run(func(view db.Database) error {
-			updatePolicy(view, compiled.String())
+			Update(view, compiled.String())
 			res := view.SelectFromPlacement(nil)
 
 			// Set the ID to 0 so that we can use reflect.DeepEqual.
@@ -1,19 +1,24 @@
 package minion
 
 import (
+	"crypto/tls"
 	"fmt"
 	"time"
 
 	"github.com/NetSys/quilt/api"
 	apiServer "github.com/NetSys/quilt/api/server"
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/metrics"
 	"github.com/NetSys/quilt/minion/docker"
 	"github.com/NetSys/quilt/minion/etcd"
 	"github.com/NetSys/quilt/minion/network"
 	"github.com/NetSys/quilt/minion/network/plugin"
+	"github.com/NetSys/quilt/minion/policy"
 	"github.com/NetSys/quilt/minion/pprofile"
 	"github.com/NetSys/quilt/minion/scheduler"
 	"github.com/NetSys/quilt/minion/supervisor"
+	"github.com/NetSys/quilt/minion/termination"
 	"github.com/NetSys/quilt/util"
 
 	log "github.com/Sirupsen/logrus"
@@ -26,31 +31,47 @@ func Run() {
 
 	log.Info("Minion Start")
 
+	// The minion's key is generated on its machine, and certified by the foreman
+	// once it connects.  All of the minion's services present the latest
+	// certificate.
+	tlsCreds, err := certs.LoadMinionCredentials(certs.MinionDir)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load TLS credentials")
+	}
+
 	conn := db.New()
+	conn.EnableHistory(db.DefaultHistorySize)
 	dk := docker.New("unix:///var/run/docker.sock")
 
 	// Not in a goroutine, want the plugin to start before the scheduler
 	plugin.Run()
 
-	go minionServerRun(conn)
+	go minionServerRun(conn, tlsCreds)
 	go supervisor.Run(conn, dk)
 	go scheduler.Run(conn, dk)
 	go network.Run(conn)
 	go etcd.Run(conn)
+	go termination.Run(conn)
 	go syncAuthorizedKeys(conn)
 
-	go apiServer.Run(conn, fmt.Sprintf("tcp://0.0.0.0:%d", api.DefaultRemotePort))
+	go apiServer.RunMinion(conn,
+		fmt.Sprintf("tcp://0.0.0.0:%d", api.DefaultRemotePort),
+		tlsCreds.ServerCredentials())
+
+	// The metrics are served over the network, so like the minion's other
+	// services, they require clients to present a certificate signed by the CA.
+	go runMetrics(tlsCreds.ServerTLSConfig())
 
 	loopLog := util.NewEventTimer("Minion-Update")
 
 	for range conn.Trigger(db.MinionTable, db.EtcdTable).C {
 		loopLog.LogStart()
 		txn := conn.Txn(db.ConnectionTable, db.ContainerTable, db.MinionTable,
-			db.EtcdTable, db.PlacementTable)
+			db.EtcdTable, db.PlacementTable, db.StatusTable)
 		txn.Run(func(view db.Database) error {
 			minion, err := view.MinionSelf()
 			if err == nil && view.EtcdLeader() {
-				updatePolicy(view, minion.Spec)
+				policy.Update(view, minion.Spec)
 			}
 			return nil
 		})
@@ -58,6 +79,12 @@ func Run() {
 	}
 }
 
+func runMetrics(tlsConfig *tls.Config) {
+	if err := metrics.Run(metrics.DefaultMinionAddress, tlsConfig); err != nil {
+		log.WithError(err).Error("Failed to serve metrics.")
+	}
+}
+
 func runProfiler(duration time.Duration) {
 	go func() {
 		p := pprofile.New("minion")
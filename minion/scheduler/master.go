@@ -3,6 +3,7 @@ package scheduler
 import (
 	"container/heap"
 	"fmt"
+	"reflect"
 	"sort"
 
 	"github.com/NetSys/quilt/db"
@@ -20,11 +21,16 @@ type context struct {
 	constraints []db.Placement
 	unassigned  []*db.Container
 	changed     []*db.Container
+	failed      []*db.Container
 }
 
+// The StitchIDs of the containers that couldn't be placed by the previous run, so that
+// each failure is only recorded as an event once.
+var unplaced = map[string]struct{}{}
+
 func runMaster(conn db.Conn) {
-	conn.Txn(db.ContainerTable, db.EtcdTable, db.MinionTable,
-		db.PlacementTable).Run(func(view db.Database) error {
+	conn.Txn(db.ContainerTable, db.EtcdTable, db.EventTable, db.MinionTable,
+		db.PlacementTable, db.StatusTable).Run(func(view db.Database) error {
 
 		if view.EtcdLeader() {
 			placeContainers(view)
@@ -45,6 +51,34 @@ func placeContainers(view db.Database) {
 	for _, change := range ctx.changed {
 		view.Commit(*change)
 	}
+
+	failed := map[string]struct{}{}
+	var unschedulable []string
+	for _, dbc := range ctx.failed {
+		failed[dbc.StitchID] = struct{}{}
+		unschedulable = append(unschedulable,
+			fmt.Sprintf("%s (%s)", dbc.StitchID, dbc.Image))
+		if _, ok := unplaced[dbc.StitchID]; ok {
+			continue
+		}
+
+		view.RecordEvent(db.Event{
+			Type: db.PlacementFailed,
+			Message: fmt.Sprintf("No minion can run container %s (%s).",
+				dbc.StitchID, dbc.Image),
+		})
+	}
+	unplaced = failed
+
+	sort.Strings(unschedulable)
+	status, err := view.GetStatus("")
+	if err != nil {
+		status = view.InsertStatus()
+	}
+	if !reflect.DeepEqual(status.UnschedulableContainers, unschedulable) {
+		status.UnschedulableContainers = unschedulable
+		view.Commit(status)
+	}
 }
 
 // Unassign all containers that are placed incorrectly.
@@ -81,6 +115,8 @@ Outer:
 			}
 		}
 
+		placementFailures.Inc()
+		ctx.failed = append(ctx.failed, dbc)
 		log.WithField("container", dbc).Warning("Failed to place container.")
 	}
 }
@@ -196,9 +232,11 @@ func makeContext(minions []db.Minion, constraints []db.Placement,
 	ctx := context{}
 	ctx.constraints = constraints
 
+	// Terminating workers are about to be reclaimed by their cloud provider, so
+	// their containers are moved elsewhere while there's still time.
 	ipMinion := map[string]*minion{}
 	for _, dbm := range minions {
-		if dbm.Role != db.Worker || dbm.PrivateIP == "" {
+		if dbm.Role != db.Worker || dbm.PrivateIP == "" || dbm.Terminating {
 			continue
 		}
 
@@ -41,6 +41,61 @@ func TestPlaceContainers(t *testing.T) {
 	})
 }
 
+func TestPlacementFailedEvents(t *testing.T) {
+	conn := db.New()
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		c := view.InsertContainer()
+		c.StitchID = "1"
+		c.Image = "alpine"
+		view.Commit(c)
+		return nil
+	})
+
+	place := func() []db.Event {
+		conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+			placeContainers(view)
+			return nil
+		})
+		return conn.SelectFromEvent(nil)
+	}
+
+	// There are no workers to place the container on.  The failure is only
+	// recorded once, no matter how many times placement is retried.
+	events := place()
+	assert.Len(t, events, 1)
+	assert.Equal(t, db.PlacementFailed, events[0].Type)
+	assert.Equal(t, "No minion can run container 1 (alpine).", events[0].Message)
+	assert.Len(t, place(), 1)
+
+	unschedulable := func() []string {
+		var status db.Status
+		conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+			status, _ = view.GetStatus("")
+			return nil
+		})
+		return status.UnschedulableContainers
+	}
+	assert.Equal(t, []string{"1 (alpine)"}, unschedulable())
+
+	var worker db.Minion
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		worker = view.InsertMinion()
+		worker.PrivateIP = "1"
+		worker.Role = db.Worker
+		view.Commit(worker)
+		return nil
+	})
+	assert.Len(t, place(), 1)
+	assert.Nil(t, unschedulable())
+
+	// Once the container's minion is gone, the new failure is recorded.
+	conn.Txn(db.AllTables...).Run(func(view db.Database) error {
+		view.Remove(worker)
+		return nil
+	})
+	assert.Len(t, place(), 2)
+}
+
 func TestCleanup(t *testing.T) {
 	t.Parallel()
 
@@ -291,6 +346,12 @@ func TestMakeContext(t *testing.T) {
 			PrivateIP: "3",
 			Region:    "Region3",
 		},
+		{
+			ID:          4,
+			PrivateIP:   "4",
+			Role:        db.Worker,
+			Terminating: true,
+		},
 	}
 	containers := []db.Container{
 		{
@@ -304,6 +365,10 @@ func TestMakeContext(t *testing.T) {
 			ID:     3,
 			Minion: "3",
 		},
+		{
+			ID:     4,
+			Minion: "4",
+		},
 	}
 	placements := []db.Placement{
 		{
@@ -328,10 +393,11 @@ func TestMakeContext(t *testing.T) {
 	}
 	assert.Equal(t, expMinions, ctx.minions)
 
-	expUnassigned := []*db.Container{&containers[0], &containers[2]}
+	expUnassigned := []*db.Container{&containers[0], &containers[2],
+		&containers[3]}
 	assert.Equal(t, expUnassigned, ctx.unassigned)
 
-	expChanged := []*db.Container{&containers[2]}
+	expChanged := []*db.Container{&containers[2], &containers[3]}
 	assert.Equal(t, expChanged, ctx.changed)
 }
 
@@ -13,8 +13,25 @@ import (
 	"github.com/NetSys/quilt/minion/network/plugin"
 	"github.com/NetSys/quilt/util"
 	log "github.com/Sirupsen/logrus"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+var (
+	placementFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "quilt_scheduler_placement_failures_total",
+		Help: "The number of times a container couldn't be placed on any minion.",
+	})
+
+	containerCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "quilt_containers",
+		Help: "The number of containers scheduled on this minion, by status.",
+	}, []string{"status"})
+)
+
+func init() {
+	prometheus.MustRegister(placementFailures, containerCount)
+}
+
 // Run blocks implementing the scheduler module.
 func Run(conn db.Conn, dk docker.Client) {
 	bootWait(conn)
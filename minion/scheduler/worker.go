@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/NetSys/quilt/db"
@@ -33,9 +34,12 @@ func runWorker(conn db.Conn, dk docker.Client, myIP string) {
 		}
 
 		conn.Txn(db.ContainerTable).Run(func(view db.Database) error {
-			dbcs := view.SelectFromContainer(func(dbc db.Container) bool {
-				return dbc.IP != "" && dbc.Minion == myIP
-			})
+			var dbcs []db.Container
+			for _, dbc := range view.SelectFromContainerByMinion(myIP) {
+				if dbc.IP != "" {
+					dbcs = append(dbcs, dbc)
+				}
+			}
 
 			var changed []db.Container
 			changed, toBoot, toKill = syncWorker(dbcs, dkcs)
@@ -43,10 +47,13 @@ func runWorker(conn db.Conn, dk docker.Client, myIP string) {
 				view.Commit(dbc)
 			}
 
+			updateContainerCount(view.SelectFromContainerByMinion(myIP))
 			return nil
 		})
 
-		doContainers(dk, toBoot, dockerRun)
+		doContainers(dk, toBoot, func(dk docker.Client, in chan interface{}) {
+			dockerRun(conn, dk, in)
+		})
 		doContainers(dk, toKill, dockerKill)
 	}
 }
@@ -79,6 +86,24 @@ func syncWorker(dbcs []db.Container, dkcs []docker.Container) (
 	return changed, toBoot, toKill
 }
 
+// updateContainerCount exports the number of containers in each status.  Containers
+// that Docker hasn't started yet have no status, so they're counted as pending.
+func updateContainerCount(dbcs []db.Container) {
+	counts := map[string]int{}
+	for _, dbc := range dbcs {
+		status := dbc.Status
+		if status == "" {
+			status = "pending"
+		}
+		counts[status]++
+	}
+
+	containerCount.Reset()
+	for status, count := range counts {
+		containerCount.WithLabelValues(status).Set(float64(count))
+	}
+}
+
 func doContainers(dk docker.Client, containers []interface{},
 	do func(docker.Client, chan interface{})) {
 
@@ -99,10 +124,23 @@ func doContainers(dk docker.Client, containers []interface{},
 	wg.Wait()
 }
 
-func dockerRun(dk docker.Client, in chan interface{}) {
+// The images that failed to pull, so that each failure is only recorded as an event
+// once, until the image is pulled successfully.
+var pullFailures = struct {
+	sync.Mutex
+	images map[string]struct{}
+}{images: map[string]struct{}{}}
+
+func dockerRun(conn db.Conn, dk docker.Client, in chan interface{}) {
 	for i := range in {
 		dbc := i.(db.Container)
 		log.WithField("container", dbc).Info("Start container")
+		if err := pullImage(conn, dk, dbc.Image); err != nil {
+			log.WithError(err).WithField("image", dbc.Image).Warning(
+				"Failed to pull image")
+			continue
+		}
+
 		_, err := dk.Run(docker.RunOptions{
 			Image:       dbc.Image,
 			Args:        dbc.Command,
@@ -123,6 +161,30 @@ func dockerRun(dk docker.Client, in chan interface{}) {
 	}
 }
 
+// pullImage pulls `image`, and records an event if it fails for the first time since
+// it was last pulled.
+func pullImage(conn db.Conn, dk docker.Client, image string) error {
+	err := dk.Pull(image)
+
+	pullFailures.Lock()
+	_, failedBefore := pullFailures.images[image]
+	if err == nil {
+		delete(pullFailures.images, image)
+	} else {
+		pullFailures.images[image] = struct{}{}
+	}
+	pullFailures.Unlock()
+
+	if err != nil && !failedBefore {
+		conn.RecordEvent(db.Event{
+			Type: db.ImagePullFailed,
+			Message: fmt.Sprintf("Failed to pull image %s: %s",
+				image, err),
+		})
+	}
+	return err
+}
+
 func dockerKill(dk docker.Client, in chan interface{}) {
 	for i := range in {
 		dkc := i.(docker.Container)
@@ -6,6 +6,7 @@ import (
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/minion/docker"
 	"github.com/davecgh/go-spew/spew"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,12 +50,33 @@ func TestRunWorker(t *testing.T) {
 	assert.Equal(t, "Image", dkcs[0].Image)
 }
 
+func TestPullFailedEvents(t *testing.T) {
+	md, dk := docker.NewMock()
+	conn := db.New()
+
+	// Each failure is only recorded once, no matter how often the pull is retried.
+	md.PullError = true
+	assert.Error(t, pullImage(conn, dk, "pull-test"))
+	assert.Error(t, pullImage(conn, dk, "pull-test"))
+
+	events := conn.SelectFromEvent(nil)
+	assert.Len(t, events, 1)
+	assert.Equal(t, db.ImagePullFailed, events[0].Type)
+	assert.Equal(t, "Failed to pull image pull-test: pull error", events[0].Message)
+
+	md.PullError = false
+	assert.NoError(t, pullImage(conn, dk, "pull-test"))
+	assert.NotContains(t, pullFailures.images, "pull-test")
+}
+
 func runSync(dk docker.Client, dbcs []db.Container,
 	dkcs []docker.Container) []db.Container {
 
 	changes, tdbcs, tdkcs := syncWorker(dbcs, dkcs)
 	doContainers(dk, tdkcs, dockerKill)
-	doContainers(dk, tdbcs, dockerRun)
+	doContainers(dk, tdbcs, func(dk docker.Client, in chan interface{}) {
+		dockerRun(db.New(), dk, in)
+	})
 	return changes
 }
 
@@ -178,3 +200,22 @@ func TestSyncJoinScore(t *testing.T) {
 	assert.Equal(t, -1, score)
 	dbc.Env = dkc.Env
 }
+
+func TestUpdateContainerCount(t *testing.T) {
+	count := func(status string) float64 {
+		var m dto.Metric
+		containerCount.WithLabelValues(status).Write(&m)
+		return m.GetGauge().GetValue()
+	}
+
+	updateContainerCount([]db.Container{
+		{Status: "running"}, {Status: "running"}, {Status: "exited"}, {}})
+	assert.Equal(t, 2.0, count("running"))
+	assert.Equal(t, 1.0, count("exited"))
+	assert.Equal(t, 1.0, count("pending"))
+
+	updateContainerCount([]db.Container{{Status: "running"}})
+	assert.Equal(t, 1.0, count("running"))
+	assert.Equal(t, 0.0, count("exited"))
+	assert.Equal(t, 0.0, count("pending"))
+}
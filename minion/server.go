@@ -6,8 +6,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/minion/pb"
+	"github.com/NetSys/quilt/version"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -17,11 +19,14 @@ import (
 
 type server struct {
 	db.Conn
+
+	// The minion's credentials, which the foreman certifies with SetCertificate.
+	creds *certs.MinionCredentials
 }
 
-func minionServerRun(conn db.Conn) {
+func minionServerRun(conn db.Conn, creds *certs.MinionCredentials) {
 	var sock net.Listener
-	server := server{conn}
+	server := server{conn, creds}
 	for {
 		var err error
 		sock, err = net.Listen("tcp", ":9999")
@@ -34,7 +39,7 @@ func minionServerRun(conn db.Conn) {
 		time.Sleep(30 * time.Second)
 	}
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(grpc.Creds(creds.ServerCredentials()))
 	pb.RegisterMinionServer(s, server)
 	s.Serve(sock)
 }
@@ -52,6 +57,7 @@ func (s server) GetMinionConfig(cts context.Context,
 		cfg.Size = m.Size
 		cfg.Region = m.Region
 		cfg.AuthorizedKeys = strings.Split(m.AuthorizedKeys, "\n")
+		cfg.Terminating = m.Terminating
 	} else {
 		cfg.Role = db.RoleToPB(db.None)
 	}
@@ -66,6 +72,69 @@ func (s server) GetMinionConfig(cts context.Context,
 	return &cfg, nil
 }
 
+// Version reports the version of Quilt the minion is running, and the schema it
+// speaks.
+func (s server) Version(cts context.Context, _ *pb.Request) (*pb.VersionInfo,
+	error) {
+
+	return &pb.VersionInfo{
+		Version:       version.Version,
+		SchemaVersion: version.Schema,
+	}, nil
+}
+
+// GetEvents returns the events the minion recorded after the requested time, from
+// oldest to newest.
+func (s server) GetEvents(cts context.Context, req *pb.EventRequest) (
+	*pb.EventReply, error) {
+
+	after := time.Unix(0, req.After)
+	reply := &pb.EventReply{}
+	for _, e := range s.SelectFromEvent(func(e db.Event) bool {
+		return e.Time.After(after)
+	}) {
+		reply.Events = append(reply.Events, &pb.EventInfo{
+			Time:    e.Time.UnixNano(),
+			Type:    string(e.Type),
+			Message: e.Message,
+		})
+	}
+	return reply, nil
+}
+
+// GetStatus returns the problems the minion found with its spec.  Only the etcd leader
+// implements the spec, so the other minions report nothing.
+func (s server) GetStatus(cts context.Context, _ *pb.Request) (*pb.StatusInfo,
+	error) {
+
+	reply := &pb.StatusInfo{}
+	s.ReadTxn(db.EtcdTable, db.StatusTable).Run(func(view db.Database) error {
+		status, err := view.GetStatus("")
+		if err == nil && view.EtcdLeader() {
+			reply.Warnings = status.Warnings
+			reply.UnschedulableContainers = status.UnschedulableContainers
+		}
+		return nil
+	})
+	return reply, nil
+}
+
+// SetCertificate replaces the minion's certificate with one the foreman issued for its
+// key.  Only the foreman may call it, as the minion requires its clients to present a
+// certificate signed by the CA.  The minion's other services pick up the new
+// certificate with their next connection.
+func (s server) SetCertificate(ctx context.Context, cert *pb.Certificate) (*pb.Reply,
+	error) {
+
+	if err := s.creds.SetCertificate(cert.Cert); err != nil {
+		log.WithError(err).Warn("Rejected certificate.")
+		return nil, err
+	}
+
+	log.Info("Received a new certificate.")
+	return &pb.Reply{}, nil
+}
+
 func (s server) SetMinionConfig(ctx context.Context,
 	msg *pb.MinionConfig) (*pb.Reply, error) {
 	go s.Txn(db.EtcdTable,
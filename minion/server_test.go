@@ -1,19 +1,24 @@
 package minion
 
 import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/minion/pb"
 )
 
 func TestSetMinionConfig(t *testing.T) {
 	t.Parallel()
-	s := server{db.New()}
+	s := server{Conn: db.New()}
 
 	cfg := pb.MinionConfig{
 		Role:           pb.MinionConfig_MASTER,
@@ -97,7 +102,7 @@ func checkEtcdEquals(t *testing.T, conn db.Conn, exp db.Etcd) {
 
 func TestGetMinionConfig(t *testing.T) {
 	t.Parallel()
-	s := server{db.New()}
+	s := server{Conn: db.New()}
 
 	// Should set Role to None if no config.
 	cfg, err := s.GetMinionConfig(nil, &pb.Request{})
@@ -115,6 +120,7 @@ func TestGetMinionConfig(t *testing.T) {
 		m.Size = "size"
 		m.Region = "region"
 		m.AuthorizedKeys = "key1\nkey2"
+		m.Terminating = true
 		view.Commit(m)
 		return nil
 	})
@@ -144,5 +150,90 @@ func TestGetMinionConfig(t *testing.T) {
 		Region:         "region",
 		EtcdMembers:    []string{"etcd1", "etcd2"},
 		AuthorizedKeys: []string{"key1", "key2"},
+		Terminating:    true,
 	}, *cfg)
 }
+
+func TestGetEvents(t *testing.T) {
+	t.Parallel()
+	s := server{Conn: db.New()}
+
+	s.RecordEvent(db.Event{Time: time.Unix(0, 100), Type: db.PlacementFailed,
+		Message: "old"})
+	s.RecordEvent(db.Event{Time: time.Unix(0, 200), Type: db.ImagePullFailed,
+		Message: "new"})
+
+	reply, err := s.GetEvents(nil, &pb.EventRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, reply.Events, 2)
+
+	reply, err = s.GetEvents(nil, &pb.EventRequest{After: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.EventInfo{{Time: 200, Type: "ImagePullFailed",
+		Message: "new"}}, reply.Events)
+}
+
+func TestGetStatus(t *testing.T) {
+	t.Parallel()
+	s := server{Conn: db.New()}
+
+	s.Txn(db.AllTables...).Run(func(view db.Database) error {
+		status := view.InsertStatus()
+		status.Warnings = []string{"warning"}
+		status.UnschedulableContainers = []string{"1 (alpine)"}
+		view.Commit(status)
+		return nil
+	})
+
+	reply, err := s.GetStatus(nil, &pb.Request{})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.StatusInfo{}, reply)
+
+	s.Txn(db.AllTables...).Run(func(view db.Database) error {
+		etcd := view.InsertEtcd()
+		etcd.Leader = true
+		view.Commit(etcd)
+		return nil
+	})
+
+	reply, err = s.GetStatus(nil, &pb.Request{})
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.StatusInfo{Warnings: []string{"warning"},
+		UnschedulableContainers: []string{"1 (alpine)"}}, reply)
+}
+
+func TestSetCertificate(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "quilt-minion")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca, err := certs.NewCA()
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, certs.CACertFile),
+		[]byte(ca.CertPEM()), 0644)
+	assert.NoError(t, err)
+
+	creds, err := certs.LoadMinionCredentials(dir)
+	assert.NoError(t, err)
+	s := server{Conn: db.New(), creds: creds}
+
+	_, err = s.SetCertificate(nil, &pb.Certificate{Cert: "garbage"})
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(dir, certs.CertFile))
+	assert.True(t, os.IsNotExist(err))
+
+	cert, err := creds.ServerTLSConfig().GetCertificate(nil)
+	assert.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	certPEM, err := ca.Certify(leaf.PublicKey, []string{"8.8.8.8"})
+	assert.NoError(t, err)
+
+	_, err = s.SetCertificate(nil, &pb.Certificate{Cert: certPEM})
+	assert.NoError(t, err)
+	saved, err := ioutil.ReadFile(filepath.Join(dir, certs.CertFile))
+	assert.NoError(t, err)
+	assert.Equal(t, certPEM, string(saved))
+}
@@ -0,0 +1,136 @@
+// Package termination watches the instance metadata of the minion's machine for notice
+// that the cloud provider is about to reclaim it.  Amazon gives spot instances two
+// minutes of warning, and Google gives preemptible instances thirty seconds.  Once
+// notice is given, the minion is marked as terminating so that the foreman can boot a
+// replacement, and the scheduler can move containers off of it, before it disappears.
+package termination
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/util"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Stored in variables so they may be mocked out.
+var (
+	amazonNoticeURL = "http://169.254.169.254/latest/meta-data/spot/instance-action"
+	googleNoticeURL = "http://metadata.google.internal/computeMetadata/v1/" +
+		"instance/preempted"
+)
+
+var httpClient = &http.Client{Timeout: 5 * time.Second}
+
+// Run blocks polling the instance metadata for a termination notice.
+func Run(conn db.Conn) {
+	loopLog := util.NewEventTimer("Termination")
+	for range conn.TriggerTick(5, db.MinionTable).C {
+		loopLog.LogStart()
+		runOnce(conn)
+		loopLog.LogEnd()
+	}
+}
+
+func runOnce(conn db.Conn) {
+	self, err := conn.MinionSelf()
+	if err != nil || self.Terminating {
+		return
+	}
+
+	var notice string
+	switch db.Provider(self.Provider) {
+	case db.Amazon:
+		notice, err = amazonNotice()
+	case db.Google:
+		notice, err = googleNotice()
+	default:
+		return
+	}
+
+	if err != nil {
+		log.WithError(err).Debug("Failed to check for a termination notice.")
+		return
+	} else if notice == "" {
+		return
+	}
+
+	log.Warn(notice)
+	conn.Txn(db.EventTable, db.MinionTable).Run(func(view db.Database) error {
+		self, err := view.MinionSelf()
+		if err != nil {
+			return err
+		}
+
+		self.Terminating = true
+		view.Commit(self)
+		view.RecordEvent(db.Event{
+			Type:    db.MachineTerminating,
+			Message: notice,
+		})
+		return nil
+	})
+}
+
+// amazonNotice returns a description of the action Amazon has scheduled for the
+// machine, or the empty string if there is none.
+func amazonNotice() (string, error) {
+	body, status, err := get(amazonNoticeURL, nil)
+	if err != nil || status == http.StatusNotFound {
+		return "", err
+	}
+
+	var action struct {
+		Action string `json:"action"`
+		Time   string `json:"time"`
+	}
+	if err := json.Unmarshal(body, &action); err != nil {
+		return "", fmt.Errorf("malformed instance action: %s", err)
+	}
+	return fmt.Sprintf("Amazon will %s the machine at %s.", action.Action,
+		action.Time), nil
+}
+
+// googleNotice returns a description of the machine's preemption, or the empty string
+// if it isn't being preempted.
+func googleNotice() (string, error) {
+	body, _, err := get(googleNoticeURL, map[string]string{
+		"Metadata-Flavor": "Google"})
+	if err != nil || strings.TrimSpace(string(body)) != "TRUE" {
+		return "", err
+	}
+	return "Google is preempting the machine.", nil
+}
+
+// get fetches `url` with the given headers.  Responses other than 200 OK and 404 Not
+// Found are considered errors.
+func get(url string, headers map[string]string) ([]byte, int, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return nil, resp.StatusCode, fmt.Errorf("unexpected status: %s",
+			resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, resp.StatusCode, err
+}
@@ -0,0 +1,126 @@
+package termination
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NetSys/quilt/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmazon(t *testing.T) {
+	var action string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if action == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(action))
+		}))
+	defer ts.Close()
+	amazonNoticeURL = ts.URL
+
+	conn := db.New()
+	self := insertSelf(conn, db.Amazon)
+
+	runOnce(conn)
+	checkTerminating(t, conn, false)
+
+	action = `{"action": "terminate", "time": "2017-09-18T08:22:00Z"}`
+	runOnce(conn)
+	checkTerminating(t, conn, true)
+
+	events := conn.SelectFromEvent(nil)
+	assert.Len(t, events, 1)
+	assert.Equal(t, db.MachineTerminating, events[0].Type)
+	assert.Equal(t, "Amazon will terminate the machine at 2017-09-18T08:22:00Z.",
+		events[0].Message)
+
+	// Terminating minions don't check again.
+	ts.Close()
+	runOnce(conn)
+	assert.Len(t, conn.SelectFromEvent(nil), 1)
+
+	// Errors don't mark the minion as terminating.
+	resetSelf(conn, self)
+	runOnce(conn)
+	checkTerminating(t, conn, false)
+
+	ts = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("not json"))
+		}))
+	defer ts.Close()
+	amazonNoticeURL = ts.URL
+	runOnce(conn)
+	checkTerminating(t, conn, false)
+}
+
+func TestGoogle(t *testing.T) {
+	preempted := "FALSE"
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				http.Error(w, "missing header", http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(preempted))
+		}))
+	defer ts.Close()
+	googleNoticeURL = ts.URL
+
+	conn := db.New()
+	insertSelf(conn, db.Google)
+
+	runOnce(conn)
+	checkTerminating(t, conn, false)
+
+	preempted = "TRUE"
+	runOnce(conn)
+	checkTerminating(t, conn, true)
+
+	events := conn.SelectFromEvent(nil)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "Google is preempting the machine.", events[0].Message)
+}
+
+func TestOtherProviders(t *testing.T) {
+	// Neither metadata server is contacted for other providers.
+	amazonNoticeURL = "http://example.invalid"
+	googleNoticeURL = "http://example.invalid"
+
+	conn := db.New()
+	runOnce(conn)
+
+	insertSelf(conn, db.Vagrant)
+	runOnce(conn)
+	checkTerminating(t, conn, false)
+}
+
+func insertSelf(conn db.Conn, provider db.Provider) db.Minion {
+	var self db.Minion
+	conn.Txn(db.MinionTable).Run(func(view db.Database) error {
+		self = view.InsertMinion()
+		self.Self = true
+		self.Provider = string(provider)
+		view.Commit(self)
+		return nil
+	})
+	return self
+}
+
+func resetSelf(conn db.Conn, self db.Minion) {
+	conn.Txn(db.MinionTable).Run(func(view db.Database) error {
+		view.Commit(self)
+		return nil
+	})
+}
+
+func checkTerminating(t *testing.T, conn db.Conn, exp bool) {
+	self, err := conn.MinionSelf()
+	assert.NoError(t, err)
+	assert.Equal(t, exp, self.Terminating)
+}
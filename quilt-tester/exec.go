@@ -14,6 +14,8 @@ import (
 
 	"github.com/NetSys/quilt/api"
 	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/api/client/getter"
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/util"
 )
@@ -27,23 +29,41 @@ func runSpecUntilConnected(spec string) (string, string, error) {
 		return stdout, stderr, err
 	}
 
-	allMachinesConnected := func() bool {
-		machines, err := queryMachines()
-		if err != nil {
-			return false
-		}
+	c, err := newClient()
+	if err != nil {
+		return stdout, stderr, err
+	}
+	defer c.Close()
 
-		for _, m := range machines {
-			if !m.Connected {
-				return false
+	watcher, err := c.Watch(db.MachineTable)
+	if err != nil {
+		return stdout, stderr, err
+	}
+	defer watcher.Stop()
+
+	timeout := time.After(8 * time.Minute)
+	for {
+		select {
+		case update, ok := <-watcher.C:
+			if !ok {
+				return stdout, stderr, watcher.Err()
+			}
+			if allMachinesConnected(update.Rows.([]db.Machine)) {
+				return stdout, stderr, nil
 			}
+		case <-timeout:
+			return stdout, stderr, errors.New("timed out")
 		}
-
-		return true
 	}
+}
 
-	err = util.WaitFor(allMachinesConnected, 1*time.Second, 8*time.Minute)
-	return stdout, stderr, err
+func allMachinesConnected(machines []db.Machine) bool {
+	for _, m := range machines {
+		if !m.Connected {
+			return false
+		}
+	}
+	return true
 }
 
 // stop stops the given namespace, and blocks until there are no more machines
@@ -166,7 +186,7 @@ func scp(host string, source string, target string) error {
 }
 
 func queryMachines() ([]db.Machine, error) {
-	c, err := client.New(api.DefaultSocket)
+	c, err := newClient()
 	if err != nil {
 		return []db.Machine{}, err
 	}
@@ -175,6 +195,14 @@ func queryMachines() ([]db.Machine, error) {
 	return c.QueryMachines()
 }
 
+func newClient() (client.Client, error) {
+	creds, err := certs.LoadClientCredentials(getter.TLSDir(), certs.DaemonName)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(api.DefaultSocket, creds, "")
+}
+
 func getAWSInstances(namespace string) ([]*string, error) {
 	// Find all of the instances under the namespace
 	svc := ec2.New(session.New(), &aws.Config{Region: aws.String("us-west-1")})
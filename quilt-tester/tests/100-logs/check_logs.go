@@ -16,7 +16,7 @@ var machineRegex = regexp.MustCompile(`Machine-(\d+){(.+?), .*, PublicIP=(.*?),`
 func main() {
 	printQuiltPs()
 
-	c, err := getter.New().Client(api.DefaultSocket)
+	c, err := getter.New(getter.TLSDir()).Client(api.DefaultSocket)
 	if err != nil {
 		log.WithError(err).Fatal("FAILED, couldn't get quiltctl client")
 	}
@@ -30,7 +30,7 @@ type testResult struct {
 }
 
 func main() {
-	clientGetter := getter.New()
+	clientGetter := getter.New(getter.TLSDir())
 
 	clnt, err := clientGetter.Client(api.DefaultSocket)
 	if err != nil {
@@ -21,7 +21,7 @@ func main() {
 	log.Info("Sleeping thirty seconds for `quilt stop -containers` to take effect")
 	time.Sleep(30 * time.Second)
 
-	c, err := getter.New().Client(api.DefaultSocket)
+	c, err := getter.New(getter.TLSDir()).Client(api.DefaultSocket)
 	if err != nil {
 		log.WithError(err).Fatal("FAILED, couldn't get quiltctl client")
 	}
@@ -13,7 +13,7 @@ import (
 )
 
 func main() {
-	clientGetter := getter.New()
+	clientGetter := getter.New(getter.TLSDir())
 
 	clnt, err := clientGetter.Client(api.DefaultSocket)
 	if err != nil {
@@ -25,7 +25,9 @@ func main() {
 			"[daemon | inspect <stitch> | run <stitch> | minion | " +
 			"stop <namespace> | get <import_path> | " +
 			"machines | containers | ps | ssh <id> [command] | " +
-			"logs <container>]")
+			"logs <container> | history [<table> -at=<time>] | " +
+			"rollback <id> | events [-f] | status | " +
+			"token create|list|revoke]")
 		fmt.Println("\nWhen provided a stitch, quilt takes responsibility\n" +
 			"for deploying it as specified.  Alternatively, quilt may be\n" +
 			"instructed to stop all deployments in a given namespace,\n" +
@@ -25,18 +25,35 @@ func TestMachineFlags(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, expHost, machineCmd.common.host)
+
+	machineCmd = NewMachineCommand()
+	assert.NoError(t, parseHelper(machineCmd, []string{"-namespace", "ns"}))
+	assert.Equal(t, "ns", machineCmd.namespace)
+}
+
+func TestFilterMachines(t *testing.T) {
+	t.Parallel()
+
+	machines := []db.Machine{{ID: 1, Namespace: "a"}, {ID: 2, Namespace: "b"}}
+	assert.Equal(t, machines, filterMachines(machines, ""))
+	assert.Equal(t, []db.Machine{{ID: 2, Namespace: "b"}},
+		filterMachines(machines, "b"))
+	assert.Empty(t, filterMachines(machines, "c"))
 }
 
 func TestMachineOutput(t *testing.T) {
 	t.Parallel()
 
 	machines := []db.Machine{{
-		StitchID: "1",
-		Role:     db.Master,
-		Provider: "Amazon",
-		Region:   "us-west-1",
-		Size:     "m4.large",
-		PublicIP: "8.8.8.8",
+		StitchID:  "1",
+		Namespace: "ns",
+		Role:      db.Master,
+		Provider:  "Amazon",
+		Region:    "us-west-1",
+		Size:      "m4.large",
+		PublicIP:  "8.8.8.8",
+
+		MinionVersion: "1.0",
 	}}
 
 	var b bytes.Buffer
@@ -47,14 +64,24 @@ func TestMachineOutput(t *testing.T) {
 	* errors easier to debug. */
 	result = strings.Replace(result, " ", "_", -1)
 
-	exp := `MACHINE____ROLE______PROVIDER____REGION_______SIZE` +
-		`________PUBLIC_IP____CONNECTED
-1__________Master____Amazon______us-west-1____m4.large____8.8.8.8______false
+	exp := `MACHINE____NAMESPACE____ROLE______PROVIDER____REGION_______SIZE` +
+		`________PUBLIC_IP____CONNECTED____VERSION____BACKOFF
+1__________ns___________Master____Amazon______us-west-1____m4.large____8.8.8.8` +
+		`______false________1.0________
 `
 
 	assert.Equal(t, exp, result)
 }
 
+func TestBackoffStr(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	assert.Equal(t, "", backoffStr(time.Time{}, now))
+	assert.Equal(t, "", backoffStr(now, now))
+	assert.Equal(t, "2 minutes", backoffStr(now.Add(150*time.Second), now))
+}
+
 func TestContainerFlags(t *testing.T) {
 	t.Parallel()
 
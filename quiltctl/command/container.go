@@ -28,7 +28,7 @@ type Container struct {
 // NewContainerCommand creates a new Container command instance.
 func NewContainerCommand() *Container {
 	return &Container{
-		clientGetter: getter.New(),
+		clientGetter: getter.New(getter.TLSDir()),
 		common:       &commonFlags{},
 	}
 }
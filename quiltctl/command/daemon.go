@@ -1,17 +1,29 @@
 package command
 
 import (
+	"crypto/tls"
 	"flag"
 	"fmt"
 
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/api"
 	"github.com/NetSys/quilt/api/server"
+	"github.com/NetSys/quilt/certs"
 	"github.com/NetSys/quilt/cluster"
 	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/engine"
+	"github.com/NetSys/quilt/metrics"
 )
 
 // Daemon contains the options for running the Quilt daemon.
 type Daemon struct {
+	dataDir     string
+	historySize int
+	tlsDir      string
+	httpAddr    string
+	metricsAddr string
+
 	common *commonFlags
 }
 
@@ -25,8 +37,27 @@ func NewDaemonCommand() *Daemon {
 // InstallFlags sets up parsing for command line flags
 func (dCmd *Daemon) InstallFlags(flags *flag.FlagSet) {
 	dCmd.common.InstallFlags(flags)
+	flags.StringVar(&dCmd.dataDir, "data-dir", "", "the directory in which to "+
+		"persist the daemon's database. If empty, it is kept only in memory")
+	flags.IntVar(&dCmd.historySize, "history-size", db.DefaultHistorySize,
+		"the number of row versions remembered by the database")
+	flags.StringVar(&dCmd.tlsDir, "tls-dir", certs.DefaultDir(), "the directory "+
+		"in which to keep the certificate authority and the daemon's "+
+		"credentials. If not the default, quiltctl must be pointed at it with "+
+		"$"+api.TLSDirEnv)
+	flags.StringVar(&dCmd.httpAddr, "http", "", "the address on which to serve "+
+		"the API as JSON over HTTPS, e.g. tcp://127.0.0.1:8443. If empty, it "+
+		"isn't served")
+	flags.StringVar(&dCmd.metricsAddr, "metrics",
+		fmt.Sprintf("tcp://127.0.0.1:%d", metrics.DefaultPort),
+		"the address on which to serve Prometheus metrics. If empty, they "+
+			"aren't served")
+
 	flags.Usage = func() {
-		fmt.Println("usage: quilt daemon [-H=<daemon_host>]")
+		fmt.Println("usage: quilt daemon [-H=<daemon_host>] " +
+			"[-data-dir=<directory>] [-history-size=<versions>] " +
+			"[-tls-dir=<directory>] [-http=<listen_address>] " +
+			"[-metrics=<listen_address>]")
 		fmt.Println("`daemon` starts the quilt daemon, which listens for" +
 			"quilt API requests")
 
@@ -36,14 +67,90 @@ func (dCmd *Daemon) InstallFlags(flags *flag.FlagSet) {
 
 // Parse parses the command line arguments for the daemon command.
 func (dCmd *Daemon) Parse(args []string) error {
+	if dCmd.historySize < 0 {
+		return fmt.Errorf("history size must not be negative: %d",
+			dCmd.historySize)
+	}
 	return nil
 }
 
 // Run starts the daemon.
 func (dCmd *Daemon) Run() int {
-	conn := db.New()
+	conn, err := dCmd.newConn()
+	if err != nil {
+		log.WithError(err).Error("Failed to restore the database.")
+		return 1
+	}
+	conn.EnableHistory(dCmd.historySize)
+
+	ca, err := certs.LoadOrCreateCA(dCmd.tlsDir)
+	if err != nil {
+		log.WithError(err).Error("Failed to load the certificate authority.")
+		return 1
+	}
+
+	creds, err := dCmd.setupCredentials(ca)
+	if err != nil {
+		log.WithError(err).Error("Failed to issue the daemon's credentials.")
+		return 1
+	}
+
+	serverCreds, err := creds.ServerCredentials()
+	if err != nil {
+		log.WithError(err).Error("Failed to load the daemon's credentials.")
+		return 1
+	}
+
+	if dCmd.httpAddr != "" {
+		tlsConfig, err := creds.ServerTLSConfig()
+		if err != nil {
+			log.WithError(err).Error(
+				"Failed to load the daemon's credentials.")
+			return 1
+		}
+		go dCmd.runHTTP(conn, tlsConfig)
+	}
+
+	if dCmd.metricsAddr != "" {
+		go runMetrics(dCmd.metricsAddr)
+	}
+
 	go engine.Run(conn)
-	go server.Run(conn, dCmd.common.host)
-	cluster.Run(conn)
+	go server.Run(conn, dCmd.common.host, serverCreds)
+	cluster.Run(conn, ca)
 	return 0
 }
+
+func (dCmd *Daemon) runHTTP(conn db.Conn, tlsConfig *tls.Config) {
+	if err := server.RunHTTP(conn, dCmd.httpAddr, tlsConfig); err != nil {
+		log.WithError(err).Error("Failed to serve the HTTP API.")
+	}
+}
+
+func runMetrics(addr string) {
+	if err := metrics.Run(addr, nil); err != nil {
+		log.WithError(err).Error("Failed to serve metrics.")
+	}
+}
+
+// setupCredentials issues the daemon a fresh certificate, and saves it alongside the
+// CA for use by the CLI, which presents it both to the daemon and to the minions.
+func (dCmd *Daemon) setupCredentials(ca certs.CA) (certs.Credentials, error) {
+	creds, err := ca.Issue(certs.DaemonName, certs.Server|certs.Client)
+	if err != nil {
+		return certs.Credentials{}, err
+	}
+	return creds, creds.Save(dCmd.tlsDir)
+}
+
+func (dCmd *Daemon) newConn() (db.Conn, error) {
+	if dCmd.dataDir == "" {
+		return db.New(), nil
+	}
+
+	journal, err := db.OpenFileJournal(dCmd.dataDir)
+	if err != nil {
+		return db.Conn{}, err
+	}
+	return db.NewWithJournal(journal)
+}
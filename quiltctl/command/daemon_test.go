@@ -0,0 +1,25 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NetSys/quilt/db"
+)
+
+func TestDaemonFlags(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewDaemonCommand()
+	assert.NoError(t, parseHelper(cmd, nil))
+	assert.Equal(t, db.DefaultHistorySize, cmd.historySize)
+
+	cmd = NewDaemonCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"-history-size=0"}))
+	assert.Equal(t, 0, cmd.historySize)
+
+	cmd = NewDaemonCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"-history-size=-1"}),
+		"history size must not be negative: -1")
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/api/client/getter"
+	"github.com/NetSys/quilt/db"
+	"github.com/NetSys/quilt/util"
+)
+
+// Events contains the options for listing the events recorded by the daemon.
+type Events struct {
+	follow    bool
+	namespace string
+
+	common       *commonFlags
+	clientGetter client.Getter
+}
+
+// NewEventsCommand creates a new Events command instance.
+func NewEventsCommand() *Events {
+	return &Events{
+		common:       &commonFlags{},
+		clientGetter: getter.New(getter.TLSDir()),
+	}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (eCmd *Events) InstallFlags(flags *flag.FlagSet) {
+	eCmd.common.InstallFlags(flags)
+	flags.BoolVar(&eCmd.follow, "f", false, "keep printing events as they happen")
+	flags.StringVar(&eCmd.namespace, "namespace", "",
+		"only show the events in this namespace")
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt events [-H=<daemon_host>] [-f] " +
+			"[-namespace=<namespace>]")
+		fmt.Println("`events` lists the events recorded by the quilt daemon, " +
+			"such as machines booting or containers failing to start.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the events command.
+func (eCmd *Events) Parse(args []string) error {
+	return nil
+}
+
+// Run prints the requested events.
+func (eCmd *Events) Run() int {
+	c, err := eCmd.clientGetter.Client(eCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer c.Close()
+
+	if !eCmd.follow {
+		events, err := c.QueryEvents()
+		if err != nil {
+			log.WithError(err).Error("Unable to query events.")
+			return 1
+		}
+		writeEvents(os.Stdout, filterEvents(events, eCmd.namespace, 0), true)
+		return 0
+	}
+
+	watcher, err := c.Watch(db.EventTable)
+	if err != nil {
+		log.WithError(err).Error("Unable to watch events.")
+		return 1
+	}
+	defer watcher.Stop()
+
+	if err := followEvents(os.Stdout, watcher, eCmd.namespace); err != nil {
+		log.WithError(err).Error("Lost the connection to the daemon.")
+		return 1
+	}
+	return 0
+}
+
+// followEvents prints each event in `namespace` delivered by `watcher` once, as it
+// arrives.
+func followEvents(fd io.Writer, watcher *client.Watcher, namespace string) error {
+	lastID := -1
+	for update := range watcher.C {
+		events, ok := update.Rows.([]db.Event)
+		if !ok {
+			return errors.New("received malformed events")
+		}
+
+		events = filterEvents(events, namespace, lastID)
+		if len(events) == 0 {
+			continue
+		}
+
+		// The header is only printed before the first events.
+		writeEvents(fd, events, lastID < 0)
+		lastID = events[len(events)-1].ID
+	}
+	return watcher.Err()
+}
+
+// filterEvents returns the events in `namespace` whose ID is greater than `afterID`.
+// All namespaces match an empty `namespace`.
+func filterEvents(events []db.Event, namespace string, afterID int) []db.Event {
+	var result []db.Event
+	for _, e := range events {
+		if e.ID > afterID && (namespace == "" || e.Namespace == namespace) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func writeEvents(fd io.Writer, events []db.Event, header bool) {
+	w := tabwriter.NewWriter(fd, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	if header {
+		fmt.Fprintln(w, "TIME\tNAMESPACE\tMACHINE\tTYPE\tMESSAGE")
+	}
+
+	for _, e := range events {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", e.Time.Format(time.RFC3339),
+			e.Namespace, util.ShortUUID(e.Machine), e.Type, e.Message)
+	}
+}
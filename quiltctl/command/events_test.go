@@ -0,0 +1,89 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/api/client/mocks"
+	"github.com/NetSys/quilt/db"
+)
+
+func TestEventsFlags(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewEventsCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"-f", "-namespace", "ns"}))
+	assert.True(t, cmd.follow)
+	assert.Equal(t, "ns", cmd.namespace)
+}
+
+func TestEventsRun(t *testing.T) {
+	t.Parallel()
+
+	c := &mocks.Client{EventReturn: []db.Event{{ID: 1}}}
+	mockGetter := new(mocks.Getter)
+	mockGetter.On("Client", mock.Anything).Return(c, nil)
+
+	cmd := NewEventsCommand()
+	cmd.clientGetter = mockGetter
+	assert.Equal(t, 0, cmd.Run())
+
+	c.EventErr = errors.New("unavailable")
+	assert.Equal(t, 1, cmd.Run())
+
+	cmd.follow = true
+	c.WatchErr = errors.New("unavailable")
+	assert.Equal(t, 1, cmd.Run())
+}
+
+func TestFollowEvents(t *testing.T) {
+	t.Parallel()
+
+	updates := make(chan client.TableUpdate, 3)
+	first := db.Event{ID: 1, Namespace: "ns", Time: time.Unix(100, 0).UTC(),
+		Type: db.MachineBooted, Message: "booted"}
+	second := db.Event{ID: 3, Namespace: "ns", Time: time.Unix(200, 0).UTC(),
+		Type: db.MachineTerminated, Message: "stopped"}
+	other := db.Event{ID: 2, Namespace: "other", Time: time.Unix(150, 0).UTC(),
+		Type: db.ProviderError, Message: "failed"}
+
+	updates <- client.TableUpdate{Table: db.EventTable, Rows: []db.Event{first}}
+	updates <- client.TableUpdate{Table: db.EventTable,
+		Rows: []db.Event{first, other, second}}
+	updates <- client.TableUpdate{Table: db.EventTable, Rows: []db.Event(nil)}
+	close(updates)
+
+	var b bytes.Buffer
+	err := followEvents(&b, &client.Watcher{C: updates}, "ns")
+	assert.NoError(t, err)
+
+	exp := "TIME                    NAMESPACE    MACHINE    TYPE" +
+		"             MESSAGE\n" +
+		"1970-01-01T00:01:40Z    ns                      MachineBooted" +
+		"    booted\n" +
+		"1970-01-01T00:03:20Z    ns        MachineTerminated    stopped\n"
+	assert.Equal(t, exp, b.String())
+}
+
+func TestWriteEvents(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	writeEvents(&b, []db.Event{
+		{ID: 1, Time: time.Unix(100, 0).UTC(), Namespace: "ns",
+			Machine: "0123456789abcdef", Type: db.PlacementFailed,
+			Message: "No minion can run container 1 (alpine)."},
+	}, true)
+
+	exp := "TIME                    NAMESPACE    MACHINE         TYPE" +
+		"               MESSAGE\n" +
+		"1970-01-01T00:01:40Z    ns           0123456789ab    PlacementFailed" +
+		"    No minion can run container 1 (alpine).\n"
+	assert.Equal(t, exp, b.String())
+}
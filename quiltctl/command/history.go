@@ -0,0 +1,240 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/api/client/getter"
+	"github.com/NetSys/quilt/db"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// History contains the options for querying past states of the database.
+type History struct {
+	at        string
+	table     db.TableType
+	namespace string
+	flags     *flag.FlagSet
+
+	common       *commonFlags
+	clientGetter client.Getter
+}
+
+// NewHistoryCommand creates a new History command instance.
+func NewHistoryCommand() *History {
+	return &History{
+		common:       &commonFlags{},
+		clientGetter: getter.New(getter.TLSDir()),
+	}
+}
+
+var historyUsage = `usage: quilt history [-H=<daemon_host>] ` +
+	`[-namespace=<namespace> | <table> -at=<time>]
+
+Without a table, list the stitches most recently deployed to each namespace, or
+only to the given namespace, newest first.  Any of them may be redeployed with ` +
+	"`quilt rollback <id>`" + `.
+
+With -at, display the contents of a database table as they were at some point in
+the past.  The time is either an RFC 3339 timestamp, or a duration before now.
+
+To see the machines that were running ten minutes ago:
+quilt history machines -at=10m
+`
+
+// The tables whose history may be queried, keyed by the name users refer to them by.
+var historyTables = map[string]db.TableType{
+	"machine":    db.MachineTable,
+	"container":  db.ContainerTable,
+	"etcd":       db.EtcdTable,
+	"connection": db.ConnectionTable,
+	"label":      db.LabelTable,
+	"cluster":    db.ClusterTable,
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (hCmd *History) InstallFlags(flags *flag.FlagSet) {
+	hCmd.common.InstallFlags(flags)
+	flags.StringVar(&hCmd.at, "at", "", "the time at which to query the table")
+	flags.StringVar(&hCmd.namespace, "namespace", "",
+		"only list the deployments of this namespace")
+	hCmd.flags = flags
+
+	flags.Usage = func() {
+		fmt.Println(historyUsage)
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the history command.
+func (hCmd *History) Parse(args []string) error {
+	if len(args) == 0 {
+		if hCmd.at != "" {
+			return errors.New("must specify a table")
+		}
+		return nil
+	}
+
+	// The flag package stops parsing at the first positional argument, so flags
+	// that follow the table name are parsed here.
+	if err := hCmd.flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if hCmd.flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %s",
+			strings.Join(hCmd.flags.Args(), " "))
+	}
+
+	name := strings.TrimPrefix(strings.ToLower(args[0]), "db.")
+	table, ok := historyTables[strings.TrimSuffix(name, "s")]
+	if !ok {
+		return fmt.Errorf("unknown table: %s", args[0])
+	}
+	hCmd.table = table
+
+	if hCmd.at == "" {
+		return errors.New("must specify a time with -at")
+	}
+	if hCmd.namespace != "" {
+		return errors.New("-namespace only applies to the list of deployments")
+	}
+	return nil
+}
+
+// Run retrieves and prints the requested table as of the requested time, or the
+// recent deployments if no table was requested.
+func (hCmd *History) Run() int {
+	if hCmd.table == "" {
+		return hCmd.listDeployments()
+	}
+
+	at, err := parseHistoryTime(hCmd.at, time.Now())
+	if err != nil {
+		log.WithError(err).Error("Invalid time.")
+		return 1
+	}
+
+	localClient, err := hCmd.clientGetter.Client(hCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer localClient.Close()
+
+	// Like `quilt containers`, the tables maintained by the minions are queried
+	// from the cluster leader.
+	c := localClient
+	if hCmd.table != db.MachineTable && hCmd.table != db.ClusterTable {
+		c, err = hCmd.clientGetter.LeaderClient(localClient)
+		if err != nil {
+			log.WithError(err).Error("Error connecting to leader.")
+			return 1
+		}
+		defer c.Close()
+	}
+
+	rows, err := c.QueryAsOf(hCmd.table, at)
+	if err != nil {
+		log.WithError(err).Error("Unable to query history.")
+		return 1
+	}
+
+	writeHistory(os.Stdout, rows)
+	return 0
+}
+
+func (hCmd *History) listDeployments() int {
+	c, err := hCmd.clientGetter.Client(hCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer c.Close()
+
+	deployments, err := c.ListDeployments()
+	if err != nil {
+		log.WithError(err).Error("Unable to list deployments.")
+		return 1
+	}
+
+	writeDeployments(os.Stdout, filterDeployments(deployments, hCmd.namespace))
+	return 0
+}
+
+// filterDeployments returns the deployments of `namespace`, or all of them if no
+// namespace is given.
+func filterDeployments(deployments []db.Deployment, namespace string) []db.Deployment {
+	if namespace == "" {
+		return deployments
+	}
+
+	var result []db.Deployment
+	for _, d := range deployments {
+		if d.Namespace == namespace {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// parseHistoryTime interprets `at` as either an RFC 3339 timestamp, or a duration
+// before `now`.
+func parseHistoryTime(at string, now time.Time) (time.Time, error) {
+	if ago, err := time.ParseDuration(at); err == nil {
+		return now.Add(-ago), nil
+	}
+	return time.Parse(time.RFC3339, at)
+}
+
+func writeHistory(fd io.Writer, rows interface{}) {
+	switch rows := rows.(type) {
+	case []db.Machine:
+		writeMachines(fd, rows)
+	case []db.Container:
+		for _, dbc := range rows {
+			fmt.Fprintln(fd, dbc)
+		}
+	case []db.Etcd:
+		for _, etcd := range rows {
+			fmt.Fprintln(fd, etcd)
+		}
+	case []db.Connection:
+		for _, conn := range rows {
+			fmt.Fprintln(fd, conn)
+		}
+	case []db.Label:
+		for _, label := range rows {
+			fmt.Fprintln(fd, label)
+		}
+	case []db.Cluster:
+		for _, clst := range rows {
+			fmt.Fprintln(fd, clst)
+		}
+	}
+}
+
+// writeDeployments prints `deployments`, which are ordered from oldest to newest,
+// with the newest first.
+func writeDeployments(fd io.Writer, deployments []db.Deployment) {
+	w := tabwriter.NewWriter(fd, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "ID\tNAMESPACE\tDEPLOYED\tHASH")
+
+	for i := len(deployments) - 1; i >= 0; i-- {
+		d := deployments[i]
+		hash := d.Hash
+		if len(hash) > 12 {
+			hash = hash[:12]
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", d.ID, d.Namespace,
+			d.Time.Format(time.RFC3339), hash)
+	}
+}
@@ -0,0 +1,156 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/NetSys/quilt/api/client/mocks"
+	"github.com/NetSys/quilt/db"
+)
+
+func TestHistoryFlags(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewHistoryCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"-at", "10m", "Machines"}))
+	assert.Equal(t, db.MachineTable, cmd.table)
+	assert.Equal(t, "10m", cmd.at)
+
+	cmd = NewHistoryCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"-at", "1m", "db.Etcd"}))
+	assert.Equal(t, db.EtcdTable, cmd.table)
+
+	cmd = NewHistoryCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"machines", "--at=10m"}))
+	assert.Equal(t, db.MachineTable, cmd.table)
+	assert.Equal(t, "10m", cmd.at)
+
+	cmd = NewHistoryCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"etcd", "-at", "1m"}))
+	assert.Equal(t, db.EtcdTable, cmd.table)
+	assert.Equal(t, "1m", cmd.at)
+
+	cmd = NewHistoryCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"etcd", "-at", "1m", "foo"}),
+		"unexpected arguments: foo")
+
+	cmd = NewHistoryCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"-at", "1m", "foo"}),
+		"unknown table: foo")
+
+	cmd = NewHistoryCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"machines"}),
+		"must specify a time with -at")
+
+	cmd = NewHistoryCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"-at", "1m"}),
+		"must specify a table")
+
+	cmd = NewHistoryCommand()
+	assert.NoError(t, parseHelper(cmd, nil))
+	assert.Equal(t, db.TableType(""), cmd.table)
+
+	cmd = NewHistoryCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"-namespace", "ns"}))
+	assert.Equal(t, "ns", cmd.namespace)
+
+	cmd = NewHistoryCommand()
+	assert.EqualError(t, parseHelper(cmd,
+		[]string{"-namespace", "ns", "machines", "-at", "1m"}),
+		"-namespace only applies to the list of deployments")
+}
+
+func TestParseHistoryTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	at, err := parseHistoryTime("10s", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Unix(990, 0), at)
+
+	at, err = parseHistoryTime("1970-01-01T00:01:40Z", now)
+	assert.NoError(t, err)
+	assert.True(t, time.Unix(100, 0).Equal(at))
+
+	_, err = parseHistoryTime("yesterday", now)
+	assert.Error(t, err)
+}
+
+func TestHistoryRun(t *testing.T) {
+	t.Parallel()
+
+	c := &mocks.Client{AsOfReturn: []db.Machine{}}
+	mockGetter := new(mocks.Getter)
+	mockGetter.On("Client", mock.Anything).Return(c, nil)
+
+	cmd := NewHistoryCommand()
+	cmd.clientGetter = mockGetter
+	cmd.table = db.MachineTable
+	cmd.at = "1970-01-01T00:01:40Z"
+	assert.Equal(t, 0, cmd.Run())
+	assert.Equal(t, db.MachineTable, c.AsOfTable)
+	assert.True(t, time.Unix(100, 0).Equal(c.AsOfTime))
+
+	c.AsOfErr = errors.New("database history is disabled")
+	assert.Equal(t, 1, cmd.Run())
+}
+
+func TestHistoryListDeployments(t *testing.T) {
+	t.Parallel()
+
+	c := &mocks.Client{DeploymentsReturn: []db.Deployment{{ID: 1}}}
+	mockGetter := new(mocks.Getter)
+	mockGetter.On("Client", mock.Anything).Return(c, nil)
+
+	cmd := NewHistoryCommand()
+	cmd.clientGetter = mockGetter
+	assert.Equal(t, 0, cmd.Run())
+
+	c.DeploymentsErr = errors.New("unavailable")
+	assert.Equal(t, 1, cmd.Run())
+}
+
+func TestFilterDeployments(t *testing.T) {
+	t.Parallel()
+
+	deployments := []db.Deployment{
+		{ID: 1, Namespace: "a"},
+		{ID: 2, Namespace: "b"},
+		{ID: 3, Namespace: "a"},
+	}
+	assert.Equal(t, deployments, filterDeployments(deployments, ""))
+	assert.Equal(t, []db.Deployment{deployments[0], deployments[2]},
+		filterDeployments(deployments, "a"))
+	assert.Empty(t, filterDeployments(deployments, "c"))
+}
+
+func TestWriteDeployments(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	writeDeployments(&b, []db.Deployment{
+		{ID: 1, Namespace: "ns", Hash: "0123456789abcdef",
+			Time: time.Unix(100, 0).UTC()},
+		{ID: 2, Namespace: "other", Hash: "fedcba9876543210",
+			Time: time.Unix(200, 0).UTC()},
+	})
+
+	exp := "ID    NAMESPACE    DEPLOYED                HASH\n" +
+		"2     other        1970-01-01T00:03:20Z    fedcba987654\n" +
+		"1     ns           1970-01-01T00:01:40Z    0123456789ab\n"
+	assert.Equal(t, exp, b.String())
+}
+
+func TestWriteHistory(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	writeHistory(&b, []db.Label{{Label: "foo", IP: "10.0.0.1"}})
+	assert.Equal(t, db.Label{Label: "foo", IP: "10.0.0.1"}.String()+"\n",
+		b.String())
+}
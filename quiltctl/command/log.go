@@ -31,7 +31,7 @@ type Log struct {
 func NewLogCommand() *Log {
 	return &Log{
 		sshGetter:    ssh.New,
-		clientGetter: getter.New(),
+		clientGetter: getter.New(getter.TLSDir()),
 		common:       &commonFlags{},
 	}
 }
@@ -6,8 +6,10 @@ import (
 	"io"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
+	units "github.com/docker/go-units"
 
 	"github.com/NetSys/quilt/api/client"
 	"github.com/NetSys/quilt/api/client/getter"
@@ -17,6 +19,8 @@ import (
 
 // Machine contains the options for querying machines.
 type Machine struct {
+	namespace string
+
 	common       *commonFlags
 	clientGetter client.Getter
 }
@@ -25,15 +29,19 @@ type Machine struct {
 func NewMachineCommand() *Machine {
 	return &Machine{
 		common:       &commonFlags{},
-		clientGetter: getter.New(),
+		clientGetter: getter.New(getter.TLSDir()),
 	}
 }
 
 // InstallFlags sets up parsing for command line flags
 func (mCmd *Machine) InstallFlags(flags *flag.FlagSet) {
 	mCmd.common.InstallFlags(flags)
+	flags.StringVar(&mCmd.namespace, "namespace", "",
+		"only show the machines in this namespace")
+
 	flags.Usage = func() {
-		fmt.Println("usage: quilt machine [-H=<daemon_host>]")
+		fmt.Println("usage: quilt machine [-H=<daemon_host>] " +
+			"[-namespace=<namespace>]")
 		fmt.Println("`machine` displays the status of quilt-managed machines.")
 		flags.PrintDefaults()
 	}
@@ -59,18 +67,45 @@ func (mCmd *Machine) Run() int {
 		return 1
 	}
 
-	writeMachines(os.Stdout, machines)
+	writeMachines(os.Stdout, filterMachines(machines, mCmd.namespace))
 	return 0
 }
 
+// filterMachines returns the machines in `namespace`, or all of them if no namespace
+// is given.
+func filterMachines(machines []db.Machine, namespace string) []db.Machine {
+	if namespace == "" {
+		return machines
+	}
+
+	var result []db.Machine
+	for _, m := range machines {
+		if m.Namespace == namespace {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
 func writeMachines(fd io.Writer, machines []db.Machine) {
 	w := tabwriter.NewWriter(fd, 0, 0, 4, ' ', 0)
 	defer w.Flush()
-	fmt.Fprintln(w, "MACHINE\tROLE\tPROVIDER\tREGION\tSIZE\tPUBLIC IP\tCONNECTED")
+	fmt.Fprintln(w, "MACHINE\tNAMESPACE\tROLE\tPROVIDER\tREGION\tSIZE\t"+
+		"PUBLIC IP\tCONNECTED\tVERSION\tBACKOFF")
 
 	for _, m := range db.SortMachines(machines) {
-		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
-			util.ShortUUID(m.StitchID), m.Role, m.Provider, m.Region, m.Size,
-			m.PublicIP, m.Connected)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			util.ShortUUID(m.StitchID), m.Namespace, m.Role, m.Provider,
+			m.Region, m.Size, m.PublicIP, m.Connected, m.MinionVersion,
+			backoffStr(m.BackoffUntil, time.Now()))
+	}
+}
+
+// backoffStr describes how much longer the daemon is leaving the machine's provider
+// alone after failures, if it is.
+func backoffStr(until, now time.Time) string {
+	if !now.Before(until) {
+		return ""
 	}
+	return units.HumanDuration(until.Sub(now))
 }
@@ -15,6 +15,8 @@ import (
 
 // Ps contains the options for querying machines and containers.
 type Ps struct {
+	namespace string
+
 	common       *commonFlags
 	clientGetter client.Getter
 }
@@ -23,15 +25,19 @@ type Ps struct {
 func NewPsCommand() *Ps {
 	return &Ps{
 		common:       &commonFlags{},
-		clientGetter: getter.New(),
+		clientGetter: getter.New(getter.TLSDir()),
 	}
 }
 
 // InstallFlags sets up parsing for command line flags
 func (pCmd *Ps) InstallFlags(flags *flag.FlagSet) {
 	pCmd.common.InstallFlags(flags)
+	flags.StringVar(&pCmd.namespace, "namespace", "",
+		"only show the machines and containers in this namespace")
+
 	flags.Usage = func() {
-		fmt.Println("usage: quilt ps [-H=<daemon_host>]")
+		fmt.Println("usage: quilt ps [-H=<daemon_host>] " +
+			"[-namespace=<namespace>]")
 		fmt.Println("`ps` displays the status of quilt-managed " +
 			"machines and containers.")
 
@@ -60,6 +66,10 @@ func (pCmd *Ps) run() error {
 	}
 	defer localClient.Close()
 
+	if pCmd.namespace != "" {
+		localClient = namespaceClient{localClient, pCmd.namespace}
+	}
+
 	var connections []db.Connection
 	var containers []db.Container
 	var machines []db.Machine
@@ -189,3 +199,15 @@ func updateContainers(lContainers []db.Container,
 	}
 	return allContainers
 }
+
+// namespaceClient is a Client whose machines are limited to those in a namespace, so
+// that the leader and workers found through it belong to that namespace's cluster.
+type namespaceClient struct {
+	client.Client
+	namespace string
+}
+
+func (c namespaceClient) QueryMachines() ([]db.Machine, error) {
+	machines, err := c.Client.QueryMachines()
+	return filterMachines(machines, c.namespace), err
+}
@@ -23,6 +23,22 @@ func TestPsFlags(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, expHost, cmd.common.host)
+
+	cmd = NewPsCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"-namespace", "ns"}))
+	assert.Equal(t, "ns", cmd.namespace)
+}
+
+func TestNamespaceClient(t *testing.T) {
+	t.Parallel()
+
+	c := namespaceClient{&clientMock.Client{MachineReturn: []db.Machine{
+		{ID: 1, Namespace: "a"}, {ID: 2, Namespace: "b"},
+	}}, "a"}
+
+	machines, err := c.QueryMachines()
+	assert.NoError(t, err)
+	assert.Equal(t, []db.Machine{{ID: 1, Namespace: "a"}}, machines)
 }
 
 func TestPsErrors(t *testing.T) {
@@ -38,7 +54,7 @@ func TestPsErrors(t *testing.T) {
 	mockGetter = new(clientMock.Getter)
 	mockGetter.On("Client", mock.Anything).Return(nil, mockErr)
 
-	cmd = &Ps{&commonFlags{}, mockGetter}
+	cmd = &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	assert.EqualError(t, cmd.run(), "error connecting to quilt daemon: error")
 	mockGetter.AssertExpectations(t)
 
@@ -48,7 +64,7 @@ func TestPsErrors(t *testing.T) {
 	mockGetter.On("Client", mock.Anything).Return(mockClient, nil)
 	mockGetter.On("LeaderClient", mock.Anything).Return(nil, mockErr)
 
-	cmd = &Ps{&commonFlags{}, mockGetter}
+	cmd = &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	assert.EqualError(t, cmd.run(), "unable to query machines: error")
 	mockGetter.AssertExpectations(t)
 
@@ -58,7 +74,7 @@ func TestPsErrors(t *testing.T) {
 	mockGetter.On("Client", mock.Anything).Return(mockClient, nil)
 	mockGetter.On("LeaderClient", mock.Anything).Return(nil, mockErr)
 
-	cmd = &Ps{&commonFlags{}, mockGetter}
+	cmd = &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	assert.NoError(t, cmd.run())
 	mockGetter.AssertExpectations(t)
 
@@ -69,7 +85,7 @@ func TestPsErrors(t *testing.T) {
 	mockGetter.On("Client", mock.Anything).Return(mockClient, nil)
 	mockGetter.On("LeaderClient", mock.Anything).Return(mockLeaderClient, nil)
 
-	cmd = &Ps{&commonFlags{}, mockGetter}
+	cmd = &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	assert.EqualError(t, cmd.run(), "unable to query containers: error")
 	mockGetter.AssertExpectations(t)
 
@@ -80,7 +96,7 @@ func TestPsErrors(t *testing.T) {
 	mockGetter.On("Client", mock.Anything).Return(mockClient, nil)
 	mockGetter.On("LeaderClient", mock.Anything).Return(mockLeaderClient, nil)
 
-	cmd = &Ps{&commonFlags{}, mockGetter}
+	cmd = &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	assert.EqualError(t, cmd.run(), "unable to query connections: error")
 	mockGetter.AssertExpectations(t)
 
@@ -99,7 +115,7 @@ func TestPsErrors(t *testing.T) {
 	mockGetter.On("Client", mock.Anything).Return(mockClient, nil)
 	mockGetter.On("LeaderClient", mock.Anything).Return(mockLeaderClient, nil)
 
-	cmd = &Ps{&commonFlags{}, mockGetter}
+	cmd = &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	assert.Equal(t, 0, cmd.Run())
 	mockGetter.AssertExpectations(t)
 }
@@ -114,7 +130,7 @@ func TestPsSuccess(t *testing.T) {
 	mockGetter.On("Client", mock.Anything).Return(mockClient, nil)
 	mockGetter.On("LeaderClient", mock.Anything).Return(mockLeaderClient, nil)
 
-	cmd := &Ps{&commonFlags{}, mockGetter}
+	cmd := &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	assert.Equal(t, 0, cmd.Run())
 	mockGetter.AssertExpectations(t)
 }
@@ -141,7 +157,7 @@ func TestQueryWorkersSuccess(t *testing.T) {
 	}
 	mockGetter.On("Client", mock.Anything).Return(mockClient, nil)
 
-	cmd := &Ps{&commonFlags{}, mockGetter}
+	cmd := &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	result := cmd.queryWorkers(machines)
 	assert.Equal(t, containers, result)
 	mockGetter.AssertExpectations(t)
@@ -177,7 +193,7 @@ func TestQueryWorkersFailure(t *testing.T) {
 	mockGetter.On("Client", api.RemoteAddress("1.2.3.4")).Return(nil, mockErr)
 	mockGetter.On("Client", api.RemoteAddress("5.6.7.8")).Return(mockClient, nil)
 
-	cmd := &Ps{&commonFlags{}, mockGetter}
+	cmd := &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	result := cmd.queryWorkers(machines)
 	assert.Equal(t, containers, result)
 	mockGetter.AssertExpectations(t)
@@ -191,7 +207,7 @@ func TestQueryWorkersFailure(t *testing.T) {
 	mockGetter.On("Client", api.RemoteAddress("1.2.3.4")).Return(failingClient, nil)
 	mockGetter.On("Client", api.RemoteAddress("5.6.7.8")).Return(mockClient, nil)
 
-	cmd = &Ps{&commonFlags{}, mockGetter}
+	cmd = &Ps{common: &commonFlags{}, clientGetter: mockGetter}
 	result = cmd.queryWorkers(machines)
 	assert.Equal(t, containers, result)
 	mockGetter.AssertExpectations(t)
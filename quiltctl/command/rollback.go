@@ -0,0 +1,105 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/api/client/getter"
+)
+
+// Rollback contains the options for redeploying a previous stitch.
+type Rollback struct {
+	id        int
+	namespace string
+
+	common       *commonFlags
+	clientGetter client.Getter
+}
+
+// NewRollbackCommand creates a new Rollback command instance.
+func NewRollbackCommand() *Rollback {
+	return &Rollback{
+		clientGetter: getter.New(getter.TLSDir()),
+		common:       &commonFlags{},
+	}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (rCmd *Rollback) InstallFlags(flags *flag.FlagSet) {
+	rCmd.common.InstallFlags(flags)
+	flags.StringVar(&rCmd.namespace, "namespace", "",
+		"refuse to roll back deployments of any other namespace")
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt rollback [-H=<daemon_host>] " +
+			"[-namespace=<namespace>] <id>")
+		fmt.Println("`rollback` redeploys the stitch of a previous " +
+			"deployment to the namespace it was deployed to.  The IDs of " +
+			"recent deployments are listed by `quilt history`.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the rollback command.
+func (rCmd *Rollback) Parse(args []string) error {
+	if len(args) != 1 {
+		return errors.New("must specify a deployment ID")
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("malformed deployment ID: %s", args[0])
+	}
+	rCmd.id = id
+	return nil
+}
+
+// Run redeploys the requested deployment.
+func (rCmd *Rollback) Run() int {
+	c, err := rCmd.clientGetter.Client(rCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer c.Close()
+
+	if rCmd.namespace != "" {
+		if err := checkDeploymentNamespace(c, rCmd.id, rCmd.namespace); err != nil {
+			log.WithError(err).Error("Unable to roll back.")
+			return 1
+		}
+	}
+
+	if err := c.Rollback(rCmd.id); err != nil {
+		log.WithError(err).Error("Unable to roll back.")
+		return 1
+	}
+
+	log.WithField("id", rCmd.id).Debug("Rolled back deployment")
+	return 0
+}
+
+// checkDeploymentNamespace returns an error unless the deployment with ID `id` was
+// deployed to `namespace`.
+func checkDeploymentNamespace(c client.Client, id int, namespace string) error {
+	deployments, err := c.ListDeployments()
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deployments {
+		if d.ID == id {
+			if d.Namespace != namespace {
+				return fmt.Errorf("deployment %d is of namespace %q, not %q",
+					id, d.Namespace, namespace)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no deployment with ID %d", id)
+}
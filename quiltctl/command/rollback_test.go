@@ -0,0 +1,79 @@
+package command
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/NetSys/quilt/api/client/mocks"
+	"github.com/NetSys/quilt/db"
+)
+
+func TestRollbackFlags(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewRollbackCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"3"}))
+	assert.Equal(t, 3, cmd.id)
+
+	cmd = NewRollbackCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"-namespace", "ns", "3"}))
+	assert.Equal(t, 3, cmd.id)
+	assert.Equal(t, "ns", cmd.namespace)
+
+	cmd = NewRollbackCommand()
+	assert.EqualError(t, parseHelper(cmd, nil), "must specify a deployment ID")
+
+	cmd = NewRollbackCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"three"}),
+		"malformed deployment ID: three")
+}
+
+func TestRollbackRun(t *testing.T) {
+	t.Parallel()
+
+	c := &mocks.Client{}
+	mockGetter := new(mocks.Getter)
+	mockGetter.On("Client", mock.Anything).Return(c, nil)
+
+	cmd := NewRollbackCommand()
+	cmd.clientGetter = mockGetter
+	cmd.id = 2
+	assert.Equal(t, 0, cmd.Run())
+	assert.Equal(t, 2, c.RollbackArg)
+
+	c.RollbackErr = errors.New("no deployment with ID 2")
+	assert.Equal(t, 1, cmd.Run())
+}
+
+func TestRollbackNamespace(t *testing.T) {
+	t.Parallel()
+
+	c := &mocks.Client{DeploymentsReturn: []db.Deployment{
+		{ID: 1, Namespace: "ns"},
+		{ID: 2, Namespace: "other"},
+	}}
+	mockGetter := new(mocks.Getter)
+	mockGetter.On("Client", mock.Anything).Return(c, nil)
+
+	cmd := NewRollbackCommand()
+	cmd.clientGetter = mockGetter
+	cmd.namespace = "ns"
+
+	cmd.id = 2
+	assert.Equal(t, 1, cmd.Run())
+	assert.Equal(t, 0, c.RollbackArg)
+
+	cmd.id = 3
+	assert.Equal(t, 1, cmd.Run())
+	assert.Equal(t, 0, c.RollbackArg)
+
+	cmd.id = 1
+	assert.Equal(t, 0, cmd.Run())
+	assert.Equal(t, 1, c.RollbackArg)
+
+	c.DeploymentsErr = errors.New("unavailable")
+	assert.Equal(t, 1, cmd.Run())
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	log "github.com/Sirupsen/logrus"
@@ -19,13 +20,15 @@ import (
 
 	"github.com/NetSys/quilt/api/client"
 	"github.com/NetSys/quilt/api/client/getter"
+	"github.com/NetSys/quilt/db"
 	"github.com/NetSys/quilt/stitch"
 )
 
 // Run contains the options for running Stitches.
 type Run struct {
-	stitch string
-	force  bool
+	stitch    string
+	namespace string
+	force     bool
 
 	common       *commonFlags
 	clientGetter client.Getter
@@ -35,7 +38,7 @@ type Run struct {
 func NewRunCommand() *Run {
 	return &Run{
 		common:       &commonFlags{},
-		clientGetter: getter.New(),
+		clientGetter: getter.New(getter.TLSDir()),
 	}
 }
 
@@ -44,16 +47,20 @@ func (rCmd *Run) InstallFlags(flags *flag.FlagSet) {
 	rCmd.common.InstallFlags(flags)
 
 	flags.StringVar(&rCmd.stitch, "stitch", "", "the stitch to run")
+	flags.StringVar(&rCmd.namespace, "namespace", "",
+		"the namespace to run the stitch in, instead of its own")
 	flags.BoolVar(&rCmd.force, "f", false, "deploy without confirming changes")
 
 	flags.Usage = func() {
 		fmt.Println("usage: quilt run [-H=<daemon_host>] [-f] " +
-			"[-stitch=<stitch>] <stitch>")
+			"[-namespace=<namespace>] [-stitch=<stitch>] <stitch>")
 		fmt.Println("`run` compiles the provided stitch, and sends the " +
-			"result to the Quilt daemon to be executed. Confirmation is " +
-			"required if deploying the stitch would cause changes to an " +
-			"existing cluster. Confirmation can be skipped with the " +
-			"`-f` flag.")
+			"result to the Quilt daemon to be executed. If a cluster is " +
+			"already running, the machines, containers, connections and " +
+			"ACLs that deploying the stitch would change are shown, and " +
+			"confirmation is required. Confirmation can be skipped with " +
+			"the `-f` flag. Stitches in different namespaces run side " +
+			"by side.")
 		flags.PrintDefaults()
 	}
 }
@@ -94,6 +101,9 @@ func (rCmd *Run) Run() int {
 		}
 		return 1
 	}
+	if rCmd.namespace != "" {
+		compiled.Namespace = rCmd.namespace
+	}
 	deployment := compiled.String()
 
 	c, err := rCmd.clientGetter.Client(rCmd.common.host)
@@ -103,23 +113,23 @@ func (rCmd *Run) Run() int {
 	}
 	defer c.Close()
 
-	curr, err := getCurrentDeployment(c)
+	curr, err := getCurrentDeployment(c, compiled.Namespace)
 	if err != nil && err != errNoCluster {
 		log.WithError(err).Error("Unable to get current deployment.")
 		return 1
 	}
 
 	if !rCmd.force && err != errNoCluster {
-		diff, err := diffDeployment(curr.String(), deployment)
+		changes, err := describeChanges(c, curr, deployment)
 		if err != nil {
 			log.WithError(err).Error("Unable to diff deployments.")
 			return 1
 		}
 
-		if diff == "" {
+		if changes == "" {
 			fmt.Println("No change.")
 		} else {
-			fmt.Println(colorizeDiff(diff))
+			fmt.Println(colorizeDiff(changes))
 		}
 		shouldDeploy, err := confirm(os.Stdin, "Continue with deployment?")
 		if err != nil {
@@ -143,21 +153,102 @@ func (rCmd *Run) Run() int {
 	return 0
 }
 
-func getCurrentDeployment(c client.Client) (stitch.Stitch, error) {
+// getCurrentDeployment returns the stitch deployed to `namespace`.
+func getCurrentDeployment(c client.Client, namespace string) (stitch.Stitch, error) {
 	clusters, err := c.QueryClusters()
 	if err != nil {
 		return stitch.Stitch{}, err
 	}
+
+	for _, clst := range clusters {
+		if clst.Namespace == namespace {
+			return stitch.FromJSON(clst.Spec)
+		}
+	}
+	return stitch.Stitch{}, errNoCluster
+}
+
+// defaultNamespace returns the namespace of the daemon's only running cluster.  The
+// clusters of stopped namespaces remain in the daemon, so they're only considered if
+// no cluster is running.  It's an error for the daemon to be running several
+// clusters, as it's ambiguous which is meant.
+func defaultNamespace(c client.Client) (string, error) {
+	clusters, err := c.QueryClusters()
+	if err != nil {
+		return "", err
+	}
+
+	var running []db.Cluster
+	for _, clst := range clusters {
+		if !stopped(clst) {
+			running = append(running, clst)
+		}
+	}
+	if len(running) != 0 {
+		clusters = running
+	}
+
 	switch len(clusters) {
 	case 0:
-		return stitch.Stitch{}, errNoCluster
+		return "", errNoCluster
 	case 1:
-		return stitch.FromJSON(clusters[0].Spec)
+		return clusters[0].Namespace, nil
 	default:
-		panic("unreached")
+		return "", errors.New("multiple namespaces are running, " +
+			"specify one with -namespace")
 	}
 }
 
+// stopped returns whether `clst` was stopped, i.e. its spec deploys nothing.
+func stopped(clst db.Cluster) bool {
+	stc, err := stitch.FromJSON(clst.Spec)
+	return err == nil && len(stc.Machines) == 0 && len(stc.Containers) == 0
+}
+
+// describeChanges describes the changes deploying `deployment` would make.  It shows
+// the daemon's plan of the machines, containers, connections and ACLs that would
+// change, or if there's nothing to show, a diff of the stitches themselves.
+func describeChanges(c client.Client, curr stitch.Stitch, deployment string) (
+	string, error) {
+
+	plan, err := c.Plan(deployment)
+	if err != nil {
+		// Daemons from before the Plan RPC can still be diffed.
+		log.WithError(err).Debug("Unable to plan deployment.")
+	} else if !plan.Empty() {
+		return formatPlan(plan), nil
+	}
+	return diffDeployment(curr.String(), deployment)
+}
+
+// formatPlan lists the changes in `plan`, prefixing additions with "+", and removals
+// with "-", as in a diff.
+func formatPlan(plan db.Plan) string {
+	var b bytes.Buffer
+	section := func(title string, added, removed interface{}) {
+		addedVal, removedVal := reflect.ValueOf(added), reflect.ValueOf(removed)
+		if addedVal.Len() == 0 && removedVal.Len() == 0 {
+			return
+		}
+
+		fmt.Fprintf(&b, "%s:\n", title)
+		for i := 0; i < addedVal.Len(); i++ {
+			fmt.Fprintf(&b, "+ %v\n", addedVal.Index(i).Interface())
+		}
+		for i := 0; i < removedVal.Len(); i++ {
+			fmt.Fprintf(&b, "- %v\n", removedVal.Index(i).Interface())
+		}
+	}
+
+	section("Machines", plan.BootMachines, plan.TerminateMachines)
+	section("Containers", plan.StartContainers, plan.StopContainers)
+	section("Connections", plan.AddConnections, plan.RemoveConnections)
+	section("Admin ACL", plan.AddAdminACLs, plan.RemoveAdminACLs)
+	section("Application Ports", plan.AddApplicationPorts,
+		plan.RemoveApplicationPorts)
+	return b.String()
+}
+
 func diffDeployment(currRaw, newRaw string) (string, error) {
 	curr, err := prettifyJSON(currRaw)
 	if err != nil {
@@ -217,6 +217,59 @@ type colorizeTest struct {
 	exp      string
 }
 
+func TestFormatPlan(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", formatPlan(db.Plan{}))
+
+	boot := db.Machine{ID: 2, Role: db.Worker, Provider: db.Amazon}
+	terminate := db.Machine{ID: 1, Role: db.Worker, Provider: db.Google}
+	conn := db.Connection{ID: 3, From: "public", To: "red", MinPort: 80,
+		MaxPort: 80}
+	exp := "Machines:\n" +
+		"+ " + boot.String() + "\n" +
+		"- " + terminate.String() + "\n" +
+		"Connections:\n" +
+		"+ " + conn.String() + "\n" +
+		"Admin ACL:\n" +
+		"- 1.2.3.4/32\n"
+	assert.Equal(t, exp, formatPlan(db.Plan{
+		BootMachines:      []db.Machine{boot},
+		TerminateMachines: []db.Machine{terminate},
+		AddConnections:    []db.Connection{conn},
+		RemoveAdminACLs:   []string{"1.2.3.4/32"},
+	}))
+}
+
+func TestDescribeChanges(t *testing.T) {
+	t.Parallel()
+
+	curr := stitch.Stitch{Namespace: "old"}
+	boot := db.Machine{Role: db.Master, Provider: db.Amazon}
+	c := &clientMock.Client{
+		PlanReturn: db.Plan{BootMachines: []db.Machine{boot}},
+	}
+	changes, err := describeChanges(c, curr, `{"Namespace":"new"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "Machines:\n+ "+boot.String()+"\n", changes)
+	assert.Equal(t, `{"Namespace":"new"}`, c.PlanArg)
+
+	// Fall back to diffing the stitches if the plan is empty, or the daemon
+	// can't make one.
+	exp, err := diffDeployment(curr.String(), `{"Namespace":"new"}`)
+	assert.NoError(t, err)
+
+	c = &clientMock.Client{}
+	changes, err = describeChanges(c, curr, `{"Namespace":"new"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, exp, changes)
+
+	c = &clientMock.Client{PlanErr: errors.New("unknown method Plan")}
+	changes, err = describeChanges(c, curr, `{"Namespace":"new"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, exp, changes)
+}
+
 func TestColorize(t *testing.T) {
 	green := "\x1b[32m"
 	red := "\x1b[31m"
@@ -316,7 +369,8 @@ func TestPromptsUser(t *testing.T) {
 		c := &clientMock.Client{
 			ClusterReturn: []db.Cluster{
 				{
-					Spec: `{"old":"spec"}`,
+					Namespace: "default-namespace",
+					Spec:      `{"old":"spec"}`,
 				},
 			},
 		}
@@ -331,6 +385,23 @@ func TestPromptsUser(t *testing.T) {
 	}
 }
 
+func TestRunNamespace(t *testing.T) {
+	util.AppFs = afero.NewMemMapFs()
+	util.WriteFile("test.js", []byte(""), 0644)
+
+	mockGetter := new(clientMock.Getter)
+	c := &clientMock.Client{}
+	mockGetter.On("Client", mock.Anything).Return(c, nil)
+
+	runCmd := NewRunCommand()
+	runCmd.clientGetter = mockGetter
+	runCmd.stitch = "test.js"
+	runCmd.namespace = "staging"
+	runCmd.force = true
+	assert.Equal(t, 0, runCmd.Run())
+	assertDeployed(t, stitch.Stitch{Namespace: "staging"}, c.DeployArg)
+}
+
 func TestRunFlags(t *testing.T) {
 	t.Parallel()
 
@@ -339,6 +410,8 @@ func TestRunFlags(t *testing.T) {
 	checkRunParsing(t, []string{expStitch}, Run{stitch: expStitch}, nil)
 	checkRunParsing(t, []string{"-f", expStitch},
 		Run{force: true, stitch: expStitch}, nil)
+	checkRunParsing(t, []string{"-namespace", "ns", expStitch},
+		Run{namespace: "ns", stitch: expStitch}, nil)
 	checkRunParsing(t, []string{}, Run{}, errors.New("no spec specified"))
 }
 
@@ -357,4 +430,5 @@ func checkRunParsing(t *testing.T, args []string, expFlags Run, expErr error) {
 	assert.Nil(t, err)
 	assert.Equal(t, expFlags.stitch, runCmd.stitch)
 	assert.Equal(t, expFlags.force, runCmd.force)
+	assert.Equal(t, expFlags.namespace, runCmd.namespace)
 }
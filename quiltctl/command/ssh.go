@@ -32,7 +32,7 @@ type SSH struct {
 // NewSSHCommand creates a new SSH command instance.
 func NewSSHCommand() *SSH {
 	return &SSH{
-		clientGetter: getter.New(),
+		clientGetter: getter.New(getter.TLSDir()),
 		sshGetter:    ssh.New,
 		common:       &commonFlags{},
 	}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/api/client/getter"
+	"github.com/NetSys/quilt/db"
+)
+
+// Status contains the options for querying the status of deployments.
+type Status struct {
+	namespace string
+
+	common       *commonFlags
+	clientGetter client.Getter
+}
+
+// NewStatusCommand creates a new Status command instance.
+func NewStatusCommand() *Status {
+	return &Status{
+		common:       &commonFlags{},
+		clientGetter: getter.New(getter.TLSDir()),
+	}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (sCmd *Status) InstallFlags(flags *flag.FlagSet) {
+	sCmd.common.InstallFlags(flags)
+	flags.StringVar(&sCmd.namespace, "namespace", "",
+		"only show the status of the deployment in this namespace")
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt status [-H=<daemon_host>] " +
+			"[-namespace=<namespace>]")
+		fmt.Println("`status` displays whether each deployment has converged, " +
+			"and the problems preventing it from doing so.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the status command.
+func (sCmd *Status) Parse(args []string) error {
+	return nil
+}
+
+// Run retrieves and prints the status of the requested deployments.
+func (sCmd *Status) Run() int {
+	c, err := sCmd.clientGetter.Client(sCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer c.Close()
+
+	statuses, err := c.QueryStatuses()
+	if err != nil {
+		log.WithError(err).Error("Unable to query deployment status.")
+		return 1
+	}
+
+	var result []db.Status
+	for _, s := range statuses {
+		if sCmd.namespace == "" || s.Namespace == sCmd.namespace {
+			result = append(result, s)
+		}
+	}
+
+	writeStatuses(os.Stdout, result)
+	return 0
+}
+
+func writeStatuses(fd io.Writer, statuses []db.Status) {
+	w := tabwriter.NewWriter(fd, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "NAMESPACE\tSTATE\tPROBLEMS")
+
+	sort.Sort(statusSlice(statuses))
+	for _, s := range statuses {
+		problems := append([]string{}, s.Warnings...)
+		problems = append(problems, s.UnsatisfiableMachines...)
+		problems = append(problems, s.MinionWarnings...)
+		for _, dbc := range s.UnschedulableContainers {
+			problems = append(problems, "No minion can run container "+dbc)
+		}
+
+		if len(problems) == 0 {
+			fmt.Fprintf(w, "%v\t%v\t\n", s.Namespace, s.State)
+			continue
+		}
+
+		// Only the first problem is labelled with the namespace and state.
+		fmt.Fprintf(w, "%v\t%v\t%v\n", s.Namespace, s.State, problems[0])
+		for _, problem := range problems[1:] {
+			fmt.Fprintf(w, "\t\t%v\n", problem)
+		}
+	}
+}
+
+type statusSlice []db.Status
+
+func (slc statusSlice) Len() int {
+	return len(slc)
+}
+
+func (slc statusSlice) Swap(i, j int) {
+	slc[i], slc[j] = slc[j], slc[i]
+}
+
+func (slc statusSlice) Less(i, j int) bool {
+	return slc[i].Namespace < slc[j].Namespace
+}
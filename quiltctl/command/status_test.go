@@ -0,0 +1,55 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/NetSys/quilt/api/client/mocks"
+	"github.com/NetSys/quilt/db"
+)
+
+func TestStatusFlags(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewStatusCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"-namespace", "ns"}))
+	assert.Equal(t, "ns", cmd.namespace)
+}
+
+func TestStatusRun(t *testing.T) {
+	t.Parallel()
+
+	c := &mocks.Client{StatusReturn: []db.Status{{Namespace: "ns"}}}
+	mockGetter := new(mocks.Getter)
+	mockGetter.On("Client", mock.Anything).Return(c, nil)
+
+	cmd := NewStatusCommand()
+	cmd.clientGetter = mockGetter
+	assert.Equal(t, 0, cmd.Run())
+
+	c.StatusErr = errors.New("unavailable")
+	assert.Equal(t, 1, cmd.Run())
+}
+
+func TestWriteStatuses(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	warning := "A Master was specified but no workers."
+	writeStatuses(&b, []db.Status{
+		{Namespace: "staging", State: db.Blocked,
+			Warnings:                []string{warning},
+			UnschedulableContainers: []string{"1 (alpine)"}},
+		{Namespace: "production", State: db.Converged},
+	})
+
+	exp := "NAMESPACE     STATE        PROBLEMS\n" +
+		"production    Converged    \n" +
+		"staging       Blocked      A Master was specified but no workers.\n" +
+		"                           No minion can run container 1 (alpine)\n"
+	assert.Equal(t, exp, b.String())
+}
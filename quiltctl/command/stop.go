@@ -23,7 +23,7 @@ type Stop struct {
 // NewStopCommand creates a new Stop command instance.
 func NewStopCommand() *Stop {
 	return &Stop{
-		clientGetter: getter.New(),
+		clientGetter: getter.New(getter.TLSDir()),
 		common:       &commonFlags{},
 	}
 }
@@ -43,7 +43,8 @@ func (sCmd *Stop) InstallFlags(flags *flag.FlagSet) {
 		fmt.Println("`stop` creates an empty Stitch for the given namespace, " +
 			"and sends it to the Quilt daemon to be executed. If no " +
 			"namespace is specified, `stop` attempts to use the namespace " +
-			"currently tracked by the daemon.")
+			"currently tracked by the daemon, which must be running " +
+			"only one.")
 		fmt.Println("The result is that resources associated with the " +
 			"namespace, such as VMs, are freed.")
 		flags.PrintDefaults()
@@ -68,28 +69,32 @@ func (sCmd *Stop) Run() int {
 	}
 	defer c.Close()
 
-	newCluster := stitch.Stitch{
-		Namespace: sCmd.namespace,
-	}
-	if sCmd.namespace == "" || sCmd.onlyContainers {
-		currDepl, err := getCurrentDeployment(c)
+	namespace := sCmd.namespace
+	if namespace == "" {
+		namespace, err = defaultNamespace(c)
 		if err != nil {
 			log.WithError(err).
 				Error("Failed to get current cluster")
 			return 1
 		}
-		if sCmd.namespace == "" {
-			newCluster.Namespace = currDepl.Namespace
-		}
-		if sCmd.onlyContainers {
-			if newCluster.Namespace != currDepl.Namespace {
-				log.Error("Stopping only containers for a namespace " +
-					"not tracked by the remote daemon is not " +
-					"currently supported")
-				return 1
-			}
-			newCluster.Machines = currDepl.Machines
+	}
+
+	newCluster := stitch.Stitch{
+		Namespace: namespace,
+	}
+	if sCmd.onlyContainers {
+		currDepl, err := getCurrentDeployment(c, namespace)
+		if err == errNoCluster {
+			log.Error("Stopping only containers for a namespace " +
+				"not tracked by the remote daemon is not " +
+				"currently supported")
+			return 1
+		} else if err != nil {
+			log.WithError(err).
+				Error("Failed to get current cluster")
+			return 1
 		}
+		newCluster.Machines = currDepl.Machines
 	}
 
 	if err = c.Deploy(newCluster.String()); err != nil {
@@ -97,6 +102,6 @@ func (sCmd *Stop) Run() int {
 		return 1
 	}
 
-	log.WithField("namespace", sCmd.namespace).Debug("Stopping namespace")
+	log.WithField("namespace", namespace).Debug("Stopping namespace")
 	return 0
 }
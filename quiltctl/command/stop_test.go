@@ -20,7 +20,8 @@ func TestStopNamespaceDefault(t *testing.T) {
 
 	c.ClusterReturn = []db.Cluster{
 		{
-			Spec: `{"namespace": "testSpace"}`,
+			Namespace: "testSpace",
+			Spec:      `{"namespace": "testSpace"}`,
 		},
 	}
 
@@ -32,6 +33,21 @@ func TestStopNamespaceDefault(t *testing.T) {
 	c.ClusterReturn = nil
 	assert.Equal(t, 1, stopCmd.Run(),
 		"can't retrieve namespace if no cluster is deployed")
+
+	c.ClusterReturn = []db.Cluster{{Namespace: "a"}, {Namespace: "b"}}
+	c.DeployArg = ""
+	assert.Equal(t, 1, stopCmd.Run(),
+		"can't choose a namespace if several are deployed")
+	assert.Empty(t, c.DeployArg)
+
+	// Stopped namespaces are ignored.
+	c.ClusterReturn = []db.Cluster{
+		{Namespace: "a", Spec: `{"namespace": "a"}`},
+		{Namespace: "b", Spec: `{"namespace": "b", "machines": ` +
+			`[{"provider": "Amazon", "role": "Master"}]}`},
+	}
+	assert.Equal(t, 0, stopCmd.Run())
+	assertDeployed(t, stitch.Stitch{Namespace: "b"}, c.DeployArg)
 }
 
 func TestStopNamespace(t *testing.T) {
@@ -58,6 +74,11 @@ func TestStopContainers(t *testing.T) {
 
 	c.ClusterReturn = []db.Cluster{
 		{
+			Namespace: "otherSpace",
+			Spec:      `{"namespace": "otherSpace"}`,
+		},
+		{
+			Namespace: "testSpace",
 			Spec: `{"namespace": "testSpace", "machines": ` +
 				`[{"provider": "Amazon"}, {"provider": "Google"}]}`,
 		},
@@ -65,6 +86,7 @@ func TestStopContainers(t *testing.T) {
 
 	stopCmd := NewStopCommand()
 	stopCmd.clientGetter = mockGetter
+	stopCmd.namespace = "testSpace"
 	stopCmd.onlyContainers = true
 	stopCmd.Run()
 
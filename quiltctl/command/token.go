@@ -0,0 +1,144 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/NetSys/quilt/api/client"
+	"github.com/NetSys/quilt/api/client/getter"
+	"github.com/NetSys/quilt/db"
+)
+
+// Token contains the options for managing API tokens.
+type Token struct {
+	action string
+
+	// Options for `create`.
+	name string
+	role db.TokenRole
+
+	// The token to `revoke`.
+	id int
+
+	common       *commonFlags
+	clientGetter client.Getter
+}
+
+// NewTokenCommand creates a new Token command instance.
+func NewTokenCommand() *Token {
+	return &Token{
+		common:       &commonFlags{},
+		clientGetter: getter.New(getter.TLSDir()),
+	}
+}
+
+// InstallFlags sets up parsing for command line flags.
+func (tCmd *Token) InstallFlags(flags *flag.FlagSet) {
+	tCmd.common.InstallFlags(flags)
+
+	flags.Usage = func() {
+		fmt.Println("usage: quilt token [-H=<daemon_host>] create " +
+			"[-name=<name>] -role=<role>\n" +
+			"       quilt token [-H=<daemon_host>] list\n" +
+			"       quilt token [-H=<daemon_host>] revoke <id>")
+		fmt.Println("`token` manages the API tokens accepted by the daemon.  " +
+			"Once a token exists, callers that don't connect over the " +
+			"daemon's unix socket must set $QUILT_TOKEN to a token whose " +
+			"role permits what they do.  The roles are read-only, deployer " +
+			"and admin.")
+		flags.PrintDefaults()
+	}
+}
+
+// Parse parses the command line arguments for the token command.
+func (tCmd *Token) Parse(args []string) error {
+	if len(args) == 0 {
+		return errors.New("must specify create, list or revoke")
+	}
+
+	tCmd.action = args[0]
+	switch tCmd.action {
+	case "create":
+		var role string
+		flags := flag.NewFlagSet("create", flag.ContinueOnError)
+		flags.StringVar(&tCmd.name, "name", "", "who the token is issued to")
+		flags.StringVar(&role, "role", "", "read-only, deployer or admin")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		parsed, err := db.ParseTokenRole(role)
+		if err != nil {
+			return err
+		}
+		tCmd.role = parsed
+	case "list":
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New("must specify a token ID")
+		}
+
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("malformed token ID: %s", args[1])
+		}
+		tCmd.id = id
+	default:
+		return fmt.Errorf("unknown token command: %s", tCmd.action)
+	}
+	return nil
+}
+
+// Run carries out the requested token command.
+func (tCmd *Token) Run() int {
+	c, err := tCmd.clientGetter.Client(tCmd.common.host)
+	if err != nil {
+		log.Error(err)
+		return 1
+	}
+	defer c.Close()
+
+	switch tCmd.action {
+	case "create":
+		id, token, err := c.CreateToken(tCmd.name, tCmd.role)
+		if err != nil {
+			log.WithError(err).Error("Unable to create token.")
+			return 1
+		}
+
+		log.WithField("id", id).Info("Created token.  It can't be shown again.")
+		fmt.Println(token)
+	case "list":
+		tokens, err := c.ListTokens()
+		if err != nil {
+			log.WithError(err).Error("Unable to list tokens.")
+			return 1
+		}
+		writeTokens(os.Stdout, tokens)
+	case "revoke":
+		if err := c.RevokeToken(tCmd.id); err != nil {
+			log.WithError(err).Error("Unable to revoke token.")
+			return 1
+		}
+	}
+	return 0
+}
+
+func writeTokens(fd io.Writer, tokens []db.Token) {
+	w := tabwriter.NewWriter(fd, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintln(w, "ID\tNAME\tROLE\tCREATED")
+
+	for _, t := range tokens {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", t.ID, t.Name, t.Role,
+			t.Created.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/NetSys/quilt/api/client/mocks"
+	"github.com/NetSys/quilt/db"
+)
+
+func TestTokenFlags(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewTokenCommand()
+	assert.NoError(t, parseHelper(cmd,
+		[]string{"create", "-name", "ci", "-role", "deployer"}))
+	assert.Equal(t, "create", cmd.action)
+	assert.Equal(t, "ci", cmd.name)
+	assert.Equal(t, db.Deployer, cmd.role)
+
+	cmd = NewTokenCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"create", "-role", "root"}),
+		"unknown token role: root")
+
+	cmd = NewTokenCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"revoke", "3"}))
+	assert.Equal(t, 3, cmd.id)
+
+	cmd = NewTokenCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"revoke"}),
+		"must specify a token ID")
+
+	cmd = NewTokenCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"revoke", "three"}),
+		"malformed token ID: three")
+
+	cmd = NewTokenCommand()
+	assert.NoError(t, parseHelper(cmd, []string{"list"}))
+
+	cmd = NewTokenCommand()
+	assert.EqualError(t, parseHelper(cmd, nil), "must specify create, list or revoke")
+
+	cmd = NewTokenCommand()
+	assert.EqualError(t, parseHelper(cmd, []string{"rotate"}),
+		"unknown token command: rotate")
+}
+
+func TestTokenRun(t *testing.T) {
+	t.Parallel()
+
+	c := &mocks.Client{CreateTokenReturn: "secret"}
+	mockGetter := new(mocks.Getter)
+	mockGetter.On("Client", mock.Anything).Return(c, nil)
+
+	cmd := NewTokenCommand()
+	cmd.clientGetter = mockGetter
+	cmd.action = "create"
+	cmd.name = "ci"
+	cmd.role = db.Admin
+	assert.Equal(t, 0, cmd.Run())
+	assert.Equal(t, db.Token{Name: "ci", Role: db.Admin}, c.CreateTokenArgs)
+
+	cmd.action = "list"
+	assert.Equal(t, 0, cmd.Run())
+
+	cmd.action = "revoke"
+	cmd.id = 2
+	assert.Equal(t, 0, cmd.Run())
+	assert.Equal(t, 2, c.RevokeTokenArg)
+
+	c.TokenErr = errors.New("denied")
+	for _, action := range []string{"create", "list", "revoke"} {
+		cmd.action = action
+		assert.Equal(t, 1, cmd.Run(), action)
+	}
+}
+
+func TestWriteTokens(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	writeTokens(&b, []db.Token{
+		{ID: 1, Name: "ci", Role: db.Deployer, Created: time.Unix(100, 0).UTC()},
+		{ID: 2, Role: db.Admin, Created: time.Unix(200, 0).UTC()},
+	})
+
+	exp := "ID    NAME    ROLE        CREATED\n" +
+		"1     ci      deployer    1970-01-01T00:01:40Z\n" +
+		"2             admin       1970-01-01T00:03:20Z\n"
+	assert.Equal(t, exp, b.String())
+}
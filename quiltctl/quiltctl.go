@@ -12,15 +12,20 @@ import (
 var commands = map[string]command.SubCommand{
 	"containers": command.NewContainerCommand(),
 	"daemon":     command.NewDaemonCommand(),
+	"events":     command.NewEventsCommand(),
 	"get":        &command.Get{},
+	"history":    command.NewHistoryCommand(),
 	"inspect":    &command.Inspect{},
 	"logs":       command.NewLogCommand(),
 	"machines":   command.NewMachineCommand(),
 	"minion":     &command.Minion{},
 	"ps":         command.NewPsCommand(),
+	"rollback":   command.NewRollbackCommand(),
 	"run":        command.NewRunCommand(),
 	"ssh":        command.NewSSHCommand(),
+	"status":     command.NewStatusCommand(),
 	"stop":       command.NewStopCommand(),
+	"token":      command.NewTokenCommand(),
 }
 
 // Run parses and runs the quiltctl subcommand given the command line arguments.
@@ -357,6 +357,15 @@ function Machine(optionalArgs) {
     this.sshKeys = optionalArgs.sshKeys || [];
     this.cpu = boxRange(optionalArgs.cpu);
     this.ram = boxRange(optionalArgs.ram);
+
+    // Only set the purchasing options when they're given, so that they don't change
+    // the IDs of existing machines.
+    if (optionalArgs.preemptible) {
+        this.preemptible = true;
+    }
+    if (optionalArgs.spotPrice) {
+        this.spotPrice = optionalArgs.spotPrice;
+    }
 }
 
 Machine.prototype.deploy = function(deployment) {
@@ -0,0 +1,154 @@
+package stitch
+
+import (
+	"github.com/NetSys/quilt/api/pb"
+)
+
+// FromPB converts the protobuf representation of a stitch, as sent to the Deploy
+// RPC, to a Stitch.
+func FromPB(p *pb.Stitch) Stitch {
+	stc := Stitch{
+		AdminACL:  p.GetAdminACL(),
+		MaxPrice:  p.GetMaxPrice(),
+		Namespace: p.GetNamespace(),
+	}
+
+	for _, c := range p.GetContainers() {
+		stc.Containers = append(stc.Containers, Container{
+			ID:      c.ID,
+			Image:   c.Image,
+			Command: c.Command,
+			Env:     c.Env,
+		})
+	}
+
+	for _, l := range p.GetLabels() {
+		stc.Labels = append(stc.Labels, Label{
+			Name:        l.Name,
+			IDs:         l.IDs,
+			Annotations: l.Annotations,
+		})
+	}
+
+	for _, c := range p.GetConnections() {
+		stc.Connections = append(stc.Connections, Connection{
+			From:    c.From,
+			To:      c.To,
+			MinPort: int(c.MinPort),
+			MaxPort: int(c.MaxPort),
+		})
+	}
+
+	for _, plcm := range p.GetPlacements() {
+		stc.Placements = append(stc.Placements, Placement{
+			TargetLabel: plcm.TargetLabel,
+			Exclusive:   plcm.Exclusive,
+			OtherLabel:  plcm.OtherLabel,
+			Provider:    plcm.Provider,
+			Size:        plcm.Size,
+			Region:      plcm.Region,
+			FloatingIP:  plcm.FloatingIP,
+		})
+	}
+
+	for _, m := range p.GetMachines() {
+		stc.Machines = append(stc.Machines, Machine{
+			ID:          m.ID,
+			Provider:    m.Provider,
+			Role:        m.Role,
+			Size:        m.Size,
+			CPU:         Range{Min: m.CPU.GetMin(), Max: m.CPU.GetMax()},
+			RAM:         Range{Min: m.RAM.GetMin(), Max: m.RAM.GetMax()},
+			DiskSize:    int(m.DiskSize),
+			Region:      m.Region,
+			SSHKeys:     m.SSHKeys,
+			FloatingIP:  m.FloatingIP,
+			Preemptible: m.Preemptible,
+			SpotPrice:   m.SpotPrice,
+		})
+	}
+
+	for _, inv := range p.GetInvariants() {
+		stc.Invariants = append(stc.Invariants, invariant{
+			Form:   invariantType(inv.Form),
+			Target: inv.Target,
+			Nodes:  inv.Nodes,
+		})
+	}
+
+	return stc
+}
+
+// ToPB converts the stitch to its protobuf representation.
+func (stitch Stitch) ToPB() *pb.Stitch {
+	p := &pb.Stitch{
+		AdminACL:  stitch.AdminACL,
+		MaxPrice:  stitch.MaxPrice,
+		Namespace: stitch.Namespace,
+	}
+
+	for _, c := range stitch.Containers {
+		p.Containers = append(p.Containers, &pb.Stitch_Container{
+			ID:      c.ID,
+			Image:   c.Image,
+			Command: c.Command,
+			Env:     c.Env,
+		})
+	}
+
+	for _, l := range stitch.Labels {
+		p.Labels = append(p.Labels, &pb.Stitch_Label{
+			Name:        l.Name,
+			IDs:         l.IDs,
+			Annotations: l.Annotations,
+		})
+	}
+
+	for _, c := range stitch.Connections {
+		p.Connections = append(p.Connections, &pb.Connection{
+			From:    c.From,
+			To:      c.To,
+			MinPort: int32(c.MinPort),
+			MaxPort: int32(c.MaxPort),
+		})
+	}
+
+	for _, plcm := range stitch.Placements {
+		p.Placements = append(p.Placements, &pb.Placement{
+			TargetLabel: plcm.TargetLabel,
+			Exclusive:   plcm.Exclusive,
+			OtherLabel:  plcm.OtherLabel,
+			Provider:    plcm.Provider,
+			Size:        plcm.Size,
+			Region:      plcm.Region,
+			FloatingIP:  plcm.FloatingIP,
+		})
+	}
+
+	for _, m := range stitch.Machines {
+		p.Machines = append(p.Machines, &pb.Stitch_Machine{
+			ID:          m.ID,
+			Provider:    m.Provider,
+			Role:        m.Role,
+			Size:        m.Size,
+			CPU:         &pb.Stitch_Range{Min: m.CPU.Min, Max: m.CPU.Max},
+			RAM:         &pb.Stitch_Range{Min: m.RAM.Min, Max: m.RAM.Max},
+			DiskSize:    int32(m.DiskSize),
+			Region:      m.Region,
+			SSHKeys:     m.SSHKeys,
+			FloatingIP:  m.FloatingIP,
+			Preemptible: m.Preemptible,
+			SpotPrice:   m.SpotPrice,
+		})
+	}
+
+	for _, inv := range stitch.Invariants {
+		p.Invariants = append(p.Invariants, &pb.Stitch_Invariant{
+			Form:   string(inv.Form),
+			Target: inv.Target,
+			Nodes:  inv.Nodes,
+		})
+	}
+
+	return p
+}
@@ -0,0 +1,34 @@
+package stitch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPBRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	stc := Stitch{
+		Containers: []Container{{ID: "1", Image: "alpine",
+			Command: []string{"sh"}, Env: map[string]string{"k": "v"}}},
+		Labels: []Label{{Name: "red", IDs: []string{"1"},
+			Annotations: []string{aclAnnotation}}},
+		Connections: []Connection{{From: "red", To: "blue", MinPort: 80,
+			MaxPort: 81}},
+		Placements: []Placement{{TargetLabel: "red", Exclusive: true,
+			OtherLabel: "blue", Provider: "Amazon", Size: "m4.large",
+			Region: "us-west-1", FloatingIP: "1.2.3.4"}},
+		Machines: []Machine{{ID: "2", Provider: "Amazon", Role: "Master",
+			Size: "m4.large", CPU: Range{Min: 2, Max: 4}, RAM: Range{Min: 8},
+			DiskSize: 32, Region: "us-west-1", SSHKeys: []string{"key"},
+			FloatingIP: "1.2.3.4", Preemptible: true, SpotPrice: 0.25}},
+		AdminACL:  []string{"local"},
+		MaxPrice:  0.5,
+		Namespace: "ns",
+		Invariants: []invariant{{Form: reachInvariant, Target: true,
+			Nodes: []string{"red", "blue"}}},
+	}
+	assert.Equal(t, stc, FromPB(stc.ToPB()))
+	assert.Equal(t, Stitch{}, FromPB(Stitch{}.ToPB()))
+}
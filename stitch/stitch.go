@@ -76,17 +76,23 @@ type Connection struct {
 type ConnectionSlice []Connection
 
 // A Machine specifies the type of VM that should be booted.
+//
+// Preemptible machines are cheaper, but may be reclaimed by the cloud provider at any
+// time.  On Amazon, they are spot instances bid at SpotPrice, if it's given.  On
+// Google, they are preemptible VM instances.
 type Machine struct {
-	ID         string   `json:",omitempty"`
-	Provider   string   `json:",omitempty"`
-	Role       string   `json:",omitempty"`
-	Size       string   `json:",omitempty"`
-	CPU        Range    `json:",omitempty"`
-	RAM        Range    `json:",omitempty"`
-	DiskSize   int      `json:",omitempty"`
-	Region     string   `json:",omitempty"`
-	SSHKeys    []string `json:",omitempty"`
-	FloatingIP string   `json:",omitempty"`
+	ID          string   `json:",omitempty"`
+	Provider    string   `json:",omitempty"`
+	Role        string   `json:",omitempty"`
+	Size        string   `json:",omitempty"`
+	CPU         Range    `json:",omitempty"`
+	RAM         Range    `json:",omitempty"`
+	DiskSize    int      `json:",omitempty"`
+	Region      string   `json:",omitempty"`
+	SSHKeys     []string `json:",omitempty"`
+	FloatingIP  string   `json:",omitempty"`
+	Preemptible bool     `json:",omitempty"`
+	SpotPrice   float64  `json:",omitempty"`
 }
 
 // A Range defines a range of acceptable values for a Machine attribute
@@ -38,6 +38,20 @@ func TestMachine(t *testing.T) {
 				SSHKeys:  []string{"key1", "key2"},
 			}})
 
+	checkMachines(t, `deployment.deploy([new Machine({
+		provider: "Amazon",
+		preemptible: true,
+		spotPrice: 0.25
+	})])`,
+		[]Machine{
+			{
+				ID:          "c0e882ec230d9400752255671e0e935f0aa0e74a",
+				Provider:    "Amazon",
+				SSHKeys:     []string{},
+				Preemptible: true,
+				SpotPrice:   0.25,
+			}})
+
 	checkMachines(t, `var baseMachine = new Machine({provider: "Amazon"});
 		deployment.deploy(baseMachine.asMaster().replicate(2));`,
 		[]Machine{
@@ -7,8 +7,25 @@ import (
 	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+var (
+	loopTriggers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "quilt_loop_triggers_total",
+		Help: "The number of times each event loop has been triggered.",
+	}, []string{"loop"})
+
+	loopDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "quilt_loop_duration_seconds",
+		Help: "How long each run of each event loop took.",
+	}, []string{"loop"})
+)
+
+func init() {
+	prometheus.MustRegister(loopTriggers, loopDuration)
+}
+
 // Formatter implements the log formatter for Quilt.
 type Formatter struct{}
 
@@ -29,7 +46,8 @@ func (f Formatter) Format(entry *log.Entry) ([]byte, error) {
 }
 
 // EventTimer is a utility struct that allows us to time how long loops take, as
-// well as how often they are triggered.
+// well as how often they are triggered.  Both are logged, and exported as Prometheus
+// metrics labeled with the event name.
 type EventTimer struct {
 	eventName string
 	lastStart time.Time
@@ -48,6 +66,7 @@ func NewEventTimer(eventName string) *EventTimer {
 // LogStart logs the start of a loop and how long it has been since the last trigger.
 func (ltl *EventTimer) LogStart() {
 	ltl.lastStart = time.Now()
+	loopTriggers.WithLabelValues(ltl.eventName).Inc()
 	log.Debugf("Starting %s event. It has been %v since the last run.",
 		ltl.eventName, ltl.lastStart.Sub(ltl.lastEnd))
 }
@@ -55,6 +74,7 @@ func (ltl *EventTimer) LogStart() {
 // LogEnd logs the end of a loop and how long it took to run.
 func (ltl *EventTimer) LogEnd() {
 	ltl.lastEnd = time.Now()
-	log.Debugf("%s event ended. It took %v", ltl.eventName,
-		ltl.lastEnd.Sub(ltl.lastStart))
+	duration := ltl.lastEnd.Sub(ltl.lastStart)
+	loopDuration.WithLabelValues(ltl.eventName).Observe(duration.Seconds())
+	log.Debugf("%s event ended. It took %v", ltl.eventName, duration)
 }
@@ -0,0 +1,23 @@
+// Package version identifies the build of Quilt, so that quiltctl, the daemon and the
+// minions can tell when they're running incompatible versions.
+package version
+
+import "fmt"
+
+// Version is the version of this build of Quilt.  Release builds set it with
+// `-ldflags "-X github.com/NetSys/quilt/version.Version=<version>"`.
+var Version = "dev"
+
+// Schema is the version of the protocol spoken by the API and Minion services.  It
+// must be incremented whenever a change prevents older peers from interoperating.
+const Schema = 1
+
+// Check returns an error if a peer running `peerVersion`, which speaks `peerSchema`,
+// can't interoperate with this build.
+func Check(peerVersion string, peerSchema int) error {
+	if peerSchema != Schema {
+		return fmt.Errorf("version %s speaks schema %d, but version %s "+
+			"speaks schema %d", peerVersion, peerSchema, Version, Schema)
+	}
+	return nil
+}
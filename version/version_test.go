@@ -0,0 +1,14 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, Check(Version, Schema))
+	assert.NoError(t, Check("other", Schema))
+	assert.EqualError(t, Check("old", Schema-1),
+		"version old speaks schema 0, but version dev speaks schema 1")
+}